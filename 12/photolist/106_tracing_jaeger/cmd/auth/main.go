@@ -2,27 +2,26 @@ package main
 
 import (
 	"context"
-	"database/sql"
 	"fmt"
 	"log"
 	"math/rand"
 	"net"
+	"net/http"
 	"time"
 
 	"photolist/pkg/config"
+	"photolist/pkg/lifecycle"
+	"photolist/pkg/logging"
+	"photolist/pkg/metrics"
 	"photolist/pkg/session"
-	"photolist/pkg/utils/traceutils"
+	"photolist/pkg/tracing"
 
-	_ "github.com/go-sql-driver/mysql"
+	"github.com/go-sql-driver/mysql"
+	grpc_prometheus "github.com/grpc-ecosystem/go-grpc-prometheus"
+	"go.uber.org/zap"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/metadata"
-
-	opentracing "github.com/opentracing/opentracing-go"
-	"github.com/opentracing/opentracing-go/ext"
-	"github.com/uber/jaeger-client-go"
-	jaegercfg "github.com/uber/jaeger-client-go/config"
-	// jaegerlog "github.com/uber/jaeger-client-go/log"
-	"github.com/uber/jaeger-lib/metrics"
+	"google.golang.org/grpc/status"
 )
 
 var (
@@ -31,38 +30,45 @@ var (
 	buildTime string = "_dev"
 )
 
-func AccessLogInterceptor(
-	ctx context.Context,
-	req interface{},
-	info *grpc.UnaryServerInfo,
-	handler grpc.UnaryHandler,
-) (interface{}, error) {
-	start := time.Now()
-	var requestID string
-	md, mdExists := metadata.FromIncomingContext(ctx)
-	if mdExists {
-		requestID = md["x-request-id"][0]
-	} else {
-		requestID = "-"
-	}
+// userIDGetter - AuthUserIn, AuthRevokeIn, AuthSession и другие сообщения с user_id
+type userIDGetter interface {
+	GetUserID() uint32
+}
 
-	clientContext, err := opentracing.GlobalTracer().Extract(opentracing.HTTPHeaders, traceutils.MetadataReaderWriter{md})
-	var serverSpan opentracing.Span
-	if err == nil {
-		serverSpan = opentracing.StartSpan(info.FullMethod, ext.RPCServerOption(clientContext))
-	} else {
-		serverSpan = opentracing.StartSpan(info.FullMethod)
+// AccessLogInterceptor пишет те же поля, что и http access-лог photolist, route - полное имя метода
+func AccessLogInterceptor(logger *zap.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		start := time.Now()
+		requestID := "-"
+		if md, ok := metadata.FromIncomingContext(ctx); ok {
+			if ids := md.Get("x-request-id"); len(ids) > 0 {
+				requestID = ids[0]
+			}
+		}
+		ctx = logging.NewContext(ctx, logger, logging.RequestID(requestID), logging.Route(info.FullMethod))
+
+		reply, err := handler(ctx, req)
+
+		// в Check user_id есть только в ответе
+		for _, msg := range []interface{}{req, reply} {
+			if u, ok := msg.(userIDGetter); ok && u.GetUserID() != 0 {
+				logging.AddFields(ctx, logging.UserID(u.GetUserID()))
+				break
+			}
+		}
+		fields := []zap.Field{
+			zap.String("code", status.Code(err).String()),
+			zap.Duration("duration", time.Since(start)),
+		}
+		if err != nil {
+			fields = append(fields, zap.Error(err))
+		}
+		logging.FromContext(ctx).Info("access", fields...)
+		return reply, err
 	}
-	defer serverSpan.Finish()
-
-	reply, err := handler(ctx, req)
-
-	log.Printf("[access] %s %s %s '%v'", requestID, time.Since(start), info.FullMethod, err)
-	return reply, err
 }
 
 func main() {
-	log.Printf("[startup] %s, commit %s, build %s", appName, buildHash, buildTime)
 	rand.Seed(time.Now().UnixNano())
 
 	cfg := &config.Config{}
@@ -70,54 +76,71 @@ func main() {
 	if err != nil {
 		log.Fatalf("[startup] cant read config, err: %v\n", err)
 	}
+	logger, err := logging.New(appName, cfg.Log)
+	if err != nil {
+		log.Fatalf("[startup] cant init logger, err: %v\n", err)
+	}
+	defer logger.Sync()
+	logger.Info("startup", zap.String("commit", buildHash), zap.String("build", buildTime))
 
-	// start tracing cfg
-	jaegerCfgInstance := jaegercfg.Configuration{
-		ServiceName: appName,
-		Sampler: &jaegercfg.SamplerConfig{
-			Type:  jaeger.SamplerTypeConst,
-			Param: 1,
-		},
-		Reporter: &jaegercfg.ReporterConfig{
-			LogSpans:           true,
-			LocalAgentHostPort: v1.GetString("JAEGER_AGENT_ADDR"),
-		},
-		Tags: []opentracing.Tag{
-			{Key: "buildHash", Value: buildHash},
-			{Key: "buildTime", Value: buildTime},
-		},
+	closeTracer, err := tracing.Init(appName, buildHash, buildTime, cfg.Tracing)
+	if err != nil {
+		logger.Fatal("cant init tracing", zap.Error(err))
 	}
 
-	tracer, closer, err := jaegerCfgInstance.NewTracer(
-		// jaegercfg.Logger(jaegerlog.StdLogger),
-		jaegercfg.Metrics(metrics.NullFactory),
-	)
-	opentracing.SetGlobalTracer(tracer)
-	defer closer.Close()
-	// end tracing cfg
+	lc := lifecycle.New(cfg.Shutdown.Timeout)
+	lc.AddCloser("tracer", closeTracer)
 
 	// основные настройки к базе
 	dsn := "%s:%s@tcp(%s)/%s?charset=utf8&interpolateParams=true"
 	dsn = fmt.Sprintf(dsn, cfg.DB.Username, cfg.DB.Password, cfg.DB.Host, cfg.DB.Database)
-	db, err := sql.Open("mysql", dsn)
+	db := tracing.OpenDB(&mysql.MySQLDriver{}, dsn)
 	err = db.Ping() // вот тут будет первое подключение к базе
 	if err != nil {
-		log.Fatalf("[startup] cant connect to db, err: %v\n", err)
+		logger.Fatal("cant connect to db", zap.Error(err))
 	}
+	lc.AddCloser("db", db.Close)
 
+	// метрики снаружи, чтобы учитывать и время логирования; спан до лога, чтобы запросы в базу попали в него
 	server := grpc.NewServer(
-		grpc.UnaryInterceptor(AccessLogInterceptor),
+		grpc.ChainUnaryInterceptor(
+			grpc_prometheus.UnaryServerInterceptor,
+			tracing.UnaryServerInterceptor,
+			AccessLogInterceptor(logger),
+		),
 	)
 	svc := &session.AuthService{
 		DB: db,
 	}
 	session.RegisterAuthServer(server, svc)
 
+	// photoauth проверяет нас через grpc.health.v1, статус зависит от базы
+	lc.Health.Add("mysql", db.PingContext)
+	lc.Health.RegisterGRPC(server, 5*time.Second, session.AuthServiceName)
+
+	// счётчики по всем методам Auth сразу с нулями, а не после первого вызова
+	grpc_prometheus.EnableHandlingTimeHistogram()
+	grpc_prometheus.Register(server)
+	err = metrics.RegisterDB("auth", db)
+	if err != nil {
+		logger.Fatal("cant register db metrics", zap.Error(err))
+	}
+	// у auth нет своего http, /metrics и пробы на отдельном порту
+	if metricsPort := v1.GetString("service.metrics_port"); metricsPort != "" {
+		httpMux := http.NewServeMux()
+		httpMux.Handle("/metrics", metrics.Handler())
+		lc.Health.Register(httpMux)
+		lc.ServeHTTP("metrics", &http.Server{
+			Addr:    ":" + metricsPort,
+			Handler: httpMux,
+		})
+	}
+
 	listenAddr := v1.GetString("service.port")
 	lis, err := net.Listen("tcp", ":"+listenAddr)
 	if err != nil {
-		log.Fatalln("[startup] cant listen port", err)
+		logger.Fatal("cant listen port", zap.Error(err))
 	}
-	log.Printf("[startup] listening server at %s", listenAddr)
-	server.Serve(lis)
+	lc.ServeGRPC("grpc", server, lis)
+	lc.Wait()
 }
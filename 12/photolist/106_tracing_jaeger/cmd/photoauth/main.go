@@ -1,7 +1,6 @@
 package main
 
 import (
-	"database/sql"
 	"fmt"
 	"log"
 	"math/rand"
@@ -9,17 +8,17 @@ import (
 	"time"
 
 	"photolist/pkg/config"
+	"photolist/pkg/lifecycle"
+	"photolist/pkg/logging"
+	"photolist/pkg/metrics"
 	"photolist/pkg/middleware"
+	"photolist/pkg/photos"
 	"photolist/pkg/session"
+	"photolist/pkg/tracing"
 	"photolist/pkg/user"
 
-	_ "github.com/go-sql-driver/mysql"
-
-	opentracing "github.com/opentracing/opentracing-go"
-	"github.com/uber/jaeger-client-go"
-	jaegercfg "github.com/uber/jaeger-client-go/config"
-	// jaegerlog "github.com/uber/jaeger-client-go/log"
-	"github.com/uber/jaeger-lib/metrics"
+	"github.com/go-sql-driver/mysql"
+	"go.uber.org/zap"
 )
 
 var (
@@ -29,73 +28,82 @@ var (
 )
 
 func main() {
-	log.Printf("[startup] %s, commit %s, build %s", appName, buildHash, buildTime)
 	rand.Seed(time.Now().UnixNano())
 
 	cfg := &config.Config{}
 	v1, err := config.Read(appName, config.Defaults, cfg)
 	if err != nil {
-		log.Fatalf("cant read config, err: %v\n", err)
+		log.Fatalf("[startup] cant read config, err: %v\n", err)
+	}
+	logger, err := logging.New(appName, cfg.Log)
+	if err != nil {
+		log.Fatalf("[startup] cant init logger, err: %v\n", err)
 	}
+	defer logger.Sync()
+	logger.Info("startup", zap.String("commit", buildHash), zap.String("build", buildTime))
 
-	env1 := v1.GetString("example.env1")
-	env2 := v1.GetString("example.env2")
-	log.Printf("[startup] cfg: %#v, env1 %#v, env2 %#v", cfg.HTTP.Port, env1, env2)
+	logger.Info("config",
+		zap.Int("http_port", cfg.HTTP.Port),
+		zap.String("env1", v1.GetString("example.env1")),
+		zap.String("env2", v1.GetString("example.env2")),
+	)
 
 	// основные настройки к базе
 	// dsn := "root:love@tcp(host.docker.internal:3306)/photolist?charset=utf8&interpolateParams=true"
 	dsn := "%s:%s@tcp(%s)/%s?charset=utf8&interpolateParams=true"
 	dsn = fmt.Sprintf(dsn, cfg.DB.Username, cfg.DB.Password, cfg.DB.Host, cfg.DB.Database)
-	db, err := sql.Open("mysql", dsn)
+	db := tracing.OpenDB(&mysql.MySQLDriver{}, dsn)
 	err = db.Ping() // вот тут будет первое подключение к базе
 	if err != nil {
-		log.Fatalf("[startup] cant connect to db, err: %v\n", err)
+		logger.Fatal("cant connect to db", zap.Error(err))
 	}
 
-	// start tracing cfg
-	jaegerCfgInstance := jaegercfg.Configuration{
-		ServiceName: appName,
-		Sampler: &jaegercfg.SamplerConfig{
-			Type:  jaeger.SamplerTypeConst,
-			Param: 1,
-		},
-		Reporter: &jaegercfg.ReporterConfig{
-			LogSpans:           true,
-			LocalAgentHostPort: v1.GetString("JAEGER_AGENT_ADDR"),
-		},
-		Tags: []opentracing.Tag{
-			{Key: "buildHash", Value: buildHash},
-			{Key: "buildTime", Value: buildTime},
-		},
+	closeTracer, err := tracing.Init(appName, buildHash, buildTime, cfg.Tracing)
+	if err != nil {
+		logger.Fatal("cant init tracing", zap.Error(err))
 	}
 
-	tracer, closer, err := jaegerCfgInstance.NewTracer(
-		// jaegercfg.Logger(jaegerlog.StdLogger),
-		jaegercfg.Metrics(metrics.NullFactory),
-	)
-	opentracing.SetGlobalTracer(tracer)
-	defer closer.Close()
-	// end tracing cfg
+	lc := lifecycle.New(cfg.Shutdown.Timeout)
+	lc.AddCloser("db", db.Close)
+	lc.AddCloser("tracer", closeTracer)
 
 	usersRepo := user.NewUsersRepository(db)
-	log.Println("sess grpc addr:", v1.GetString("session.grpc_addr"))
-	sm, err := session.NewSessionsGRPC(v1.GetString("session.grpc_addr"))
+	logger.Info("session grpc", zap.String("addr", cfg.Session.GRPCAddr))
+	sessStore, err := session.NewStoreGRPC(cfg.Session.GRPCAddr)
 	if err != nil {
-		log.Fatalf("[startup] cant connect to session grpc, err: %v\n", err)
+		logger.Fatal("cant connect to session grpc", zap.Error(err))
+	}
+	lc.AddCloser("authGRPC", sessStore.Close)
+	// в куке session_id короткий jwt от photolist, тип и секрет должны совпадать
+	sm, err := session.New(cfg, db, sessStore)
+	if err != nil {
+		logger.Fatal("cant init sessions", zap.Error(err))
 	}
 
 	u := &user.UserHandler{
 		Tmpl:      nil,
 		Sessions:  sm,
 		UsersRepo: usersRepo,
+		Images:    photos.NewPhotosRepository(db),
 	}
 
-	handlers := middleware.AccessLog(http.HandlerFunc(u.InternalImagesAuth))
+	handlers := middleware.AccessLog(logger, nil, http.HandlerFunc(u.InternalImagesAuth))
 	handlers = middleware.RequestIDMiddleware(handlers)
 
 	http.Handle("/api/v1/internal/images/auth", handlers)
 
-	listenAddr := ":" + v1.GetString("http.port")
-	log.Printf("[startup] listening server at %s", listenAddr)
-	http.ListenAndServe(listenAddr, nil)
+	lc.Health.Add("mysql", db.PingContext)
+	lc.Health.Add("auth", sessStore.Ping)
+	lc.Health.Register(http.DefaultServeMux)
+	http.Handle("/metrics", metrics.Handler())
+	err = metrics.RegisterDB("photolist", db)
+	if err != nil {
+		logger.Fatal("cant register db metrics", zap.Error(err))
+	}
+
+	lc.ServeHTTP("http", &http.Server{
+		Addr:    ":" + v1.GetString("http.port"),
+		Handler: metrics.HTTPMiddleware(metrics.MuxRoute(http.DefaultServeMux), http.DefaultServeMux),
+	})
+	lc.Wait()
 }
@@ -1,35 +1,42 @@
 package main
 
 import (
-	"database/sql"
+	"context"
 	"fmt"
 	"io/ioutil"
 	"log"
 	"math/rand"
 	"net/http"
+	"os"
+	"strings"
 	"time"
 
 	"photolist/pkg/assets"
 	"photolist/pkg/blobstorage"
+	"photolist/pkg/comments"
 	"photolist/pkg/config"
 	"photolist/pkg/graphql"
 	"photolist/pkg/index"
+	"photolist/pkg/lifecycle"
+	"photolist/pkg/logging"
+	"photolist/pkg/mailer"
+	"photolist/pkg/metrics"
 	"photolist/pkg/middleware"
+	"photolist/pkg/notifications"
+	"photolist/pkg/oauth"
 	"photolist/pkg/photos"
 	"photolist/pkg/session"
 	"photolist/pkg/templates"
 	"photolist/pkg/token"
+	"photolist/pkg/tracing"
 	"photolist/pkg/user"
+	"photolist/pkg/utils/httputils"
 
 	// "github.com/99designs/gqlgen-contrib/gqlopentracing"
 	gqlgenHandler "github.com/99designs/gqlgen/handler"
-	_ "github.com/go-sql-driver/mysql"
-
-	opentracing "github.com/opentracing/opentracing-go"
-	"github.com/uber/jaeger-client-go"
-	jaegercfg "github.com/uber/jaeger-client-go/config"
-	// jaegerlog "github.com/uber/jaeger-client-go/log"
-	"github.com/uber/jaeger-lib/metrics"
+	"github.com/go-sql-driver/mysql"
+	"github.com/gorilla/websocket"
+	"go.uber.org/zap"
 )
 
 var (
@@ -39,84 +46,217 @@ var (
 )
 
 func main() {
-	log.Printf("[startup] %s, commit %s, build %s", appName, buildHash, buildTime)
 	rand.Seed(time.Now().UnixNano())
 
 	cfg := &config.Config{}
 	v1, err := config.Read(appName, config.Defaults, cfg)
-	log.Println(v1, cfg, err, v1.GetString("example.env2"))
+	if err != nil {
+		log.Fatalf("[startup] cant read config, err: %v\n", err)
+	}
+	logger, err := logging.New(appName, cfg.Log)
+	if err != nil {
+		log.Fatalf("[startup] cant init logger, err: %v\n", err)
+	}
+	defer logger.Sync()
+	logger.Info("startup", zap.String("commit", buildHash), zap.String("build", buildTime))
 
 	// основные настройки к базе
 	// dsn := "root:love@tcp(127.0.0.1:3306)/photolist?charset=utf8&interpolateParams=true"
 	dsn := "%s:%s@tcp(%s)/%s?charset=utf8&interpolateParams=true"
 	dsn = fmt.Sprintf(dsn, cfg.DB.Username, cfg.DB.Password, cfg.DB.Host, cfg.DB.Database)
-	db, err := sql.Open("mysql", dsn)
+	// запросы с ctx запроса попадают в трейс дочерними спанами
+	db := tracing.OpenDB(&mysql.MySQLDriver{}, dsn)
 	err = db.Ping() // вот тут будет первое подключение к базе
 	if err != nil {
-		log.Fatalf("cant connect to db, err: %v\n", err)
+		logger.Fatal("cant connect to db", zap.Error(err))
 	}
 
-	// log.Println("JAEGER_AGENT_HOST", )
+	if len(os.Args) > 1 && os.Args[1] == "reconcile-counters" {
+		err = reconcileCounters(db, os.Args[2:])
+		if err != nil {
+			logger.Fatal("reconcile-counters", zap.Error(err))
+		}
+		return
+	}
 
-	// start tracing cfg
-	jaegerCfgInstance := jaegercfg.Configuration{
-		ServiceName: appName,
-		Sampler: &jaegercfg.SamplerConfig{
-			Type:  jaeger.SamplerTypeConst,
-			Param: 1,
-		},
-		Reporter: &jaegercfg.ReporterConfig{
-			LogSpans:           true,
-			LocalAgentHostPort: v1.GetString("JAEGER_AGENT_ADDR"),
-		},
-		Tags: []opentracing.Tag{
-			{Key: "buildHash", Value: buildHash},
-			{Key: "buildTime", Value: buildTime},
-		},
+	closeTracer, err := tracing.Init(appName, buildHash, buildTime, cfg.Tracing)
+	if err != nil {
+		logger.Fatal("cant init tracing", zap.Error(err))
 	}
 
-	tracer, closer, err := jaegerCfgInstance.NewTracer(
-		// jaegercfg.Logger(jaegerlog.StdLogger),
-		jaegercfg.Metrics(metrics.NullFactory),
-	)
-	opentracing.SetGlobalTracer(tracer)
-	defer closer.Close()
-	// end tracing cfg
+	// закрываются в обратном порядке: очередь, трейсер, база
+	lc := lifecycle.New(cfg.Shutdown.Timeout)
+	lc.DrainDelay = cfg.Shutdown.DrainDelay
+	lc.AddCloser("db", db.Close)
+	lc.AddCloser("tracer", closeTracer)
 
-	// tokens, err := token.NewHMACHashToken("golangcourse")
-	// tokens, err := token.NewAesCryptHashToken("qsRY2e4hcM5T7X984E9WQ5uZ8Nty7fxB")
-	tokens, err := token.NewJwtToken(cfg.Token.Secret)
+	tokens, err := token.New(cfg)
+	if err != nil {
+		logger.Fatal("cant init tokens", zap.Error(err))
+	}
 	tmpls := templates.NewTemplates(assets.Assets, tokens)
+
+	storage, err := blobstorage.New(cfg)
 	if err != nil {
-		log.Fatalf("cant init tokens: %v\n", err)
+		logger.Fatal("cant create blobstorage", zap.String("type", cfg.Storage.Type), zap.Error(err))
 	}
+	storage = metrics.InstrumentStorage(storage, cfg.Storage.Type)
 
-	// storage := blobstorage.NewFSStorage("./images/")
-	storage, err := blobstorage.NewS3Storage(cfg.S3.Host,
-		cfg.S3.Access, cfg.S3.Secret,
-		cfg.S3.Bucket)
+	err = metrics.RegisterDB("photolist", db)
 	if err != nil {
-		log.Fatalln("cant creat s3 blobstorage", err)
+		logger.Fatal("cant register db metrics", zap.Error(err))
 	}
 
 	photosRepo := photos.NewPhotosRepository(db)
 	usersRepo := user.NewUsersRepository(db)
+	usersRepo.Passwords, err = user.NewPasswordHasher(cfg.Password)
+	if err != nil {
+		logger.Fatal("bad password config", zap.Error(err))
+	}
+
+	presets, err := photos.NewPresets(cfg.Thumbs.Presets)
+	if err != nil {
+		logger.Fatal("bad thumbs presets", zap.Error(err))
+	}
+
+	if len(os.Args) > 1 && os.Args[1] == "migrate-thumbs" {
+		err = migrateThumbs(storage, presets, os.Args[2:])
+		if err != nil {
+			logger.Fatal("migrate-thumbs", zap.Error(err))
+		}
+		return
+	}
+
+	var thumbQueue photos.ThumbQueue
+	switch cfg.Thumbs.Queue {
+	case "rabbit":
+		thumbQueue, err = photos.NewRabbitQueue(cfg.Thumbs.RabbitAddr)
+		if err != nil {
+			logger.Fatal("cant connect to rabbit", zap.Error(err))
+		}
+	default:
+		thumbQueue = photos.NewChanQueue(cfg.Thumbs.QueueSize, 5*time.Second)
+	}
+
+	thumbWorker := &photos.ThumbWorker{
+		Queue:       thumbQueue,
+		Storage:     storage,
+		Repo:        photosRepo,
+		Presets:     presets,
+		Concurrency: cfg.Thumbs.Workers,
+		MaxAttempts: cfg.Thumbs.MaxAttempts,
+		RetryDelay:  time.Second,
+	}
+	thumbsDone := make(chan struct{})
+	go func() {
+		thumbWorker.Run()
+		close(thumbsDone)
+	}()
+	// закрытие очереди будит воркер, он доделывает начатое, пока база ещё открыта
+	lc.AddWaiter("thumbWorker", thumbsDone)
+	lc.AddCloser("thumbQueue", thumbQueue.Close)
+	go func() {
+		if err := thumbWorker.Requeue(context.Background()); err != nil {
+			logger.Error("cant requeue thumbnails", zap.Error(err))
+		}
+	}()
+
+	uploader := &photos.Uploader{
+		Repo:    photosRepo,
+		Storage: storage,
+		Queue:   thumbQueue,
+	}
+
+	notifier := &notifications.Notifier{
+		Repo: notifications.NewNotificationsRepository(db),
+		Hub:  notifications.NewHub(),
+	}
+
+	avatars := &photos.Avatars{
+		Repo:    usersRepo,
+		Storage: storage,
+		Presets: presets.Only(photos.AvatarPreset),
+	}
 
 	h := &photos.PhotolistHandler{
 		UsersRepo:   usersRepo,
 		PhotosRepo:  photosRepo,
 		Tmpl:        tmpls,
 		BlobStorage: storage,
+		Uploader:    uploader,
+		Avatars:     avatars,
+		Notifier:    notifier,
+	}
+
+	moderator := comments.Chain{comments.NewBannedWords(cfg.Comments.BannedWords)}
+	if cfg.Comments.BannedWordsFile != "" {
+		fileWords, err := comments.LoadBannedWords(cfg.Comments.BannedWordsFile)
+		if err != nil {
+			logger.Fatal("cant load banned words", zap.Error(err))
+		}
+		moderator = append(moderator, fileWords)
+	}
+
+	sessStore, err := session.NewStore(cfg, db)
+	if err != nil {
+		logger.Fatal("cant init session store", zap.Error(err))
+	}
+	// соединение с auth закрываем после серверов, его же проверяет readyz
+	authStore, withAuth := sessStore.(*session.StoreGRPC)
+	if withAuth {
+		lc.AddCloser("authGRPC", authStore.Close)
+	}
+	sm, err := session.New(cfg, db, sessStore)
+	if err != nil {
+		logger.Fatal("cant init sessions", zap.Error(err))
+	}
+
+	oauthRegistry, err := oauth.NewRegistry(cfg.OAuth.RedirectURL, cfg.OAuth.Providers)
+	if err != nil {
+		logger.Fatal("cant init oauth providers", zap.Error(err))
+	}
+
+	mail, err := mailer.New(cfg.Mail)
+	if err != nil {
+		logger.Fatal("cant init mailer", zap.Error(err))
 	}
 
-	sm := session.NewSessionsDB(db)
-	// sm := session.NewSessionsJWT("golangcourseSessionSecret")
-	// sm := session.NewSessionsJWTVer(cfg.Session.Secret, db)
+	err = httputils.SetTrustedProxies(cfg.HTTP.TrustedProxies)
+	if err != nil {
+		logger.Fatal("bad trusted proxies", zap.Error(err))
+	}
+
+	limitStore, err := middleware.NewStore(cfg.RateLimit)
+	if err != nil {
+		logger.Fatal("cant init ratelimit store", zap.Error(err))
+	}
+	limiter, err := middleware.NewRateLimiter(limitStore, cfg.RateLimit.Rules, func(ctx context.Context) (uint32, bool) {
+		sess, err := session.SessionFromContext(ctx)
+		if err != nil {
+			return 0, false
+		}
+		return sess.UserID, true
+	})
+	if err != nil {
+		logger.Fatal("bad ratelimit rules", zap.Error(err))
+	}
 
 	u := &user.UserHandler{
 		Tmpl:      tmpls,
 		Sessions:  sm,
 		UsersRepo: usersRepo,
+		Notifier:  notifier,
+		Images:    photosRepo,
+		OAuth:     oauthRegistry,
+		Mailer:    mail,
+		Actions:   token.NewActionTokens(cfg.Token.Secret),
+		BaseURL:   cfg.Mail.BaseURL,
+		Lockout: &middleware.Lockout{
+			Store:     limitStore,
+			Threshold: cfg.RateLimit.Lockout.Threshold,
+			Base:      cfg.RateLimit.Lockout.Base,
+			Max:       cfg.RateLimit.Lockout.Max,
+		},
 	}
 
 	mux := http.NewServeMux()
@@ -127,16 +267,45 @@ func main() {
 	mux.HandleFunc("/api/v1/photos/list", h.ListAPI)
 	mux.HandleFunc("/api/v1/photos/upload", h.UploadAPI)
 	mux.HandleFunc("/api/v1/photos/rate", h.RateAPI)
+	mux.HandleFunc("/api/v1/photos/delete", h.DeleteAPI)
+	mux.HandleFunc("/api/v1/photos/edit", h.EditAPI)
+
+	imageHandler := &photos.ImageHandler{
+		PhotosRepo: photosRepo,
+		Avatars:    usersRepo,
+		Storage:    storage,
+		Presets:    presets,
+		MaxAge:     cfg.Thumbs.MaxAge,
+	}
+	mux.Handle("/img/", imageHandler)
+	// у fs и memory объекты под url_prefix отдаёт приложение, с той же проверкой видимости
+	if strings.HasPrefix(cfg.Storage.URLPrefix, "/") {
+		session.AllowAnonymous(cfg.Storage.URLPrefix)
+		mux.Handle(cfg.Storage.URLPrefix, &photos.ObjectHandler{
+			Images: imageHandler,
+			Prefix: cfg.Storage.URLPrefix,
+		})
+	}
 
 	mux.HandleFunc("/user/login", u.Login)
+	mux.HandleFunc("/user/login/2fa", u.LoginSecondFactor)
 	mux.HandleFunc("/user/login_oauth", u.LoginOauth)
 	mux.HandleFunc("/user/logout", u.Logout)
 	mux.HandleFunc("/user/reg", u.Reg)
 	mux.HandleFunc("/user/change_pass", u.ChangePassword)
+	mux.HandleFunc("/user/sessions", u.SessionsPage)
+	mux.HandleFunc("/user/2fa", u.TwoFactorPage)
+	mux.HandleFunc("/user/forgot", u.ForgotPassword)
+	mux.HandleFunc("/user/reset", u.ResetPassword)
+	mux.HandleFunc("/user/verify_email", u.VerifyEmail)
 
 	mux.HandleFunc("/api/v1/user/follow", u.FollowAPI)
 	mux.HandleFunc("/api/v1/user/following", u.FollowingAPI)
 	mux.HandleFunc("/api/v1/user/recomends", u.RecomendsAPI)
+	mux.HandleFunc("/api/v1/user/profile", u.ProfileAPI)
+	mux.HandleFunc("/api/v1/user/avatar", h.AvatarAPI)
+	mux.HandleFunc("/api/v1/user/sessions", u.SessionsAPI)
+	mux.HandleFunc("/api/v1/user/verify_email", u.VerifyEmailAPI)
 
 	mux.HandleFunc("/", index.Index)
 
@@ -145,40 +314,65 @@ func main() {
 			PhotosRepo:  photosRepo,
 			UsersRepo:   usersRepo,
 			BlobStorage: storage,
+			Uploader:    uploader,
+			Avatars:     avatars,
+			Comments:    comments.NewCommentsRepository(db),
+			Moderator:   moderator,
+			Notifier:    notifier,
+			Sessions:    sm,
 		}
 		gqlCfg := graphql.Config{
 			Resolvers: resolver,
 		}
+		gqlCfg.Directives.HasRole = resolver.HasRole
 		gqlHandler := gqlgenHandler.GraphQL(
 			graphql.NewExecutableSchema(gqlCfg),
 			gqlgenHandler.ComplexityLimit(500),
-			gqlgenHandler.RequestMiddleware(graphql.RequestMiddleware),   // каждый запрос после парсинга
-			gqlgenHandler.ResolverMiddleware(graphql.ResolverMiddleware), // каждый вызлв ресолвера
+			gqlgenHandler.RequestMiddleware(graphql.RequestMiddleware),    // каждый запрос после парсинга
+			gqlgenHandler.RequestMiddleware(graphql.MutationCSRF(tokens)), // мутации только с csrf-токеном
+			gqlgenHandler.ResolverMiddleware(graphql.ResolverMiddleware),  // каждый вызлв ресолвера
 			gqlgenHandler.Tracer(graphql.NewTracer()),
+			gqlgenHandler.ErrorPresenter(graphql.ErrorPresenter), // extensions.code и безопасные сообщения
+			gqlgenHandler.RecoverFunc(graphql.Recover),
+			// подписки ходят по websocket на тот же /graphql, сессия берётся из кук при апгрейде
+			gqlgenHandler.WebsocketUpgrader(websocket.Upgrader{
+				ReadBufferSize:  1024,
+				WriteBufferSize: 1024,
+			}),
+			gqlgenHandler.WebsocketKeepAliveDuration(10*time.Second),
 		)
-		myGqlHandler := graphql.UserLoaderMiddleware(resolver, gqlHandler)
+		// подписки держат websocket, сам http.Server их при остановке не закроет
+		myGqlHandler := lc.TrackHijacked("graphql subscriptions", graphql.UserLoaderMiddleware(resolver, gqlHandler))
 
 		mux.Handle("/graphql", myGqlHandler)
 		mux.Handle("/graphql/", myGqlHandler)
-		// TODO enable csrf for graphql after playground done
-		mux.HandleFunc("/playground", gqlgenHandler.Playground("GraphQL playground", "/graphql"))
+		mux.HandleFunc("/playground", graphql.Playground("GraphQL playground", "/graphql", tokens))
 	} // END gqlgen part
 
 	// отрабатывают в обратном добавлению порядке, те AuthMiddleware будет 1-м
 	handlers := token.CsrfTokenMiddleware(tokens, mux)
+	// после AuthMiddleware, чтобы лимиты by: user видели сессию
+	handlers = limiter.AfterAuth(handlers)
 	handlers = session.AuthMiddleware(sm, handlers)
-	handlers = middleware.AccessLog(handlers)
+	// лимиты by: ip и route - до проверки сессии, её не надо делать для тех, кого всё равно отсечём
+	handlers = limiter.BeforeAuth(handlers)
+	// чужие формы отсекаем ещё до сессии, в том числе на логине, где токена нет
+	handlers = token.OriginMiddleware(cfg.Token.TrustedOrigins, handlers)
+	handlers = middleware.AccessLog(logger, metrics.MuxRoute(mux), handlers)
 	handlers = middleware.RequestIDMiddleware(handlers)
 
 	http.Handle("/", handlers)
 
-	http.HandleFunc("/api/v1/internal/images/auth", u.InternalImagesAuth)
+	// пробы мимо сессий и лимитов, их дёргает оркестратор
+	lc.Health.Add("mysql", db.PingContext)
+	lc.Health.Add("storage", storage.Ping)
+	if withAuth {
+		lc.Health.Add("auth", authStore.Ping)
+	}
+	lc.Health.Register(http.DefaultServeMux)
+	http.Handle("/metrics", metrics.Handler())
 
-	imagesHandler := http.StripPrefix(
-		"/images/",
-		http.FileServer(http.Dir("./images")),
-	)
-	http.Handle("/images/", imagesHandler)
+	http.HandleFunc("/api/v1/internal/images/auth", u.InternalImagesAuth)
 
 	http.Handle("/static/", http.FileServer(assets.Assets))
 
@@ -189,7 +383,9 @@ func main() {
 		w.Write(favicon)
 	})
 
-	listenAddr := ":" + v1.GetString("http.port")
-	log.Printf("[startup] listening server at %s", listenAddr)
-	http.ListenAndServe(listenAddr, nil)
+	lc.ServeHTTP("http", &http.Server{
+		Addr:    ":" + v1.GetString("http.port"),
+		Handler: metrics.HTTPMiddleware(metrics.MuxRoute(http.DefaultServeMux, mux), http.DefaultServeMux),
+	})
+	lc.Wait()
 }
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"flag"
+	"fmt"
+
+	"photolist/pkg/photos"
+	"photolist/pkg/user"
+	"photolist/pkg/utils/dbutils"
+)
+
+// reconcileCounters - photolist reconcile-counters [-dry-run]
+// пересчитывает rating, followers_cnt и following_cnt по исходным таблицам и печатает расхождения
+func reconcileCounters(db *sql.DB, args []string) error {
+	fs := flag.NewFlagSet("reconcile-counters", flag.ContinueOnError)
+	dryRun := fs.Bool("dry-run", false, "only report drift, dont fix")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	fix := !*dryRun
+	ctx := context.Background()
+
+	ratings, err := photos.NewPhotosRepository(db).ReconcileRatings(ctx, fix)
+	if err != nil {
+		return err
+	}
+	follows, err := user.NewUsersRepository(db).ReconcileFollowCounters(ctx, fix)
+	if err != nil {
+		return err
+	}
+
+	drifts := append([]*dbutils.Drift{}, ratings...)
+	drifts = append(drifts, follows...)
+	for _, d := range drifts {
+		fmt.Println(d)
+	}
+	action := "fixed"
+	if !fix {
+		action = "found"
+	}
+	fmt.Printf("%s %d drifted counters\n", action, len(drifts))
+	return nil
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+
+	"photolist/pkg/blobstorage"
+	"photolist/pkg/photos"
+)
+
+// migrateThumbs - photolist migrate-thumbs [-dry-run]
+// переводит превьюшки со старыми именами uuid_32.jpg / uuid_600.jpg на имена пресетов
+func migrateThumbs(storage blobstorage.Storage, presets photos.Presets, args []string) error {
+	fs := flag.NewFlagSet("migrate-thumbs", flag.ContinueOnError)
+	dryRun := fs.Bool("dry-run", false, "only list legacy thumbnails, dont migrate")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	ctx := context.Background()
+
+	thumbs, err := photos.FindLegacyThumbs(ctx, storage, presets)
+	if err != nil {
+		return err
+	}
+	failed := 0
+	for _, t := range thumbs {
+		fmt.Println(t.Name, "->", photos.ThumbName(t.ObjectName, t.Format, t.Preset))
+		if *dryRun {
+			continue
+		}
+		// одна битая картинка не повод бросать остальные
+		if err := photos.MigrateLegacyThumb(ctx, storage, t); err != nil {
+			fmt.Println("\terror:", err)
+			failed++
+		}
+	}
+	action := "migrated"
+	if *dryRun {
+		action = "found"
+	}
+	fmt.Printf("%s %d legacy thumbnails, %d failed\n", action, len(thumbs)-failed, failed)
+	if failed > 0 {
+		return fmt.Errorf("%d thumbnails not migrated", failed)
+	}
+	return nil
+}
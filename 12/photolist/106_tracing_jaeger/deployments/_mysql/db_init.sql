@@ -12,6 +12,13 @@ CREATE TABLE `photos` (
   `path` varchar(255) NOT NULL,
   `rating` bigint(20) NOT NULL DEFAULT '0',
   `comment` text NOT NULL,
+  `format` varchar(8) NOT NULL DEFAULT 'jpeg',
+  `width` int(11) NOT NULL DEFAULT '0',
+  `height` int(11) NOT NULL DEFAULT '0',
+  `status` enum('processing','ready','failed') NOT NULL DEFAULT 'ready',
+  `visibility` enum('public','followers','private') NOT NULL DEFAULT 'public',
+  -- скрыто модератором, видно только владельцу
+  `hidden` tinyint(1) NOT NULL DEFAULT '0',
   PRIMARY KEY (`id`),
   KEY `user_id` (`user_id`)
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8;
@@ -40,18 +47,125 @@ DROP TABLE IF EXISTS `sessions`;
 CREATE TABLE `sessions` (
   `id` varchar(32) NOT NULL,
   `user_id` int(10) unsigned NOT NULL,
+  `ver` tinyint(4) NOT NULL DEFAULT '0',
+  `seed` varbinary(32) NOT NULL,
+  `gen` int(10) unsigned NOT NULL DEFAULT 0,
+  `rotated_at` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `user_agent` varchar(255) NOT NULL DEFAULT '',
+  `ip` varchar(45) NOT NULL DEFAULT '',
+  `created_at` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `last_seen_at` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP,
   UNIQUE KEY `id` (`id`),
   KEY `user_id` (`user_id`)
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8;
 
 
+-- вход после пароля, который ждёт второй фактор
+DROP TABLE IF EXISTS `pending_logins`;
+CREATE TABLE `pending_logins` (
+  `id` varchar(32) NOT NULL,
+  `user_id` int(10) unsigned NOT NULL,
+  `ver` tinyint(4) NOT NULL DEFAULT '0',
+  `seed` varbinary(32) NOT NULL,
+  `attempts` tinyint(3) unsigned NOT NULL DEFAULT '0',
+  `user_agent` varchar(255) NOT NULL DEFAULT '',
+  `ip` varchar(45) NOT NULL DEFAULT '',
+  `created_at` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  KEY `user_id` (`user_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+
+-- totp (RFC 6238), enabled = 0 пока пользователь не ввёл первый код
+-- last_step - последний принятый шаг, коды не новее него повторно не принимаются
+DROP TABLE IF EXISTS `user_totp`;
+CREATE TABLE `user_totp` (
+  `user_id` int(10) unsigned NOT NULL,
+  `secret` varchar(64) NOT NULL,
+  `enabled` tinyint(1) NOT NULL DEFAULT '0',
+  `last_step` bigint(20) NOT NULL DEFAULT '0',
+  `created_at` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`user_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+
+-- одноразовые коды восстановления, храним только sha256
+DROP TABLE IF EXISTS `user_recovery_codes`;
+CREATE TABLE `user_recovery_codes` (
+  `user_id` int(10) unsigned NOT NULL,
+  `code_hash` varbinary(32) NOT NULL,
+  PRIMARY KEY (`user_id`, `code_hash`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+
+-- внешние аккаунты (vk, github, google...), к одному пользователю можно привязать несколько
+DROP TABLE IF EXISTS `user_identities`;
+CREATE TABLE `user_identities` (
+  `provider` varchar(32) NOT NULL,
+  `subject` varchar(255) NOT NULL,
+  `user_id` int(11) NOT NULL,
+  `email` varchar(255) NOT NULL DEFAULT '',
+  `email_verified` tinyint(1) NOT NULL DEFAULT '0',
+  `created_at` datetime NOT NULL,
+  PRIMARY KEY (`provider`, `subject`),
+  KEY `user_id` (`user_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+
+DROP TABLE IF EXISTS `notifications`;
+CREATE TABLE `notifications` (
+  `id` int(11) NOT NULL AUTO_INCREMENT,
+  `user_id` int(11) NOT NULL,
+  `actor_id` int(11) NOT NULL,
+  `type` enum('follow','like') NOT NULL,
+  `photo_id` int(11) NOT NULL DEFAULT '0',
+  `is_read` tinyint(1) NOT NULL DEFAULT '0',
+  `created_at` datetime NOT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `user_id_actor_id_type_photo_id` (`user_id`,`actor_id`,`type`,`photo_id`),
+  KEY `user_id_is_read` (`user_id`,`is_read`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+DROP TABLE IF EXISTS `photo_comments`;
+CREATE TABLE `photo_comments` (
+  `id` int(11) NOT NULL AUTO_INCREMENT,
+  `photo_id` int(11) NOT NULL,
+  `user_id` int(11) NOT NULL,
+  `parent_id` int(11) NOT NULL DEFAULT '0',
+  `text` text NOT NULL,
+  `created_at` datetime NOT NULL,
+  PRIMARY KEY (`id`),
+  KEY `photo_id_parent_id` (`photo_id`,`parent_id`),
+  KEY `parent_id` (`parent_id`),
+  CONSTRAINT `photo_comments_ibfk_1` FOREIGN KEY (`photo_id`) REFERENCES `photos` (`id`),
+  CONSTRAINT `photo_comments_ibfk_2` FOREIGN KEY (`user_id`) REFERENCES `users` (`id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+-- жалобы на фото, открытые - очередь модерации
+-- от одного пользователя на одно фото одна жалоба
+DROP TABLE IF EXISTS `photo_reports`;
+CREATE TABLE `photo_reports` (
+  `id` int(11) NOT NULL AUTO_INCREMENT,
+  `photo_id` int(11) NOT NULL,
+  `user_id` int(11) NOT NULL,
+  `reason` varchar(500) NOT NULL DEFAULT '',
+  `status` enum('open','hidden','dismissed') NOT NULL DEFAULT 'open',
+  `created_at` datetime NOT NULL,
+  `resolved_by` int(11) NOT NULL DEFAULT '0',
+  `resolved_at` datetime DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `photo_id_user_id` (`photo_id`,`user_id`),
+  KEY `status` (`status`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
 DROP TABLE IF EXISTS `user_follows`;
 CREATE TABLE `user_follows` (
   `user_id` int(11) NOT NULL,
   `follow_id` int(11) NOT NULL,
+  PRIMARY KEY (`user_id`,`follow_id`),
   KEY `follow_id` (`follow_id`),
-  KEY `user_id_follow_id` (`user_id`,`follow_id`),
-  CONSTRAINT `user_follows_ibfk_1` FOREIGN KEY (`user_id`) REFERENCES `users` (`id`)
+  CONSTRAINT `user_follows_ibfk_1` FOREIGN KEY (`user_id`) REFERENCES `users` (`id`),
+  CONSTRAINT `user_follows_ibfk_2` FOREIGN KEY (`follow_id`) REFERENCES `users` (`id`)
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8;
 
 INSERT INTO `user_follows` (`user_id`, `follow_id`) VALUES
@@ -61,8 +175,8 @@ DROP TABLE IF EXISTS `user_photos_likes`;
 CREATE TABLE `user_photos_likes` (
   `photo_id` int(11) NOT NULL,
   `user_id` int(11) NOT NULL,
+  PRIMARY KEY (`user_id`,`photo_id`),
   KEY `photo_id` (`photo_id`),
-  KEY `user_id_photo_id` (`user_id`,`photo_id`),
   CONSTRAINT `user_photos_likes_ibfk_1` FOREIGN KEY (`photo_id`) REFERENCES `photos` (`id`),
   CONSTRAINT `user_photos_likes_ibfk_2` FOREIGN KEY (`user_id`) REFERENCES `users` (`id`)
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8;
@@ -77,15 +191,27 @@ CREATE TABLE `users` (
   `id` int(11) NOT NULL AUTO_INCREMENT,
   `login` varchar(255) NOT NULL,
   `email` varchar(255) NOT NULL,
-  `password` varbinary(100) NOT NULL,
+  -- PHC-строка $алгоритм$параметры$соль$хеш или старый формат: 8 байт соли + argon2id
+  `password` varbinary(255) NOT NULL,
   `ver` tinyint(4) NOT NULL DEFAULT '0',
+  `email_verified` tinyint(1) NOT NULL DEFAULT '0',
+  `followers_cnt` int(11) NOT NULL DEFAULT '0',
+  `following_cnt` int(11) NOT NULL DEFAULT '0',
+  `display_name` varchar(64) NOT NULL DEFAULT '',
+  `bio` varchar(1000) NOT NULL DEFAULT '',
+  `avatar` varchar(64) NOT NULL DEFAULT '',
+  `avatar_format` varchar(8) NOT NULL DEFAULT '',
+  `role` enum('user','admin') NOT NULL DEFAULT 'user',
+  -- забаненный не может войти, сессии гасятся через ver
+  `banned` tinyint(1) NOT NULL DEFAULT '0',
   PRIMARY KEY (`id`),
   UNIQUE KEY `login` (`login`),
-  UNIQUE KEY `email` (`email`)
+  UNIQUE KEY `email` (`email`),
+  KEY `avatar` (`avatar`)
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8;
 
-INSERT INTO `users` (`id`, `login`, `password`) VALUES
-(1,	'golangcourse',	UNHEX('7362415A62716D7072AC09EAE839A4A1C95E73EC5FA3FC6EACE4D2C78BF4BF1C6906789B557F8C55'));
+INSERT INTO `users` (`id`, `login`, `password`, `role`) VALUES
+(1,	'golangcourse',	UNHEX('7362415A62716D7072AC09EAE839A4A1C95E73EC5FA3FC6EACE4D2C78BF4BF1C6906789B557F8C55'),	'admin');
 
 INSERT INTO `users` (`id`, `login`, `email`, `password`, `ver`) VALUES
 (5,	'rvasily.msk',	'romanov.vasily@gmail.com',	UNHEX('6F7A6B5341575172BF22E32CBCE77A1942B342B996B0EF172673FD214512D2675C7843C6A0FA9597'),	0),
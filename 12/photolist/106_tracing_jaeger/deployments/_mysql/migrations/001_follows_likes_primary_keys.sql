@@ -0,0 +1,35 @@
+-- PRIMARY KEY на user_follows и user_photos_likes для баз, созданных до него
+-- db_init.sql эти ключи уже содержит, для новой базы миграция не нужна
+-- до ключей Rate и Follow были неатомарными, поэтому дубли в старых базах есть - сначала убираем их
+-- запускать при остановленном photolist, после - photolist reconcile-counters
+
+-- подписки на удалённых пользователей не дадут создать внешний ключ по follow_id
+DELETE FROM `user_follows` WHERE `follow_id` NOT IN (SELECT `id` FROM `users`);
+
+CREATE TEMPORARY TABLE `user_follows_dedup` AS
+  SELECT DISTINCT `user_id`, `follow_id` FROM `user_follows`;
+DELETE FROM `user_follows`;
+INSERT INTO `user_follows` (`user_id`, `follow_id`)
+  SELECT `user_id`, `follow_id` FROM `user_follows_dedup`;
+DROP TEMPORARY TABLE `user_follows_dedup`;
+
+ALTER TABLE `user_follows`
+  ADD PRIMARY KEY (`user_id`,`follow_id`),
+  DROP KEY `user_id_follow_id`,
+  ADD CONSTRAINT `user_follows_ibfk_2` FOREIGN KEY (`follow_id`) REFERENCES `users` (`id`);
+
+CREATE TEMPORARY TABLE `user_photos_likes_dedup` AS
+  SELECT DISTINCT `photo_id`, `user_id` FROM `user_photos_likes`;
+DELETE FROM `user_photos_likes`;
+INSERT INTO `user_photos_likes` (`photo_id`, `user_id`)
+  SELECT `photo_id`, `user_id` FROM `user_photos_likes_dedup`;
+DROP TEMPORARY TABLE `user_photos_likes_dedup`;
+
+ALTER TABLE `user_photos_likes`
+  ADD PRIMARY KEY (`user_id`,`photo_id`),
+  DROP KEY `user_id_photo_id`;
+
+-- счётчики подписок появились вместе с ключами, заполняет их reconcile-counters
+ALTER TABLE `users`
+  ADD COLUMN IF NOT EXISTS `followers_cnt` int(11) NOT NULL DEFAULT '0' AFTER `ver`,
+  ADD COLUMN IF NOT EXISTS `following_cnt` int(11) NOT NULL DEFAULT '0' AFTER `followers_cnt`;
@@ -0,0 +1,20 @@
+-- до user_identities вход через vk заводил пользователя с логином vk<id в vk> без всякой привязки
+-- без этой миграции такой пользователь при следующем входе через vk получит второй аккаунт vk_<логин>
+-- subject у vk - тот же числовой id, что и в логине
+-- provider - ключ из oauth.providers в конфиге, если vk там назван иначе - поменять и тут
+
+CREATE TABLE IF NOT EXISTS `user_identities` (
+  `provider` varchar(32) NOT NULL,
+  `subject` varchar(255) NOT NULL,
+  `user_id` int(11) NOT NULL,
+  `email` varchar(255) NOT NULL DEFAULT '',
+  `created_at` datetime NOT NULL,
+  PRIMARY KEY (`provider`, `subject`),
+  KEY `user_id` (`user_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+-- уже привязанные (повторный запуск) пропускаем
+INSERT IGNORE INTO `user_identities` (`provider`, `subject`, `user_id`, `email`, `created_at`)
+  SELECT 'vk', SUBSTRING(`login`, 3), `id`, `email`, NOW()
+  FROM `users`
+  WHERE `login` REGEXP '^vk[0-9]+$';
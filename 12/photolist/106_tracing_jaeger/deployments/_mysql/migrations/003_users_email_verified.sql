@@ -0,0 +1,22 @@
+-- ссылка для сброса пароля уходит только на подтверждённый email
+-- старые пользователи подтверждают адрес заново после входа (письмо из профиля)
+
+ALTER TABLE `users`
+  ADD COLUMN IF NOT EXISTS `email_verified` tinyint(1) NOT NULL DEFAULT '0' AFTER `ver`;
+
+-- ver пользователя на момент входа, после сброса пароля сессия с меньшим ver не действует
+ALTER TABLE `sessions`
+  ADD COLUMN IF NOT EXISTS `ver` tinyint(4) NOT NULL DEFAULT '0' AFTER `user_id`;
+
+-- подтвердил ли провайдер email: github - verified из /user/emails, oidc - email_verified = true, vk - никогда
+ALTER TABLE `user_identities`
+  ADD COLUMN IF NOT EXISTS `email_verified` tinyint(1) NOT NULL DEFAULT '0' AFTER `email`;
+
+-- то же правило, что и при входе: подтверждённым считается только email, который провайдер явно подтвердил
+-- у привязок, сделанных до этой миграции, флага нет, поэтому они остаются неподтверждёнными,
+-- а *@oauth.invalid - заглушка, а не адрес
+UPDATE `users` u
+  JOIN `user_identities` i ON i.`user_id` = u.`id` AND i.`email` = u.`email`
+  SET u.`email_verified` = 1
+  WHERE i.`email_verified` = 1
+    AND u.`email` NOT LIKE '%@oauth.invalid';
@@ -0,0 +1,7 @@
+-- формат и размеры оригинала определяются при загрузке по содержимому
+-- у старых фото формат неизвестен - до этого всё сохранялось как jpeg, размеры 0 - не знаем
+
+ALTER TABLE `photos`
+  ADD COLUMN IF NOT EXISTS `format` varchar(8) NOT NULL DEFAULT 'jpeg' AFTER `comment`,
+  ADD COLUMN IF NOT EXISTS `width` int(11) NOT NULL DEFAULT '0' AFTER `format`,
+  ADD COLUMN IF NOT EXISTS `height` int(11) NOT NULL DEFAULT '0' AFTER `width`;
@@ -0,0 +1,5 @@
+-- превьюшки режет очередь, пока они не готовы - processing
+-- старые фото уже нарезаны синхронно, поэтому по умолчанию ready
+
+ALTER TABLE `photos`
+  ADD COLUMN IF NOT EXISTS `status` enum('processing','ready','failed') NOT NULL DEFAULT 'ready' AFTER `height`;
@@ -0,0 +1,9 @@
+-- аватарка, отображаемое имя и описание профиля
+-- по avatar отвечает /images/ и InternalImagesAuth, поэтому индекс
+
+ALTER TABLE `users`
+  ADD COLUMN IF NOT EXISTS `display_name` varchar(64) NOT NULL DEFAULT '' AFTER `following_cnt`,
+  ADD COLUMN IF NOT EXISTS `bio` varchar(1000) NOT NULL DEFAULT '' AFTER `display_name`,
+  ADD COLUMN IF NOT EXISTS `avatar` varchar(64) NOT NULL DEFAULT '' AFTER `bio`,
+  ADD COLUMN IF NOT EXISTS `avatar_format` varchar(8) NOT NULL DEFAULT '' AFTER `avatar`,
+  ADD KEY IF NOT EXISTS `avatar` (`avatar`);
@@ -0,0 +1,14 @@
+-- уведомления о подписках и лайках, повторный лайк не плодит дубль
+
+CREATE TABLE IF NOT EXISTS `notifications` (
+  `id` int(11) NOT NULL AUTO_INCREMENT,
+  `user_id` int(11) NOT NULL,
+  `actor_id` int(11) NOT NULL,
+  `type` enum('follow','like') NOT NULL,
+  `photo_id` int(11) NOT NULL DEFAULT '0',
+  `is_read` tinyint(1) NOT NULL DEFAULT '0',
+  `created_at` datetime NOT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `user_id_actor_id_type_photo_id` (`user_id`,`actor_id`,`type`,`photo_id`),
+  KEY `user_id_is_read` (`user_id`,`is_read`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
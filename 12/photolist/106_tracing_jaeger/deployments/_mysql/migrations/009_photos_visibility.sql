@@ -0,0 +1,4 @@
+-- видимость фото, все старые фото остаются публичными
+
+ALTER TABLE `photos`
+  ADD COLUMN IF NOT EXISTS `visibility` enum('public','followers','private') NOT NULL DEFAULT 'public' AFTER `status`;
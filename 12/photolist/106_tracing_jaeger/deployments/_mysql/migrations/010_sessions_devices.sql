@@ -0,0 +1,17 @@
+-- сессии устройств с ротацией refresh-токена
+-- у старых сессий нет seed, подписать их токены нечем - такие удаляем, пользователи войдут заново
+
+ALTER TABLE `sessions`
+  ADD COLUMN IF NOT EXISTS `seed` varbinary(32) NOT NULL DEFAULT '' AFTER `ver`,
+  ADD COLUMN IF NOT EXISTS `gen` int(10) unsigned NOT NULL DEFAULT 0 AFTER `seed`,
+  ADD COLUMN IF NOT EXISTS `rotated_at` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP AFTER `gen`,
+  ADD COLUMN IF NOT EXISTS `user_agent` varchar(255) NOT NULL DEFAULT '' AFTER `rotated_at`,
+  ADD COLUMN IF NOT EXISTS `ip` varchar(45) NOT NULL DEFAULT '' AFTER `user_agent`,
+  ADD COLUMN IF NOT EXISTS `created_at` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP AFTER `ip`,
+  ADD COLUMN IF NOT EXISTS `last_seen_at` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP AFTER `created_at`;
+
+DELETE FROM `sessions` WHERE `seed` = '';
+
+-- default нужен был только для старых строк
+ALTER TABLE `sessions`
+  ALTER COLUMN `seed` DROP DEFAULT;
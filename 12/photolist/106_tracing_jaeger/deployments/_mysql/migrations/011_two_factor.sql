@@ -0,0 +1,32 @@
+-- второй фактор: незавершённые входы, totp и коды восстановления
+
+-- вход после пароля, который ждёт второй фактор
+CREATE TABLE IF NOT EXISTS `pending_logins` (
+  `id` varchar(32) NOT NULL,
+  `user_id` int(10) unsigned NOT NULL,
+  `ver` tinyint(4) NOT NULL DEFAULT '0',
+  `seed` varbinary(32) NOT NULL,
+  `attempts` tinyint(3) unsigned NOT NULL DEFAULT '0',
+  `user_agent` varchar(255) NOT NULL DEFAULT '',
+  `ip` varchar(45) NOT NULL DEFAULT '',
+  `created_at` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  KEY `user_id` (`user_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+-- enabled = 0 пока пользователь не ввёл первый код
+CREATE TABLE IF NOT EXISTS `user_totp` (
+  `user_id` int(10) unsigned NOT NULL,
+  `secret` varchar(64) NOT NULL,
+  `enabled` tinyint(1) NOT NULL DEFAULT '0',
+  `last_step` bigint(20) NOT NULL DEFAULT '0',
+  `created_at` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`user_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+-- храним только sha256 кодов
+CREATE TABLE IF NOT EXISTS `user_recovery_codes` (
+  `user_id` int(10) unsigned NOT NULL,
+  `code_hash` varbinary(32) NOT NULL,
+  PRIMARY KEY (`user_id`, `code_hash`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
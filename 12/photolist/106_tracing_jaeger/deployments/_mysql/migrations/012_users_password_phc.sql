@@ -0,0 +1,5 @@
+-- PHC-строка с параметрами хеша длиннее старых 8 байт соли + argon2id
+-- старые хеши остаются как есть и перехешируются при входе
+
+ALTER TABLE `users`
+  MODIFY COLUMN `password` varbinary(255) NOT NULL;
@@ -0,0 +1,24 @@
+-- роли, бан, скрытие фото модератором и жалобы
+-- админа назначить руками: UPDATE users SET role = 'admin' WHERE login = '...'
+
+ALTER TABLE `users`
+  ADD COLUMN IF NOT EXISTS `role` enum('user','admin') NOT NULL DEFAULT 'user' AFTER `avatar_format`,
+  ADD COLUMN IF NOT EXISTS `banned` tinyint(1) NOT NULL DEFAULT '0' AFTER `role`;
+
+ALTER TABLE `photos`
+  ADD COLUMN IF NOT EXISTS `hidden` tinyint(1) NOT NULL DEFAULT '0' AFTER `visibility`;
+
+-- от одного пользователя на одно фото одна жалоба
+CREATE TABLE IF NOT EXISTS `photo_reports` (
+  `id` int(11) NOT NULL AUTO_INCREMENT,
+  `photo_id` int(11) NOT NULL,
+  `user_id` int(11) NOT NULL,
+  `reason` varchar(500) NOT NULL DEFAULT '',
+  `status` enum('open','hidden','dismissed') NOT NULL DEFAULT 'open',
+  `created_at` datetime NOT NULL,
+  `resolved_by` int(11) NOT NULL DEFAULT '0',
+  `resolved_at` datetime DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `photo_id_user_id` (`photo_id`,`user_id`),
+  KEY `status` (`status`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
@@ -0,0 +1,92 @@
+package apierr
+
+import (
+	"errors"
+	"net/http"
+)
+
+// Code - машиночитаемый код ошибки, одинаковый в REST и в extensions.code GraphQL
+// клиенты ветвятся по нему, а не по тексту сообщения
+type Code string
+
+const (
+	CodeBadRequest       Code = "BAD_REQUEST"
+	CodeUnauthenticated  Code = "UNAUTHENTICATED"
+	CodeForbidden        Code = "FORBIDDEN"
+	CodeCSRF             Code = "CSRF"
+	CodeNotFound         Code = "NOT_FOUND"
+	CodeMethodNotAllowed Code = "METHOD_NOT_ALLOWED"
+	CodeConflict         Code = "CONFLICT"
+	CodeTooManyRequests  Code = "TOO_MANY_REQUESTS"
+	CodeInternal         Code = "INTERNAL"
+	CodeNotImplemented   Code = "NOT_IMPLEMENTED"
+	CodeUnavailable      Code = "UNAVAILABLE"
+)
+
+var statuses = map[Code]int{
+	CodeBadRequest:       http.StatusBadRequest,
+	CodeUnauthenticated:  http.StatusUnauthorized,
+	CodeForbidden:        http.StatusForbidden,
+	CodeCSRF:             http.StatusForbidden,
+	CodeNotFound:         http.StatusNotFound,
+	CodeMethodNotAllowed: http.StatusMethodNotAllowed,
+	CodeConflict:         http.StatusConflict,
+	CodeTooManyRequests:  http.StatusTooManyRequests,
+	CodeInternal:         http.StatusInternalServerError,
+	CodeNotImplemented:   http.StatusNotImplemented,
+	CodeUnavailable:      http.StatusServiceUnavailable,
+}
+
+// HTTPStatus - статус ответа для кода, неизвестный код считаем внутренней ошибкой
+func (c Code) HTTPStatus() int {
+	if status, ok := statuses[c]; ok {
+		return status
+	}
+	return http.StatusInternalServerError
+}
+
+// сообщение для внутренних ошибок, подробности только в логе
+const internalMessage = "internal error"
+
+// Error - ошибка, которую можно отдать клиенту
+// Message уходит в ответ как есть, Cause - только в лог
+type Error struct {
+	Code    Code
+	Message string
+	Cause   error
+}
+
+func (e *Error) Error() string {
+	if e.Cause != nil {
+		return e.Message + ": " + e.Cause.Error()
+	}
+	return e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.Cause
+}
+
+func New(code Code, message string) *Error {
+	return &Error{Code: code, Message: message}
+}
+
+// Wrap - клиент увидит message, в лог попадёт и причина
+func Wrap(code Code, message string, cause error) *Error {
+	return &Error{Code: code, Message: message, Cause: cause}
+}
+
+// Internal - ошибка базы, хранилища и т.п., клиенту только "internal error"
+func Internal(cause error) *Error {
+	return &Error{Code: CodeInternal, Message: internalMessage, Cause: cause}
+}
+
+// From достаёт типизированную ошибку из цепочки
+// всё остальное - внутренняя ошибка, текст которой клиенту показывать нельзя
+func From(err error) *Error {
+	var e *Error
+	if errors.As(err, &e) {
+		return e
+	}
+	return Internal(err)
+}
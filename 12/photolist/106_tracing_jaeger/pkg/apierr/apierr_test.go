@@ -0,0 +1,48 @@
+package apierr
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestFrom(t *testing.T) {
+	dbErr := fmt.Errorf("dial tcp 10.0.0.1:3306: connection refused")
+	cases := []struct {
+		name    string
+		err     error
+		code    Code
+		status  int
+		message string
+	}{
+		{"typed", New(CodeNotFound, "no photo"), CodeNotFound, http.StatusNotFound, "no photo"},
+		{"wrapped typed", fmt.Errorf("handler: %w", New(CodeForbidden, "not owner")), CodeForbidden, http.StatusForbidden, "not owner"},
+		{"internal with cause", Internal(dbErr), CodeInternal, http.StatusInternalServerError, "internal error"},
+		{"untyped is internal", dbErr, CodeInternal, http.StatusInternalServerError, "internal error"},
+		{"unknown code", New(Code("WHATEVER"), "x"), Code("WHATEVER"), http.StatusInternalServerError, "x"},
+	}
+	for _, c := range cases {
+		e := From(c.err)
+		if e.Code != c.code {
+			t.Errorf("[%s] bad code: %s, expected %s", c.name, e.Code, c.code)
+		}
+		if status := e.Code.HTTPStatus(); status != c.status {
+			t.Errorf("[%s] bad status: %d, expected %d", c.name, status, c.status)
+		}
+		// текст причины клиенту уходить не должен
+		if e.Message != c.message {
+			t.Errorf("[%s] bad message: %q, expected %q", c.name, e.Message, c.message)
+		}
+	}
+}
+
+func TestCause(t *testing.T) {
+	cause := fmt.Errorf("smtp timeout")
+	e := Wrap(CodeInternal, "cant send email", cause)
+	if e.Unwrap() != cause {
+		t.Errorf("cause lost")
+	}
+	if e.Error() != "cant send email: smtp timeout" {
+		t.Errorf("bad error text: %s", e.Error())
+	}
+}
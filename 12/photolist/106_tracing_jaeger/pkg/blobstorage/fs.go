@@ -1,30 +1,116 @@
 package blobstorage
 
 import (
+	"context"
+	"fmt"
 	"io"
+	"io/ioutil"
+	"mime"
 	"os"
+	"path/filepath"
+	"sort"
+	"strings"
 )
 
+// FSStorage хранит файлы в локальной папке
+// user-id никуда не сохраняется, поэтому в ObjectInfo он всегда 0
 type FSStorage struct {
 	path string
 }
 
 func NewFSStorage(path string) (*FSStorage, error) {
+	err := os.MkdirAll(path, 0755)
+	if err != nil {
+		return nil, err
+	}
 	return &FSStorage{
 		path: path,
 	}, nil
 }
 
-func (st *FSStorage) Put(data io.ReadSeeker, objectName, contentType string, userID uint32) error {
-	newFile, err := os.Create(st.path + objectName)
+// fullPath не даёт выйти за пределы папки хранилища через ../
+func (st *FSStorage) fullPath(objectName string) string {
+	return filepath.Join(st.path, filepath.Clean("/"+objectName))
+}
+
+func (st *FSStorage) Put(_ context.Context, data io.ReadSeeker, objectName, contentType string, userID uint32) error {
+	newFile, err := os.Create(st.fullPath(objectName))
 	if err != nil {
 		return err
 	}
 	_, err = io.Copy(newFile, data)
 	if err != nil {
+		newFile.Close()
 		return err
 	}
 	newFile.Sync()
-	newFile.Close()
+	return newFile.Close()
+}
+
+func (st *FSStorage) Get(ctx context.Context, objectName string) (io.ReadCloser, *ObjectInfo, error) {
+	info, err := st.Stat(ctx, objectName)
+	if err != nil {
+		return nil, nil, err
+	}
+	f, err := os.Open(st.fullPath(objectName))
+	if err != nil {
+		return nil, nil, err
+	}
+	return f, info, nil
+}
+
+func (st *FSStorage) Delete(_ context.Context, objectName string) error {
+	err := os.Remove(st.fullPath(objectName))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	return err
+}
+
+func (st *FSStorage) Stat(_ context.Context, objectName string) (*ObjectInfo, error) {
+	fi, err := os.Stat(st.fullPath(objectName))
+	if os.IsNotExist(err) {
+		return nil, ErrNotFound
+	} else if err != nil {
+		return nil, err
+	}
+	return fileInfo(objectName, fi), nil
+}
+
+func (st *FSStorage) List(_ context.Context, prefix string) ([]*ObjectInfo, error) {
+	files, err := ioutil.ReadDir(st.path)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*ObjectInfo, 0, len(files))
+	for _, fi := range files {
+		if fi.IsDir() || !strings.HasPrefix(fi.Name(), prefix) {
+			continue
+		}
+		result = append(result, fileInfo(fi.Name(), fi))
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+	return result, nil
+}
+
+func fileInfo(name string, fi os.FileInfo) *ObjectInfo {
+	return &ObjectInfo{
+		Name:        name,
+		Size:        fi.Size(),
+		ContentType: mime.TypeByExtension(filepath.Ext(name)),
+		ModTime:     fi.ModTime(),
+	}
+}
+
+func (st *FSStorage) Ping(ctx context.Context) error {
+	fi, err := os.Stat(st.path)
+	if err != nil {
+		return err
+	}
+	if !fi.IsDir() {
+		return fmt.Errorf("%s is not a directory", st.path)
+	}
 	return nil
 }
@@ -0,0 +1,98 @@
+package blobstorage
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"io/ioutil"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// MemoryStorage держит всё в памяти, для тестов и локального запуска без minio
+type MemoryStorage struct {
+	mu      sync.RWMutex
+	objects map[string]*memObject
+}
+
+type memObject struct {
+	info ObjectInfo
+	data []byte
+}
+
+func NewMemoryStorage() *MemoryStorage {
+	return &MemoryStorage{
+		objects: make(map[string]*memObject),
+	}
+}
+
+func (st *MemoryStorage) Put(_ context.Context, data io.ReadSeeker, objectName, contentType string, userID uint32) error {
+	body, err := ioutil.ReadAll(data)
+	if err != nil {
+		return err
+	}
+	st.mu.Lock()
+	st.objects[objectName] = &memObject{
+		info: ObjectInfo{
+			Name:        objectName,
+			Size:        int64(len(body)),
+			ContentType: contentType,
+			UserID:      userID,
+			ModTime:     time.Now(),
+		},
+		data: body,
+	}
+	st.mu.Unlock()
+	return nil
+}
+
+func (st *MemoryStorage) Get(_ context.Context, objectName string) (io.ReadCloser, *ObjectInfo, error) {
+	st.mu.RLock()
+	obj, ok := st.objects[objectName]
+	st.mu.RUnlock()
+	if !ok {
+		return nil, nil, ErrNotFound
+	}
+	info := obj.info
+	return ioutil.NopCloser(bytes.NewReader(obj.data)), &info, nil
+}
+
+func (st *MemoryStorage) Delete(_ context.Context, objectName string) error {
+	st.mu.Lock()
+	delete(st.objects, objectName)
+	st.mu.Unlock()
+	return nil
+}
+
+func (st *MemoryStorage) Stat(_ context.Context, objectName string) (*ObjectInfo, error) {
+	st.mu.RLock()
+	obj, ok := st.objects[objectName]
+	st.mu.RUnlock()
+	if !ok {
+		return nil, ErrNotFound
+	}
+	info := obj.info
+	return &info, nil
+}
+
+func (st *MemoryStorage) List(_ context.Context, prefix string) ([]*ObjectInfo, error) {
+	st.mu.RLock()
+	result := make([]*ObjectInfo, 0, len(st.objects))
+	for name, obj := range st.objects {
+		if strings.HasPrefix(name, prefix) {
+			info := obj.info
+			result = append(result, &info)
+		}
+	}
+	st.mu.RUnlock()
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+	return result, nil
+}
+
+func (st *MemoryStorage) Ping(ctx context.Context) error {
+	return nil
+}
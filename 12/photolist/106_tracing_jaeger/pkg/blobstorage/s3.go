@@ -1,16 +1,23 @@
 package blobstorage
 
 import (
+	"context"
 	"errors"
 	"io"
 	"strconv"
+	"strings"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/request"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/s3"
 	// "github.com/aws/aws-sdk-go/service/s3/s3manager"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"photolist/pkg/tracing"
 )
 
 type S3Storage struct {
@@ -34,6 +41,7 @@ func NewS3Storage(host, access, secret, bucketName string) (*S3Storage, error) {
 
 	storage.session = session.New(s3Config)
 	storage.client = s3.New(storage.session)
+	traceS3(storage.client)
 
 	_, err := storage.client.CreateBucket(&s3.CreateBucketInput{
 		Bucket: storage.bucket,
@@ -47,6 +55,7 @@ func NewS3Storage(host, access, secret, bucketName string) (*S3Storage, error) {
 		}
 	}
 
+	// публичное чтение нужно nginx: /images/ после auth_request проксируется прямо в бакет
 	policy := `{ 
 		"Version":"2012-10-17",
 		"Statement":[
@@ -70,8 +79,35 @@ func NewS3Storage(host, access, secret, bucketName string) (*S3Storage, error) {
 	return storage, nil
 }
 
-func (storage *S3Storage) Put(data io.ReadSeeker, objectName, contentType string, userID uint32) error {
-	_, err := storage.client.PutObject(&s3.PutObjectInput{
+type s3SpanKey struct{}
+
+// traceS3 - каждый вызов s3 с ctx запроса становится дочерним спаном
+// спан открывается до подписи запроса и закрывается в Complete, так что ретраи sdk попадают в один спан
+func traceS3(client *s3.S3) {
+	client.Handlers.Validate.PushFront(func(r *request.Request) {
+		ctx, span := tracing.StartChild(r.Context(), "S3."+r.Operation.Name,
+			trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithAttributes(attribute.String("rpc.system", "aws-api"), attribute.String("rpc.service", "S3")),
+		)
+		if !span.SpanContext().IsValid() {
+			return
+		}
+		r.SetContext(context.WithValue(ctx, s3SpanKey{}, span))
+	})
+	client.Handlers.Complete.PushBack(func(r *request.Request) {
+		span, ok := r.Context().Value(s3SpanKey{}).(trace.Span)
+		if !ok {
+			return
+		}
+		if r.HTTPResponse != nil {
+			span.SetAttributes(attribute.Int("http.status_code", r.HTTPResponse.StatusCode))
+		}
+		tracing.End(span, r.Error)
+	})
+}
+
+func (storage *S3Storage) Put(ctx context.Context, data io.ReadSeeker, objectName, contentType string, userID uint32) error {
+	_, err := storage.client.PutObjectWithContext(ctx, &s3.PutObjectInput{
 		Body:        data,
 		Bucket:      storage.bucket,
 		Key:         aws.String(objectName),
@@ -82,3 +118,102 @@ func (storage *S3Storage) Put(data io.ReadSeeker, objectName, contentType string
 	})
 	return err
 }
+
+func (storage *S3Storage) Get(ctx context.Context, objectName string) (io.ReadCloser, *ObjectInfo, error) {
+	out, err := storage.client.GetObjectWithContext(ctx, &s3.GetObjectInput{
+		Bucket: storage.bucket,
+		Key:    aws.String(objectName),
+	})
+	if err != nil {
+		return nil, nil, s3Err(err)
+	}
+	info := &ObjectInfo{
+		Name:        objectName,
+		Size:        aws.Int64Value(out.ContentLength),
+		ContentType: aws.StringValue(out.ContentType),
+		UserID:      metaUserID(out.Metadata),
+		ModTime:     aws.TimeValue(out.LastModified),
+	}
+	return out.Body, info, nil
+}
+
+func (storage *S3Storage) Delete(ctx context.Context, objectName string) error {
+	_, err := storage.client.DeleteObjectWithContext(ctx, &s3.DeleteObjectInput{
+		Bucket: storage.bucket,
+		Key:    aws.String(objectName),
+	})
+	return s3Err(err)
+}
+
+func (storage *S3Storage) Stat(ctx context.Context, objectName string) (*ObjectInfo, error) {
+	out, err := storage.client.HeadObjectWithContext(ctx, &s3.HeadObjectInput{
+		Bucket: storage.bucket,
+		Key:    aws.String(objectName),
+	})
+	if err != nil {
+		return nil, s3Err(err)
+	}
+	return &ObjectInfo{
+		Name:        objectName,
+		Size:        aws.Int64Value(out.ContentLength),
+		ContentType: aws.StringValue(out.ContentType),
+		UserID:      metaUserID(out.Metadata),
+		ModTime:     aws.TimeValue(out.LastModified),
+	}, nil
+}
+
+// List не возвращает user-id - в листинге s3 нет метаданных
+func (storage *S3Storage) List(ctx context.Context, prefix string) ([]*ObjectInfo, error) {
+	result := make([]*ObjectInfo, 0, 10)
+	err := storage.client.ListObjectsV2PagesWithContext(ctx, &s3.ListObjectsV2Input{
+		Bucket: storage.bucket,
+		Prefix: aws.String(prefix),
+	}, func(page *s3.ListObjectsV2Output, lastPage bool) bool {
+		for _, obj := range page.Contents {
+			result = append(result, &ObjectInfo{
+				Name:    aws.StringValue(obj.Key),
+				Size:    aws.Int64Value(obj.Size),
+				ModTime: aws.TimeValue(obj.LastModified),
+			})
+		}
+		return true
+	})
+	if err != nil {
+		return nil, s3Err(err)
+	}
+	return result, nil
+}
+
+// s3Err приводит ошибки "нет такого ключа" к ErrNotFound
+func s3Err(err error) error {
+	if err == nil {
+		return nil
+	}
+	var awsErr awserr.Error
+	if errors.As(err, &awsErr) {
+		switch awsErr.Code() {
+		case s3.ErrCodeNoSuchKey, "NotFound":
+			return ErrNotFound
+		}
+	}
+	return err
+}
+
+// aws приводит ключи метаданных к виду User-Id
+func metaUserID(meta map[string]*string) uint32 {
+	for k, v := range meta {
+		if strings.EqualFold(k, "user-id") {
+			uid, _ := strconv.Atoi(aws.StringValue(v))
+			return uint32(uid)
+		}
+	}
+	return 0
+}
+
+// Ping - HeadBucket заодно проверяет и доступ по ключам
+func (storage *S3Storage) Ping(ctx context.Context) error {
+	_, err := storage.client.HeadBucketWithContext(ctx, &s3.HeadBucketInput{
+		Bucket: storage.bucket,
+	})
+	return err
+}
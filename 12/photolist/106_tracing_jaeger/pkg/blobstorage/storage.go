@@ -0,0 +1,55 @@
+package blobstorage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"photolist/pkg/config"
+)
+
+var (
+	ErrNotFound = errors.New("object not found")
+)
+
+// ObjectInfo - метаданные сохранённого объекта
+type ObjectInfo struct {
+	Name        string
+	Size        int64
+	ContentType string
+	UserID      uint32
+	ModTime     time.Time
+}
+
+// Storage - общий интерфейс для всех хранилищ картинок
+type Storage interface {
+	Put(ctx context.Context, data io.ReadSeeker, objectName, contentType string, userID uint32) error
+	Get(ctx context.Context, objectName string) (io.ReadCloser, *ObjectInfo, error)
+	Delete(ctx context.Context, objectName string) error
+	Stat(ctx context.Context, objectName string) (*ObjectInfo, error)
+	List(ctx context.Context, prefix string) ([]*ObjectInfo, error)
+	// Ping проверяет, что хранилище доступно, для /readyz
+	Ping(ctx context.Context) error
+}
+
+var (
+	_ Storage = (*S3Storage)(nil)
+	_ Storage = (*FSStorage)(nil)
+	_ Storage = (*MemoryStorage)(nil)
+)
+
+// New выбирает хранилище по storage.type из конфига
+func New(cfg *config.Config) (Storage, error) {
+	switch cfg.Storage.Type {
+	case "s3":
+		return NewS3Storage(cfg.S3.Host, cfg.S3.Access, cfg.S3.Secret, cfg.S3.Bucket)
+	case "fs":
+		return NewFSStorage(cfg.Storage.Path)
+	case "memory":
+		return NewMemoryStorage(), nil
+	default:
+		return nil, fmt.Errorf("unknown storage type %q", cfg.Storage.Type)
+	}
+}
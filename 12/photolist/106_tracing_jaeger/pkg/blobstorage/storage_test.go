@@ -0,0 +1,176 @@
+package blobstorage
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"photolist/pkg/config"
+)
+
+func newTestStorages(t *testing.T) (map[string]Storage, func()) {
+	dir, err := ioutil.TempDir("", "blobstorage")
+	if err != nil {
+		t.Fatalf("tempdir: %v", err)
+	}
+	fs, err := NewFSStorage(filepath.Join(dir, "images"))
+	if err != nil {
+		os.RemoveAll(dir)
+		t.Fatalf("NewFSStorage: %v", err)
+	}
+	return map[string]Storage{
+		"memory": NewMemoryStorage(),
+		"fs":     fs,
+	}, func() {
+		os.RemoveAll(dir)
+	}
+}
+
+func TestStorage(t *testing.T) {
+	storages, cleanup := newTestStorages(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	for name, st := range storages {
+		if err := st.Ping(ctx); err != nil {
+			t.Errorf("[%s] Ping: %v", name, err)
+		}
+
+		if _, err := st.Stat(ctx, "a.jpg"); err != ErrNotFound {
+			t.Errorf("[%s] Stat missing: expected ErrNotFound, got %v", name, err)
+		}
+		if _, _, err := st.Get(ctx, "a.jpg"); err != ErrNotFound {
+			t.Errorf("[%s] Get missing: expected ErrNotFound, got %v", name, err)
+		}
+
+		for _, obj := range []string{"a.jpg", "a_160.jpg", "b.png"} {
+			err := st.Put(ctx, strings.NewReader("data-"+obj), obj, "image/jpeg", 1)
+			if err != nil {
+				t.Fatalf("[%s] Put %s: %v", name, obj, err)
+			}
+		}
+
+		rc, info, err := st.Get(ctx, "a.jpg")
+		if err != nil {
+			t.Fatalf("[%s] Get: %v", name, err)
+		}
+		body, _ := ioutil.ReadAll(rc)
+		rc.Close()
+		if string(body) != "data-a.jpg" {
+			t.Errorf("[%s] Get: bad body %q", name, body)
+		}
+		if info.Name != "a.jpg" || info.Size != int64(len(body)) || info.ContentType != "image/jpeg" {
+			t.Errorf("[%s] Get: bad info %+v", name, info)
+		}
+
+		list, err := st.List(ctx, "a")
+		if err != nil {
+			t.Fatalf("[%s] List: %v", name, err)
+		}
+		if len(list) != 2 || list[0].Name != "a.jpg" || list[1].Name != "a_160.jpg" {
+			t.Errorf("[%s] List: bad result %+v", name, list)
+		}
+
+		if err := st.Delete(ctx, "a.jpg"); err != nil {
+			t.Errorf("[%s] Delete: %v", name, err)
+		}
+		if _, err := st.Stat(ctx, "a.jpg"); err != ErrNotFound {
+			t.Errorf("[%s] Stat deleted: expected ErrNotFound, got %v", name, err)
+		}
+		// повторное удаление не ошибка, как и в s3
+		if err := st.Delete(ctx, "a.jpg"); err != nil {
+			t.Errorf("[%s] Delete twice: %v", name, err)
+		}
+
+		list, err = st.List(ctx, "")
+		if err != nil {
+			t.Fatalf("[%s] List all: %v", name, err)
+		}
+		if len(list) != 2 {
+			t.Errorf("[%s] List all: expected 2 objects, got %d", name, len(list))
+		}
+	}
+}
+
+func TestFSStoragePathTraversal(t *testing.T) {
+	dir, err := ioutil.TempDir("", "blobstorage")
+	if err != nil {
+		t.Fatalf("tempdir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	st, err := NewFSStorage(filepath.Join(dir, "images"))
+	if err != nil {
+		t.Fatalf("NewFSStorage: %v", err)
+	}
+	ctx := context.Background()
+
+	err = st.Put(ctx, strings.NewReader("x"), "../escape.jpg", "image/jpeg", 1)
+	if err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "escape.jpg")); !os.IsNotExist(err) {
+		t.Errorf("file escaped storage dir: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "images", "escape.jpg")); err != nil {
+		t.Errorf("file not in storage dir: %v", err)
+	}
+}
+
+func TestFSStoragePing(t *testing.T) {
+	dir, err := ioutil.TempDir("", "blobstorage")
+	if err != nil {
+		t.Fatalf("tempdir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	st, err := NewFSStorage(dir)
+	if err != nil {
+		t.Fatalf("NewFSStorage: %v", err)
+	}
+	os.RemoveAll(dir)
+	if err := st.Ping(context.Background()); err == nil {
+		t.Errorf("expected error for removed dir")
+	}
+}
+
+func TestNew(t *testing.T) {
+	dir, err := ioutil.TempDir("", "blobstorage")
+	if err != nil {
+		t.Fatalf("tempdir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	cases := []struct {
+		typ     string
+		wantErr bool
+		want    string
+	}{
+		{"memory", false, "*blobstorage.MemoryStorage"},
+		{"fs", false, "*blobstorage.FSStorage"},
+		{"ftp", true, ""},
+	}
+	for _, item := range cases {
+		cfg := &config.Config{}
+		cfg.Storage.Type = item.typ
+		cfg.Storage.Path = dir
+		st, err := New(cfg)
+		if item.wantErr {
+			if err == nil {
+				t.Errorf("[%s] expected error", item.typ)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("[%s] unexpected error: %v", item.typ, err)
+			continue
+		}
+		if got := fmt.Sprintf("%T", st); got != item.want {
+			t.Errorf("[%s] expected %s, got %s", item.typ, item.want, got)
+		}
+	}
+}
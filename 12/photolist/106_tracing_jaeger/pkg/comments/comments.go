@@ -0,0 +1,190 @@
+package comments
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strconv"
+	"time"
+
+	"photolist/pkg/photos"
+	"photolist/pkg/utils/pagination"
+)
+
+const MaxTextLen = 2000
+
+var (
+	errCommentNotFound = errors.New("No comment found")
+	errPhotoNotFound   = errors.New("No photo found")
+	errNotAllowed      = errors.New("Not allowed")
+	errEmptyText       = errors.New("Empty comment")
+	errTextTooLong     = errors.New("Comment too long")
+)
+
+// Comment - комментарий к фото
+// треды двухуровневые: ответ на ответ цепляется к тому же корневому комментарию
+type Comment struct {
+	ID        uint32
+	PhotoID   uint32
+	UserID    uint32
+	ParentID  uint32 // 0 - комментарий верхнего уровня
+	Text      string
+	CreatedAt time.Time
+}
+
+func (c *Comment) Id() string {
+	return strconv.Itoa(int(c.ID))
+}
+
+func (c *Comment) Cursor() string {
+	return (&pagination.Cursor{ID: c.ID}).Encode()
+}
+
+type CommentsRepo struct {
+	db *sql.DB
+}
+
+func NewCommentsRepository(db *sql.DB) *CommentsRepo {
+	return &CommentsRepo{
+		db: db,
+	}
+}
+
+// Add сохраняет комментарий и заполняет ID, ParentID и CreatedAt
+func (repo *CommentsRepo) Add(ctx context.Context, c *Comment) error {
+	// комментировать можно только то, что видно
+	visible, visibleArgs := photos.VisibleCond(c.UserID)
+	var photoOwner uint32
+	err := repo.db.QueryRowContext(ctx, "SELECT user_id FROM photos WHERE id = ? AND "+visible,
+		append([]interface{}{c.PhotoID}, visibleArgs...)...).Scan(&photoOwner)
+	if err == sql.ErrNoRows {
+		return errPhotoNotFound
+	} else if err != nil {
+		return err
+	}
+
+	if c.ParentID != 0 {
+		var parentPhoto, parentParent uint32
+		err = repo.db.QueryRowContext(ctx, "SELECT photo_id, parent_id FROM photo_comments WHERE id = ?", c.ParentID).
+			Scan(&parentPhoto, &parentParent)
+		if err == sql.ErrNoRows || (err == nil && parentPhoto != c.PhotoID) {
+			return errCommentNotFound
+		} else if err != nil {
+			return err
+		}
+		if parentParent != 0 {
+			c.ParentID = parentParent
+		}
+	}
+
+	c.CreatedAt = time.Now().Truncate(time.Second)
+	res, err := repo.db.ExecContext(ctx, "INSERT INTO photo_comments(photo_id, user_id, parent_id, text, created_at) VALUES(?, ?, ?, ?, ?)",
+		c.PhotoID, c.UserID, c.ParentID, c.Text, c.CreatedAt)
+	if err != nil {
+		return err
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return err
+	}
+	c.ID = uint32(id)
+	return nil
+}
+
+// GetByPhoto - страница комментариев верхнего уровня, старые сверху
+func (repo *CommentsRepo) GetByPhoto(ctx context.Context, photoID uint32, page pagination.Page) ([]*Comment, bool, error) {
+	return repo.queryPage(ctx, "photo_id = ? AND parent_id = 0", []interface{}{photoID}, page)
+}
+
+// GetReplies - страница ответов на комментарий
+func (repo *CommentsRepo) GetReplies(ctx context.Context, commentID uint32, page pagination.Page) ([]*Comment, bool, error) {
+	return repo.queryPage(ctx, "parent_id = ?", []interface{}{commentID}, page)
+}
+
+func (repo *CommentsRepo) queryPage(ctx context.Context, where string, args []interface{}, page pagination.Page) ([]*Comment, bool, error) {
+	q := "SELECT id, photo_id, user_id, parent_id, text, UNIX_TIMESTAMP(created_at) FROM photo_comments WHERE " + where
+	if page.After != nil {
+		q += " AND id > ?"
+		args = append(args, page.After.ID)
+	}
+	q += " ORDER BY id LIMIT ?"
+	args = append(args, page.FetchLimit())
+
+	rows, err := repo.db.QueryContext(ctx, q, args...)
+	if err != nil {
+		return nil, false, err
+	}
+	defer rows.Close()
+	result := make([]*Comment, 0, page.FetchLimit())
+	for rows.Next() {
+		c := &Comment{}
+		var created int64
+		err := rows.Scan(&c.ID, &c.PhotoID, &c.UserID, &c.ParentID, &c.Text, &created)
+		if err != nil {
+			return nil, false, err
+		}
+		c.CreatedAt = time.Unix(created, 0)
+		result = append(result, c)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, false, err
+	}
+
+	hasNext := page.HasNext(len(result))
+	if hasNext {
+		result = result[:page.Limit]
+	}
+	return result, hasNext, nil
+}
+
+// Delete удаляет комментарий вместе с ответами
+// удалить может автор комментария или владелец фото
+func (repo *CommentsRepo) Delete(ctx context.Context, commentID, userID uint32) (*Comment, error) {
+	tx, err := repo.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	c := &Comment{ID: commentID}
+	var photoOwner uint32
+	err = tx.QueryRowContext(ctx, `SELECT photo_comments.photo_id, photo_comments.user_id, photo_comments.parent_id, photos.user_id 
+		FROM photo_comments 
+		JOIN photos ON photos.id = photo_comments.photo_id 
+		WHERE photo_comments.id = ? FOR UPDATE`, commentID).
+		Scan(&c.PhotoID, &c.UserID, &c.ParentID, &photoOwner)
+	if err == sql.ErrNoRows {
+		return nil, errCommentNotFound
+	} else if err != nil {
+		return nil, err
+	}
+	if c.UserID != userID && photoOwner != userID {
+		return nil, errNotAllowed
+	}
+
+	_, err = tx.ExecContext(ctx, "DELETE FROM photo_comments WHERE parent_id = ?", commentID)
+	if err != nil {
+		return nil, err
+	}
+	_, err = tx.ExecContext(ctx, "DELETE FROM photo_comments WHERE id = ?", commentID)
+	if err != nil {
+		return nil, err
+	}
+	return c, tx.Commit()
+}
+
+func IsErrCommentNotFound(err error) bool {
+	return err == errCommentNotFound
+}
+
+func IsErrPhotoNotFound(err error) bool {
+	return err == errPhotoNotFound
+}
+
+func IsErrNotAllowed(err error) bool {
+	return err == errNotAllowed
+}
+
+func IsErrBadText(err error) bool {
+	return err == errEmptyText || err == errTextTooLong
+}
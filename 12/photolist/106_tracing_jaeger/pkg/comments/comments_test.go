@@ -0,0 +1,242 @@
+package comments
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"photolist/pkg/utils/pagination"
+
+	sqlmock "gopkg.in/DATA-DOG/go-sqlmock.v1"
+)
+
+const (
+	photoOwner uint32 = 1
+	author     uint32 = 2
+	stranger   uint32 = 3
+)
+
+func TestAdd(t *testing.T) {
+	// условие видимости из photos.VisibleCond, сам запрос проверяется в его тестах
+	selectPhoto := `SELECT user_id FROM photos WHERE id = \? AND \(photos.user_id = \? OR \(photos.hidden = 0`
+	selectParent := `SELECT photo_id, parent_id FROM photo_comments WHERE id = \?`
+	insert := `INSERT INTO photo_comments\(photo_id, user_id, parent_id, text, created_at\)`
+	photoRow := func() *sqlmock.Rows {
+		return sqlmock.NewRows([]string{"user_id"}).AddRow(photoOwner)
+	}
+	cases := []struct {
+		name           string
+		parentID       uint32
+		prepare        func(mock sqlmock.Sqlmock)
+		checkFn        func(error) bool
+		expectedParent uint32
+	}{
+		{"top level", 0, func(mock sqlmock.Sqlmock) {
+			mock.ExpectQuery(selectPhoto).WithArgs(10, author, author).WillReturnRows(photoRow())
+			mock.ExpectExec(insert).WithArgs(10, author, 0, "hi", sqlmock.AnyArg()).
+				WillReturnResult(sqlmock.NewResult(100, 1))
+		}, func(err error) bool { return err == nil }, 0},
+		{"reply", 5, func(mock sqlmock.Sqlmock) {
+			mock.ExpectQuery(selectPhoto).WithArgs(10, author, author).WillReturnRows(photoRow())
+			mock.ExpectQuery(selectParent).WithArgs(5).
+				WillReturnRows(sqlmock.NewRows([]string{"photo_id", "parent_id"}).AddRow(10, 0))
+			mock.ExpectExec(insert).WithArgs(10, author, 5, "hi", sqlmock.AnyArg()).
+				WillReturnResult(sqlmock.NewResult(100, 1))
+		}, func(err error) bool { return err == nil }, 5},
+		// ответ на ответ цепляется к корневому комментарию
+		{"reply to reply", 7, func(mock sqlmock.Sqlmock) {
+			mock.ExpectQuery(selectPhoto).WithArgs(10, author, author).WillReturnRows(photoRow())
+			mock.ExpectQuery(selectParent).WithArgs(7).
+				WillReturnRows(sqlmock.NewRows([]string{"photo_id", "parent_id"}).AddRow(10, 5))
+			mock.ExpectExec(insert).WithArgs(10, author, 5, "hi", sqlmock.AnyArg()).
+				WillReturnResult(sqlmock.NewResult(100, 1))
+		}, func(err error) bool { return err == nil }, 5},
+		{"invisible photo", 0, func(mock sqlmock.Sqlmock) {
+			mock.ExpectQuery(selectPhoto).WithArgs(10, author, author).
+				WillReturnRows(sqlmock.NewRows([]string{"user_id"}))
+		}, IsErrPhotoNotFound, 0},
+		{"no parent", 5, func(mock sqlmock.Sqlmock) {
+			mock.ExpectQuery(selectPhoto).WithArgs(10, author, author).WillReturnRows(photoRow())
+			mock.ExpectQuery(selectParent).WithArgs(5).
+				WillReturnRows(sqlmock.NewRows([]string{"photo_id", "parent_id"}))
+		}, IsErrCommentNotFound, 5},
+		{"parent on another photo", 5, func(mock sqlmock.Sqlmock) {
+			mock.ExpectQuery(selectPhoto).WithArgs(10, author, author).WillReturnRows(photoRow())
+			mock.ExpectQuery(selectParent).WithArgs(5).
+				WillReturnRows(sqlmock.NewRows([]string{"photo_id", "parent_id"}).AddRow(11, 0))
+		}, IsErrCommentNotFound, 5},
+		{"db error", 0, func(mock sqlmock.Sqlmock) {
+			mock.ExpectQuery(selectPhoto).WithArgs(10, author, author).WillReturnRows(photoRow())
+			mock.ExpectExec(insert).WillReturnError(fmt.Errorf("bad connection"))
+		}, func(err error) bool { return err != nil && !IsErrPhotoNotFound(err) }, 0},
+	}
+	for _, c := range cases {
+		db, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("cant create mock: %s", err)
+		}
+		repo := NewCommentsRepository(db)
+		c.prepare(mock)
+
+		cm := &Comment{PhotoID: 10, UserID: author, ParentID: c.parentID, Text: "hi"}
+		err = repo.Add(context.Background(), cm)
+		if !c.checkFn(err) {
+			t.Errorf("[%s] unexpected err: %v", c.name, err)
+		}
+		if err == nil {
+			if cm.ID != 100 || cm.CreatedAt.IsZero() {
+				t.Errorf("[%s] comment not filled: %+v", c.name, cm)
+			}
+			if cm.ParentID != c.expectedParent {
+				t.Errorf("[%s] expected parent %d, got %d", c.name, c.expectedParent, cm.ParentID)
+			}
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("[%s] there were unfulfilled expectations: %s", c.name, err)
+		}
+		db.Close()
+	}
+}
+
+func TestDelete(t *testing.T) {
+	selectComment := `SELECT photo_comments.photo_id, photo_comments.user_id, photo_comments.parent_id, photos.user_id (.+) WHERE photo_comments.id = \? FOR UPDATE`
+	commentRow := func() *sqlmock.Rows {
+		return sqlmock.NewRows([]string{"photo_id", "user_id", "parent_id", "photo_user_id"}).
+			AddRow(10, author, 0, photoOwner)
+	}
+	allowed := func(mock sqlmock.Sqlmock) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(selectComment).WithArgs(5).WillReturnRows(commentRow())
+		// сначала ответы, потом сам комментарий
+		mock.ExpectExec(`DELETE FROM photo_comments WHERE parent_id = \?`).WithArgs(5).
+			WillReturnResult(sqlmock.NewResult(0, 3))
+		mock.ExpectExec(`DELETE FROM photo_comments WHERE id = \?`).WithArgs(5).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+	}
+	cases := []struct {
+		name    string
+		userID  uint32
+		prepare func(mock sqlmock.Sqlmock)
+		checkFn func(error) bool
+	}{
+		{"author", author, allowed, func(err error) bool { return err == nil }},
+		{"photo owner", photoOwner, allowed, func(err error) bool { return err == nil }},
+		{"stranger", stranger, func(mock sqlmock.Sqlmock) {
+			mock.ExpectBegin()
+			mock.ExpectQuery(selectComment).WithArgs(5).WillReturnRows(commentRow())
+			mock.ExpectRollback()
+		}, IsErrNotAllowed},
+		{"not found", author, func(mock sqlmock.Sqlmock) {
+			mock.ExpectBegin()
+			mock.ExpectQuery(selectComment).WithArgs(5).
+				WillReturnRows(sqlmock.NewRows([]string{"photo_id", "user_id", "parent_id", "photo_user_id"}))
+			mock.ExpectRollback()
+		}, IsErrCommentNotFound},
+		{"replies error", author, func(mock sqlmock.Sqlmock) {
+			mock.ExpectBegin()
+			mock.ExpectQuery(selectComment).WithArgs(5).WillReturnRows(commentRow())
+			mock.ExpectExec(`DELETE FROM photo_comments WHERE parent_id = \?`).WithArgs(5).
+				WillReturnError(fmt.Errorf("bad connection"))
+			mock.ExpectRollback()
+		}, func(err error) bool { return err != nil && !IsErrNotAllowed(err) }},
+	}
+	for _, c := range cases {
+		db, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("cant create mock: %s", err)
+		}
+		repo := NewCommentsRepository(db)
+		c.prepare(mock)
+
+		cm, err := repo.Delete(context.Background(), 5, c.userID)
+		if !c.checkFn(err) {
+			t.Errorf("[%s] unexpected err: %v", c.name, err)
+		}
+		if err == nil && (cm.PhotoID != 10 || cm.UserID != author) {
+			t.Errorf("[%s] bad deleted comment: %+v", c.name, cm)
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("[%s] there were unfulfilled expectations: %s", c.name, err)
+		}
+		db.Close()
+	}
+}
+
+func TestQueryPage(t *testing.T) {
+	columns := []string{"id", "photo_id", "user_id", "parent_id", "text", "created"}
+	cases := []struct {
+		name        string
+		page        pagination.Page
+		prepare     func(mock sqlmock.Sqlmock)
+		expectedIDs []uint32
+		hasNext     bool
+	}{
+		{"first page", pagination.Page{Limit: 2}, func(mock sqlmock.Sqlmock) {
+			// просим на одну строку больше, чтобы узнать про следующую страницу
+			mock.ExpectQuery(`FROM photo_comments WHERE photo_id = \? AND parent_id = 0 ORDER BY id LIMIT \?`).
+				WithArgs(10, 3).
+				WillReturnRows(sqlmock.NewRows(columns).
+					AddRow(1, 10, author, 0, "a", 1600000000).
+					AddRow(2, 10, author, 0, "b", 1600000001).
+					AddRow(3, 10, author, 0, "c", 1600000002))
+		}, []uint32{1, 2}, true},
+		{"after cursor", pagination.Page{Limit: 2, After: &pagination.Cursor{ID: 2}}, func(mock sqlmock.Sqlmock) {
+			mock.ExpectQuery(`FROM photo_comments WHERE photo_id = \? AND parent_id = 0 AND id > \? ORDER BY id LIMIT \?`).
+				WithArgs(10, 2, 3).
+				WillReturnRows(sqlmock.NewRows(columns).
+					AddRow(3, 10, author, 0, "c", 1600000002))
+		}, []uint32{3}, false},
+	}
+	for _, c := range cases {
+		db, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("cant create mock: %s", err)
+		}
+		repo := NewCommentsRepository(db)
+		c.prepare(mock)
+
+		result, hasNext, err := repo.GetByPhoto(context.Background(), 10, c.page)
+		if err != nil {
+			t.Errorf("[%s] unexpected err: %v", c.name, err)
+		}
+		if hasNext != c.hasNext {
+			t.Errorf("[%s] expected hasNext %v, got %v", c.name, c.hasNext, hasNext)
+		}
+		ids := make([]uint32, 0, len(result))
+		for _, cm := range result {
+			ids = append(ids, cm.ID)
+		}
+		if fmt.Sprint(ids) != fmt.Sprint(c.expectedIDs) {
+			t.Errorf("[%s] expected %v, got %v", c.name, c.expectedIDs, ids)
+		}
+		if len(result) > 0 && result[0].CreatedAt.Unix() < 1600000000 {
+			t.Errorf("[%s] bad created_at: %v", c.name, result[0].CreatedAt)
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("[%s] there were unfulfilled expectations: %s", c.name, err)
+		}
+		db.Close()
+	}
+}
+
+func TestGetReplies(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("cant create mock: %s", err)
+	}
+	defer db.Close()
+	repo := NewCommentsRepository(db)
+
+	mock.ExpectQuery(`FROM photo_comments WHERE parent_id = \? ORDER BY id LIMIT \?`).
+		WithArgs(5, pagination.DefaultLimit+1).
+		WillReturnError(fmt.Errorf("bad connection"))
+
+	_, _, err = repo.GetReplies(context.Background(), 5, pagination.Page{Limit: pagination.DefaultLimit})
+	if err == nil {
+		t.Errorf("expected error")
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
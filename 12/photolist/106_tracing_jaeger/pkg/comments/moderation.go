@@ -0,0 +1,117 @@
+package comments
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Moderator проверяет текст перед сохранением
+// вернуть надо *RejectedError, если текст не прошёл, любая другая ошибка считается внутренней
+type Moderator interface {
+	Moderate(text string) error
+}
+
+type RejectedError struct {
+	Reason string
+}
+
+func (e *RejectedError) Error() string {
+	return "comment rejected: " + e.Reason
+}
+
+func IsErrRejected(err error) bool {
+	_, ok := err.(*RejectedError)
+	return ok
+}
+
+// CheckText - общие для всех проверки плюс модерация, возвращает нормализованный текст
+func CheckText(m Moderator, text string) (string, error) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return "", errEmptyText
+	}
+	if utf8.RuneCountInString(text) > MaxTextLen {
+		return "", errTextTooLong
+	}
+	if m == nil {
+		return text, nil
+	}
+	return text, m.Moderate(text)
+}
+
+// Chain прогоняет текст через все модераторы по очереди, до первой ошибки
+type Chain []Moderator
+
+func (ch Chain) Moderate(text string) error {
+	for _, m := range ch {
+		if err := m.Moderate(text); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// BannedWords - простейший фильтр по списку слов, без внешних сервисов
+// сравнение по целым словам, без учёта регистра, ё считается за е
+type BannedWords struct {
+	words map[string]struct{}
+}
+
+func NewBannedWords(words []string) *BannedWords {
+	bw := &BannedWords{
+		words: make(map[string]struct{}, len(words)),
+	}
+	for _, w := range words {
+		w = normalizeWord(strings.TrimSpace(w))
+		if w != "" {
+			bw.words[w] = struct{}{}
+		}
+	}
+	return bw
+}
+
+// LoadBannedWords читает слова из файла, по одному на строку, # - комментарий
+func LoadBannedWords(path string) (*BannedWords, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	words := []string{}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		words = append(words, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("cant read %s: %w", path, err)
+	}
+	return NewBannedWords(words), nil
+}
+
+func (bw *BannedWords) Moderate(text string) error {
+	if len(bw.words) == 0 {
+		return nil
+	}
+	words := strings.FieldsFunc(text, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+	for _, w := range words {
+		if _, banned := bw.words[normalizeWord(w)]; banned {
+			return &RejectedError{Reason: "banned word"}
+		}
+	}
+	return nil
+}
+
+func normalizeWord(w string) string {
+	return strings.ReplaceAll(strings.ToLower(w), "ё", "е")
+}
@@ -0,0 +1,133 @@
+package comments
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestBannedWords(t *testing.T) {
+	bw := NewBannedWords([]string{"Ёжик", "  spam ", ""})
+	cases := []struct {
+		text     string
+		rejected bool
+	}{
+		{"обычный текст", false},
+		{"ежик в тумане", true},
+		{"ЁЖИК!", true},
+		{"buy SPAM,now", true},
+		{"spammer", false}, // только целые слова
+		{"ежики", false},
+	}
+	for _, c := range cases {
+		err := bw.Moderate(c.text)
+		if c.rejected != IsErrRejected(err) {
+			t.Errorf("[%s] expected rejected=%v, got %v", c.text, c.rejected, err)
+		}
+		if !c.rejected && err != nil {
+			t.Errorf("[%s] unexpected err: %v", c.text, err)
+		}
+	}
+
+	if err := NewBannedWords(nil).Moderate("spam"); err != nil {
+		t.Errorf("empty list: unexpected err: %v", err)
+	}
+}
+
+func TestLoadBannedWords(t *testing.T) {
+	f, err := ioutil.TempFile("", "banned")
+	if err != nil {
+		t.Fatalf("tempfile: %v", err)
+	}
+	defer os.Remove(f.Name())
+	f.WriteString("# список слов\nspam\n\n  scam  \n#ham\n")
+	f.Close()
+
+	bw, err := LoadBannedWords(f.Name())
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if len(bw.words) != 2 {
+		t.Errorf("expected 2 words, got %v", bw.words)
+	}
+	if !IsErrRejected(bw.Moderate("it is a scam")) {
+		t.Errorf("scam not rejected")
+	}
+	if err := bw.Moderate("ham"); err != nil {
+		t.Errorf("commented word rejected: %v", err)
+	}
+
+	_, err = LoadBannedWords(f.Name() + ".missing")
+	if err == nil {
+		t.Errorf("expected error for missing file")
+	}
+}
+
+type moderatorFunc func(string) error
+
+func (f moderatorFunc) Moderate(text string) error {
+	return f(text)
+}
+
+func TestChain(t *testing.T) {
+	calls := []string{}
+	mod := func(name string, err error) Moderator {
+		return moderatorFunc(func(string) error {
+			calls = append(calls, name)
+			return err
+		})
+	}
+	internal := fmt.Errorf("service unavailable")
+	rejected := &RejectedError{Reason: "x"}
+
+	cases := []struct {
+		name          string
+		chain         Chain
+		expectedErr   error
+		expectedCalls string
+	}{
+		{"all pass", Chain{mod("a", nil), mod("b", nil)}, nil, "a,b"},
+		{"stops on first", Chain{mod("a", rejected), mod("b", nil)}, rejected, "a"},
+		{"internal error", Chain{mod("a", nil), mod("b", internal), mod("c", nil)}, internal, "a,b"},
+		{"empty", Chain{}, nil, ""},
+	}
+	for _, c := range cases {
+		calls = calls[:0]
+		err := c.chain.Moderate("text")
+		if err != c.expectedErr {
+			t.Errorf("[%s] expected %v, got %v", c.name, c.expectedErr, err)
+		}
+		if got := strings.Join(calls, ","); got != c.expectedCalls {
+			t.Errorf("[%s] expected calls %s, got %s", c.name, c.expectedCalls, got)
+		}
+	}
+}
+
+func TestCheckText(t *testing.T) {
+	bw := NewBannedWords([]string{"spam"})
+	cases := []struct {
+		name     string
+		m        Moderator
+		text     string
+		expected string
+		checkFn  func(error) bool
+	}{
+		{"trim", nil, "  hi  ", "hi", func(err error) bool { return err == nil }},
+		{"empty", nil, "   ", "", IsErrBadText},
+		{"too long", nil, strings.Repeat("я", MaxTextLen+1), "", IsErrBadText},
+		{"max len", nil, strings.Repeat("я", MaxTextLen), strings.Repeat("я", MaxTextLen), func(err error) bool { return err == nil }},
+		{"rejected", bw, "spam", "spam", IsErrRejected},
+		{"moderated ok", bw, "hello", "hello", func(err error) bool { return err == nil }},
+	}
+	for _, c := range cases {
+		text, err := CheckText(c.m, c.text)
+		if !c.checkFn(err) {
+			t.Errorf("[%s] unexpected err: %v", c.name, err)
+		}
+		if text != c.expected {
+			t.Errorf("[%s] expected %q, got %q", c.name, c.expected, text)
+		}
+	}
+}
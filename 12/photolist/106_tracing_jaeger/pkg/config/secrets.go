@@ -0,0 +1,52 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	ModeDev        = "dev"
+	ModeProduction = "production"
+)
+
+// CheckMode - опечатка в mode не должна молча выключать проверки для production
+func (cfg *Config) CheckMode() error {
+	switch cfg.Mode {
+	case ModeDev, ModeProduction:
+		return nil
+	}
+	return fmt.Errorf("unknown mode %q, expected %s or %s", cfg.Mode, ModeDev, ModeProduction)
+}
+
+func (cfg *Config) IsProduction() bool {
+	return cfg.Mode == ModeProduction
+}
+
+// CheckSecret - секрет обязателен, а в production не должен совпадать с тем, что лежит в репозитории
+// key - путь в конфиге, например session.secret, по нему ищется значение в Defaults
+func (cfg *Config) CheckSecret(key, secret string) error {
+	if secret == "" {
+		return fmt.Errorf("%s is empty", key)
+	}
+	if cfg.IsProduction() && secret == defaultString(key) {
+		return fmt.Errorf("%s has default value, set your own in production", key)
+	}
+	return nil
+}
+
+func defaultString(key string) string {
+	parts := strings.SplitN(key, ".", 2)
+	if len(parts) != 2 {
+		val, _ := Defaults[key].(string)
+		return val
+	}
+	switch section := Defaults[parts[0]].(type) {
+	case map[string]string:
+		return section[parts[1]]
+	case map[string]interface{}:
+		val, _ := section[parts[1]].(string)
+		return val
+	}
+	return ""
+}
@@ -0,0 +1,109 @@
+package graphql
+
+import (
+	"strings"
+
+	"photolist/pkg/comments"
+	"photolist/pkg/notifications"
+	"photolist/pkg/photos"
+	"photolist/pkg/user"
+)
+
+func photoOrder(order *PhotoOrder) photos.Order {
+	if order != nil && *order == PhotoOrderTop {
+		return photos.OrderTop
+	}
+	return photos.OrderNew
+}
+
+func photoVisibility(v *PhotoVisibility) photos.Visibility {
+	if v == nil {
+		return photos.VisibilityPublic
+	}
+	return photos.Visibility(strings.ToLower(string(*v)))
+}
+
+func newPhotoConnection(items []*photos.Photo, hasNext bool, order photos.Order) *PhotoConnection {
+	conn := &PhotoConnection{
+		Edges:    make([]*PhotoEdge, 0, len(items)),
+		PageInfo: &PageInfo{HasNextPage: hasNext},
+	}
+	for _, ph := range items {
+		conn.Edges = append(conn.Edges, &PhotoEdge{
+			Cursor: ph.Cursor(order),
+			Node:   ph,
+		})
+	}
+	if len(conn.Edges) > 0 {
+		conn.PageInfo.EndCursor = &conn.Edges[len(conn.Edges)-1].Cursor
+	}
+	return conn
+}
+
+func newUserConnection(items []*user.User, hasNext bool) *UserConnection {
+	conn := &UserConnection{
+		Edges:    make([]*UserEdge, 0, len(items)),
+		PageInfo: &PageInfo{HasNextPage: hasNext},
+	}
+	for _, u := range items {
+		conn.Edges = append(conn.Edges, &UserEdge{
+			Cursor: u.Cursor(),
+			Node:   u,
+		})
+	}
+	if len(conn.Edges) > 0 {
+		conn.PageInfo.EndCursor = &conn.Edges[len(conn.Edges)-1].Cursor
+	}
+	return conn
+}
+
+func newCommentConnection(items []*comments.Comment, hasNext bool) *CommentConnection {
+	conn := &CommentConnection{
+		Edges:    make([]*CommentEdge, 0, len(items)),
+		PageInfo: &PageInfo{HasNextPage: hasNext},
+	}
+	for _, c := range items {
+		conn.Edges = append(conn.Edges, &CommentEdge{
+			Cursor: c.Cursor(),
+			Node:   c,
+		})
+	}
+	if len(conn.Edges) > 0 {
+		conn.PageInfo.EndCursor = &conn.Edges[len(conn.Edges)-1].Cursor
+	}
+	return conn
+}
+
+func newNotificationConnection(items []*notifications.Notification, hasNext bool) *NotificationConnection {
+	conn := &NotificationConnection{
+		Edges:    make([]*NotificationEdge, 0, len(items)),
+		PageInfo: &PageInfo{HasNextPage: hasNext},
+	}
+	for _, n := range items {
+		conn.Edges = append(conn.Edges, &NotificationEdge{
+			Cursor: n.Cursor(),
+			Node:   n,
+		})
+	}
+	if len(conn.Edges) > 0 {
+		conn.PageInfo.EndCursor = &conn.Edges[len(conn.Edges)-1].Cursor
+	}
+	return conn
+}
+
+func newPhotoReportConnection(items []*photos.Report, hasNext bool) *PhotoReportConnection {
+	conn := &PhotoReportConnection{
+		Edges:    make([]*PhotoReportEdge, 0, len(items)),
+		PageInfo: &PageInfo{HasNextPage: hasNext},
+	}
+	for _, rep := range items {
+		conn.Edges = append(conn.Edges, &PhotoReportEdge{
+			Cursor: rep.Cursor(),
+			Node:   rep,
+		})
+	}
+	if len(conn.Edges) > 0 {
+		conn.PageInfo.EndCursor = &conn.Edges[len(conn.Edges)-1].Cursor
+	}
+	return conn
+}
@@ -0,0 +1,118 @@
+package graphql
+
+import (
+	"context"
+	"fmt"
+	"html/template"
+	"net/http"
+	"time"
+
+	"photolist/pkg/apierr"
+	"photolist/pkg/logging"
+	"photolist/pkg/session"
+	"photolist/pkg/token"
+	"photolist/pkg/utils/httputils"
+
+	"github.com/99designs/gqlgen/graphql"
+	gqlgenHandler "github.com/99designs/gqlgen/handler"
+	"github.com/vektah/gqlparser/ast"
+	"github.com/vektah/gqlparser/gqlerror"
+	"go.uber.org/zap"
+)
+
+// MutationCSRF пропускает мутации только с csrf-токеном
+// по http его проверил token.CsrfTokenMiddleware из заголовка csrf-token,
+// по websocket заголовков нет - токен приходит в payload connection_init
+func MutationCSRF(tm token.TokenManager) graphql.RequestMiddleware {
+	return func(ctx context.Context, next func(ctx context.Context) []byte) []byte {
+		reqCtx := graphql.GetRequestContext(ctx)
+		op := reqCtx.Doc.Operations.ForName(reqCtx.OperationName)
+		if op == nil || op.Operation != ast.Mutation || token.CSRFValid(ctx) {
+			return next(ctx)
+		}
+		if token.Valid(ctx, tm, gqlgenHandler.GetInitPayload(ctx).GetString("csrf-token")) {
+			return next(ctx)
+		}
+
+		logging.FromContext(ctx).Warn("graphql mutation without csrf token", zap.String("operation_name", reqCtx.OperationName))
+		reqCtx.Error(ctx, &gqlerror.Error{
+			Message:    "bad csrf token",
+			Extensions: map[string]interface{}{"code": apierr.CodeCSRF},
+		})
+		return []byte("null")
+	}
+}
+
+var playgroundPage = template.Must(template.New("playground").Parse(`<!DOCTYPE html>
+<html>
+<head>
+	<meta charset=utf-8/>
+	<meta name="viewport" content="user-scalable=no, initial-scale=1.0, minimum-scale=1.0, maximum-scale=1.0, minimal-ui">
+	<link rel="stylesheet" href="https://cdn.jsdelivr.net/npm/graphql-playground-react@{{ .version }}/build/static/css/index.css"
+		integrity="{{ .cssSRI }}" crossorigin="anonymous"/>
+	<link rel="shortcut icon" href="https://cdn.jsdelivr.net/npm/graphql-playground-react@{{ .version }}/build/favicon.png"
+		integrity="{{ .faviconSRI }}" crossorigin="anonymous"/>
+	<script src="https://cdn.jsdelivr.net/npm/graphql-playground-react@{{ .version }}/build/static/js/middleware.js"
+		integrity="{{ .jsSRI }}" crossorigin="anonymous"></script>
+	<title>{{.title}}</title>
+</head>
+<body>
+<style type="text/css">
+	html { font-family: "Open Sans", sans-serif; overflow: hidden; }
+	body { margin: 0; background: #172a3a; }
+</style>
+<div id="root"/>
+<script type="text/javascript">
+	window.addEventListener('load', function (event) {
+		const root = document.getElementById('root');
+		root.classList.add('playgroundIn');
+		const wsProto = location.protocol == 'https:' ? 'wss:' : 'ws:'
+		const endpoint = location.protocol + '//' + location.host + '{{.endpoint}}'
+		GraphQLPlayground.init(root, {
+			endpoint: endpoint,
+			subscriptionsEndpoint: wsProto + '//' + location.host + '{{.endpoint}}',
+			// токен в шаринг не отдаём
+			shareEnabled: false,
+			settings: {
+				'request.credentials': 'same-origin'
+			},
+			tabs: [{
+				endpoint: endpoint,
+				query: '',
+				headers: {'csrf-token': '{{.token}}'}
+			}]
+		})
+	})
+</script>
+</body>
+</html>
+`))
+
+// Playground - как gqlgenHandler.Playground, но во вкладку сразу подставлен csrf-токен текущей сессии
+func Playground(title, endpoint string, tm token.TokenManager) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		sess, err := session.SessionFromContext(r.Context())
+		if err != nil {
+			httputils.RespError(w, r, apierr.New(apierr.CodeUnauthenticated, "No auth"))
+			return
+		}
+		csrfToken, err := tm.Create(sess, time.Now().Add(24*time.Hour).Unix())
+		if err != nil {
+			httputils.RespError(w, r, apierr.Internal(fmt.Errorf("playground csrf token: %w", err)))
+			return
+		}
+		w.Header().Add("Content-Type", "text/html")
+		err = playgroundPage.Execute(w, map[string]string{
+			"title":      title,
+			"endpoint":   endpoint,
+			"token":      csrfToken,
+			"version":    "1.7.20",
+			"cssSRI":     "sha256-cS9Vc2OBt9eUf4sykRWukeFYaInL29+myBmFDSa7F/U=",
+			"faviconSRI": "sha256-GhTyE+McTU79R4+pRO6ih+4TfsTOrpPwD8ReKFzb3PM=",
+			"jsSRI":      "sha256-4QG1Uza2GgGdlBL3RCBCGtGeZB6bDbsw8OltCMGeJsA=",
+		})
+		if err != nil {
+			logging.FromContext(r.Context()).Error("playground template err", zap.Error(err))
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package graphql
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"photolist/pkg/session"
+	"photolist/pkg/token"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/vektah/gqlparser/ast"
+	"github.com/vektah/gqlparser/parser"
+)
+
+// csrfContext - контекст после token.CsrfTokenMiddleware с заголовком csrf-token
+func csrfContext(tm token.TokenManager, sess *session.Session, csrfToken string) context.Context {
+	var ctx context.Context
+	handler := token.CsrfTokenMiddleware(tm, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx = r.Context()
+	}))
+	r := httptest.NewRequest("POST", "/graphql", nil)
+	r.Header.Set("csrf-token", csrfToken)
+	handler.ServeHTTP(httptest.NewRecorder(), r.WithContext(session.ContextWithSession(r.Context(), sess)))
+	return ctx
+}
+
+func TestMutationCSRF(t *testing.T) {
+	sess := &session.Session{ID: "sid", UserID: 7}
+	tm, _ := token.NewJwtToken("secret")
+	csrfToken, _ := tm.Create(sess, time.Now().Add(time.Hour).Unix())
+	mw := MutationCSRF(tm)
+
+	cases := []struct {
+		name   string
+		query  string
+		token  string
+		passed bool
+	}{
+		{"query without token", `query me { me { id } }`, "", true},
+		{"mutation with token", `mutation rate { ratePhoto(photoID: "1", direction: "up") { id } }`, csrfToken, true},
+		{"mutation without token", `mutation rate { ratePhoto(photoID: "1", direction: "up") { id } }`, "", false},
+		{"mutation with bad token", `mutation rate { ratePhoto(photoID: "1", direction: "up") { id } }`, "garbage", false},
+	}
+	for _, item := range cases {
+		doc, gqlErr := parser.ParseQuery(&ast.Source{Input: item.query})
+		if gqlErr != nil {
+			t.Fatalf("[%s] parse err: %v", item.name, gqlErr)
+		}
+		reqCtx := graphql.NewRequestContext(doc, item.query, nil)
+		ctx := graphql.WithRequestContext(csrfContext(tm, sess, item.token), reqCtx)
+
+		passed := false
+		mw(ctx, func(ctx context.Context) []byte {
+			passed = true
+			return []byte("{}")
+		})
+		if passed != item.passed {
+			t.Errorf("[%s] unexpected result: passed %v, expected %v", item.name, passed, item.passed)
+		}
+		if !item.passed && len(reqCtx.Errors) != 1 {
+			t.Errorf("[%s] expected csrf error, got %v", item.name, reqCtx.Errors)
+		}
+	}
+}
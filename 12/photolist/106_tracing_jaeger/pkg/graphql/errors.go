@@ -0,0 +1,66 @@
+package graphql
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/vektah/gqlparser/gqlerror"
+	"go.uber.org/zap"
+
+	"photolist/pkg/apierr"
+	"photolist/pkg/comments"
+	"photolist/pkg/logging"
+	"photolist/pkg/photos"
+	"photolist/pkg/user"
+	"photolist/pkg/utils/pagination"
+)
+
+// publicError - ошибки предметной области, текст которых можно показать клиенту
+// ресолверы возвращают их как есть, код проставляется тут
+func publicError(err error) *apierr.Error {
+	switch {
+	case photos.IsErrPhotoNotFound(err), comments.IsErrPhotoNotFound(err),
+		comments.IsErrCommentNotFound(err), user.IsErrUserNotFound(err):
+		return apierr.New(apierr.CodeNotFound, err.Error())
+	case photos.IsErrNotOwner(err), comments.IsErrNotAllowed(err), user.IsErrCantBanAdmin(err):
+		return apierr.New(apierr.CodeForbidden, err.Error())
+	case photos.IsErrUnsupportedFormat(err), photos.IsErrImageTooLarge(err), photos.IsErrReasonTooLong(err), user.IsErrBadProfile(err),
+		comments.IsErrBadText(err), comments.IsErrRejected(err), err == pagination.ErrBadCursor:
+		return apierr.New(apierr.CodeBadRequest, err.Error())
+	}
+	return apierr.From(err)
+}
+
+// ErrorPresenter - у каждой ошибки в ответе есть extensions.code
+// внутренние ошибки клиент видит как "internal error", причина - в логе запроса
+func ErrorPresenter(ctx context.Context, err error) *gqlerror.Error {
+	// ошибки самого gqlgen (разбор аргументов и т.п.) уже безопасны, им только код
+	if gqlErr, ok := err.(*gqlerror.Error); ok {
+		if gqlErr.Extensions == nil {
+			gqlErr.Extensions = map[string]interface{}{"code": apierr.CodeBadRequest}
+		}
+		return graphql.DefaultErrorPresenter(ctx, gqlErr)
+	}
+
+	e := publicError(err)
+	path := graphql.GetResolverContext(ctx).Path()
+	if e.Code == apierr.CodeInternal && e.Cause != nil {
+		logging.FromContext(ctx).Error(e.Message,
+			zap.String("path", fmt.Sprint(path)),
+			zap.Error(e.Cause),
+		)
+	}
+	return &gqlerror.Error{
+		Message:    e.Message,
+		Path:       path,
+		Extensions: map[string]interface{}{"code": e.Code},
+	}
+}
+
+// Recover - паника в ресолвере превращается в обычную внутреннюю ошибку
+// причину пишем сразу: вне ресолвера gqlgen отдаёт текст ошибки клиенту без презентера
+func Recover(ctx context.Context, err interface{}) error {
+	logging.FromContext(ctx).Error("graphql panic", zap.Any("panic", err), zap.Stack("stack"))
+	return apierr.New(apierr.CodeInternal, "internal error")
+}
@@ -0,0 +1,38 @@
+package graphql
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/vektah/gqlparser/gqlerror"
+
+	"photolist/pkg/apierr"
+	"photolist/pkg/photos"
+)
+
+func TestErrorPresenter(t *testing.T) {
+	cases := []struct {
+		name    string
+		err     error
+		code    apierr.Code
+		message string
+	}{
+		{"typed", apierr.New(apierr.CodeBadRequest, "bad id"), apierr.CodeBadRequest, "bad id"},
+		{"internal", apierr.Internal(fmt.Errorf("PhotosRepo.Rate: deadlock")), apierr.CodeInternal, "internal error"},
+		{"raw db error", fmt.Errorf("Error 1146: Table 'photolist.photos' doesn't exist"), apierr.CodeInternal, "internal error"},
+		{"domain error", &photos.UnsupportedFormatError{Detected: "text/plain"}, apierr.CodeBadRequest, "unsupported image format: text/plain"},
+		{"gqlgen error", gqlerror.Errorf("input: bad enum"), apierr.CodeBadRequest, "input: bad enum"},
+	}
+	ctx := graphql.WithResolverContext(context.Background(), &graphql.ResolverContext{})
+	for _, c := range cases {
+		gqlErr := ErrorPresenter(ctx, c.err)
+		if gqlErr.Message != c.message {
+			t.Errorf("[%s] bad message: %q, expected %q", c.name, gqlErr.Message, c.message)
+		}
+		if code := gqlErr.Extensions["code"]; code != c.code {
+			t.Errorf("[%s] bad extensions.code: %v, expected %s", c.name, code, c.code)
+		}
+	}
+}
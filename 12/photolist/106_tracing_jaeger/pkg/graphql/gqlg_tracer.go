@@ -1,21 +1,24 @@
 package graphql
 
-// original github.com/99designs/gqlgen-contrib/gqlopentracing
+// original github.com/99designs/gqlgen-contrib/gqlopentracing, переписан на opentelemetry
 
 import (
 	"context"
-	"fmt"
 
 	"github.com/99designs/gqlgen/graphql"
-	"github.com/opentracing/opentracing-go"
-	"github.com/opentracing/opentracing-go/ext"
-	"github.com/opentracing/opentracing-go/log"
+	"github.com/99designs/gqlgen/handler"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+
+	"photolist/pkg/tracing"
 )
 
 var _ graphql.Tracer = (tracerImpl)(0)
 
-// New returns Tracer for OpenTracing.
-// see https://opentracing.io/
+// NewTracer - спан на операцию и дочерние на каждое поле
 func NewTracer() graphql.Tracer {
 	return tracerImpl(0)
 }
@@ -36,37 +39,53 @@ func (tracerImpl) StartOperationValidation(ctx context.Context) context.Context
 func (tracerImpl) EndOperationValidation(ctx context.Context) {
 }
 
-func (tracerImpl) StartOperationExecution(ctx context.Context) context.Context {
-
-	fmt.Printf("StartOperationExecution %#v", ctx)
+// initPayloadCarrier - traceparent, который клиент передал в connection_init websocket-а
+// у websocket нет заголовков на каждую подписку, поэтому так
+func initPayloadCarrier(payload handler.InitPayload) propagation.HeaderCarrier {
+	carrier := propagation.HeaderCarrier{}
+	for _, key := range otel.GetTextMapPropagator().Fields() {
+		if val := payload.GetString(key); val != "" {
+			carrier.Set(key, val)
+		}
+	}
+	return carrier
+}
 
+func (tracerImpl) StartOperationExecution(ctx context.Context) context.Context {
 	requestContext := graphql.GetRequestContext(ctx)
-	opName := requestContext.OperationName
-	if opName == "" {
-		opName = requestContext.RawQuery
+	if payload := handler.GetInitPayload(ctx); payload != nil {
+		ctx = otel.GetTextMapPropagator().Extract(ctx, initPayloadCarrier(payload))
 	}
-	span, ctx := opentracing.StartSpanFromContext(ctx, opName)
-	ext.SpanKind.Set(span, "server")
-	ext.Component.Set(span, "gqlgen")
-	span.SetTag("opName", requestContext.OperationName)
 
+	opType := "unknown"
+	if op := requestContext.Doc.Operations.ForName(requestContext.OperationName); op != nil {
+		opType = string(op.Operation)
+	}
+	// имя операции задаёт клиент, в имя спана только тип
+	ctx, _ = tracing.Tracer().Start(ctx, "GraphQL "+opType,
+		trace.WithSpanKind(trace.SpanKindServer),
+		trace.WithAttributes(
+			attribute.String("graphql.operation.type", opType),
+			attribute.String("graphql.operation.name", requestContext.OperationName),
+			attribute.Int("graphql.complexity", requestContext.OperationComplexity),
+		),
+	)
 	return ctx
 }
 
 func (tracerImpl) StartFieldExecution(ctx context.Context, field graphql.CollectedField) context.Context {
-	span, ctx := opentracing.StartSpanFromContext(ctx, "unnamed")
-	ext.SpanKind.Set(span, "server")
-	ext.Component.Set(span, "gqlgen")
-
+	// имя проставится в StartFieldResolverExecution, когда будет известен объект
+	ctx, _ = tracing.StartChild(ctx, field.Name)
 	return ctx
 }
 
 func (tracerImpl) StartFieldResolverExecution(ctx context.Context, rc *graphql.ResolverContext) context.Context {
-	span := opentracing.SpanFromContext(ctx)
-	span.SetOperationName(rc.Object + "_" + rc.Field.Name)
-	span.SetTag("resolver.object", rc.Object)
-	span.SetTag("resolver.field", rc.Field.Name)
-
+	span := trace.SpanFromContext(ctx)
+	span.SetName(rc.Object + "." + rc.Field.Name)
+	span.SetAttributes(
+		attribute.String("graphql.object", rc.Object),
+		attribute.String("graphql.field", rc.Field.Name),
+	)
 	return ctx
 }
 
@@ -75,29 +94,22 @@ func (tracerImpl) StartFieldChildExecution(ctx context.Context) context.Context
 }
 
 func (tracerImpl) EndFieldExecution(ctx context.Context) {
-	span := opentracing.SpanFromContext(ctx)
-	defer span.Finish()
+	span := trace.SpanFromContext(ctx)
+	defer span.End()
 
 	rc := graphql.GetResolverContext(ctx)
 	reqCtx := graphql.GetRequestContext(ctx)
 
 	errList := reqCtx.GetErrors(rc)
-	if len(errList) != 0 {
-		ext.Error.Set(span, true)
-		span.LogFields(
-			log.String("event", "error"),
-		)
-
-		for idx, err := range errList {
-			span.LogFields(
-				log.String(fmt.Sprintf("error.%d.message", idx), err.Error()),
-				log.String(fmt.Sprintf("error.%d.kind", idx), fmt.Sprintf("%T", err)),
-			)
-		}
+	if len(errList) == 0 {
+		return
+	}
+	for _, err := range errList {
+		span.RecordError(err)
 	}
+	span.SetStatus(codes.Error, errList[0].Message)
 }
 
 func (tracerImpl) EndOperationExecution(ctx context.Context) {
-	span := opentracing.SpanFromContext(ctx)
-	defer span.Finish()
+	trace.SpanFromContext(ctx).End()
 }
@@ -2,15 +2,17 @@ package graphql
 
 import (
 	"context"
-	"log"
+	"fmt"
 	"net/http"
 	"time"
 
-	"photolist/pkg/middleware"
+	"photolist/pkg/logging"
+	"photolist/pkg/metrics"
 	"photolist/pkg/session"
 	"photolist/pkg/user"
 
 	"github.com/99designs/gqlgen/graphql"
+	"go.uber.org/zap"
 )
 
 // go run github.com/vektah/dataloaden UserLoader uint32 *coursera/3p/photolist/100_gqlgen/main.User
@@ -22,7 +24,7 @@ func UserLoaderMiddleware(resolver *Resolver, next http.Handler) http.Handler {
 			Wait:     1 * time.Millisecond,
 			Fetch: func(ids []uint32) ([]*user.User, []error) {
 				sess, _ := session.SessionFromContext(r.Context())
-				return resolver.UsersRepo.LookupByIDs(sess.UserID, ids)
+				return resolver.UsersRepo.LookupByIDs(r.Context(), sess.UserID, ids)
 			},
 		}
 		userLoader := user.NewUserLoader(cfg)
@@ -42,8 +44,13 @@ func ResolverMiddleware(ctx context.Context, next graphql.Resolver) (res interfa
 	reqCtx := graphql.GetResolverContext(ctx)
 	start := time.Now()
 	res, err = next(ctx)
-	requestID := middleware.RequestIDFromContext(ctx)
-	log.Printf("[ResolverMiddleware] %s %s %v", requestID, time.Since(start), reqCtx.Path())
+	elapsed := time.Since(start)
+	metrics.ObserveResolver(reqCtx.Object+"."+reqCtx.Field.Name, elapsed)
+	// по строке на каждое поле - только для отладки
+	logging.FromContext(ctx).Debug("graphql resolver",
+		zap.String("path", fmt.Sprint(reqCtx.Path())),
+		zap.Duration("duration", elapsed),
+	)
 	return
 }
 
@@ -51,7 +58,17 @@ func RequestMiddleware(ctx context.Context, next func(ctx context.Context) []byt
 	reqCtx := graphql.GetRequestContext(ctx)
 	start := time.Now()
 	result := next(ctx)
-	requestID := middleware.RequestIDFromContext(ctx)
-	log.Printf("[RequestMiddleware] %s %s %s %d", requestID, time.Since(start), reqCtx.OperationName, reqCtx.OperationComplexity)
+	elapsed := time.Since(start)
+	opType := "unknown"
+	if op := reqCtx.Doc.Operations.ForName(reqCtx.OperationName); op != nil {
+		opType = string(op.Operation)
+	}
+	metrics.ObserveOperation(opType, reqCtx.OperationComplexity, elapsed)
+	logging.FromContext(ctx).Info("graphql operation",
+		zap.String("operation", opType),
+		zap.String("operation_name", reqCtx.OperationName),
+		zap.Int("complexity", reqCtx.OperationComplexity),
+		zap.Duration("duration", elapsed),
+	)
 	return result
 }
@@ -0,0 +1,284 @@
+// Code generated by github.com/99designs/gqlgen, DO NOT EDIT.
+
+package graphql
+
+import (
+	"fmt"
+	"io"
+	"photolist/pkg/comments"
+	"photolist/pkg/notifications"
+	"photolist/pkg/photos"
+	"photolist/pkg/user"
+	"strconv"
+)
+
+type CommentConnection struct {
+	Edges    []*CommentEdge `json:"edges"`
+	PageInfo *PageInfo      `json:"pageInfo"`
+}
+
+type CommentEdge struct {
+	Cursor string            `json:"cursor"`
+	Node   *comments.Comment `json:"node"`
+}
+
+type NotificationConnection struct {
+	Edges    []*NotificationEdge `json:"edges"`
+	PageInfo *PageInfo           `json:"pageInfo"`
+}
+
+type NotificationEdge struct {
+	Cursor string                      `json:"cursor"`
+	Node   *notifications.Notification `json:"node"`
+}
+
+type PageInfo struct {
+	HasNextPage bool    `json:"hasNextPage"`
+	EndCursor   *string `json:"endCursor"`
+}
+
+type PhotoConnection struct {
+	Edges    []*PhotoEdge `json:"edges"`
+	PageInfo *PageInfo    `json:"pageInfo"`
+}
+
+type PhotoEdge struct {
+	Cursor string        `json:"cursor"`
+	Node   *photos.Photo `json:"node"`
+}
+
+type PhotoReportConnection struct {
+	Edges    []*PhotoReportEdge `json:"edges"`
+	PageInfo *PageInfo          `json:"pageInfo"`
+}
+
+type PhotoReportEdge struct {
+	Cursor string         `json:"cursor"`
+	Node   *photos.Report `json:"node"`
+}
+
+type UserConnection struct {
+	Edges    []*UserEdge `json:"edges"`
+	PageInfo *PageInfo   `json:"pageInfo"`
+}
+
+type UserEdge struct {
+	Cursor string     `json:"cursor"`
+	Node   *user.User `json:"node"`
+}
+
+type NotificationType string
+
+const (
+	NotificationTypeFollow NotificationType = "FOLLOW"
+	NotificationTypeLike   NotificationType = "LIKE"
+)
+
+var AllNotificationType = []NotificationType{
+	NotificationTypeFollow,
+	NotificationTypeLike,
+}
+
+func (e NotificationType) IsValid() bool {
+	switch e {
+	case NotificationTypeFollow, NotificationTypeLike:
+		return true
+	}
+	return false
+}
+
+func (e NotificationType) String() string {
+	return string(e)
+}
+
+func (e *NotificationType) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = NotificationType(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid NotificationType", str)
+	}
+	return nil
+}
+
+func (e NotificationType) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+// порядок выдачи фотографий
+type PhotoOrder string
+
+const (
+	// свежие сверху
+	PhotoOrderNew PhotoOrder = "NEW"
+	// по рейтингу
+	PhotoOrderTop PhotoOrder = "TOP"
+)
+
+var AllPhotoOrder = []PhotoOrder{
+	PhotoOrderNew,
+	PhotoOrderTop,
+}
+
+func (e PhotoOrder) IsValid() bool {
+	switch e {
+	case PhotoOrderNew, PhotoOrderTop:
+		return true
+	}
+	return false
+}
+
+func (e PhotoOrder) String() string {
+	return string(e)
+}
+
+func (e *PhotoOrder) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = PhotoOrder(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid PhotoOrder", str)
+	}
+	return nil
+}
+
+func (e PhotoOrder) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type PhotoStatus string
+
+const (
+	PhotoStatusProcessing PhotoStatus = "PROCESSING"
+	PhotoStatusReady      PhotoStatus = "READY"
+	PhotoStatusFailed     PhotoStatus = "FAILED"
+)
+
+var AllPhotoStatus = []PhotoStatus{
+	PhotoStatusProcessing,
+	PhotoStatusReady,
+	PhotoStatusFailed,
+}
+
+func (e PhotoStatus) IsValid() bool {
+	switch e {
+	case PhotoStatusProcessing, PhotoStatusReady, PhotoStatusFailed:
+		return true
+	}
+	return false
+}
+
+func (e PhotoStatus) String() string {
+	return string(e)
+}
+
+func (e *PhotoStatus) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = PhotoStatus(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid PhotoStatus", str)
+	}
+	return nil
+}
+
+func (e PhotoStatus) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type PhotoVisibility string
+
+const (
+	// всем
+	PhotoVisibilityPublic PhotoVisibility = "PUBLIC"
+	// владельцу и подписчикам
+	PhotoVisibilityFollowers PhotoVisibility = "FOLLOWERS"
+	// только владельцу
+	PhotoVisibilityPrivate PhotoVisibility = "PRIVATE"
+)
+
+var AllPhotoVisibility = []PhotoVisibility{
+	PhotoVisibilityPublic,
+	PhotoVisibilityFollowers,
+	PhotoVisibilityPrivate,
+}
+
+func (e PhotoVisibility) IsValid() bool {
+	switch e {
+	case PhotoVisibilityPublic, PhotoVisibilityFollowers, PhotoVisibilityPrivate:
+		return true
+	}
+	return false
+}
+
+func (e PhotoVisibility) String() string {
+	return string(e)
+}
+
+func (e *PhotoVisibility) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = PhotoVisibility(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid PhotoVisibility", str)
+	}
+	return nil
+}
+
+func (e PhotoVisibility) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+// роль пользователя
+type Role string
+
+const (
+	RoleUser  Role = "USER"
+	RoleAdmin Role = "ADMIN"
+)
+
+var AllRole = []Role{
+	RoleUser,
+	RoleAdmin,
+}
+
+func (e Role) IsValid() bool {
+	switch e {
+	case RoleUser, RoleAdmin:
+		return true
+	}
+	return false
+}
+
+func (e Role) String() string {
+	return string(e)
+}
+
+func (e *Role) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = Role(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid Role", str)
+	}
+	return nil
+}
+
+func (e Role) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
@@ -6,30 +6,54 @@ import (
 	"bytes"
 	"context"
 	"fmt"
-	"log"
 	"strconv"
+	"strings"
 
 	"github.com/99designs/gqlgen/graphql"
-	"github.com/gofrs/uuid"
+	"go.uber.org/zap"
 
+	"photolist/pkg/apierr"
 	"photolist/pkg/blobstorage"
+	"photolist/pkg/comments"
+	"photolist/pkg/logging"
+	"photolist/pkg/notifications"
 	"photolist/pkg/photos"
 	"photolist/pkg/session"
 	"photolist/pkg/user"
+	"photolist/pkg/utils/pagination"
 )
 
 type Resolver struct {
 	UsersRepo   *user.UserRepository
 	PhotosRepo  *photos.PhotosRepo
-	BlobStorage *blobstorage.S3Storage
+	BlobStorage blobstorage.Storage
+	Uploader    *photos.Uploader
+	Avatars     *photos.Avatars
+	Comments    *comments.CommentsRepo
+	Moderator   comments.Moderator
+	Notifier    *notifications.Notifier
+	// нужен, чтобы при бане закрыть сессии пользователя на всех устройствах
+	Sessions session.SessionManager
 }
 
+func (r *Resolver) Comment() CommentResolver {
+	return &commentResolver{r}
+}
 func (r *Resolver) Mutation() MutationResolver {
 	return &mutationResolver{r}
 }
+func (r *Resolver) Notification() NotificationResolver {
+	return &notificationResolver{r}
+}
+func (r *Resolver) Subscription() SubscriptionResolver {
+	return &subscriptionResolver{r}
+}
 func (r *Resolver) Photo() PhotoResolver {
 	return &photoResolver{r}
 }
+func (r *Resolver) PhotoReport() PhotoReportResolver {
+	return &photoReportResolver{r}
+}
 func (r *Resolver) User() UserResolver {
 	return &userResolver{r}
 }
@@ -49,16 +73,25 @@ func (r *mutationResolver) RatePhoto(ctx context.Context, idStr string, directio
 	sess, _ := session.SessionFromContext(ctx)
 	id, err := strconv.Atoi(idStr)
 	if err != nil {
-		return nil, fmt.Errorf("bad id")
+		return nil, apierr.New(apierr.CodeBadRequest, "bad id")
 	}
 
-	err = r.PhotosRepo.Rate(uint32(id), sess.UserID, rate)
+	err = r.PhotosRepo.Rate(ctx, uint32(id), sess.UserID, rate)
+	if photos.IsErrPhotoNotFound(err) {
+		return nil, err
+	}
 	if err != nil {
-		log.Println("PhotosRepo.Rate err:", err)
-		return nil, fmt.Errorf("db err")
+		return nil, apierr.Internal(fmt.Errorf("PhotosRepo.Rate: %w", err))
 	}
 
-	return r.PhotosRepo.GetByID(uint32(id), sess.UserID)
+	ph, err := r.PhotosRepo.GetByID(ctx, uint32(id), sess.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if rate > 0 {
+		r.Notifier.Liked(ctx, ph.UserID, ph.ID, sess.UserID)
+	}
+	return ph, nil
 }
 
 func (r *mutationResolver) FollowUser(ctx context.Context, userIDStr string, direction string) (*user.User, error) {
@@ -66,10 +99,10 @@ func (r *mutationResolver) FollowUser(ctx context.Context, userIDStr string, dir
 
 	userID, err := strconv.Atoi(userIDStr)
 	if err != nil {
-		return nil, fmt.Errorf("bad id")
+		return nil, apierr.New(apierr.CodeBadRequest, "bad id")
 	}
 
-	folUser, err := r.UsersRepo.GetByID(uint32(userID))
+	folUser, err := r.UsersRepo.GetByID(ctx, uint32(userID))
 	if user.IsErrUserNotFound(err) {
 		return nil, err
 	}
@@ -82,54 +115,216 @@ func (r *mutationResolver) FollowUser(ctx context.Context, userIDStr string, dir
 		rate = -1
 	}
 
-	err = r.UsersRepo.Follow(folUser.ID, sess.UserID, rate)
-	if err == nil {
+	err = r.UsersRepo.Follow(ctx, folUser.ID, sess.UserID, rate)
+	if err != nil {
 		return nil, err
 	}
+	if rate > 0 {
+		r.Notifier.Followed(ctx, folUser.ID, sess.UserID)
+	}
 	return folUser, nil
 }
 
-func (r *mutationResolver) UploadPhoto(ctx context.Context, comment string, file graphql.Upload) (*photos.Photo, error) {
+func (r *mutationResolver) UploadPhoto(ctx context.Context, comment string, file graphql.Upload, visibility *PhotoVisibility) (*photos.Photo, error) {
 	sess, _ := session.SessionFromContext(ctx)
 
-	photoUUID, _ := uuid.NewV4()
-
 	uploadedFile := bytes.NewBuffer(make([]byte, 0, file.Size))
 	uploadedFile.ReadFrom(file.File)
-	uploadData := bytes.NewReader(uploadedFile.Bytes())
 
-	err := r.BlobStorage.Put(uploadData,
-		photoUUID.String()+".jpg", "image/jpeg",
-		sess.UserID)
+	ph, err := r.Uploader.Upload(ctx, sess.UserID, uploadedFile.Bytes(), comment, photoVisibility(visibility))
+	if photos.IsErrUnsupportedFormat(err) || photos.IsErrImageTooLarge(err) {
+		return nil, err
+	}
+	if err != nil {
+		return nil, apierr.Internal(fmt.Errorf("Uploader.Upload: %w", err))
+	}
+	return ph, nil
+}
+
+func (r *mutationResolver) DeletePhoto(ctx context.Context, photoIDStr string) (string, error) {
+	sess, _ := session.SessionFromContext(ctx)
+	id, err := strconv.Atoi(photoIDStr)
+	if err != nil {
+		return "", apierr.New(apierr.CodeBadRequest, "bad id")
+	}
+
+	ph, err := r.PhotosRepo.Delete(ctx, uint32(id), sess.UserID)
+	if photos.IsErrPhotoNotFound(err) || photos.IsErrNotOwner(err) {
+		return "", err
+	}
+	if err != nil {
+		return "", apierr.Internal(fmt.Errorf("PhotosRepo.Delete: %w", err))
+	}
+
+	// запись в базе уже удалена, поэтому ошибку хранилища только логируем
+	err = photos.RemoveImages(ctx, r.BlobStorage, ph.URL)
+	if err != nil {
+		logging.FromContext(ctx).Error("RemoveImages err", zap.String("url", ph.URL), zap.Error(err))
+	}
+	return ph.Id(), nil
+}
+
+func (r *mutationResolver) UpdatePhotoComment(ctx context.Context, photoIDStr string, comment string) (*photos.Photo, error) {
+	sess, _ := session.SessionFromContext(ctx)
+	id, err := strconv.Atoi(photoIDStr)
 	if err != nil {
+		return nil, apierr.New(apierr.CodeBadRequest, "bad id")
+	}
+
+	err = r.PhotosRepo.UpdateComment(ctx, uint32(id), sess.UserID, comment)
+	if photos.IsErrPhotoNotFound(err) || photos.IsErrNotOwner(err) {
 		return nil, err
 	}
+	if err != nil {
+		return nil, apierr.Internal(fmt.Errorf("PhotosRepo.UpdateComment: %w", err))
+	}
+	return r.PhotosRepo.GetByID(ctx, uint32(id), sess.UserID)
+}
 
-	err = photos.MakeThumbnails(r.BlobStorage,
-		uploadData, photoUUID.String(),
-		sess.UserID)
+func (r *mutationResolver) UpdateProfile(ctx context.Context, displayName *string, bio *string, avatar *graphql.Upload) (*user.User, error) {
+	sess, _ := session.SessionFromContext(ctx)
+	u, err := r.UsersRepo.GetByID(ctx, sess.UserID)
 	if err != nil {
 		return nil, err
 	}
 
-	ph := &photos.Photo{
+	if displayName != nil || bio != nil {
+		if displayName != nil {
+			u.DisplayName = strings.TrimSpace(*displayName)
+		}
+		if bio != nil {
+			u.Bio = strings.TrimSpace(*bio)
+		}
+		err = r.UsersRepo.UpdateProfile(ctx, u.ID, u.DisplayName, u.Bio)
+		if user.IsErrBadProfile(err) {
+			return nil, err
+		}
+		if err != nil {
+			return nil, apierr.Internal(fmt.Errorf("UsersRepo.UpdateProfile: %w", err))
+		}
+	}
+
+	if avatar != nil {
+		uploadedFile := bytes.NewBuffer(make([]byte, 0, avatar.Size))
+		uploadedFile.ReadFrom(avatar.File)
+		u.AvatarName, err = r.Avatars.Upload(ctx, u.ID, uploadedFile.Bytes())
+		if photos.IsErrUnsupportedFormat(err) || photos.IsErrImageTooLarge(err) {
+			return nil, err
+		}
+		if err != nil {
+			return nil, apierr.Internal(fmt.Errorf("Avatars.Upload: %w", err))
+		}
+	}
+	return u, nil
+}
+
+func (r *mutationResolver) AddComment(ctx context.Context, photoIDStr string, text string, parentIDStr *string) (*comments.Comment, error) {
+	sess, _ := session.SessionFromContext(ctx)
+	photoID, err := strconv.Atoi(photoIDStr)
+	if err != nil {
+		return nil, apierr.New(apierr.CodeBadRequest, "bad id")
+	}
+	c := &comments.Comment{
+		PhotoID: uint32(photoID),
 		UserID:  sess.UserID,
-		Comment: comment,
-		URL:     photoUUID.String(),
 	}
-	ph.ID, err = r.PhotosRepo.Add(ph)
+	if parentIDStr != nil {
+		parentID, err := strconv.Atoi(*parentIDStr)
+		if err != nil {
+			return nil, apierr.New(apierr.CodeBadRequest, "bad parent id")
+		}
+		c.ParentID = uint32(parentID)
+	}
+
+	c.Text, err = comments.CheckText(r.Moderator, text)
+	if comments.IsErrBadText(err) || comments.IsErrRejected(err) {
+		return nil, err
+	}
 	if err != nil {
-		return nil, fmt.Errorf("db err")
+		return nil, apierr.Internal(fmt.Errorf("Moderator: %w", err))
 	}
-	return ph, nil
+
+	err = r.Comments.Add(ctx, c)
+	if comments.IsErrPhotoNotFound(err) || comments.IsErrCommentNotFound(err) {
+		return nil, err
+	}
+	if err != nil {
+		return nil, apierr.Internal(fmt.Errorf("Comments.Add: %w", err))
+	}
+	return c, nil
+}
+
+func (r *mutationResolver) DeleteComment(ctx context.Context, commentIDStr string) (string, error) {
+	sess, _ := session.SessionFromContext(ctx)
+	id, err := strconv.Atoi(commentIDStr)
+	if err != nil {
+		return "", apierr.New(apierr.CodeBadRequest, "bad id")
+	}
+	c, err := r.Comments.Delete(ctx, uint32(id), sess.UserID)
+	if comments.IsErrCommentNotFound(err) || comments.IsErrNotAllowed(err) {
+		return "", err
+	}
+	if err != nil {
+		return "", apierr.Internal(fmt.Errorf("Comments.Delete: %w", err))
+	}
+	return c.Id(), nil
+}
+
+func (r *mutationResolver) MarkNotificationsRead(ctx context.Context, idsStr []string) (int, error) {
+	sess, _ := session.SessionFromContext(ctx)
+	ids := make([]uint32, 0, len(idsStr))
+	for _, idStr := range idsStr {
+		id, err := strconv.Atoi(idStr)
+		if err != nil {
+			return 0, apierr.New(apierr.CodeBadRequest, "bad id")
+		}
+		ids = append(ids, uint32(id))
+	}
+	err := r.Notifier.Repo.MarkRead(ctx, sess.UserID, ids)
+	if err != nil {
+		return 0, apierr.Internal(fmt.Errorf("NotificationsRepo.MarkRead: %w", err))
+	}
+	return r.Notifier.Repo.UnreadCount(ctx, sess.UserID)
+}
+
+func (r *mutationResolver) SetPhotoVisibility(ctx context.Context, photoIDStr string, visibility PhotoVisibility) (*photos.Photo, error) {
+	sess, _ := session.SessionFromContext(ctx)
+	id, err := strconv.Atoi(photoIDStr)
+	if err != nil {
+		return nil, apierr.New(apierr.CodeBadRequest, "bad id")
+	}
+
+	err = r.PhotosRepo.SetVisibility(ctx, uint32(id), sess.UserID, photoVisibility(&visibility))
+	if photos.IsErrPhotoNotFound(err) || photos.IsErrNotOwner(err) {
+		return nil, err
+	}
+	if err != nil {
+		return nil, apierr.Internal(fmt.Errorf("PhotosRepo.SetVisibility: %w", err))
+	}
+	return r.PhotosRepo.GetByID(ctx, uint32(id), sess.UserID)
 }
 
 type userResolver struct{ *Resolver }
 
-func (r *userResolver) Photos(ctx context.Context, obj *user.User, count *int) ([]*photos.Photo, error) {
+func (r *userResolver) UnreadNotifications(ctx context.Context, obj *user.User) (int, error) {
 	sess, _ := session.SessionFromContext(ctx)
-	// TODO handle count param
-	return r.PhotosRepo.GetPhotos(obj.ID, sess.UserID)
+	if obj.ID != sess.UserID {
+		return 0, apierr.New(apierr.CodeForbidden, "only for current user")
+	}
+	return r.Notifier.Repo.UnreadCount(ctx, obj.ID)
+}
+
+func (r *userResolver) Photos(ctx context.Context, obj *user.User, first *int, after *string, order *PhotoOrder) (*PhotoConnection, error) {
+	sess, _ := session.SessionFromContext(ctx)
+	page, err := pagination.NewPage(first, after)
+	if err != nil {
+		return nil, err
+	}
+	items, hasNext, err := r.PhotosRepo.GetPhotos(ctx, obj.ID, sess.UserID, photoOrder(order), page)
+	if err != nil {
+		return nil, err
+	}
+	return newPhotoConnection(items, hasNext, photoOrder(order)), nil
 }
 
 func (r *userResolver) Followed(ctx context.Context, obj *user.User) (bool, error) {
@@ -137,19 +332,31 @@ func (r *userResolver) Followed(ctx context.Context, obj *user.User) (bool, erro
 		return *obj.Followed, nil
 	}
 	sess, _ := session.SessionFromContext(ctx)
-	return r.UsersRepo.IsFollowed(obj.ID, sess.UserID)
+	return r.UsersRepo.IsFollowed(ctx, obj.ID, sess.UserID)
 }
 
-func (r *userResolver) FollowedUsers(ctx context.Context, obj *user.User, count *int) ([]*user.User, error) {
-	// sess, _ := session.SessionFromContext(ctx)
-	// TODO handle count param
-	return r.UsersRepo.GetFollowedUsers(obj.ID)
+func (r *userResolver) FollowedUsers(ctx context.Context, obj *user.User, first *int, after *string) (*UserConnection, error) {
+	page, err := pagination.NewPage(first, after)
+	if err != nil {
+		return nil, err
+	}
+	items, hasNext, err := r.UsersRepo.GetFollowedUsers(ctx, obj.ID, page)
+	if err != nil {
+		return nil, err
+	}
+	return newUserConnection(items, hasNext), nil
 }
 
-func (r *userResolver) RecomendedUsers(ctx context.Context, obj *user.User, count *int) ([]*user.User, error) {
-	// sess, _ := session.SessionFromContext(ctx)
-	// TODO handle count param
-	return r.UsersRepo.GetRecomendedUsers(obj.ID)
+func (r *userResolver) RecomendedUsers(ctx context.Context, obj *user.User, first *int, after *string) (*UserConnection, error) {
+	page, err := pagination.NewPage(first, after)
+	if err != nil {
+		return nil, err
+	}
+	items, hasNext, err := r.UsersRepo.GetRecomendedUsers(ctx, obj.ID, page)
+	if err != nil {
+		return nil, err
+	}
+	return newUserConnection(items, hasNext), nil
 }
 
 type photoResolver struct{ *Resolver }
@@ -158,41 +365,161 @@ func (r *photoResolver) User(ctx context.Context, obj *photos.Photo) (*user.User
 	return ctx.Value("userLoaderKey").(*user.UserLoader).Load(obj.UserID)
 }
 
+func (r *photoResolver) Status(ctx context.Context, obj *photos.Photo) (PhotoStatus, error) {
+	return PhotoStatus(strings.ToUpper(string(obj.Status))), nil
+}
+
+func (r *photoResolver) Visibility(ctx context.Context, obj *photos.Photo) (PhotoVisibility, error) {
+	return PhotoVisibility(strings.ToUpper(string(obj.Visibility))), nil
+}
+
+func (r *photoResolver) Format(ctx context.Context, obj *photos.Photo) (string, error) {
+	return string(obj.Format), nil
+}
+
+func (r *photoResolver) Comments(ctx context.Context, obj *photos.Photo, first *int, after *string) (*CommentConnection, error) {
+	page, err := pagination.NewPage(first, after)
+	if err != nil {
+		return nil, err
+	}
+	items, hasNext, err := r.Resolver.Comments.GetByPhoto(ctx, obj.ID, page)
+	if err != nil {
+		return nil, err
+	}
+	return newCommentConnection(items, hasNext), nil
+}
+
+type commentResolver struct{ *Resolver }
+
+// User - автор грузится через UserLoader, чтобы не делать запрос на каждый комментарий
+func (r *commentResolver) User(ctx context.Context, obj *comments.Comment) (*user.User, error) {
+	return UserLoaderFromContext(ctx).Load(obj.UserID)
+}
+
+func (r *commentResolver) PhotoID(ctx context.Context, obj *comments.Comment) (string, error) {
+	return strconv.Itoa(int(obj.PhotoID)), nil
+}
+
+func (r *commentResolver) ParentID(ctx context.Context, obj *comments.Comment) (*string, error) {
+	if obj.ParentID == 0 {
+		return nil, nil
+	}
+	id := strconv.Itoa(int(obj.ParentID))
+	return &id, nil
+}
+
+func (r *commentResolver) Replies(ctx context.Context, obj *comments.Comment, first *int, after *string) (*CommentConnection, error) {
+	page, err := pagination.NewPage(first, after)
+	if err != nil {
+		return nil, err
+	}
+	items, hasNext, err := r.Resolver.Comments.GetReplies(ctx, obj.ID, page)
+	if err != nil {
+		return nil, err
+	}
+	return newCommentConnection(items, hasNext), nil
+}
+
+type notificationResolver struct{ *Resolver }
+
+func (r *notificationResolver) Type(ctx context.Context, obj *notifications.Notification) (NotificationType, error) {
+	return NotificationType(strings.ToUpper(string(obj.Type))), nil
+}
+
+func (r *notificationResolver) Actor(ctx context.Context, obj *notifications.Notification) (*user.User, error) {
+	return UserLoaderFromContext(ctx).Load(obj.ActorID)
+}
+
+// Photo может быть уже удалено - тогда просто null
+func (r *notificationResolver) Photo(ctx context.Context, obj *notifications.Notification) (*photos.Photo, error) {
+	if obj.PhotoID == 0 {
+		return nil, nil
+	}
+	ph, err := r.PhotosRepo.GetByID(ctx, obj.PhotoID, obj.UserID)
+	if photos.IsErrPhotoNotFound(err) {
+		return nil, nil
+	}
+	return ph, err
+}
+
+type subscriptionResolver struct{ *Resolver }
+
+func (r *subscriptionResolver) NotificationAdded(ctx context.Context) (<-chan *notifications.Notification, error) {
+	sess, err := session.SessionFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	ch, cancel := r.Notifier.Hub.Subscribe(sess.UserID)
+	go func() {
+		<-ctx.Done()
+		cancel()
+	}()
+	return ch, nil
+}
+
 type queryResolver struct{ *Resolver }
 
-func (r *queryResolver) Timeline(ctx context.Context) ([]*photos.Photo, error) {
+func (r *queryResolver) Timeline(ctx context.Context, first *int, after *string) (*PhotoConnection, error) {
 	sess, _ := session.SessionFromContext(ctx)
-	// TODO handle count param
-	return r.PhotosRepo.GetPhotos(sess.UserID, sess.UserID)
+	page, err := pagination.NewPage(first, after)
+	if err != nil {
+		return nil, err
+	}
+	items, hasNext, err := r.PhotosRepo.GetTimeline(ctx, sess.UserID, page)
+	if err != nil {
+		return nil, err
+	}
+	return newPhotoConnection(items, hasNext, photos.OrderNew), nil
 }
 
 func (r *queryResolver) User(ctx context.Context, userIDStr string) (*user.User, error) {
 	userID, err := strconv.Atoi(userIDStr)
 	if err != nil {
-		return nil, fmt.Errorf("bad id")
+		return nil, apierr.New(apierr.CodeBadRequest, "bad id")
 	}
-	return r.UsersRepo.GetByID(uint32(userID))
+	return r.UsersRepo.GetByID(ctx, uint32(userID))
 }
 
 func (r *queryResolver) Me(ctx context.Context) (*user.User, error) {
 	sess, _ := session.SessionFromContext(ctx)
-	return r.UsersRepo.GetByID(sess.UserID)
+	return r.UsersRepo.GetByID(ctx, sess.UserID)
 }
 
 func (r *queryResolver) Photo(ctx context.Context, photoIDStr string) (*photos.Photo, error) {
 	sess, _ := session.SessionFromContext(ctx)
 	id, err := strconv.Atoi(photoIDStr)
 	if err != nil {
-		return nil, fmt.Errorf("bad id")
+		return nil, apierr.New(apierr.CodeBadRequest, "bad id")
 	}
-	return r.PhotosRepo.GetByID(uint32(id), sess.UserID)
+	return r.PhotosRepo.GetByID(ctx, uint32(id), sess.UserID)
 }
 
-func (r *queryResolver) Photos(ctx context.Context, userIDStr string) ([]*photos.Photo, error) {
+func (r *queryResolver) Photos(ctx context.Context, userIDStr string, first *int, after *string, order *PhotoOrder) (*PhotoConnection, error) {
 	sess, _ := session.SessionFromContext(ctx)
 	userID, err := strconv.Atoi(userIDStr)
 	if err != nil {
-		return nil, fmt.Errorf("bad id")
+		return nil, apierr.New(apierr.CodeBadRequest, "bad id")
+	}
+	page, err := pagination.NewPage(first, after)
+	if err != nil {
+		return nil, err
+	}
+	items, hasNext, err := r.PhotosRepo.GetPhotos(ctx, uint32(userID), sess.UserID, photoOrder(order), page)
+	if err != nil {
+		return nil, err
+	}
+	return newPhotoConnection(items, hasNext, photoOrder(order)), nil
+}
+
+func (r *queryResolver) Notifications(ctx context.Context, first *int, after *string) (*NotificationConnection, error) {
+	sess, _ := session.SessionFromContext(ctx)
+	page, err := pagination.NewPage(first, after)
+	if err != nil {
+		return nil, err
+	}
+	items, hasNext, err := r.Notifier.Repo.GetByUser(ctx, sess.UserID, page)
+	if err != nil {
+		return nil, err
 	}
-	return r.PhotosRepo.GetPhotos(uint32(userID), sess.UserID)
+	return newNotificationConnection(items, hasNext), nil
 }
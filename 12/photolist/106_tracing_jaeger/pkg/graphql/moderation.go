@@ -0,0 +1,141 @@
+package graphql
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/99designs/gqlgen/graphql"
+
+	"photolist/pkg/apierr"
+	"photolist/pkg/photos"
+	"photolist/pkg/session"
+	"photolist/pkg/user"
+	"photolist/pkg/utils/pagination"
+)
+
+// HasRole - директива @hasRole, роль каждый раз берём из базы,
+// чтобы снятие прав и бан действовали сразу, а не после перелогина
+func (r *Resolver) HasRole(ctx context.Context, obj interface{}, next graphql.Resolver, role Role) (interface{}, error) {
+	sess, err := session.SessionFromContext(ctx)
+	if err != nil {
+		return nil, apierr.New(apierr.CodeUnauthenticated, "User not authorized")
+	}
+	userRole, banned, err := r.UsersRepo.GetRole(ctx, sess.UserID)
+	if err != nil {
+		return nil, apierr.Internal(fmt.Errorf("UsersRepo.GetRole: %w", err))
+	}
+	if banned || !userRole.Allows(user.Role(strings.ToLower(string(role)))) {
+		return nil, apierr.New(apierr.CodeForbidden, "access denied")
+	}
+	return next(ctx)
+}
+
+func (r *mutationResolver) ReportPhoto(ctx context.Context, photoIDStr string, reason *string) (bool, error) {
+	sess, _ := session.SessionFromContext(ctx)
+	id, err := strconv.Atoi(photoIDStr)
+	if err != nil {
+		return false, apierr.New(apierr.CodeBadRequest, "bad id")
+	}
+	text := ""
+	if reason != nil {
+		text = *reason
+	}
+
+	err = r.PhotosRepo.Report(ctx, uint32(id), sess.UserID, text)
+	if photos.IsErrPhotoNotFound(err) || photos.IsErrReasonTooLong(err) {
+		return false, err
+	}
+	if err != nil {
+		return false, apierr.Internal(fmt.Errorf("PhotosRepo.Report: %w", err))
+	}
+	return true, nil
+}
+
+func (r *mutationResolver) HidePhoto(ctx context.Context, photoIDStr string, hidden *bool) (*photos.Photo, error) {
+	sess, _ := session.SessionFromContext(ctx)
+	id, err := strconv.Atoi(photoIDStr)
+	if err != nil {
+		return nil, apierr.New(apierr.CodeBadRequest, "bad id")
+	}
+
+	err = r.PhotosRepo.SetHidden(ctx, uint32(id), sess.UserID, hidden == nil || *hidden)
+	if photos.IsErrPhotoNotFound(err) {
+		return nil, err
+	}
+	if err != nil {
+		return nil, apierr.Internal(fmt.Errorf("PhotosRepo.SetHidden: %w", err))
+	}
+	return r.PhotosRepo.GetForModeration(ctx, uint32(id))
+}
+
+func (r *mutationResolver) DismissReports(ctx context.Context, photoIDStr string) (string, error) {
+	sess, _ := session.SessionFromContext(ctx)
+	id, err := strconv.Atoi(photoIDStr)
+	if err != nil {
+		return "", apierr.New(apierr.CodeBadRequest, "bad id")
+	}
+
+	err = r.PhotosRepo.DismissReports(ctx, uint32(id), sess.UserID)
+	if err != nil {
+		return "", apierr.Internal(fmt.Errorf("PhotosRepo.DismissReports: %w", err))
+	}
+	return photoIDStr, nil
+}
+
+func (r *mutationResolver) BanUser(ctx context.Context, userIDStr string, banned *bool) (*user.User, error) {
+	userID, err := strconv.Atoi(userIDStr)
+	if err != nil {
+		return nil, apierr.New(apierr.CodeBadRequest, "bad id")
+	}
+
+	ban := banned == nil || *banned
+	err = r.UsersRepo.SetBanned(ctx, uint32(userID), ban)
+	if user.IsErrUserNotFound(err) || user.IsErrCantBanAdmin(err) {
+		return nil, err
+	}
+	if err != nil {
+		return nil, apierr.Internal(fmt.Errorf("UsersRepo.SetBanned: %w", err))
+	}
+	u, err := r.UsersRepo.GetByID(ctx, uint32(userID))
+	if err != nil {
+		return nil, apierr.Internal(fmt.Errorf("UsersRepo.GetByID: %w", err))
+	}
+	// куки в ответе принадлежат модератору, поэтому ResponseWriter не передаём
+	if ban {
+		err = r.Sessions.DestroyAll(ctx, nil, u)
+		if err != nil {
+			return nil, apierr.Internal(fmt.Errorf("Sessions.DestroyAll: %w", err))
+		}
+	}
+	return u, nil
+}
+
+func (r *queryResolver) ModerationQueue(ctx context.Context, first *int, after *string) (*PhotoReportConnection, error) {
+	page, err := pagination.NewPage(first, after)
+	if err != nil {
+		return nil, err
+	}
+	items, hasNext, err := r.PhotosRepo.ModerationQueue(ctx, page)
+	if err != nil {
+		return nil, apierr.Internal(fmt.Errorf("PhotosRepo.ModerationQueue: %w", err))
+	}
+	return newPhotoReportConnection(items, hasNext), nil
+}
+
+func (r *userResolver) Banned(ctx context.Context, obj *user.User) (bool, error) {
+	_, banned, err := r.UsersRepo.GetRole(ctx, obj.ID)
+	return banned, err
+}
+
+type photoReportResolver struct{ *Resolver }
+
+// Photo - в очереди модератор видит фото независимо от видимости и скрытия
+func (r *photoReportResolver) Photo(ctx context.Context, obj *photos.Report) (*photos.Photo, error) {
+	return r.PhotosRepo.GetForModeration(ctx, obj.PhotoID)
+}
+
+func (r *photoReportResolver) Reporter(ctx context.Context, obj *photos.Report) (*user.User, error) {
+	return UserLoaderFromContext(ctx).Load(obj.UserID)
+}
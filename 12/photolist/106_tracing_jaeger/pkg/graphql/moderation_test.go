@@ -0,0 +1,189 @@
+package graphql
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	sqlmock "gopkg.in/DATA-DOG/go-sqlmock.v1"
+
+	"photolist/pkg/apierr"
+	"photolist/pkg/session"
+	"photolist/pkg/user"
+)
+
+func TestHasRole(t *testing.T) {
+	cases := []struct {
+		name   string
+		role   string
+		banned bool
+		need   Role
+		passed bool
+	}{
+		{"admin for admin field", "admin", false, RoleAdmin, true},
+		{"admin for user field", "admin", false, RoleUser, true},
+		{"user for admin field", "user", false, RoleAdmin, false},
+		{"user for user field", "user", false, RoleUser, true},
+		{"banned admin", "admin", true, RoleAdmin, false},
+	}
+	for _, c := range cases {
+		db, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("cant create mock: %s", err)
+		}
+		r := &Resolver{UsersRepo: user.NewUsersRepository(db)}
+		mock.ExpectQuery(`SELECT role, banned FROM users WHERE id = \?`).WithArgs(7).
+			WillReturnRows(sqlmock.NewRows([]string{"role", "banned"}).AddRow(c.role, c.banned))
+
+		ctx := session.ContextWithSession(context.Background(), &session.Session{ID: "sid", UserID: 7})
+		passed := false
+		_, err = r.HasRole(ctx, nil, func(ctx context.Context) (interface{}, error) {
+			passed = true
+			return nil, nil
+		}, c.need)
+		if passed != c.passed {
+			t.Errorf("[%s] unexpected result: passed %v, expected %v", c.name, passed, c.passed)
+		}
+		if !c.passed && err == nil {
+			t.Errorf("[%s] expected error", c.name)
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("[%s] there were unfulfilled expectations: %s", c.name, err)
+		}
+		db.Close()
+	}
+
+	// ошибка базы - не повод пускать
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("cant create mock: %s", err)
+	}
+	defer db.Close()
+	mock.ExpectQuery(`SELECT role, banned FROM users WHERE id = \?`).WithArgs(7).
+		WillReturnError(fmt.Errorf("bad connection"))
+	ctx := session.ContextWithSession(context.Background(), &session.Session{ID: "sid", UserID: 7})
+	_, err = (&Resolver{UsersRepo: user.NewUsersRepository(db)}).HasRole(ctx, nil, func(ctx context.Context) (interface{}, error) {
+		t.Errorf("[db error] resolver must not be called")
+		return nil, nil
+	}, RoleUser)
+	if apierr.From(err).Code != apierr.CodeInternal {
+		t.Errorf("[db error] expected internal error, got %v", err)
+	}
+
+	// без сессии в базу даже не ходим
+	r := &Resolver{}
+	_, err = r.HasRole(context.Background(), nil, func(ctx context.Context) (interface{}, error) {
+		t.Errorf("[no session] resolver must not be called")
+		return nil, nil
+	}, RoleUser)
+	if err == nil {
+		t.Errorf("[no session] expected error")
+	}
+}
+
+// fakeSessions - запоминает, чьи сессии закрывали
+type fakeSessions struct {
+	session.SessionManager
+	destroyed []uint32
+}
+
+func (fs *fakeSessions) DestroyAll(ctx context.Context, w http.ResponseWriter, u session.UserInterface) error {
+	fs.destroyed = append(fs.destroyed, u.GetID())
+	return nil
+}
+
+func TestBanUser(t *testing.T) {
+	cases := []struct {
+		name      string
+		banned    bool
+		destroyed int
+	}{
+		{"ban", true, 1},
+		{"unban", false, 0},
+	}
+	for _, c := range cases {
+		db, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("cant create mock: %s", err)
+		}
+		sm := &fakeSessions{}
+		r := &mutationResolver{&Resolver{UsersRepo: user.NewUsersRepository(db), Sessions: sm}}
+
+		mock.ExpectQuery(`SELECT role, banned FROM users WHERE id = \?`).WithArgs(3).
+			WillReturnRows(sqlmock.NewRows([]string{"role", "banned"}).AddRow("user", !c.banned))
+		mock.ExpectExec(`UPDATE users SET banned = \?, ver = ver \+ 1 WHERE id = \?`).
+			WithArgs(c.banned, 3).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery(`SELECT id, login, email, ver, display_name, bio, avatar FROM users WHERE id = \?`).WithArgs(3).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "login", "email", "ver", "display_name", "bio", "avatar"}).
+				AddRow(3, "user", "user@example.com", 2, "", "", ""))
+
+		u, err := r.BanUser(context.Background(), "3", &c.banned)
+		if err != nil || u.ID != 3 {
+			t.Errorf("[%s] unexpected result: %v %v", c.name, u, err)
+		}
+		if len(sm.destroyed) != c.destroyed || (c.destroyed > 0 && sm.destroyed[0] != 3) {
+			t.Errorf("[%s] bad destroyed sessions: %v", c.name, sm.destroyed)
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("[%s] there were unfulfilled expectations: %s", c.name, err)
+		}
+		db.Close()
+	}
+}
+
+func TestBanUserErrors(t *testing.T) {
+	cases := []struct {
+		name    string
+		id      string
+		prepare func(mock sqlmock.Sqlmock)
+		checkFn func(error) bool
+	}{
+		{"bad id", "x", func(mock sqlmock.Sqlmock) {}, func(err error) bool {
+			return apierr.From(err).Code == apierr.CodeBadRequest
+		}},
+		{"admin", "3", func(mock sqlmock.Sqlmock) {
+			mock.ExpectQuery(`SELECT role, banned FROM users WHERE id = \?`).WithArgs(3).
+				WillReturnRows(sqlmock.NewRows([]string{"role", "banned"}).AddRow("admin", false))
+		}, user.IsErrCantBanAdmin},
+		{"not found", "3", func(mock sqlmock.Sqlmock) {
+			mock.ExpectQuery(`SELECT role, banned FROM users WHERE id = \?`).WithArgs(3).
+				WillReturnRows(sqlmock.NewRows([]string{"role", "banned"}))
+		}, user.IsErrUserNotFound},
+		// бан уже записан, а пользователя прочитать не смогли - текст ошибки базы клиенту не отдаём
+		{"get error", "3", func(mock sqlmock.Sqlmock) {
+			mock.ExpectQuery(`SELECT role, banned FROM users WHERE id = \?`).WithArgs(3).
+				WillReturnRows(sqlmock.NewRows([]string{"role", "banned"}).AddRow("user", false))
+			mock.ExpectExec(`UPDATE users SET banned = \?, ver = ver \+ 1 WHERE id = \?`).
+				WithArgs(true, 3).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+			mock.ExpectQuery(`SELECT id, login, email, ver, display_name, bio, avatar FROM users WHERE id = \?`).WithArgs(3).
+				WillReturnError(fmt.Errorf("bad connection"))
+		}, func(err error) bool {
+			e, ok := err.(*apierr.Error)
+			return ok && e.Code == apierr.CodeInternal
+		}},
+	}
+	for _, c := range cases {
+		db, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("cant create mock: %s", err)
+		}
+		sm := &fakeSessions{}
+		r := &mutationResolver{&Resolver{UsersRepo: user.NewUsersRepository(db), Sessions: sm}}
+		c.prepare(mock)
+
+		_, err = r.BanUser(context.Background(), c.id, nil)
+		if !c.checkFn(err) {
+			t.Errorf("[%s] unexpected err: %v", c.name, err)
+		}
+		if len(sm.destroyed) != 0 {
+			t.Errorf("[%s] sessions destroyed: %v", c.name, sm.destroyed)
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("[%s] there were unfulfilled expectations: %s", c.name, err)
+		}
+		db.Close()
+	}
+}
@@ -0,0 +1,165 @@
+package lifecycle
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// CheckTimeout - сколько ждём одну проверку в readyz
+var CheckTimeout = 2 * time.Second
+
+// Health - проверки зависимостей для /readyz и grpc health
+// /healthz отвечает ok всегда, пока процесс жив и принимает запросы
+type Health struct {
+	mu           sync.RWMutex
+	names        []string
+	checks       map[string]func(ctx context.Context) error
+	shuttingDown bool
+	done         chan struct{}
+	grpc         []*health.Server
+}
+
+func NewHealth() *Health {
+	return &Health{
+		checks: make(map[string]func(ctx context.Context) error),
+		done:   make(chan struct{}),
+	}
+}
+
+// Add добавляет проверку, например db.PingContext
+func (h *Health) Add(name string, check func(ctx context.Context) error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if _, exists := h.checks[name]; !exists {
+		h.names = append(h.names, name)
+	}
+	h.checks[name] = check
+}
+
+// SetShuttingDown - балансировщик должен перестать слать запросы раньше, чем закроется порт
+func (h *Health) SetShuttingDown() {
+	h.mu.Lock()
+	if h.shuttingDown {
+		h.mu.Unlock()
+		return
+	}
+	h.shuttingDown = true
+	close(h.done)
+	servers := h.grpc
+	h.mu.Unlock()
+	for _, hs := range servers {
+		hs.Shutdown()
+	}
+}
+
+type readyResp struct {
+	Status string            `json:"status"`
+	Checks map[string]string `json:"checks"`
+}
+
+// Check прогоняет все проверки параллельно, в ответе ok или текст ошибки по каждой
+func (h *Health) Check(ctx context.Context) (bool, map[string]string) {
+	h.mu.RLock()
+	names := append([]string(nil), h.names...)
+	checks := h.checks
+	shuttingDown := h.shuttingDown
+	h.mu.RUnlock()
+
+	result := make(map[string]string, len(names))
+	if shuttingDown {
+		return false, result
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, CheckTimeout)
+	defer cancel()
+
+	mu := &sync.Mutex{}
+	wg := &sync.WaitGroup{}
+	ok := true
+	for _, name := range names {
+		wg.Add(1)
+		go func(name string, check func(ctx context.Context) error) {
+			defer wg.Done()
+			err := check(ctx)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				ok = false
+				result[name] = err.Error()
+				return
+			}
+			result[name] = "ok"
+		}(name, checks[name])
+	}
+	wg.Wait()
+	return ok, result
+}
+
+func (h *Health) Healthz(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain")
+	w.Write([]byte("ok"))
+}
+
+func (h *Health) Readyz(w http.ResponseWriter, r *http.Request) {
+	ok, checks := h.Check(r.Context())
+	resp := readyResp{Status: "ok", Checks: checks}
+	status := http.StatusOK
+	if !ok {
+		resp.Status = "unavailable"
+		status = http.StatusServiceUnavailable
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(resp)
+}
+
+// Register вешает /healthz и /readyz на mux
+func (h *Health) Register(mux *http.ServeMux) {
+	mux.HandleFunc("/healthz", h.Healthz)
+	mux.HandleFunc("/readyz", h.Readyz)
+}
+
+// RegisterGRPC подключает стандартный grpc.health.v1.Health к серверу
+// статус "" и services раз в interval обновляется по тем же проверкам, что и readyz
+func (h *Health) RegisterGRPC(srv *grpc.Server, interval time.Duration, services ...string) *health.Server {
+	hs := health.NewServer()
+	healthpb.RegisterHealthServer(srv, hs)
+
+	h.mu.Lock()
+	h.grpc = append(h.grpc, hs)
+	h.mu.Unlock()
+
+	update := func() {
+		status := healthpb.HealthCheckResponse_SERVING
+		if ok, checks := h.Check(context.Background()); !ok {
+			status = healthpb.HealthCheckResponse_NOT_SERVING
+			zap.L().Warn("not ready", zap.Any("checks", checks))
+		}
+		// после hs.Shutdown статус уже не меняется, так что гонки с остановкой нет
+		for _, svc := range append([]string{""}, services...) {
+			hs.SetServingStatus(svc, status)
+		}
+	}
+	update()
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				update()
+			case <-h.done:
+				return
+			}
+		}
+	}()
+	return hs
+}
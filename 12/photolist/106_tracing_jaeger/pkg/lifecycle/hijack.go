@@ -0,0 +1,98 @@
+package lifecycle
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// hijacked - соединения, которые обработчик забрал у сервера (websocket)
+// http.Server.Shutdown их не ждёт и не закрывает, поэтому считаем их сами
+type hijacked struct {
+	mu    sync.Mutex
+	conns map[net.Conn]struct{}
+	done  bool
+}
+
+func (hj *hijacked) add(conn net.Conn) bool {
+	hj.mu.Lock()
+	defer hj.mu.Unlock()
+	if hj.done {
+		return false
+	}
+	hj.conns[conn] = struct{}{}
+	return true
+}
+
+func (hj *hijacked) remove(conn net.Conn) {
+	hj.mu.Lock()
+	delete(hj.conns, conn)
+	hj.mu.Unlock()
+}
+
+// closeAll рвёт все соединения, обработчики получат ошибку чтения и завершатся,
+// а с ними закончится и контекст запроса, на котором живут подписки
+func (hj *hijacked) closeAll(ctx context.Context) error {
+	hj.mu.Lock()
+	hj.done = true
+	conns := make([]net.Conn, 0, len(hj.conns))
+	for conn := range hj.conns {
+		conns = append(conns, conn)
+	}
+	hj.mu.Unlock()
+	for _, conn := range conns {
+		conn.Close()
+	}
+	return nil
+}
+
+type trackedConn struct {
+	net.Conn
+	hj *hijacked
+}
+
+func (tc *trackedConn) Close() error {
+	tc.hj.remove(tc)
+	return tc.Conn.Close()
+}
+
+type hijackWriter struct {
+	http.ResponseWriter
+	hj *hijacked
+}
+
+func (hw *hijackWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	h, ok := hw.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, fmt.Errorf("hijack not supported")
+	}
+	conn, rw, err := h.Hijack()
+	if err != nil {
+		return nil, nil, err
+	}
+	tc := &trackedConn{Conn: conn, hj: hw.hj}
+	if !hw.hj.add(tc) {
+		conn.Close()
+		return nil, nil, fmt.Errorf("shutting down")
+	}
+	return tc, rw, nil
+}
+
+// TrackHijacked - для обработчиков с websocket (подписки graphql):
+// при остановке их соединения закрываются вместе с серверами
+func (lc *Lifecycle) TrackHijacked(name string, next http.Handler) http.Handler {
+	hj := &hijacked{
+		conns: make(map[net.Conn]struct{}),
+	}
+	lc.addStopper(name, hj.closeAll)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.EqualFold(r.Header.Get("Upgrade"), "websocket") {
+			w = &hijackWriter{ResponseWriter: w, hj: hj}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,173 @@
+package lifecycle
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+)
+
+// Lifecycle запускает серверы и по SIGINT/SIGTERM гасит их в обратном порядке:
+// сначала readyz начинает отвечать 503, потом серверы дорабатывают текущие запросы,
+// потом закрываются ресурсы (база, трейсер, очереди) - последний добавленный первым
+type Lifecycle struct {
+	Timeout time.Duration // сколько ждём остановки серверов и закрытия ресурсов
+	// сколько после перехода readyz в 503 ещё принимаем запросы как обычно -
+	// балансировщик (или preStop в k8s) не сразу замечает, что под уходит
+	DrainDelay time.Duration
+	Health     *Health
+
+	mu       sync.Mutex
+	stoppers []namedFn
+	closers  []namedFn
+	failed   chan error
+}
+
+type namedFn struct {
+	name string
+	fn   func(ctx context.Context) error
+}
+
+func New(timeout time.Duration) *Lifecycle {
+	return &Lifecycle{
+		Timeout: timeout,
+		Health:  NewHealth(),
+		failed:  make(chan error, 1),
+	}
+}
+
+// AddCloser регистрирует ресурс, который надо закрыть после остановки серверов
+func (lc *Lifecycle) AddCloser(name string, fn func() error) {
+	lc.mu.Lock()
+	lc.closers = append(lc.closers, namedFn{name, func(context.Context) error { return fn() }})
+	lc.mu.Unlock()
+}
+
+// AddWaiter - как AddCloser, но только ждёт done: фоновый обработчик дорабатывает
+// то, что успел взять, пока ресурсы, добавленные раньше него, ещё открыты
+// после общего дедлайна больше не ждём
+func (lc *Lifecycle) AddWaiter(name string, done <-chan struct{}) {
+	lc.mu.Lock()
+	lc.closers = append(lc.closers, namedFn{name, func(ctx context.Context) error {
+		select {
+		case <-done:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}})
+	lc.mu.Unlock()
+}
+
+func (lc *Lifecycle) addStopper(name string, fn func(ctx context.Context) error) {
+	lc.mu.Lock()
+	lc.stoppers = append(lc.stoppers, namedFn{name, fn})
+	lc.mu.Unlock()
+}
+
+// fail будит Wait, если сервер упал сам, а не по сигналу
+func (lc *Lifecycle) fail(name string, err error) {
+	select {
+	case lc.failed <- fmt.Errorf("%s: %v", name, err):
+	default:
+	}
+}
+
+// ServeHTTP запускает сервер в фоне, при остановке он доотдаёт начатые ответы
+func (lc *Lifecycle) ServeHTTP(name string, srv *http.Server) {
+	lc.addStopper(name, srv.Shutdown)
+	go func() {
+		zap.L().Info("listening", zap.String("server", name), zap.String("addr", srv.Addr))
+		err := srv.ListenAndServe()
+		if err != nil && err != http.ErrServerClosed {
+			lc.fail(name, err)
+		}
+	}()
+}
+
+// ServeGRPC - то же для grpc, если GracefulStop не успел за дедлайн - рвём соединения
+func (lc *Lifecycle) ServeGRPC(name string, srv *grpc.Server, lis net.Listener) {
+	lc.addStopper(name, func(ctx context.Context) error {
+		done := make(chan struct{})
+		go func() {
+			srv.GracefulStop()
+			close(done)
+		}()
+		select {
+		case <-done:
+			return nil
+		case <-ctx.Done():
+			srv.Stop()
+			return ctx.Err()
+		}
+	})
+	go func() {
+		zap.L().Info("listening", zap.String("server", name), zap.Stringer("addr", lis.Addr()))
+		err := srv.Serve(lis)
+		if err != nil && err != grpc.ErrServerStopped {
+			lc.fail(name, err)
+		}
+	}()
+}
+
+// Wait блокируется до сигнала или падения сервера, затем всё останавливает
+func (lc *Lifecycle) Wait() {
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(sig)
+
+	select {
+	case s := <-sig:
+		zap.L().Info("shutdown", zap.Stringer("signal", s))
+	case err := <-lc.failed:
+		zap.L().Error("shutdown, server failed", zap.Error(err))
+	}
+	lc.Shutdown()
+}
+
+// Shutdown останавливает серверы и закрывает ресурсы, общий дедлайн - Timeout
+// отсчёт дедлайна начинается после DrainDelay
+func (lc *Lifecycle) Shutdown() {
+	lc.Health.SetShuttingDown()
+	if lc.DrainDelay > 0 {
+		zap.L().Info("draining", zap.Duration("delay", lc.DrainDelay))
+		time.Sleep(lc.DrainDelay)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), lc.Timeout)
+	defer cancel()
+
+	lc.mu.Lock()
+	stoppers, closers := lc.stoppers, lc.closers
+	lc.mu.Unlock()
+
+	// серверы гасим параллельно, они друг от друга не зависят
+	wg := &sync.WaitGroup{}
+	for _, s := range stoppers {
+		wg.Add(1)
+		go func(s namedFn) {
+			defer wg.Done()
+			if err := s.fn(ctx); err != nil {
+				zap.L().Error("stop err", zap.String("server", s.name), zap.Error(err))
+			}
+		}(s)
+	}
+	wg.Wait()
+
+	// ресурсы закрываем и после дедлайна: соединения с базой и буфер трейсов лучше не бросать
+	for i := len(closers) - 1; i >= 0; i-- {
+		c := closers[i]
+		if err := c.fn(ctx); err != nil {
+			zap.L().Error("close err", zap.String("closer", c.name), zap.Error(err))
+		}
+	}
+	zap.L().Info("shutdown done")
+}
@@ -0,0 +1,197 @@
+package lifecycle
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestReadyz(t *testing.T) {
+	ok := func(ctx context.Context) error { return nil }
+	bad := func(ctx context.Context) error { return fmt.Errorf("connection refused") }
+	slow := func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+	CheckTimeout = 50 * time.Millisecond
+
+	cases := []struct {
+		name     string
+		checks   map[string]func(ctx context.Context) error
+		shutdown bool
+		code     int
+		result   map[string]string
+	}{
+		{"all ok", map[string]func(ctx context.Context) error{"mysql": ok, "storage": ok},
+			false, http.StatusOK, map[string]string{"mysql": "ok", "storage": "ok"}},
+		{"storage down", map[string]func(ctx context.Context) error{"mysql": ok, "storage": bad},
+			false, http.StatusServiceUnavailable, map[string]string{"mysql": "ok", "storage": "connection refused"}},
+		{"timeout", map[string]func(ctx context.Context) error{"auth": slow},
+			false, http.StatusServiceUnavailable, map[string]string{"auth": context.DeadlineExceeded.Error()}},
+		{"shutting down", map[string]func(ctx context.Context) error{"mysql": ok},
+			true, http.StatusServiceUnavailable, map[string]string{}},
+	}
+	for _, c := range cases {
+		h := NewHealth()
+		for name, check := range c.checks {
+			h.Add(name, check)
+		}
+		if c.shutdown {
+			h.SetShuttingDown()
+		}
+
+		w := httptest.NewRecorder()
+		h.Readyz(w, httptest.NewRequest("GET", "/readyz", nil))
+		if w.Code != c.code {
+			t.Errorf("[%s] expected code %d, got %d", c.name, c.code, w.Code)
+		}
+		resp := readyResp{}
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Errorf("[%s] bad json: %v", c.name, err)
+			continue
+		}
+		if !reflect.DeepEqual(resp.Checks, c.result) {
+			t.Errorf("[%s] expected checks %v, got %v", c.name, c.result, resp.Checks)
+		}
+
+		// healthz от зависимостей не зависит
+		w = httptest.NewRecorder()
+		h.Healthz(w, httptest.NewRequest("GET", "/healthz", nil))
+		if w.Code != http.StatusOK {
+			t.Errorf("[%s] healthz expected 200, got %d", c.name, w.Code)
+		}
+	}
+}
+
+// серверы останавливаются до закрытия ресурсов, ресурсы - в обратном порядке
+func TestShutdownOrder(t *testing.T) {
+	lc := New(time.Second)
+	order := []string{}
+	closer := func(name string) func() error {
+		return func() error {
+			order = append(order, name)
+			return nil
+		}
+	}
+	lc.AddCloser("db", closer("db"))
+	lc.AddCloser("tracer", closer("tracer"))
+	lc.AddCloser("queue", closer("queue"))
+
+	srv := &http.Server{Addr: "127.0.0.1:0"}
+	lc.addStopper("http", func(ctx context.Context) error {
+		order = append(order, "http")
+		return srv.Shutdown(ctx)
+	})
+
+	lc.Shutdown()
+
+	expected := []string{"http", "queue", "tracer", "db"}
+	if !reflect.DeepEqual(order, expected) {
+		t.Errorf("expected order %v, got %v", expected, order)
+	}
+	if ok, _ := lc.Health.Check(context.Background()); ok {
+		t.Errorf("expected not ready after shutdown")
+	}
+}
+
+// воркер дорабатывает после закрытия очереди, но до закрытия базы
+func TestShutdownWaiter(t *testing.T) {
+	lc := New(time.Second)
+	order := []string{}
+	done := make(chan struct{})
+	lc.AddCloser("db", func() error {
+		order = append(order, "db")
+		return nil
+	})
+	lc.AddWaiter("worker", done)
+	lc.AddCloser("queue", func() error {
+		order = append(order, "queue")
+		go func() {
+			time.Sleep(10 * time.Millisecond)
+			order = append(order, "worker")
+			close(done)
+		}()
+		return nil
+	})
+
+	lc.Shutdown()
+
+	expected := []string{"queue", "worker", "db"}
+	if !reflect.DeepEqual(order, expected) {
+		t.Errorf("expected order %v, got %v", expected, order)
+	}
+
+	// зависший воркер не держит остановку дольше дедлайна
+	lc = New(20 * time.Millisecond)
+	lc.AddWaiter("stuck", make(chan struct{}))
+	start := time.Now()
+	lc.Shutdown()
+	if time.Since(start) > time.Second {
+		t.Errorf("waiter ignored shutdown deadline")
+	}
+}
+
+// забранное websocket-ом соединение закрывается при остановке, хотя Shutdown сервера его не ждёт
+func TestTrackHijacked(t *testing.T) {
+	lc := New(time.Second)
+	hijacked := make(chan struct{})
+	h := lc.TrackHijacked("ws", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, _, err := w.(http.Hijacker).Hijack()
+		if err != nil {
+			t.Errorf("hijack err: %v", err)
+			return
+		}
+		close(hijacked)
+		ioutil.ReadAll(conn)
+	}))
+	srv := httptest.NewServer(h)
+	defer srv.Close()
+	lc.addStopper("http", srv.Config.Shutdown)
+
+	conn, err := net.Dial("tcp", srv.Listener.Addr().String())
+	if err != nil {
+		t.Fatalf("dial err: %v", err)
+	}
+	defer conn.Close()
+	fmt.Fprintf(conn, "GET / HTTP/1.1\r\nHost: test\r\nConnection: Upgrade\r\nUpgrade: websocket\r\n\r\n")
+	<-hijacked
+
+	lc.Shutdown()
+
+	conn.SetReadDeadline(time.Now().Add(time.Second))
+	if _, err := conn.Read(make([]byte, 1)); err == nil || isTimeout(err) {
+		t.Errorf("expected closed connection, got %v", err)
+	}
+}
+
+func isTimeout(err error) bool {
+	netErr, ok := err.(net.Error)
+	return ok && netErr.Timeout()
+}
+
+// пока идёт DrainDelay, readyz уже не ok, а серверы ещё не остановлены
+func TestShutdownDrainDelay(t *testing.T) {
+	lc := New(time.Second)
+	lc.DrainDelay = 50 * time.Millisecond
+	var stoppedAfter time.Duration
+	start := time.Now()
+	lc.addStopper("http", func(ctx context.Context) error {
+		stoppedAfter = time.Since(start)
+		if ok, _ := lc.Health.Check(ctx); ok {
+			t.Errorf("expected not ready while stopping")
+		}
+		return nil
+	})
+
+	lc.Shutdown()
+	if stoppedAfter < lc.DrainDelay {
+		t.Errorf("server stopped after %v, before drain delay", stoppedAfter)
+	}
+}
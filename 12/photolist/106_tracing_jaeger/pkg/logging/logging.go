@@ -0,0 +1,107 @@
+package logging
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+
+	"photolist/pkg/config"
+)
+
+// имена полей одни и те же в http, graphql и grpc, по ним ищем в агрегаторе логов
+const (
+	FieldRequestID = "request_id"
+	FieldUserID    = "user_id"
+	FieldTraceID   = "trace_id"
+	FieldRoute     = "route"
+)
+
+func RequestID(id string) zap.Field {
+	return zap.String(FieldRequestID, id)
+}
+
+func UserID(id uint32) zap.Field {
+	return zap.Uint32(FieldUserID, id)
+}
+
+func Route(route string) zap.Field {
+	return zap.String(FieldRoute, route)
+}
+
+// New собирает логгер по конфигу и делает его глобальным
+// стандартный log тоже уходит в него, уровнем info, чтобы в выводе не было строк не в json
+func New(service string, cfg config.LogConfig) (*zap.Logger, error) {
+	var level zapcore.Level
+	if err := level.UnmarshalText([]byte(cfg.Level)); err != nil {
+		return nil, fmt.Errorf("bad log.level %q", cfg.Level)
+	}
+
+	var zcfg zap.Config
+	switch cfg.Format {
+	case "json", "":
+		zcfg = zap.NewProductionConfig()
+		zcfg.EncoderConfig.TimeKey = "time"
+		zcfg.EncoderConfig.EncodeTime = zapcore.ISO8601TimeEncoder
+	case "console":
+		zcfg = zap.NewDevelopmentConfig()
+	default:
+		return nil, fmt.Errorf("unknown log.format %q", cfg.Format)
+	}
+	zcfg.Level = zap.NewAtomicLevelAt(level)
+	// под нагрузкой одинаковые ошибки не должны теряться молча
+	zcfg.Sampling = nil
+
+	logger, err := zcfg.Build(zap.Fields(zap.String("service", service)))
+	if err != nil {
+		return nil, err
+	}
+	zap.ReplaceGlobals(logger)
+	zap.RedirectStdLog(logger)
+	log.SetFlags(0)
+	return logger, nil
+}
+
+// requestLogger живёт в ctx запроса
+// поля добавляются по ходу middleware, и внешние (access-лог) видят то, что добавили внутренние
+type requestLogger struct {
+	mu     sync.RWMutex
+	logger *zap.Logger
+}
+
+type ctxKey struct{}
+
+// NewContext начинает логгер запроса
+func NewContext(ctx context.Context, logger *zap.Logger, fields ...zap.Field) context.Context {
+	return context.WithValue(ctx, ctxKey{}, &requestLogger{logger: logger.With(fields...)})
+}
+
+// AddFields дописывает поля в логгер запроса, например user_id после проверки сессии
+func AddFields(ctx context.Context, fields ...zap.Field) {
+	rl, ok := ctx.Value(ctxKey{}).(*requestLogger)
+	if !ok {
+		return
+	}
+	rl.mu.Lock()
+	rl.logger = rl.logger.With(fields...)
+	rl.mu.Unlock()
+}
+
+// FromContext - логгер запроса с trace_id текущего спана
+// вне запроса (воркеры, старт) - глобальный
+func FromContext(ctx context.Context) *zap.Logger {
+	logger := zap.L()
+	if rl, ok := ctx.Value(ctxKey{}).(*requestLogger); ok {
+		rl.mu.RLock()
+		logger = rl.logger
+		rl.mu.RUnlock()
+	}
+	if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+		logger = logger.With(zap.String(FieldTraceID, sc.TraceID().String()))
+	}
+	return logger
+}
@@ -0,0 +1,89 @@
+package logging
+
+import (
+	"context"
+	"testing"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+
+	"photolist/pkg/config"
+)
+
+func TestRequestFields(t *testing.T) {
+	core, logs := observer.New(zapcore.DebugLevel)
+	logger := zap.New(core)
+
+	ctx := NewContext(context.Background(), logger, RequestID("req1"), Route("/api/v1/graphql"))
+	ctx, span := sdktrace.NewTracerProvider().Tracer("test").Start(ctx, "request")
+	defer span.End()
+
+	// так делает AuthMiddleware, а access-лог пишется уже после него
+	AddFields(ctx, UserID(42))
+	FromContext(ctx).Info("access")
+
+	entries := logs.All()
+	if len(entries) != 1 {
+		t.Fatalf("expected 1 entry, got %d", len(entries))
+	}
+	fields := entries[0].ContextMap()
+	expected := map[string]interface{}{
+		FieldRequestID: "req1",
+		FieldRoute:     "/api/v1/graphql",
+		FieldUserID:    uint32(42),
+		FieldTraceID:   span.SpanContext().TraceID().String(),
+	}
+	for name, val := range expected {
+		if fields[name] != val {
+			t.Errorf("[%s] expected %v, got %v", name, val, fields[name])
+		}
+	}
+}
+
+func TestWithoutRequest(t *testing.T) {
+	core, logs := observer.New(zapcore.DebugLevel)
+	defer zap.ReplaceGlobals(zap.New(core))()
+
+	// AddFields вне запроса ничего не ломает
+	AddFields(context.Background(), UserID(1))
+	FromContext(context.Background()).Info("worker")
+
+	entries := logs.All()
+	if len(entries) != 1 {
+		t.Fatalf("expected 1 entry in global logger, got %d", len(entries))
+	}
+	if len(entries[0].Context) != 0 {
+		t.Errorf("unexpected fields: %v", entries[0].ContextMap())
+	}
+}
+
+func TestNew(t *testing.T) {
+	cases := []struct {
+		name string
+		cfg  config.LogConfig
+		ok   bool
+	}{
+		{"json", config.LogConfig{Level: "info", Format: "json"}, true},
+		{"console", config.LogConfig{Level: "debug", Format: "console"}, true},
+		{"bad level", config.LogConfig{Level: "verbose", Format: "json"}, false},
+		{"bad format", config.LogConfig{Level: "info", Format: "xml"}, false},
+	}
+	for _, c := range cases {
+		// New подменяет глобальный логгер и стандартный log - возвращаем их, чтобы не задеть другие тесты
+		restoreGlobals := zap.ReplaceGlobals(zap.L())
+		restoreStdLog := zap.RedirectStdLog(zap.L())
+
+		_, err := New("test", c.cfg)
+		if c.ok && err != nil {
+			t.Errorf("[%s] unexpected err: %s", c.name, err)
+		}
+		if !c.ok && err == nil {
+			t.Errorf("[%s] expected error", c.name)
+		}
+
+		restoreStdLog()
+		restoreGlobals()
+	}
+}
@@ -0,0 +1,131 @@
+package mailer
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"mime"
+	"net/smtp"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"photolist/pkg/config"
+	"photolist/pkg/logging"
+	"photolist/pkg/utils/randutils"
+
+	"go.uber.org/zap"
+)
+
+// Message - простое текстовое письмо
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+type Mailer interface {
+	Send(ctx context.Context, msg *Message) error
+}
+
+var (
+	_ Mailer = (*SMTPMailer)(nil)
+	_ Mailer = (*FileMailer)(nil)
+	_ Mailer = (*LogMailer)(nil)
+)
+
+// New выбирает реализацию по mail.type
+func New(cfg config.MailConfig) (Mailer, error) {
+	switch cfg.Type {
+	case "smtp":
+		return &SMTPMailer{
+			Addr:     cfg.Addr,
+			From:     cfg.From,
+			Username: cfg.Username,
+			Password: cfg.Password,
+		}, nil
+	case "file":
+		err := os.MkdirAll(cfg.Dir, 0755)
+		if err != nil {
+			return nil, err
+		}
+		return &FileMailer{Dir: cfg.Dir, From: cfg.From}, nil
+	case "log", "":
+		return &LogMailer{From: cfg.From}, nil
+	}
+	return nil, fmt.Errorf("unknown mail type %q", cfg.Type)
+}
+
+// buildMessage собирает письмо с заголовками, тема кодируется для кириллицы
+func buildMessage(from string, msg *Message) []byte {
+	buf := &bytes.Buffer{}
+	fmt.Fprintf(buf, "From: %s\r\n", from)
+	fmt.Fprintf(buf, "To: %s\r\n", msg.To)
+	fmt.Fprintf(buf, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", msg.Subject))
+	fmt.Fprintf(buf, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	fmt.Fprintf(buf, "MIME-Version: 1.0\r\n")
+	fmt.Fprintf(buf, "Content-Type: text/plain; charset=utf-8\r\n")
+	fmt.Fprintf(buf, "Content-Transfer-Encoding: 8bit\r\n")
+	buf.WriteString("\r\n")
+	buf.WriteString(strings.Replace(msg.Body, "\n", "\r\n", -1))
+	return buf.Bytes()
+}
+
+// адрес получателя попадает в заголовок и в RCPT TO, переводы строк там недопустимы
+func checkAddr(addr string) error {
+	if addr == "" || strings.ContainsAny(addr, "\r\n") {
+		return fmt.Errorf("bad email address %q", addr)
+	}
+	return nil
+}
+
+type SMTPMailer struct {
+	Addr     string // host:port
+	From     string
+	Username string
+	Password string
+}
+
+func (m *SMTPMailer) Send(ctx context.Context, msg *Message) error {
+	if err := checkAddr(msg.To); err != nil {
+		return err
+	}
+	var auth smtp.Auth
+	if m.Username != "" {
+		host := m.Addr
+		if idx := strings.LastIndex(host, ":"); idx != -1 {
+			host = host[:idx]
+		}
+		auth = smtp.PlainAuth("", m.Username, m.Password, host)
+	}
+	return smtp.SendMail(m.Addr, auth, m.From, []string{msg.To}, buildMessage(m.From, msg))
+}
+
+// FileMailer складывает письма в .eml файлы - для разработки, чтобы ходить по ссылкам из писем
+type FileMailer struct {
+	Dir  string
+	From string
+}
+
+func (m *FileMailer) Send(ctx context.Context, msg *Message) error {
+	if err := checkAddr(msg.To); err != nil {
+		return err
+	}
+	name := fmt.Sprintf("%d_%s.eml", time.Now().UnixNano(), randutils.RandStringRunes(6))
+	return ioutil.WriteFile(filepath.Join(m.Dir, name), buildMessage(m.From, msg), 0644)
+}
+
+// LogMailer просто пишет письмо в лог
+type LogMailer struct {
+	From string
+}
+
+func (m *LogMailer) Send(ctx context.Context, msg *Message) error {
+	if err := checkAddr(msg.To); err != nil {
+		return err
+	}
+	logging.FromContext(ctx).Info("mail", zap.String("to", msg.To), zap.String("subject", msg.Subject), zap.String("body", msg.Body))
+	return nil
+}
@@ -0,0 +1,96 @@
+package mailer
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	"photolist/pkg/config"
+	"photolist/pkg/mailer/mailtest"
+)
+
+func TestSMTPMailer(t *testing.T) {
+	srv, err := mailtest.NewServer()
+	if err != nil {
+		t.Fatalf("cant start smtp: %v", err)
+	}
+	defer srv.Close()
+
+	m, err := New(config.MailConfig{Type: "smtp", Addr: srv.Addr, From: "noreply@photolist.test"})
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	err = m.Send(context.Background(), &Message{
+		To:      "user@example.com",
+		Subject: "Сброс пароля",
+		Body:    "first line\n.dot line\n",
+	})
+	if err != nil {
+		t.Fatalf("send err: %v", err)
+	}
+
+	msgs := srv.Messages()
+	if len(msgs) != 1 {
+		t.Fatalf("expected 1 message, got %d", len(msgs))
+	}
+	msg := msgs[0]
+	if msg.From != "noreply@photolist.test" || len(msg.To) != 1 || msg.To[0] != "user@example.com" {
+		t.Errorf("bad envelope: %+v", msg)
+	}
+	for _, expected := range []string{
+		"To: user@example.com\r\n",
+		"Subject: =?utf-8?q?",
+		"Content-Type: text/plain; charset=utf-8\r\n",
+		"\r\n\r\nfirst line\r\n.dot line\r\n",
+	} {
+		if !strings.Contains(msg.Data, expected) {
+			t.Errorf("message has no %q:\n%s", expected, msg.Data)
+		}
+	}
+}
+
+func TestFileMailer(t *testing.T) {
+	dir, err := ioutil.TempDir("", "mail")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	m, err := New(config.MailConfig{Type: "file", Dir: dir, From: "noreply@photolist.test"})
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	err = m.Send(context.Background(), &Message{To: "user@example.com", Subject: "hi", Body: "body"})
+	if err != nil {
+		t.Fatalf("send err: %v", err)
+	}
+	files, _ := ioutil.ReadDir(dir)
+	if len(files) != 1 || !strings.HasSuffix(files[0].Name(), ".eml") {
+		t.Fatalf("expected one .eml file, got %v", files)
+	}
+}
+
+func TestBadAddress(t *testing.T) {
+	cases := []string{"", "user@example.com\r\nBcc: evil@example.com"}
+	mailers := map[string]Mailer{
+		"smtp": &SMTPMailer{Addr: "127.0.0.1:1"},
+		"file": &FileMailer{Dir: "/nonexistent"},
+		"log":  &LogMailer{},
+	}
+	for name, m := range mailers {
+		for _, to := range cases {
+			err := m.Send(context.Background(), &Message{To: to})
+			if err == nil {
+				t.Errorf("[%s] expected error for %q", name, to)
+			}
+		}
+	}
+}
+
+func TestNewUnknownType(t *testing.T) {
+	_, err := New(config.MailConfig{Type: "pigeon"})
+	if err == nil {
+		t.Errorf("expected error")
+	}
+}
@@ -0,0 +1,138 @@
+// Package mailtest - минимальный smtp-сервер для тестов
+// принимает всё подряд без авторизации и складывает письма в память
+package mailtest
+
+import (
+	"bufio"
+	"net"
+	"strings"
+	"sync"
+)
+
+type Mail struct {
+	From string
+	To   []string
+	Data string // заголовки и тело как пришли, с \r\n
+}
+
+type Server struct {
+	Addr string
+
+	ln   net.Listener
+	mu   sync.Mutex
+	mail []*Mail
+	wg   sync.WaitGroup
+}
+
+// NewServer слушает случайный порт на localhost
+func NewServer() (*Server, error) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, err
+	}
+	s := &Server{
+		Addr: ln.Addr().String(),
+		ln:   ln,
+	}
+	s.wg.Add(1)
+	go s.serve()
+	return s, nil
+}
+
+func (s *Server) Close() {
+	s.ln.Close()
+	s.wg.Wait()
+}
+
+// Messages - всё, что успели принять
+func (s *Server) Messages() []*Mail {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	res := make([]*Mail, len(s.mail))
+	copy(res, s.mail)
+	return res
+}
+
+func (s *Server) serve() {
+	defer s.wg.Done()
+	for {
+		conn, err := s.ln.Accept()
+		if err != nil {
+			return
+		}
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			s.handle(conn)
+		}()
+	}
+}
+
+func (s *Server) handle(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	w := bufio.NewWriter(conn)
+	reply := func(line string) {
+		w.WriteString(line + "\r\n")
+		w.Flush()
+	}
+
+	reply("220 mailtest ready")
+	cur := &Mail{}
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		line = strings.TrimRight(line, "\r\n")
+		cmd := strings.ToUpper(line)
+		switch {
+		case strings.HasPrefix(cmd, "EHLO"), strings.HasPrefix(cmd, "HELO"):
+			reply("250 mailtest")
+		case strings.HasPrefix(cmd, "MAIL FROM:"):
+			cur = &Mail{From: trimAddr(line[len("MAIL FROM:"):])}
+			reply("250 OK")
+		case strings.HasPrefix(cmd, "RCPT TO:"):
+			cur.To = append(cur.To, trimAddr(line[len("RCPT TO:"):]))
+			reply("250 OK")
+		case cmd == "DATA":
+			reply("354 end with <CRLF>.<CRLF>")
+			data := &strings.Builder{}
+			for {
+				dl, err := r.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if dl == ".\r\n" {
+					break
+				}
+				// точка в начале строки экранируется удвоением
+				data.WriteString(strings.TrimPrefix(dl, "."))
+			}
+			cur.Data = data.String()
+			s.mu.Lock()
+			s.mail = append(s.mail, cur)
+			s.mu.Unlock()
+			cur = &Mail{}
+			reply("250 OK")
+		case cmd == "RSET":
+			cur = &Mail{}
+			reply("250 OK")
+		case cmd == "NOOP":
+			reply("250 OK")
+		case cmd == "QUIT":
+			reply("221 bye")
+			return
+		default:
+			reply("502 not implemented")
+		}
+	}
+}
+
+func trimAddr(in string) string {
+	in = strings.TrimSpace(in)
+	if idx := strings.Index(in, " "); idx != -1 {
+		in = in[:idx]
+	}
+	return strings.Trim(in, "<>")
+}
@@ -0,0 +1,159 @@
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "photolist"
+
+var (
+	httpDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "http_request_duration_seconds",
+			Help:      "HTTP request latency by route and status",
+			Buckets:   prometheus.DefBuckets,
+		},
+		[]string{"route", "method", "status"},
+	)
+	httpRequests = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "http_requests_total",
+			Help:      "HTTP requests by route and status",
+		},
+		[]string{"route", "method", "status"},
+	)
+	resolverDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "graphql_resolver_duration_seconds",
+			Help:      "GraphQL resolver duration by field",
+			Buckets:   []float64{.0005, .001, .005, .01, .025, .05, .1, .25, .5, 1},
+		},
+		[]string{"field"},
+	)
+	operationDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "graphql_operation_duration_seconds",
+			Help:      "GraphQL operation duration by type",
+			Buckets:   prometheus.DefBuckets,
+		},
+		[]string{"operation"},
+	)
+	operationComplexity = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "graphql_operation_complexity",
+			Help:      "GraphQL operation complexity by type",
+			Buckets:   []float64{1, 5, 10, 25, 50, 100, 200, 300, 400, 500},
+		},
+		[]string{"operation"},
+	)
+	blobPutDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "blob_put_duration_seconds",
+			Help:      "Blob storage Put latency",
+			Buckets:   prometheus.DefBuckets,
+		},
+		[]string{"storage", "status"},
+	)
+)
+
+func init() {
+	prometheus.MustRegister(httpDuration)
+	prometheus.MustRegister(httpRequests)
+	prometheus.MustRegister(resolverDuration)
+	prometheus.MustRegister(operationDuration)
+	prometheus.MustRegister(operationComplexity)
+	prometheus.MustRegister(blobPutDuration)
+}
+
+// Handler - то, что вешается на /metrics
+func Handler() http.Handler {
+	return promhttp.Handler()
+}
+
+// MuxRoute достаёт шаблон маршрута, под который попал запрос
+// r.URL.Path приходит от юзера, по нему метки не делаем - иначе рядов будет столько же, сколько урлов
+// muxes перебираются, пока шаблон "/", те запрос ушёл во вложенный mux
+func MuxRoute(muxes ...*http.ServeMux) func(r *http.Request) string {
+	return func(r *http.Request) string {
+		route := ""
+		for _, mux := range muxes {
+			_, route = mux.Handler(r)
+			if route != "/" {
+				break
+			}
+		}
+		if route == "" {
+			return "unmatched"
+		}
+		return route
+	}
+}
+
+// HTTPMiddleware меряет время и статус ответа
+// websocket пропускаем: соединение живёт минутами и только портило бы гистограмму
+func HTTPMiddleware(route func(r *http.Request) string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.EqualFold(r.Header.Get("Upgrade"), "websocket") {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		start := time.Now()
+		sw := &statusWriter{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(sw, r)
+
+		labels := []string{route(r), r.Method, strconv.Itoa(sw.status)}
+		httpDuration.WithLabelValues(labels...).Observe(time.Since(start).Seconds())
+		httpRequests.WithLabelValues(labels...).Inc()
+	})
+}
+
+type statusWriter struct {
+	http.ResponseWriter
+	status int
+}
+
+func (w *statusWriter) WriteHeader(status int) {
+	w.status = status
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *statusWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+func (w *statusWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	h, ok := w.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, fmt.Errorf("hijack not supported")
+	}
+	return h.Hijack()
+}
+
+// ObserveResolver - field вида Photo.user, без индексов из пути, чтобы число рядов было ограничено схемой
+func ObserveResolver(field string, d time.Duration) {
+	resolverDuration.WithLabelValues(field).Observe(d.Seconds())
+}
+
+// ObserveOperation - operation это query, mutation или subscription, имя операции задаёт клиент
+func ObserveOperation(operation string, complexity int, d time.Duration) {
+	operationDuration.WithLabelValues(operation).Observe(d.Seconds())
+	operationComplexity.WithLabelValues(operation).Observe(float64(complexity))
+}
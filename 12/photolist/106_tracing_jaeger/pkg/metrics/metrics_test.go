@@ -0,0 +1,88 @@
+package metrics
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	dto "github.com/prometheus/client_model/go"
+
+	"photolist/pkg/blobstorage"
+)
+
+// метка route - шаблон маршрута, а не путь из запроса
+func TestHTTPMiddleware(t *testing.T) {
+	inner := http.NewServeMux()
+	inner.HandleFunc("/photos/", func(w http.ResponseWriter, r *http.Request) {})
+	inner.HandleFunc("/api/v1/photos/delete", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "forbidden", http.StatusForbidden)
+	})
+	outer := http.NewServeMux()
+	outer.Handle("/", inner)
+	outer.HandleFunc("/favicon.ico", func(w http.ResponseWriter, r *http.Request) {})
+
+	handler := HTTPMiddleware(MuxRoute(outer, inner), outer)
+
+	cases := []struct {
+		method, path  string
+		route, status string
+		expected      float64
+	}{
+		{"GET", "/photos/1", "/photos/", "200", 1},
+		{"GET", "/photos/2", "/photos/", "200", 2},
+		{"POST", "/api/v1/photos/delete", "/api/v1/photos/delete", "403", 1},
+		{"GET", "/favicon.ico", "/favicon.ico", "200", 1},
+		{"GET", "/nope", "unmatched", "404", 1},
+	}
+	for _, c := range cases {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(c.method, c.path, nil))
+		got := testutil.ToFloat64(httpRequests.WithLabelValues(c.route, c.method, c.status))
+		if got != c.expected {
+			t.Errorf("[%s %s] expected %v requests for route %s status %s, got %v",
+				c.method, c.path, c.expected, c.route, c.status, got)
+		}
+	}
+}
+
+type failStorage struct {
+	blobstorage.Storage
+}
+
+func (failStorage) Put(_ context.Context, data io.ReadSeeker, objectName, contentType string, userID uint32) error {
+	return fmt.Errorf("no space left")
+}
+
+func TestInstrumentStorage(t *testing.T) {
+	st := InstrumentStorage(blobstorage.NewMemoryStorage(), "memory")
+	if err := st.Put(context.Background(), bytes.NewReader([]byte("img")), "a.jpg", "image/jpeg", 1); err != nil {
+		t.Errorf("unexpected err: %v", err)
+	}
+	if _, _, err := st.Get(context.Background(), "a.jpg"); err != nil {
+		t.Errorf("object must be stored, got %v", err)
+	}
+
+	st = InstrumentStorage(failStorage{}, "broken")
+	if err := st.Put(context.Background(), bytes.NewReader(nil), "b.jpg", "image/jpeg", 1); err == nil {
+		t.Errorf("expected put error")
+	}
+
+	for _, labels := range [][]string{{"memory", "ok"}, {"broken", "error"}} {
+		if n := sampleCount(t, blobPutDuration.WithLabelValues(labels...)); n != 1 {
+			t.Errorf("[%v] expected 1 observation, got %d", labels, n)
+		}
+	}
+}
+
+func sampleCount(t *testing.T, o prometheus.Observer) uint64 {
+	m := &dto.Metric{}
+	if err := o.(prometheus.Metric).Write(m); err != nil {
+		t.Fatalf("cant read metric: %v", err)
+	}
+	return m.GetHistogram().GetSampleCount()
+}
@@ -0,0 +1,36 @@
+package metrics
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"photolist/pkg/blobstorage"
+)
+
+var _ blobstorage.Storage = (*instrumentedStorage)(nil)
+
+// instrumentedStorage меряет Put, остальные методы уходят в хранилище как есть
+type instrumentedStorage struct {
+	blobstorage.Storage
+	name string
+}
+
+// InstrumentStorage оборачивает хранилище, name - его тип из storage.type
+func InstrumentStorage(st blobstorage.Storage, name string) blobstorage.Storage {
+	return &instrumentedStorage{
+		Storage: st,
+		name:    name,
+	}
+}
+
+func (st *instrumentedStorage) Put(ctx context.Context, data io.ReadSeeker, objectName, contentType string, userID uint32) error {
+	start := time.Now()
+	err := st.Storage.Put(ctx, data, objectName, contentType, userID)
+	status := "ok"
+	if err != nil {
+		status = "error"
+	}
+	blobPutDuration.WithLabelValues(st.name, status).Observe(time.Since(start).Seconds())
+	return err
+}
@@ -1,26 +1,95 @@
 package middleware
 
 import (
-	"log"
+	"bufio"
+	"fmt"
+	"net"
 	"net/http"
 	"time"
 
-	"github.com/opentracing/opentracing-go"
+	"go.opentelemetry.io/otel/attribute"
+	"go.uber.org/zap"
+
+	"photolist/pkg/logging"
+	"photolist/pkg/tracing"
 )
 
-func AccessLog(next http.Handler) http.Handler {
+// AccessLog открывает спан запроса и кладёт в ctx логгер с request_id и route
+// route - шаблон маршрута, как у метрик; если nil - пишем путь как есть
+func AccessLog(logger *zap.Logger, route func(r *http.Request) string, next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		ctx := r.Context()
-		span, newCtx := opentracing.StartSpanFromContext(ctx, r.URL.Path)
-		defer span.Finish()
+		// путь в имя спана не ставим - он есть в http.target, а имён должно быть немного
+		newCtx, span := tracing.StartHTTPServer(r, "HTTP "+r.Method)
+		defer span.End()
+
+		requestID := RequestIDFromContext(r.Context())
+		span.SetAttributes(attribute.String("request_id", requestID))
 
-		requestID := RequestIDFromContext(ctx)
-		span.SetTag("myrequestid", requestID)
+		routeName := r.URL.Path
+		if route != nil {
+			routeName = route(r)
+		}
+		newCtx = logging.NewContext(newCtx, logger, logging.RequestID(requestID), logging.Route(routeName))
 
 		start := time.Now()
 		r = r.WithContext(newCtx)
-		next.ServeHTTP(w, r)
+		aw := &accessWriter{ResponseWriter: w}
+		next.ServeHTTP(aw, r)
 
-		log.Printf("[access] %s %s %s %s %s", requestID, time.Since(start), r.RemoteAddr, r.Method, r.URL.Path)
+		// status - пара к code в access-логе grpc
+		logging.FromContext(newCtx).Info("access",
+			zap.String("method", r.Method),
+			zap.String("path", r.URL.Path),
+			zap.String("remote_addr", r.RemoteAddr),
+			zap.Int("status", aw.Status()),
+			zap.Duration("duration", time.Since(start)),
+		)
 	})
 }
+
+// accessWriter запоминает статус ответа
+type accessWriter struct {
+	http.ResponseWriter
+	status int
+}
+
+// Status - если обработчик ничего не записал, net/http сам ответит 200
+func (w *accessWriter) Status() int {
+	if w.status == 0 {
+		return http.StatusOK
+	}
+	return w.status
+}
+
+func (w *accessWriter) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *accessWriter) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *accessWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Hijack - websocket отвечает 101 уже мимо ResponseWriter
+func (w *accessWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	h, ok := w.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, fmt.Errorf("hijack not supported")
+	}
+	conn, rw, err := h.Hijack()
+	if err == nil && w.status == 0 {
+		w.status = http.StatusSwitchingProtocols
+	}
+	return conn, rw, err
+}
@@ -0,0 +1,46 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestAccessLogStatus(t *testing.T) {
+	cases := []struct {
+		name    string
+		handler http.HandlerFunc
+		status  int64
+	}{
+		{"explicit", func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, "no photo", http.StatusNotFound)
+		}, http.StatusNotFound},
+		{"implicit on write", func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte("ok"))
+		}, http.StatusOK},
+		{"empty", func(w http.ResponseWriter, r *http.Request) {}, http.StatusOK},
+		{"second WriteHeader ignored", func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusForbidden)
+			w.WriteHeader(http.StatusInternalServerError)
+		}, http.StatusForbidden},
+	}
+	for _, c := range cases {
+		core, logs := observer.New(zapcore.InfoLevel)
+		h := AccessLog(zap.New(core), nil, c.handler)
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/photos/", nil))
+
+		entries := logs.FilterMessage("access").All()
+		if len(entries) != 1 {
+			t.Errorf("[%s] expected 1 access entry, got %d", c.name, len(entries))
+			continue
+		}
+		if status := entries[0].ContextMap()["status"]; status != c.status {
+			t.Errorf("[%s] expected status %d, got %v", c.name, c.status, status)
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package middleware
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"photolist/pkg/logging"
+)
+
+// Lockout - прогрессивная блокировка входа после неудачных паролей
+// первые Threshold ошибок бесплатны, дальше блокировка Base, и каждая следующая ошибка её удваивает до Max
+// ключ - логин, а не ip: перебор с множества адресов тоже упрётся в блокировку,
+// цена этого - чужими ошибками аккаунт можно заблокировать, но не дольше чем на Max
+// nil Lockout ничего не блокирует
+type Lockout struct {
+	Store     Store
+	Threshold int
+	Base      time.Duration
+	Max       time.Duration
+}
+
+// логины в mysql сравниваются без учёта регистра, счётчик тоже должен быть один
+func lockoutKey(login string) string {
+	return "lockout:" + strings.ToLower(login)
+}
+
+func (lo *Lockout) lockFor(fails int) time.Duration {
+	if fails < lo.Threshold {
+		return 0
+	}
+	d := lo.Base
+	for i := lo.Threshold; i < fails && d < lo.Max; i++ {
+		d *= 2
+	}
+	if d > lo.Max {
+		d = lo.Max
+	}
+	return d
+}
+
+// счётчик помним сутки после последней попытки
+const lockoutForget = 24 * time.Hour
+
+// Attempt - попытка входа, сколько ещё ждать, 0 - можно проверять пароль
+// попытка сразу считается неудачной, успешный вход её сбрасывает через Reset
+// проверка и счёт делаются в хранилище одним действием - иначе параллельные запросы
+// успеют проверить пароль до того, как первая ошибка попадёт в счётчик
+func (lo *Lockout) Attempt(ctx context.Context, login string) time.Duration {
+	if lo == nil {
+		return 0
+	}
+	wait, err := lo.Store.Attempt(ctx, lockoutKey(login), lo)
+	if err != nil {
+		logging.FromContext(ctx).Error("lockout store err", zap.Error(err))
+		return 0
+	}
+	return wait
+}
+
+// Reset - успешный вход
+func (lo *Lockout) Reset(ctx context.Context, login string) {
+	if lo == nil {
+		return
+	}
+	err := lo.Store.Reset(ctx, lockoutKey(login))
+	if err != nil {
+		logging.FromContext(ctx).Error("lockout store err", zap.Error(err))
+	}
+}
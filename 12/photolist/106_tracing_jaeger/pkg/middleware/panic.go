@@ -1,8 +1,11 @@
 package middleware
 
 import (
-	// "fmt"
+	"fmt"
 	"net/http"
+
+	"photolist/pkg/apierr"
+	"photolist/pkg/utils/httputils"
 )
 
 func Panic(next http.Handler) http.Handler {
@@ -11,7 +14,7 @@ func Panic(next http.Handler) http.Handler {
 		defer func() {
 			if err := recover(); err != nil {
 				// fmt.Println("recovered", err)
-				http.Error(w, "Internal server error", 500)
+				httputils.RespError(w, r, apierr.Internal(fmt.Errorf("panic: %v", err)))
 			}
 		}()
 		next.ServeHTTP(w, r)
@@ -0,0 +1,209 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"photolist/pkg/apierr"
+	"photolist/pkg/config"
+	"photolist/pkg/logging"
+	"photolist/pkg/utils/httputils"
+
+	"go.uber.org/zap"
+)
+
+type Algorithm int
+
+const (
+	// TokenBucket - ведро на Burst запросов, пополняется со скоростью Requests за Per
+	// хорошо для api, где нормальны короткие всплески
+	TokenBucket Algorithm = iota
+	// SlidingWindow - не больше Requests за любые Per, окно считается приближённо по двум соседним
+	// хорошо для логина и регистрации, где всплески не нужны
+	SlidingWindow
+)
+
+type Limit struct {
+	Algorithm Algorithm
+	Requests  int
+	Per       time.Duration
+	Burst     int
+}
+
+// Store - счётчики лимитов и неудачных входов
+// MemoryStore - на один процесс, RedisStore - общий для всех инстансов
+type Store interface {
+	// Allow засчитывает запрос, если лимит не исчерпан, иначе говорит, через сколько приходить
+	Allow(ctx context.Context, key string, l Limit) (bool, time.Duration, error)
+	// Attempt засчитывает попытку входа, если блокировка по прошлым неудачам уже кончилась,
+	// иначе говорит, сколько ещё ждать
+	Attempt(ctx context.Context, key string, lo *Lockout) (time.Duration, error)
+	Reset(ctx context.Context, key string) error
+}
+
+// NewStore - хранилище из конфига ratelimit
+func NewStore(cfg config.RateLimitConfig) (Store, error) {
+	switch cfg.Store {
+	case "memory", "":
+		return NewMemoryStore(), nil
+	case "redis":
+		return NewRedisStore(cfg.RedisAddr), nil
+	}
+	return nil, fmt.Errorf("unknown ratelimit store %q", cfg.Store)
+}
+
+// takeToken - одно ведро: сколько токенов осталось после запроса и сколько ждать, если не хватило
+func takeToken(tokens float64, last, now time.Time, l Limit) (float64, bool, time.Duration) {
+	perToken := float64(l.Per) / float64(l.Requests)
+	tokens = math.Min(float64(l.Burst), tokens+float64(now.Sub(last))/perToken)
+	if tokens >= 1 {
+		return tokens - 1, true, 0
+	}
+	return tokens, false, time.Duration((1 - tokens) * perToken)
+}
+
+// slidingCount - запросов за последние Per: текущее окно целиком плюс хвост предыдущего
+// elapsed - сколько прошло от начала текущего окна
+func slidingCount(prev, curr int, elapsed time.Duration, l Limit) float64 {
+	return float64(prev)*float64(l.Per-elapsed)/float64(l.Per) + float64(curr)
+}
+
+// slidingWait - через сколько хвост предыдущего окна уменьшится настолько, чтобы влез ещё запрос
+func slidingWait(prev, curr int, elapsed time.Duration, l Limit) time.Duration {
+	free := float64(l.Requests - curr - 1)
+	if free < 0 || prev == 0 {
+		// текущее окно уже полное - только ждать следующего
+		return l.Per - elapsed
+	}
+	wait := time.Duration(float64(l.Per)*(1-free/float64(prev))) - elapsed
+	if wait <= 0 {
+		wait = time.Millisecond
+	}
+	return wait
+}
+
+// TooManyRequests - 429 с Retry-After в секундах
+func TooManyRequests(w http.ResponseWriter, r *http.Request, retry time.Duration) {
+	sec := int(math.Ceil(retry.Seconds()))
+	if sec < 1 {
+		sec = 1
+	}
+	w.Header().Set("Retry-After", strconv.Itoa(sec))
+	httputils.RespError(w, r, apierr.New(apierr.CodeTooManyRequests, "Too many requests"))
+}
+
+// Rule - лимит на путь (точный или префикс, если кончается на /)
+// точный путь совпадает и со слешем на конце: /graphql и /graphql/ - один и тот же обработчик
+// By: ip, user (без сессии - по ip) или route - один счётчик на всех
+type Rule struct {
+	Path  string
+	By    string
+	Limit Limit
+}
+
+func (rule *Rule) match(path string) bool {
+	if strings.HasSuffix(rule.Path, "/") {
+		return strings.HasPrefix(path, rule.Path)
+	}
+	return path == rule.Path || path == rule.Path+"/"
+}
+
+func (rule *Rule) key(r *http.Request, userID func(context.Context) (uint32, bool)) string {
+	switch rule.By {
+	case "route":
+		return "rl:" + rule.Path
+	case "user":
+		if userID != nil {
+			if uid, ok := userID(r.Context()); ok {
+				return "rl:" + rule.Path + ":u:" + strconv.FormatUint(uint64(uid), 10)
+			}
+		}
+	}
+	return "rl:" + rule.Path + ":ip:" + httputils.ClientIP(r)
+}
+
+// RateLimiter проверяет все подходящие правила по порядку, до первого отказа
+// при ошибке хранилища запрос пропускаем - лежащий redis не должен класть сайт
+type RateLimiter struct {
+	Store Store
+	Rules []*Rule
+	// UserID достаёт пользователя из контекста, сюда его передаёт AuthMiddleware
+	// пакет session сам зависит от middleware, поэтому функция, а не импорт
+	UserID func(context.Context) (uint32, bool)
+}
+
+// NewRateLimiter собирает правила из конфига
+func NewRateLimiter(store Store, rules []config.RateLimitRule, userID func(context.Context) (uint32, bool)) (*RateLimiter, error) {
+	rl := &RateLimiter{
+		Store:  store,
+		UserID: userID,
+	}
+	for _, rc := range rules {
+		rule := &Rule{
+			Path: rc.Path,
+			By:   rc.By,
+			Limit: Limit{
+				Requests: rc.Requests,
+				Per:      rc.Per,
+				Burst:    rc.Burst,
+			},
+		}
+		switch rc.Algorithm {
+		case "token_bucket", "":
+			rule.Limit.Algorithm = TokenBucket
+		case "sliding_window":
+			rule.Limit.Algorithm = SlidingWindow
+		default:
+			return nil, fmt.Errorf("rule %s: unknown algorithm %q", rc.Path, rc.Algorithm)
+		}
+		switch rc.By {
+		case "ip", "user", "route":
+		default:
+			return nil, fmt.Errorf("rule %s: unknown key %q", rc.Path, rc.By)
+		}
+		if rc.Path == "" || rc.Requests <= 0 || rc.Per <= 0 {
+			return nil, fmt.Errorf("rule %q: path, requests and per are required", rc.Path)
+		}
+		if rule.Limit.Burst <= 0 {
+			rule.Limit.Burst = rc.Requests
+		}
+		rl.Rules = append(rl.Rules, rule)
+	}
+	return rl, nil
+}
+
+// BeforeAuth - правила by: ip и route, ставится до AuthMiddleware:
+// поток запросов без сессии режется раньше, чем дойдёт до проверки сессии в базе или сервисе auth
+func (rl *RateLimiter) BeforeAuth(next http.Handler) http.Handler {
+	return rl.handler(next, func(rule *Rule) bool { return rule.By != "user" })
+}
+
+// AfterAuth - правила by: user, ставится после AuthMiddleware, чтобы видеть сессию
+func (rl *RateLimiter) AfterAuth(next http.Handler) http.Handler {
+	return rl.handler(next, func(rule *Rule) bool { return rule.By == "user" })
+}
+
+func (rl *RateLimiter) handler(next http.Handler, use func(*Rule) bool) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		for _, rule := range rl.Rules {
+			if !use(rule) || !rule.match(r.URL.Path) {
+				continue
+			}
+			ok, retry, err := rl.Store.Allow(r.Context(), rule.key(r, rl.UserID), rule.Limit)
+			if err != nil {
+				logging.FromContext(r.Context()).Error("ratelimit store err", zap.Error(err))
+				continue
+			}
+			if !ok {
+				TooManyRequests(w, r, retry)
+				return
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,116 @@
+package middleware
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+var (
+	_ Store = (*MemoryStore)(nil)
+)
+
+type memoryEntry struct {
+	// token bucket
+	tokens float64
+	last   time.Time
+	// sliding window
+	window     int64
+	prev, curr int
+	// неудачные входы
+	fails    int
+	lastFail time.Time
+	expires  time.Time
+}
+
+// MemoryStore - счётчики в памяти процесса
+// протухшие записи чистятся по ходу дела, не чаще раза в sweepInterval
+type MemoryStore struct {
+	mu        sync.Mutex
+	entries   map[string]*memoryEntry
+	nextSweep time.Time
+	now       func() time.Time // подменяется в тестах
+}
+
+const sweepInterval = time.Minute
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		entries: make(map[string]*memoryEntry),
+		now:     time.Now,
+	}
+}
+
+// entry вызывается под mu
+func (ms *MemoryStore) entry(key string, now time.Time) *memoryEntry {
+	if now.After(ms.nextSweep) {
+		for k, e := range ms.entries {
+			if now.After(e.expires) {
+				delete(ms.entries, k)
+			}
+		}
+		ms.nextSweep = now.Add(sweepInterval)
+	}
+	e, ok := ms.entries[key]
+	if !ok || now.After(e.expires) {
+		e = &memoryEntry{}
+		ms.entries[key] = e
+	}
+	return e
+}
+
+func (ms *MemoryStore) Allow(ctx context.Context, key string, l Limit) (bool, time.Duration, error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	now := ms.now()
+	e := ms.entry(key, now)
+
+	switch l.Algorithm {
+	case SlidingWindow:
+		window := now.UnixNano() / int64(l.Per)
+		switch {
+		case window == e.window+1:
+			e.prev, e.curr = e.curr, 0
+		case window != e.window:
+			e.prev, e.curr = 0, 0
+		}
+		e.window = window
+		elapsed := time.Duration(now.UnixNano() - window*int64(l.Per))
+		if slidingCount(e.prev, e.curr, elapsed, l)+1 > float64(l.Requests) {
+			return false, slidingWait(e.prev, e.curr, elapsed, l), nil
+		}
+		e.curr++
+		e.expires = now.Add(2 * l.Per)
+		return true, 0, nil
+	default:
+		if e.last.IsZero() {
+			e.tokens, e.last = float64(l.Burst), now
+		}
+		tokens, ok, wait := takeToken(e.tokens, e.last, now, l)
+		e.tokens, e.last = tokens, now
+		// пустое ведро наполняется полностью за Burst * Per / Requests
+		e.expires = now.Add(time.Duration(float64(l.Per) * float64(l.Burst) / float64(l.Requests)))
+		return ok, wait, nil
+	}
+}
+
+func (ms *MemoryStore) Attempt(ctx context.Context, key string, lo *Lockout) (time.Duration, error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	now := ms.now()
+	e := ms.entry(key, now)
+	if wait := e.lastFail.Add(lo.lockFor(e.fails)).Sub(now); wait > 0 {
+		return wait, nil
+	}
+	e.fails++
+	e.lastFail = now
+	e.expires = now.Add(lockoutForget)
+	return 0, nil
+}
+
+func (ms *MemoryStore) Reset(ctx context.Context, key string) error {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	delete(ms.entries, key)
+	return nil
+}
@@ -0,0 +1,145 @@
+package middleware
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/garyburd/redigo/redis"
+)
+
+var (
+	_ Store = (*RedisStore)(nil)
+)
+
+// RedisStore - счётчики в redis, общие для всех инстансов photolist
+// проверка и списание делаются одним lua-скриптом, иначе параллельные запросы проскочат лимит
+type RedisStore struct {
+	pool *redis.Pool
+}
+
+func NewRedisStore(addr string) *RedisStore {
+	return &RedisStore{
+		pool: &redis.Pool{
+			MaxIdle:     10,
+			IdleTimeout: 4 * time.Minute,
+			Dial: func() (redis.Conn, error) {
+				return redis.DialURL(addr)
+			},
+		},
+	}
+}
+
+// время передаём из приложения в мс, а не берём TIME в скрипте - так он детерминирован
+var tokenBucketScript = redis.NewScript(1, `
+local rate  = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+local now   = tonumber(ARGV[3])
+local b = redis.call("HMGET", KEYS[1], "tokens", "ts")
+local tokens = tonumber(b[1]) or burst
+local ts = tonumber(b[2]) or now
+tokens = math.min(burst, tokens + math.max(0, now - ts) * rate)
+local allowed, wait = 0, 0
+if tokens >= 1 then
+	tokens = tokens - 1
+	allowed = 1
+else
+	wait = math.ceil((1 - tokens) / rate)
+end
+redis.call("HMSET", KEYS[1], "tokens", tostring(tokens), "ts", now)
+redis.call("PEXPIRE", KEYS[1], math.ceil(burst / rate))
+return {allowed, wait}
+`)
+
+// KEYS[1] - текущее окно, KEYS[2] - предыдущее, отдаём счётчики, чтобы время ожидания посчитать в go
+var slidingWindowScript = redis.NewScript(2, `
+local limit   = tonumber(ARGV[1])
+local per     = tonumber(ARGV[2])
+local elapsed = tonumber(ARGV[3])
+local curr = tonumber(redis.call("GET", KEYS[1]) or "0")
+local prev = tonumber(redis.call("GET", KEYS[2]) or "0")
+if prev * (per - elapsed) / per + curr + 1 > limit then
+	return {0, prev, curr}
+end
+redis.call("INCR", KEYS[1])
+redis.call("PEXPIRE", KEYS[1], 2 * per)
+return {1, prev, curr}
+`)
+
+func ms(d time.Duration) int64 {
+	return int64(d / time.Millisecond)
+}
+
+func (rs *RedisStore) Allow(ctx context.Context, key string, l Limit) (bool, time.Duration, error) {
+	conn := rs.pool.Get()
+	defer conn.Close()
+	now := time.Now()
+
+	if l.Algorithm == SlidingWindow {
+		window := now.UnixNano() / int64(l.Per)
+		elapsed := time.Duration(now.UnixNano() - window*int64(l.Per))
+		res, err := redis.Int64s(slidingWindowScript.Do(conn,
+			key+":"+strconv.FormatInt(window, 10), key+":"+strconv.FormatInt(window-1, 10),
+			l.Requests, ms(l.Per), ms(elapsed)))
+		if err != nil {
+			return false, 0, err
+		}
+		if res[0] == 1 {
+			return true, 0, nil
+		}
+		return false, slidingWait(int(res[1]), int(res[2]), elapsed, l), nil
+	}
+
+	rate := float64(l.Requests) / float64(ms(l.Per)) // токенов в мс
+	res, err := redis.Int64s(tokenBucketScript.Do(conn, key,
+		strconv.FormatFloat(rate, 'f', -1, 64), l.Burst, now.UnixNano()/int64(time.Millisecond)))
+	if err != nil {
+		return false, 0, err
+	}
+	return res[0] == 1, time.Duration(res[1]) * time.Millisecond, nil
+}
+
+// блокировка считается так же, как Lockout.lockFor: после threshold неудач base, дальше удвоение до max
+var lockoutScript = redis.NewScript(1, `
+local threshold = tonumber(ARGV[1])
+local base      = tonumber(ARGV[2])
+local max       = tonumber(ARGV[3])
+local forget    = tonumber(ARGV[4])
+local now       = tonumber(ARGV[5])
+local b = redis.call("HMGET", KEYS[1], "fails", "last")
+local fails = tonumber(b[1]) or 0
+local last = tonumber(b[2]) or 0
+if fails >= threshold then
+	local d = base
+	for i = threshold + 1, fails do
+		if d >= max then break end
+		d = d * 2
+	end
+	d = math.min(d, max)
+	if last + d > now then
+		return last + d - now
+	end
+end
+redis.call("HINCRBY", KEYS[1], "fails", 1)
+redis.call("HSET", KEYS[1], "last", now)
+redis.call("PEXPIRE", KEYS[1], forget)
+return 0
+`)
+
+func (rs *RedisStore) Attempt(ctx context.Context, key string, lo *Lockout) (time.Duration, error) {
+	conn := rs.pool.Get()
+	defer conn.Close()
+	wait, err := redis.Int64(lockoutScript.Do(conn, key,
+		lo.Threshold, ms(lo.Base), ms(lo.Max), ms(lockoutForget), ms(time.Duration(time.Now().UnixNano()))))
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(wait) * time.Millisecond, nil
+}
+
+func (rs *RedisStore) Reset(ctx context.Context, key string) error {
+	conn := rs.pool.Get()
+	defer conn.Close()
+	_, err := conn.Do("DEL", key)
+	return err
+}
@@ -0,0 +1,263 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"photolist/pkg/config"
+)
+
+// fakeClock - время MemoryStore двигаем руками
+type fakeClock struct {
+	t time.Time
+}
+
+func (fc *fakeClock) now() time.Time {
+	return fc.t
+}
+
+func newTestStore() (*MemoryStore, *fakeClock) {
+	clock := &fakeClock{t: time.Unix(1570000000, 0)}
+	ms := NewMemoryStore()
+	ms.now = clock.now
+	return ms, clock
+}
+
+func TestTokenBucket(t *testing.T) {
+	ms, clock := newTestStore()
+	l := Limit{Algorithm: TokenBucket, Requests: 60, Per: time.Minute, Burst: 3}
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		if ok, _, _ := ms.Allow(ctx, "k", l); !ok {
+			t.Fatalf("[burst] request %d denied", i)
+		}
+	}
+	ok, wait, _ := ms.Allow(ctx, "k", l)
+	if ok || wait != time.Second {
+		t.Errorf("[empty] expected deny with 1s wait, got %v %v", ok, wait)
+	}
+
+	clock.t = clock.t.Add(time.Second)
+	if ok, _, _ := ms.Allow(ctx, "k", l); !ok {
+		t.Errorf("[refill] expected allow after 1s")
+	}
+	if ok, _, _ := ms.Allow(ctx, "other", l); !ok {
+		t.Errorf("[other key] expected allow")
+	}
+}
+
+func TestSlidingWindow(t *testing.T) {
+	ms, clock := newTestStore()
+	l := Limit{Algorithm: SlidingWindow, Requests: 4, Per: time.Minute}
+	ctx := context.Background()
+	// начало окна, чтобы хвост предыдущего считался предсказуемо
+	clock.t = time.Unix(0, 0).Add(time.Duration(clock.t.UnixNano()/int64(time.Minute)) * time.Minute)
+
+	for i := 0; i < 4; i++ {
+		if ok, _, _ := ms.Allow(ctx, "k", l); !ok {
+			t.Fatalf("[fill] request %d denied", i)
+		}
+	}
+	ok, wait, _ := ms.Allow(ctx, "k", l)
+	if ok || wait != time.Minute {
+		t.Errorf("[full] expected deny until next window, got %v %v", ok, wait)
+	}
+
+	// середина следующего окна: от прошлого осталась половина, 2 запроса, ещё 2 влезут
+	clock.t = clock.t.Add(90 * time.Second)
+	for i := 0; i < 2; i++ {
+		if ok, _, _ := ms.Allow(ctx, "k", l); !ok {
+			t.Errorf("[half] request %d denied", i)
+		}
+	}
+	ok, wait, _ = ms.Allow(ctx, "k", l)
+	// нужен ещё 1 свободный: хвост 4*(60-x)/60 + 2 + 1 <= 4 при x >= 45s, то есть ещё 15s
+	if ok || wait != 15*time.Second {
+		t.Errorf("[half] expected deny with 15s wait, got %v %v", ok, wait)
+	}
+}
+
+func TestRateLimiterMiddleware(t *testing.T) {
+	ms, _ := newTestStore()
+	rl, err := NewRateLimiter(ms, []config.RateLimitRule{
+		{Path: "/user/login", By: "ip", Algorithm: "sliding_window", Requests: 2, Per: time.Minute},
+		{Path: "/api/", By: "user", Requests: 1, Per: time.Hour},
+	}, func(ctx context.Context) (uint32, bool) {
+		uid, ok := ctx.Value(testUserKey).(uint32)
+		return uid, ok
+	})
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	// между ними в main стоит AuthMiddleware
+	h := rl.BeforeAuth(rl.AfterAuth(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})))
+
+	cases := []struct {
+		name   string
+		path   string
+		ip     string
+		user   uint32
+		status int
+	}{
+		{"login 1", "/user/login", "1.1.1.1", 0, http.StatusOK},
+		{"login 2", "/user/login", "1.1.1.1", 0, http.StatusOK},
+		{"login 3", "/user/login", "1.1.1.1", 0, http.StatusTooManyRequests},
+		{"login other ip", "/user/login", "2.2.2.2", 0, http.StatusOK},
+		{"no rule", "/photos/", "1.1.1.1", 0, http.StatusOK},
+		{"login slash", "/user/login/", "2.2.2.2", 0, http.StatusOK},
+		{"login slash over limit", "/user/login/", "2.2.2.2", 0, http.StatusTooManyRequests},
+		{"api user 1", "/api/v1/photos/upload", "1.1.1.1", 1, http.StatusOK},
+		{"api user 1 again", "/api/v1/user/profile", "3.3.3.3", 1, http.StatusTooManyRequests},
+		{"api user 2", "/api/v1/photos/upload", "1.1.1.1", 2, http.StatusOK},
+	}
+	for _, c := range cases {
+		r := httptest.NewRequest(http.MethodPost, c.path, nil)
+		r.RemoteAddr = c.ip + ":12345"
+		if c.user != 0 {
+			r = r.WithContext(context.WithValue(r.Context(), testUserKey, c.user))
+		}
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		if w.Code != c.status {
+			t.Errorf("[%s] expected %d, got %d", c.name, c.status, w.Code)
+		}
+		if w.Code == http.StatusTooManyRequests && w.Header().Get("Retry-After") == "" {
+			t.Errorf("[%s] no Retry-After", c.name)
+		}
+	}
+}
+
+// отсечённый по ip запрос не доходит до проверки сессии
+func TestRateLimiterBeforeAuth(t *testing.T) {
+	ms, _ := newTestStore()
+	rl, err := NewRateLimiter(ms, []config.RateLimitRule{
+		{Path: "/", By: "ip", Requests: 1, Per: time.Hour},
+		{Path: "/", By: "user", Requests: 100, Per: time.Hour},
+	}, func(ctx context.Context) (uint32, bool) { return 1, true })
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	authCalls := 0
+	auth := func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			authCalls++
+			next.ServeHTTP(w, r)
+		})
+	}
+	h := rl.BeforeAuth(auth(rl.AfterAuth(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))))
+
+	for i, status := range []int{http.StatusOK, http.StatusTooManyRequests} {
+		r := httptest.NewRequest(http.MethodGet, "/photos/", nil)
+		r.RemoteAddr = "1.1.1.1:12345"
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		if w.Code != status {
+			t.Errorf("[%d] expected %d, got %d", i, status, w.Code)
+		}
+	}
+	if authCalls != 1 {
+		t.Errorf("expected 1 auth call, got %d", authCalls)
+	}
+}
+
+type testKey int
+
+const testUserKey testKey = 1
+
+func TestNewRateLimiterErrors(t *testing.T) {
+	cases := []config.RateLimitRule{
+		{Path: "/x", By: "ip", Algorithm: "leaky", Requests: 1, Per: time.Second},
+		{Path: "/x", By: "cookie", Requests: 1, Per: time.Second},
+		{Path: "/x", By: "ip", Per: time.Second},
+		{By: "ip", Requests: 1, Per: time.Second},
+	}
+	for i, rule := range cases {
+		_, err := NewRateLimiter(NewMemoryStore(), []config.RateLimitRule{rule}, nil)
+		if err == nil {
+			t.Errorf("[%d] expected error", i)
+		}
+	}
+}
+
+func TestLockout(t *testing.T) {
+	store, clock := newTestStore()
+	lo := &Lockout{
+		Store:     store,
+		Threshold: 3,
+		Base:      time.Minute,
+		Max:       5 * time.Minute,
+	}
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		if wait := lo.Attempt(ctx, "User"); wait != 0 {
+			t.Fatalf("[free] attempt %d locked for %v", i, wait)
+		}
+	}
+	// регистр логина не важен
+	if wait := lo.Attempt(ctx, "user"); wait != time.Minute {
+		t.Errorf("[locked] expected 1m, got %v", wait)
+	}
+	// попытка во время блокировки не продлевает её
+	clock.t = clock.t.Add(30 * time.Second)
+	if wait := lo.Attempt(ctx, "user"); wait != 30*time.Second {
+		t.Errorf("[still locked] expected 30s, got %v", wait)
+	}
+	clock.t = clock.t.Add(30 * time.Second)
+	if wait := lo.Attempt(ctx, "user"); wait != 0 {
+		t.Errorf("[unlocked] expected attempt allowed, got %v", wait)
+	}
+	if wait := lo.Attempt(ctx, "user"); wait != 2*time.Minute {
+		t.Errorf("[doubled] expected 2m, got %v", wait)
+	}
+
+	expected := []time.Duration{2 * time.Minute, 4 * time.Minute, 5 * time.Minute, 5 * time.Minute}
+	for i, d := range expected {
+		if got := lo.lockFor(4 + i); got != d {
+			t.Errorf("[progressive %d] expected %v, got %v", 4+i, d, got)
+		}
+	}
+
+	lo.Reset(ctx, "user")
+	if wait := lo.Attempt(ctx, "User"); wait != 0 {
+		t.Errorf("[reset] still locked for %v", wait)
+	}
+
+	var nilLockout *Lockout
+	if nilLockout.Attempt(ctx, "user") != 0 {
+		t.Errorf("[nil] nil lockout must not lock")
+	}
+}
+
+// TestLockoutConcurrent - параллельные попытки не должны проскакивать мимо порога
+func TestLockoutConcurrent(t *testing.T) {
+	lo := &Lockout{
+		Store:     NewMemoryStore(),
+		Threshold: 3,
+		Base:      time.Minute,
+		Max:       time.Hour,
+	}
+	ctx := context.Background()
+
+	var allowed int32
+	wg := &sync.WaitGroup{}
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if lo.Attempt(ctx, "user") == 0 {
+				atomic.AddInt32(&allowed, 1)
+			}
+		}()
+	}
+	wg.Wait()
+	if allowed != 3 {
+		t.Errorf("expected 3 attempts allowed, got %d", allowed)
+	}
+}
@@ -0,0 +1,56 @@
+package notifications
+
+import (
+	"sync"
+)
+
+// Hub раздаёт свежие уведомления подписчикам внутри одного процесса
+// если запущено несколько экземпляров - каждый видит только свои события
+type Hub struct {
+	mu   sync.RWMutex
+	subs map[uint32]map[chan *Notification]struct{}
+}
+
+func NewHub() *Hub {
+	return &Hub{
+		subs: make(map[uint32]map[chan *Notification]struct{}),
+	}
+}
+
+// Subscribe возвращает канал с уведомлениями для userID и функцию отписки
+func (h *Hub) Subscribe(userID uint32) (<-chan *Notification, func()) {
+	ch := make(chan *Notification, 10)
+	h.mu.Lock()
+	if h.subs[userID] == nil {
+		h.subs[userID] = make(map[chan *Notification]struct{})
+	}
+	h.subs[userID][ch] = struct{}{}
+	h.mu.Unlock()
+
+	once := sync.Once{}
+	cancel := func() {
+		once.Do(func() {
+			h.mu.Lock()
+			delete(h.subs[userID], ch)
+			if len(h.subs[userID]) == 0 {
+				delete(h.subs, userID)
+			}
+			h.mu.Unlock()
+			close(ch)
+		})
+	}
+	return ch, cancel
+}
+
+// Publish не блокируется: медленный подписчик просто пропустит событие,
+// оно всё равно есть в базе и придёт со следующим запросом notifications
+func (h *Hub) Publish(n *Notification) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	for ch := range h.subs[n.UserID] {
+		select {
+		case ch <- n:
+		default:
+		}
+	}
+}
@@ -0,0 +1,122 @@
+package notifications
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+/*
+	go test -race -v -run Hub ./pkg/notifications/
+*/
+
+func receive(ch <-chan *Notification) (*Notification, bool) {
+	select {
+	case n, ok := <-ch:
+		return n, ok
+	case <-time.After(time.Second):
+		return nil, false
+	}
+}
+
+func TestHubFanOut(t *testing.T) {
+	h := NewHub()
+	ch1, cancel1 := h.Subscribe(1)
+	defer cancel1()
+	ch2, cancel2 := h.Subscribe(1)
+	defer cancel2()
+	other, cancelOther := h.Subscribe(2)
+	defer cancelOther()
+
+	h.Publish(&Notification{ID: 10, UserID: 1})
+
+	for i, ch := range []<-chan *Notification{ch1, ch2} {
+		n, ok := receive(ch)
+		if !ok || n.ID != 10 {
+			t.Errorf("subscriber %d: expected notification 10, got %v", i, n)
+		}
+	}
+	select {
+	case n := <-other:
+		t.Errorf("other user got %v", n)
+	default:
+	}
+}
+
+func TestHubUnsubscribe(t *testing.T) {
+	h := NewHub()
+	ch, cancel := h.Subscribe(1)
+	cancel()
+	// повторная отписка не должна паниковать на закрытом канале
+	cancel()
+
+	if _, ok := receive(ch); ok {
+		t.Errorf("channel not closed")
+	}
+	h.mu.RLock()
+	left := len(h.subs)
+	h.mu.RUnlock()
+	if left != 0 {
+		t.Errorf("subscription not removed: %d users left", left)
+	}
+
+	// публикация после отписки не пишет в закрытый канал
+	h.Publish(&Notification{ID: 10, UserID: 1})
+}
+
+func TestHubSlowSubscriber(t *testing.T) {
+	h := NewHub()
+	ch, cancel := h.Subscribe(1)
+	defer cancel()
+
+	// буфер на 10, остальное отбрасывается без блокировки
+	done := make(chan struct{})
+	go func() {
+		for i := 1; i <= 20; i++ {
+			h.Publish(&Notification{ID: uint32(i), UserID: 1})
+		}
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatalf("Publish blocked on slow subscriber")
+	}
+	if len(ch) != cap(ch) {
+		t.Errorf("expected full buffer, got %d", len(ch))
+	}
+}
+
+// публикация и отписка одновременно - смысл теста в запуске с -race
+func TestHubConcurrent(t *testing.T) {
+	h := NewHub()
+	wg := &sync.WaitGroup{}
+	for i := 0; i < 20; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			ch, cancel := h.Subscribe(1)
+			for j := 0; j < 5; j++ {
+				select {
+				case <-ch:
+				default:
+				}
+			}
+			cancel()
+		}()
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 20; j++ {
+				h.Publish(&Notification{ID: uint32(i*100 + j), UserID: 1})
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	h.mu.RLock()
+	left := len(h.subs)
+	h.mu.RUnlock()
+	if left != 0 {
+		t.Errorf("subscriptions left: %d", left)
+	}
+}
@@ -0,0 +1,130 @@
+package notifications
+
+import (
+	"context"
+	"database/sql"
+	"strconv"
+	"strings"
+	"time"
+
+	"photolist/pkg/utils/pagination"
+)
+
+type Type string
+
+const (
+	TypeFollow Type = "follow"
+	TypeLike   Type = "like"
+)
+
+// Notification - событие для пользователя UserID, которое совершил ActorID
+type Notification struct {
+	ID        uint32
+	UserID    uint32
+	ActorID   uint32
+	Type      Type
+	PhotoID   uint32 // только для лайков
+	Read      bool
+	CreatedAt time.Time
+}
+
+func (n *Notification) Id() string {
+	return strconv.Itoa(int(n.ID))
+}
+
+func (n *Notification) Cursor() string {
+	return (&pagination.Cursor{ID: n.ID}).Encode()
+}
+
+type NotificationsRepo struct {
+	db *sql.DB
+}
+
+func NewNotificationsRepository(db *sql.DB) *NotificationsRepo {
+	return &NotificationsRepo{
+		db: db,
+	}
+}
+
+// Add сохраняет уведомление, повторное (тот же лайк после отмены, та же подписка) игнорируется
+// первым параметром возвращает, было ли уведомление создано
+func (repo *NotificationsRepo) Add(ctx context.Context, n *Notification) (bool, error) {
+	n.CreatedAt = time.Now().Truncate(time.Second)
+	res, err := repo.db.ExecContext(ctx, `INSERT IGNORE INTO notifications(user_id, actor_id, type, photo_id, created_at) 
+		VALUES(?, ?, ?, ?, ?)`, n.UserID, n.ActorID, n.Type, n.PhotoID, n.CreatedAt)
+	if err != nil {
+		return false, err
+	}
+	aff, _ := res.RowsAffected()
+	if aff <= 0 {
+		return false, nil
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return false, err
+	}
+	n.ID = uint32(id)
+	return true, nil
+}
+
+// GetByUser - страница уведомлений, свежие сверху
+func (repo *NotificationsRepo) GetByUser(ctx context.Context, userID uint32, page pagination.Page) ([]*Notification, bool, error) {
+	q := `SELECT id, user_id, actor_id, type, photo_id, is_read, UNIX_TIMESTAMP(created_at) 
+		FROM notifications WHERE user_id = ?`
+	args := []interface{}{userID}
+	if page.After != nil {
+		q += " AND id < ?"
+		args = append(args, page.After.ID)
+	}
+	q += " ORDER BY id DESC LIMIT ?"
+	args = append(args, page.FetchLimit())
+
+	rows, err := repo.db.QueryContext(ctx, q, args...)
+	if err != nil {
+		return nil, false, err
+	}
+	defer rows.Close()
+	result := make([]*Notification, 0, page.FetchLimit())
+	for rows.Next() {
+		n := &Notification{}
+		var created int64
+		err := rows.Scan(&n.ID, &n.UserID, &n.ActorID, &n.Type, &n.PhotoID, &n.Read, &created)
+		if err != nil {
+			return nil, false, err
+		}
+		n.CreatedAt = time.Unix(created, 0)
+		result = append(result, n)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, false, err
+	}
+
+	hasNext := page.HasNext(len(result))
+	if hasNext {
+		result = result[:page.Limit]
+	}
+	return result, hasNext, nil
+}
+
+// MarkRead помечает прочитанными выбранные уведомления, а если ids пустой - все
+func (repo *NotificationsRepo) MarkRead(ctx context.Context, userID uint32, ids []uint32) error {
+	q := "UPDATE notifications SET is_read = 1 WHERE user_id = ? AND is_read = 0"
+	args := []interface{}{userID}
+	if len(ids) > 0 {
+		placeholders := make([]string, len(ids))
+		for i, id := range ids {
+			placeholders[i] = "?"
+			args = append(args, id)
+		}
+		q += " AND id IN (" + strings.Join(placeholders, ",") + ")"
+	}
+	_, err := repo.db.ExecContext(ctx, q, args...)
+	return err
+}
+
+func (repo *NotificationsRepo) UnreadCount(ctx context.Context, userID uint32) (int, error) {
+	var cnt int
+	err := repo.db.QueryRowContext(ctx, "SELECT count(*) FROM notifications WHERE user_id = ? AND is_read = 0", userID).
+		Scan(&cnt)
+	return cnt, err
+}
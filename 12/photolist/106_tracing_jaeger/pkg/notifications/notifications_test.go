@@ -0,0 +1,161 @@
+package notifications
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"testing"
+
+	"photolist/pkg/utils/pagination"
+
+	sqlmock "gopkg.in/DATA-DOG/go-sqlmock.v1"
+)
+
+func TestAdd(t *testing.T) {
+	cases := []struct {
+		name     string
+		result   driver.Result
+		err      error
+		created  bool
+		expectID uint32
+	}{
+		{"ok", sqlmock.NewResult(7, 1), nil, true, 7},
+		{"duplicate", sqlmock.NewResult(0, 0), nil, false, 0},
+		{"db error", nil, fmt.Errorf("bad connection"), false, 0},
+	}
+	for _, c := range cases {
+		db, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("cant create mock: %s", err)
+		}
+		repo := NewNotificationsRepository(db)
+		exp := mock.ExpectExec(`INSERT IGNORE INTO notifications`).
+			WithArgs(1, 2, TypeLike, 10, sqlmock.AnyArg())
+		if c.err != nil {
+			exp.WillReturnError(c.err)
+		} else {
+			exp.WillReturnResult(c.result)
+		}
+
+		n := &Notification{UserID: 1, ActorID: 2, Type: TypeLike, PhotoID: 10}
+		created, err := repo.Add(context.Background(), n)
+		if (err != nil) != (c.err != nil) {
+			t.Errorf("[%s] unexpected err: %v", c.name, err)
+		}
+		if created != c.created || n.ID != c.expectID {
+			t.Errorf("[%s] expected created=%v id=%d, got %v %d", c.name, c.created, c.expectID, created, n.ID)
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("[%s] there were unfulfilled expectations: %s", c.name, err)
+		}
+		db.Close()
+	}
+}
+
+func TestGetByUser(t *testing.T) {
+	columns := []string{"id", "user_id", "actor_id", "type", "photo_id", "is_read", "created"}
+	cases := []struct {
+		name        string
+		page        pagination.Page
+		prepare     func(mock sqlmock.Sqlmock)
+		expectedIDs []uint32
+		hasNext     bool
+	}{
+		{"first page", pagination.Page{Limit: 2}, func(mock sqlmock.Sqlmock) {
+			mock.ExpectQuery(`FROM notifications WHERE user_id = \? ORDER BY id DESC LIMIT \?`).
+				WithArgs(1, 3).
+				WillReturnRows(sqlmock.NewRows(columns).
+					AddRow(9, 1, 2, "like", 10, false, 1600000002).
+					AddRow(8, 1, 3, "follow", 0, true, 1600000001).
+					AddRow(5, 1, 2, "like", 11, true, 1600000000))
+		}, []uint32{9, 8}, true},
+		// свежие сверху, поэтому следующая страница - id меньше курсора
+		{"after cursor", pagination.Page{Limit: 2, After: &pagination.Cursor{ID: 8}}, func(mock sqlmock.Sqlmock) {
+			mock.ExpectQuery(`FROM notifications WHERE user_id = \? AND id < \? ORDER BY id DESC LIMIT \?`).
+				WithArgs(1, 8, 3).
+				WillReturnRows(sqlmock.NewRows(columns).
+					AddRow(5, 1, 2, "like", 11, true, 1600000000))
+		}, []uint32{5}, false},
+	}
+	for _, c := range cases {
+		db, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("cant create mock: %s", err)
+		}
+		repo := NewNotificationsRepository(db)
+		c.prepare(mock)
+
+		result, hasNext, err := repo.GetByUser(context.Background(), 1, c.page)
+		if err != nil {
+			t.Errorf("[%s] unexpected err: %v", c.name, err)
+		}
+		if hasNext != c.hasNext {
+			t.Errorf("[%s] expected hasNext %v, got %v", c.name, c.hasNext, hasNext)
+		}
+		ids := make([]uint32, 0, len(result))
+		for _, n := range result {
+			ids = append(ids, n.ID)
+		}
+		if fmt.Sprint(ids) != fmt.Sprint(c.expectedIDs) {
+			t.Errorf("[%s] expected %v, got %v", c.name, c.expectedIDs, ids)
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("[%s] there were unfulfilled expectations: %s", c.name, err)
+		}
+		db.Close()
+	}
+}
+
+func TestMarkRead(t *testing.T) {
+	cases := []struct {
+		name    string
+		ids     []uint32
+		prepare func(mock sqlmock.Sqlmock)
+	}{
+		{"all", nil, func(mock sqlmock.Sqlmock) {
+			mock.ExpectExec(`UPDATE notifications SET is_read = 1 WHERE user_id = \? AND is_read = 0$`).
+				WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 5))
+		}},
+		{"selected", []uint32{3, 4}, func(mock sqlmock.Sqlmock) {
+			mock.ExpectExec(`UPDATE notifications SET is_read = 1 WHERE user_id = \? AND is_read = 0 AND id IN \(\?,\?\)`).
+				WithArgs(1, 3, 4).WillReturnResult(sqlmock.NewResult(0, 2))
+		}},
+	}
+	for _, c := range cases {
+		db, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("cant create mock: %s", err)
+		}
+		repo := NewNotificationsRepository(db)
+		c.prepare(mock)
+
+		err = repo.MarkRead(context.Background(), 1, c.ids)
+		if err != nil {
+			t.Errorf("[%s] unexpected err: %v", c.name, err)
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("[%s] there were unfulfilled expectations: %s", c.name, err)
+		}
+		db.Close()
+	}
+}
+
+func TestUnreadCount(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("cant create mock: %s", err)
+	}
+	defer db.Close()
+	repo := NewNotificationsRepository(db)
+
+	mock.ExpectQuery(`SELECT count\(\*\) FROM notifications WHERE user_id = \? AND is_read = 0`).
+		WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"cnt"}).AddRow(4))
+
+	cnt, err := repo.UnreadCount(context.Background(), 1)
+	if err != nil || cnt != 4 {
+		t.Errorf("expected 4, got %d, %v", cnt, err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
@@ -0,0 +1,48 @@
+package notifications
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+
+	"photolist/pkg/logging"
+)
+
+// Notifier сохраняет уведомление и отправляет его подписчикам
+// ошибки только логируются - из-за уведомлений не должен ломаться лайк или подписка
+type Notifier struct {
+	Repo *NotificationsRepo
+	Hub  *Hub
+}
+
+func (nf *Notifier) Followed(ctx context.Context, userID, followerID uint32) {
+	nf.notify(ctx, &Notification{
+		UserID:  userID,
+		ActorID: followerID,
+		Type:    TypeFollow,
+	})
+}
+
+func (nf *Notifier) Liked(ctx context.Context, ownerID, photoID, likerID uint32) {
+	nf.notify(ctx, &Notification{
+		UserID:  ownerID,
+		ActorID: likerID,
+		Type:    TypeLike,
+		PhotoID: photoID,
+	})
+}
+
+func (nf *Notifier) notify(ctx context.Context, n *Notification) {
+	// о своих действиях не уведомляем
+	if nf == nil || n.UserID == n.ActorID {
+		return
+	}
+	created, err := nf.Repo.Add(ctx, n)
+	if err != nil {
+		logging.FromContext(ctx).Error("NotificationsRepo.Add err", zap.Error(err))
+		return
+	}
+	if created {
+		nf.Hub.Publish(n)
+	}
+}
@@ -0,0 +1,62 @@
+package notifications
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	sqlmock "gopkg.in/DATA-DOG/go-sqlmock.v1"
+)
+
+func TestNotifier(t *testing.T) {
+	cases := []struct {
+		name      string
+		call      func(nf *Notifier)
+		prepare   func(mock sqlmock.Sqlmock)
+		published bool
+	}{
+		{"like", func(nf *Notifier) { nf.Liked(context.Background(), 1, 10, 2) }, func(mock sqlmock.Sqlmock) {
+			mock.ExpectExec(`INSERT IGNORE INTO notifications`).WithArgs(1, 2, TypeLike, 10, sqlmock.AnyArg()).
+				WillReturnResult(sqlmock.NewResult(7, 1))
+		}, true},
+		{"follow", func(nf *Notifier) { nf.Followed(context.Background(), 1, 2) }, func(mock sqlmock.Sqlmock) {
+			mock.ExpectExec(`INSERT IGNORE INTO notifications`).WithArgs(1, 2, TypeFollow, 0, sqlmock.AnyArg()).
+				WillReturnResult(sqlmock.NewResult(7, 1))
+		}, true},
+		// повторный лайк после отмены - в базе уже есть, второй раз не шлём
+		{"duplicate", func(nf *Notifier) { nf.Liked(context.Background(), 1, 10, 2) }, func(mock sqlmock.Sqlmock) {
+			mock.ExpectExec(`INSERT IGNORE INTO notifications`).WillReturnResult(sqlmock.NewResult(0, 0))
+		}, false},
+		{"own photo", func(nf *Notifier) { nf.Liked(context.Background(), 1, 10, 1) }, func(mock sqlmock.Sqlmock) {}, false},
+		{"db error", func(nf *Notifier) { nf.Followed(context.Background(), 1, 2) }, func(mock sqlmock.Sqlmock) {
+			mock.ExpectExec(`INSERT IGNORE INTO notifications`).WillReturnError(fmt.Errorf("bad connection"))
+		}, false},
+	}
+	for _, c := range cases {
+		db, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("cant create mock: %s", err)
+		}
+		nf := &Notifier{
+			Repo: NewNotificationsRepository(db),
+			Hub:  NewHub(),
+		}
+		ch, cancel := nf.Hub.Subscribe(1)
+		c.prepare(mock)
+
+		c.call(nf)
+		published := len(ch) > 0
+		if published != c.published {
+			t.Errorf("[%s] expected published=%v", c.name, c.published)
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("[%s] there were unfulfilled expectations: %s", c.name, err)
+		}
+		cancel()
+		db.Close()
+	}
+
+	// без настроенных уведомлений вызовы ничего не делают
+	var nf *Notifier
+	nf.Liked(context.Background(), 1, 10, 2)
+}
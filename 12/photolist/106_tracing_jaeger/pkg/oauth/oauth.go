@@ -0,0 +1,194 @@
+package oauth
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+
+	"golang.org/x/oauth2"
+
+	"photolist/pkg/config"
+	"photolist/pkg/utils/randutils"
+)
+
+// Identity - кто пришёл от провайдера
+// Provider + Subject однозначно определяют внешний аккаунт, остальное - для красоты
+// Verified - провайдер явно сказал, что Email подтверждён, без этого адресу не верим
+type Identity struct {
+	Provider string
+	Subject  string
+	Login    string
+	Email    string
+	Name     string
+	Verified bool
+}
+
+// Provider - один внешний сервис авторизации
+type Provider interface {
+	AuthCodeURL(ctx context.Context, state string, opts ...oauth2.AuthCodeOption) (string, error)
+	Exchange(ctx context.Context, code string, opts ...oauth2.AuthCodeOption) (*Identity, error)
+}
+
+const stateCookieName = "oauth_state"
+
+var (
+	errUnknownProvider = errors.New("unknown oauth provider")
+	errBadState        = errors.New("bad oauth state")
+	errDenied          = errors.New("oauth access denied")
+)
+
+func IsErrUnknownProvider(err error) bool {
+	return err == errUnknownProvider
+}
+
+func IsErrBadState(err error) bool {
+	return err == errBadState
+}
+
+func IsErrDenied(err error) bool {
+	return err == errDenied
+}
+
+// Registry - провайдеры из конфига, state и PKCE живут в короткой куке между Begin и Complete
+type Registry struct {
+	providers map[string]Provider
+}
+
+func NewRegistry(redirectURL string, cfgs map[string]config.OAuthProvider) (*Registry, error) {
+	reg := &Registry{
+		providers: make(map[string]Provider, len(cfgs)),
+	}
+	for name, cfg := range cfgs {
+		p, err := newProvider(name, cfg, redirectURL)
+		if err != nil {
+			return nil, fmt.Errorf("oauth provider %s: %v", name, err)
+		}
+		reg.providers[name] = p
+	}
+	return reg, nil
+}
+
+func newProvider(name string, cfg config.OAuthProvider, redirectURL string) (Provider, error) {
+	if cfg.ClientID == "" {
+		return nil, fmt.Errorf("empty client_id")
+	}
+	switch cfg.Type {
+	case "vk":
+		return newVK(cfg, redirectURL), nil
+	case "github":
+		return newGitHub(cfg, redirectURL), nil
+	case "google":
+		if cfg.Issuer == "" {
+			cfg.Issuer = googleIssuer
+		}
+		return newOIDC(cfg, redirectURL), nil
+	case "oidc":
+		if cfg.Issuer == "" {
+			return nil, fmt.Errorf("empty issuer")
+		}
+		return newOIDC(cfg, redirectURL), nil
+	}
+	return nil, fmt.Errorf("unknown type %q", cfg.Type)
+}
+
+// Names - провайдеры для кнопок на странице логина
+func (reg *Registry) Names() []string {
+	res := make([]string, 0, len(reg.providers))
+	for name := range reg.providers {
+		res = append(res, name)
+	}
+	sort.Strings(res)
+	return res
+}
+
+// pkceChallenge - S256 из RFC 7636
+func pkceChallenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+func randToken() string {
+	return base64.RawURLEncoding.EncodeToString(randutils.RandCryptBytes(32))
+}
+
+// Begin запоминает state и code_verifier в куке и возвращает адрес, куда отправить пользователя
+func (reg *Registry) Begin(w http.ResponseWriter, r *http.Request, name string) (string, error) {
+	p, ok := reg.providers[name]
+	if !ok {
+		return "", errUnknownProvider
+	}
+	state := randToken()
+	verifier := randToken()
+
+	redirect, err := p.AuthCodeURL(r.Context(), state,
+		oauth2.SetAuthURLParam("code_challenge", pkceChallenge(verifier)),
+		oauth2.SetAuthURLParam("code_challenge_method", "S256"),
+	)
+	if err != nil {
+		return "", err
+	}
+
+	val := url.Values{}
+	val.Set("p", name)
+	val.Set("s", state)
+	val.Set("v", verifier)
+	http.SetCookie(w, &http.Cookie{
+		Name:     stateCookieName,
+		Value:    val.Encode(),
+		Path:     r.URL.Path,
+		MaxAge:   600,
+		HttpOnly: true,
+		// провайдер возвращает пользователя обычным переходом по ссылке, Lax это пропускает
+		SameSite: http.SameSiteLaxMode,
+	})
+	return redirect, nil
+}
+
+// Complete проверяет state из ответа провайдера и меняет code на пользователя
+func (reg *Registry) Complete(w http.ResponseWriter, r *http.Request) (*Identity, error) {
+	cookie, err := r.Cookie(stateCookieName)
+	if err != nil {
+		return nil, errBadState
+	}
+	// кука одноразовая
+	http.SetCookie(w, &http.Cookie{
+		Name:     stateCookieName,
+		Path:     r.URL.Path,
+		MaxAge:   -1,
+		HttpOnly: true,
+	})
+
+	val, err := url.ParseQuery(cookie.Value)
+	if err != nil {
+		return nil, errBadState
+	}
+	state := r.FormValue("state")
+	if state == "" || subtle.ConstantTimeCompare([]byte(state), []byte(val.Get("s"))) != 1 {
+		return nil, errBadState
+	}
+	p, ok := reg.providers[val.Get("p")]
+	if !ok {
+		return nil, errUnknownProvider
+	}
+
+	if r.FormValue("error") != "" {
+		return nil, errDenied
+	}
+	code := r.FormValue("code")
+	if code == "" {
+		return nil, errBadState
+	}
+
+	ident, err := p.Exchange(r.Context(), code, oauth2.SetAuthURLParam("code_verifier", val.Get("v")))
+	if err != nil {
+		return nil, err
+	}
+	ident.Provider = val.Get("p")
+	return ident, nil
+}
@@ -0,0 +1,357 @@
+package oauth
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"sync"
+	"testing"
+
+	"photolist/pkg/config"
+)
+
+const (
+	testClientID = "client-1"
+	testRedirect = "http://localhost:8080/user/login_oauth"
+)
+
+// fakeAuthServer - провайдер целиком: authorize, token, userinfo, discovery и api vk/github
+// authorize сразу "соглашается" и запоминает code_challenge под выданный code
+type fakeAuthServer struct {
+	*httptest.Server
+	issuer string // что отдавать в discovery, по умолчанию свой адрес
+
+	mu         sync.Mutex
+	challenges map[string]string
+	codes      int
+	bodies     map[string]interface{} // ответы api по пути, меняются через setBody
+}
+
+func newFakeAuthServer() *fakeAuthServer {
+	fs := &fakeAuthServer{
+		challenges: map[string]string{},
+		bodies: map[string]interface{}{
+			"/userinfo": map[string]interface{}{
+				"sub": "abc-123", "email": "oidc@example.com", "email_verified": true,
+				"name": "Oidc User", "preferred_username": "oidcuser",
+			},
+			"/github/user": map[string]interface{}{
+				"id": 7, "login": "octo", "name": "Octo Cat", "email": nil,
+			},
+			"/github/user/emails": []map[string]interface{}{
+				{"email": "old@example.com", "primary": false, "verified": true},
+				{"email": "octo@example.com", "primary": true, "verified": true},
+			},
+		},
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/authorize", fs.authorize)
+	mux.HandleFunc("/token", fs.token)
+	mux.HandleFunc("/.well-known/openid-configuration", fs.discovery)
+	mux.HandleFunc("/userinfo", fs.bearer)
+	mux.HandleFunc("/github/user", fs.bearer)
+	mux.HandleFunc("/github/user/emails", fs.bearer)
+	mux.HandleFunc("/vk/users.get", func(w http.ResponseWriter, r *http.Request) {
+		if r.FormValue("access_token") == "" {
+			http.Error(w, "no token", http.StatusUnauthorized)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"response": []map[string]string{{"first_name": "Ivan", "last_name": "Petrov"}},
+		})
+	})
+	fs.Server = httptest.NewServer(mux)
+	return fs
+}
+
+func (fs *fakeAuthServer) authorize(w http.ResponseWriter, r *http.Request) {
+	if r.FormValue("client_id") != testClientID || r.FormValue("code_challenge_method") != "S256" {
+		http.Error(w, "bad request", http.StatusBadRequest)
+		return
+	}
+	fs.mu.Lock()
+	fs.codes++
+	code := fmt.Sprintf("code-%d", fs.codes)
+	fs.challenges[code] = r.FormValue("code_challenge")
+	fs.mu.Unlock()
+
+	back, _ := url.Parse(r.FormValue("redirect_uri"))
+	q := back.Query()
+	q.Set("code", code)
+	q.Set("state", r.FormValue("state"))
+	back.RawQuery = q.Encode()
+	http.Redirect(w, r, back.String(), http.StatusFound)
+}
+
+func (fs *fakeAuthServer) token(w http.ResponseWriter, r *http.Request) {
+	code := r.FormValue("code")
+	fs.mu.Lock()
+	challenge, ok := fs.challenges[code]
+	delete(fs.challenges, code)
+	fs.mu.Unlock()
+
+	clientID, _, basic := r.BasicAuth()
+	if !basic {
+		clientID = r.FormValue("client_id")
+	}
+	if !ok || clientID != testClientID || pkceChallenge(r.FormValue("code_verifier")) != challenge {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"error": "invalid_grant"}`))
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"access_token": "at-" + code,
+		"token_type":   "Bearer",
+		"user_id":      42,
+		"email":        "vk@example.com",
+	})
+}
+
+func (fs *fakeAuthServer) discovery(w http.ResponseWriter, r *http.Request) {
+	issuer := fs.issuer
+	if issuer == "" {
+		issuer = fs.URL
+	}
+	json.NewEncoder(w).Encode(map[string]string{
+		"issuer":                 issuer,
+		"authorization_endpoint": fs.URL + "/authorize",
+		"token_endpoint":         fs.URL + "/token",
+		"userinfo_endpoint":      fs.URL + "/userinfo",
+	})
+}
+
+func (fs *fakeAuthServer) bearer(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("Authorization") == "" {
+		http.Error(w, "no token", http.StatusUnauthorized)
+		return
+	}
+	fs.mu.Lock()
+	body := fs.bodies[r.URL.Path]
+	fs.mu.Unlock()
+	json.NewEncoder(w).Encode(body)
+}
+
+func (fs *fakeAuthServer) setBody(path string, body interface{}) {
+	fs.mu.Lock()
+	fs.bodies[path] = body
+	fs.mu.Unlock()
+}
+
+func (fs *fakeAuthServer) providers() map[string]config.OAuthProvider {
+	return map[string]config.OAuthProvider{
+		"vk": {Type: "vk", ClientID: testClientID, ClientSecret: "s",
+			AuthURL: fs.URL + "/authorize", TokenURL: fs.URL + "/token", APIURL: fs.URL + "/vk"},
+		"github": {Type: "github", ClientID: testClientID, ClientSecret: "s",
+			AuthURL: fs.URL + "/authorize", TokenURL: fs.URL + "/token", APIURL: fs.URL + "/github"},
+		"corp": {Type: "oidc", ClientID: testClientID, ClientSecret: "s", Issuer: fs.URL},
+	}
+}
+
+// login проходит Begin, "соглашается" у провайдера и возвращает запрос-колбэк с кукой state
+func login(t *testing.T, reg *Registry, name string) (*http.Request, error) {
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/user/login_oauth?provider="+name, nil)
+	redirect, err := reg.Begin(w, r, name)
+	if err != nil {
+		return nil, err
+	}
+
+	client := &http.Client{
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+	resp, err := client.Get(redirect)
+	if err != nil {
+		t.Fatalf("[%s] authorize err: %v", name, err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusFound {
+		t.Fatalf("[%s] authorize status %d, url %s", name, resp.StatusCode, redirect)
+	}
+
+	callback := httptest.NewRequest(http.MethodGet, resp.Header.Get("Location"), nil)
+	for _, c := range w.Result().Cookies() {
+		callback.AddCookie(c)
+	}
+	return callback, nil
+}
+
+func TestLogin(t *testing.T) {
+	fs := newFakeAuthServer()
+	defer fs.Close()
+	reg, err := NewRegistry(testRedirect, fs.providers())
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+
+	cases := []struct {
+		provider string
+		expected *Identity
+	}{
+		{"vk", &Identity{Provider: "vk", Subject: "42", Email: "vk@example.com", Name: "Ivan Petrov"}},
+		{"github", &Identity{Provider: "github", Subject: "7", Login: "octo", Email: "octo@example.com", Name: "Octo Cat", Verified: true}},
+		{"corp", &Identity{Provider: "corp", Subject: "abc-123", Login: "oidcuser", Email: "oidc@example.com", Name: "Oidc User", Verified: true}},
+	}
+	for _, item := range cases {
+		callback, err := login(t, reg, item.provider)
+		if err != nil {
+			t.Errorf("[%s] begin err: %v", item.provider, err)
+			continue
+		}
+		ident, err := reg.Complete(httptest.NewRecorder(), callback)
+		if err != nil {
+			t.Errorf("[%s] complete err: %v", item.provider, err)
+			continue
+		}
+		if !reflect.DeepEqual(ident, item.expected) {
+			t.Errorf("[%s] bad identity: got %+v, expected %+v", item.provider, ident, item.expected)
+		}
+	}
+}
+
+// Verified только если провайдер явно подтвердил адрес
+func TestLoginVerified(t *testing.T) {
+	cases := []struct {
+		name     string
+		provider string
+		path     string
+		body     interface{}
+		email    string
+		verified bool
+	}{
+		{"oidc no flag", "corp", "/userinfo", map[string]interface{}{
+			"sub": "abc-123", "email": "oidc@example.com",
+		}, "oidc@example.com", false},
+		{"oidc unverified", "corp", "/userinfo", map[string]interface{}{
+			"sub": "abc-123", "email": "oidc@example.com", "email_verified": false,
+		}, "", false},
+		{"github profile email verified", "github", "/github/user/emails", []map[string]interface{}{
+			{"email": "public@example.com", "primary": false, "verified": true},
+		}, "public@example.com", true},
+		{"github profile email unverified", "github", "/github/user/emails", []map[string]interface{}{
+			{"email": "public@example.com", "primary": false, "verified": false},
+			{"email": "octo@example.com", "primary": true, "verified": true},
+		}, "public@example.com", false},
+		{"github no verified email", "github", "/github/user/emails", []map[string]interface{}{
+			{"email": "octo@example.com", "primary": true, "verified": false},
+		}, "", false},
+		{"vk", "vk", "", nil, "vk@example.com", false},
+	}
+	for _, item := range cases {
+		fs := newFakeAuthServer()
+		if item.path != "" {
+			fs.setBody(item.path, item.body)
+		}
+		if item.provider == "github" && item.email != "" {
+			fs.setBody("/github/user", map[string]interface{}{"id": 7, "login": "octo", "email": item.email})
+		}
+		reg, _ := NewRegistry(testRedirect, fs.providers())
+
+		callback, err := login(t, reg, item.provider)
+		if err != nil {
+			t.Fatalf("[%s] begin err: %v", item.name, err)
+		}
+		ident, err := reg.Complete(httptest.NewRecorder(), callback)
+		if err != nil {
+			t.Errorf("[%s] complete err: %v", item.name, err)
+		} else if ident.Email != item.email || ident.Verified != item.verified {
+			t.Errorf("[%s] expected %q verified=%v, got %q verified=%v",
+				item.name, item.email, item.verified, ident.Email, ident.Verified)
+		}
+		fs.Close()
+	}
+}
+
+func TestCompleteErrors(t *testing.T) {
+	fs := newFakeAuthServer()
+	defer fs.Close()
+	reg, _ := NewRegistry(testRedirect, fs.providers())
+
+	cases := []struct {
+		name    string
+		tamper  func(r *http.Request) *http.Request
+		checkFn func(error) bool
+	}{
+		{"no cookie", func(r *http.Request) *http.Request {
+			return httptest.NewRequest(http.MethodGet, r.URL.String(), nil)
+		}, IsErrBadState},
+		{"state mismatch", func(r *http.Request) *http.Request {
+			q := r.URL.Query()
+			q.Set("state", "forged")
+			return withQuery(r, q)
+		}, IsErrBadState},
+		{"denied", func(r *http.Request) *http.Request {
+			q := r.URL.Query()
+			q.Del("code")
+			q.Set("error", "access_denied")
+			return withQuery(r, q)
+		}, IsErrDenied},
+		{"wrong verifier", func(r *http.Request) *http.Request {
+			c, _ := r.Cookie(stateCookieName)
+			val, _ := url.ParseQuery(c.Value)
+			val.Set("v", "someone-elses-verifier")
+			res := httptest.NewRequest(http.MethodGet, r.URL.String(), nil)
+			res.AddCookie(&http.Cookie{Name: stateCookieName, Value: val.Encode()})
+			return res
+		}, func(err error) bool { return err != nil && !IsErrBadState(err) }},
+	}
+	for _, item := range cases {
+		callback, err := login(t, reg, "github")
+		if err != nil {
+			t.Fatalf("[%s] begin err: %v", item.name, err)
+		}
+		_, err = reg.Complete(httptest.NewRecorder(), item.tamper(callback))
+		if !item.checkFn(err) {
+			t.Errorf("[%s] unexpected err: %v", item.name, err)
+		}
+	}
+}
+
+func withQuery(r *http.Request, q url.Values) *http.Request {
+	u := *r.URL
+	u.RawQuery = q.Encode()
+	res := httptest.NewRequest(http.MethodGet, u.String(), nil)
+	for _, c := range r.Cookies() {
+		res.AddCookie(c)
+	}
+	return res
+}
+
+func TestBeginErrors(t *testing.T) {
+	fs := newFakeAuthServer()
+	defer fs.Close()
+	fs.issuer = "https://evil.example.com"
+	reg, _ := NewRegistry(testRedirect, fs.providers())
+
+	_, err := reg.Begin(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/user/login_oauth", nil), "nope")
+	if !IsErrUnknownProvider(err) {
+		t.Errorf("[unknown provider] unexpected err: %v", err)
+	}
+	_, err = reg.Begin(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/user/login_oauth", nil), "corp")
+	if err == nil {
+		t.Errorf("[issuer mismatch] expected error")
+	}
+}
+
+func TestNewRegistryErrors(t *testing.T) {
+	cases := []struct {
+		name string
+		cfg  config.OAuthProvider
+	}{
+		{"unknown type", config.OAuthProvider{Type: "myspace", ClientID: "1"}},
+		{"no client id", config.OAuthProvider{Type: "github"}},
+		{"oidc without issuer", config.OAuthProvider{Type: "oidc", ClientID: "1"}},
+	}
+	for _, item := range cases {
+		_, err := NewRegistry(testRedirect, map[string]config.OAuthProvider{"p": item.cfg})
+		if err == nil {
+			t.Errorf("[%s] expected error", item.name)
+		}
+	}
+}
@@ -0,0 +1,284 @@
+package oauth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/github"
+	"golang.org/x/oauth2/vk"
+
+	"photolist/pkg/config"
+)
+
+const (
+	googleIssuer = "https://accounts.google.com"
+	vkAPIURL     = "https://api.vk.com/method"
+	githubAPIURL = "https://api.github.com"
+)
+
+// oauth2Provider - общий код: адреса из oauth2.Config, а как узнать пользователя - у каждого своё
+type oauth2Provider struct {
+	config   func(ctx context.Context) (*oauth2.Config, error)
+	identity func(ctx context.Context, client *http.Client, tok *oauth2.Token) (*Identity, error)
+}
+
+func (p *oauth2Provider) AuthCodeURL(ctx context.Context, state string, opts ...oauth2.AuthCodeOption) (string, error) {
+	conf, err := p.config(ctx)
+	if err != nil {
+		return "", err
+	}
+	return conf.AuthCodeURL(state, opts...), nil
+}
+
+func (p *oauth2Provider) Exchange(ctx context.Context, code string, opts ...oauth2.AuthCodeOption) (*Identity, error) {
+	conf, err := p.config(ctx)
+	if err != nil {
+		return nil, err
+	}
+	tok, err := conf.Exchange(ctx, code, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("exchange err: %v", err)
+	}
+	ident, err := p.identity(ctx, conf.Client(ctx, tok), tok)
+	if err != nil {
+		return nil, err
+	}
+	if ident.Subject == "" {
+		return nil, fmt.Errorf("provider returned empty user id")
+	}
+	return ident, nil
+}
+
+func staticConfig(conf *oauth2.Config) func(context.Context) (*oauth2.Config, error) {
+	return func(context.Context) (*oauth2.Config, error) {
+		return conf, nil
+	}
+}
+
+func newConfig(cfg config.OAuthProvider, redirectURL string, endpoint oauth2.Endpoint, scopes []string) *oauth2.Config {
+	if cfg.AuthURL != "" {
+		endpoint.AuthURL = cfg.AuthURL
+	}
+	if cfg.TokenURL != "" {
+		endpoint.TokenURL = cfg.TokenURL
+	}
+	if len(cfg.Scopes) > 0 {
+		scopes = cfg.Scopes
+	}
+	return &oauth2.Config{
+		ClientID:     cfg.ClientID,
+		ClientSecret: cfg.ClientSecret,
+		RedirectURL:  redirectURL,
+		Endpoint:     endpoint,
+		Scopes:       scopes,
+	}
+}
+
+func getJSON(ctx context.Context, client *http.Client, addr string, out interface{}) error {
+	req, err := http.NewRequest(http.MethodGet, addr, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	resp, err := client.Do(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s: status %d", addr, resp.StatusCode)
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+func apiURL(cfg config.OAuthProvider, def string) string {
+	if cfg.APIURL != "" {
+		return strings.TrimRight(cfg.APIURL, "/")
+	}
+	return def
+}
+
+// vk отдаёт user_id и email прямо в ответе на обмен кода, имя берём из users.get
+// подтверждён ли email, vk не сообщает - считаем, что нет
+func newVK(cfg config.OAuthProvider, redirectURL string) Provider {
+	conf := newConfig(cfg, redirectURL, vk.Endpoint, []string{"email"})
+	api := apiURL(cfg, vkAPIURL)
+	return &oauth2Provider{
+		config: staticConfig(conf),
+		identity: func(ctx context.Context, client *http.Client, tok *oauth2.Token) (*Identity, error) {
+			uid, ok := tok.Extra("user_id").(float64)
+			if !ok {
+				return nil, fmt.Errorf("vk: no user_id in token, got %T", tok.Extra("user_id"))
+			}
+			email, _ := tok.Extra("email").(string)
+
+			resp := struct {
+				Response []struct {
+					FirstName string `json:"first_name"`
+					LastName  string `json:"last_name"`
+				}
+			}{}
+			addr := api + "/users.get?v=5.131&access_token=" + url.QueryEscape(tok.AccessToken)
+			err := getJSON(ctx, client, addr, &resp)
+			if err != nil {
+				return nil, fmt.Errorf("vk users.get err: %v", err)
+			}
+			ident := &Identity{
+				Subject: strconv.FormatInt(int64(uid), 10),
+				Email:   email,
+			}
+			if len(resp.Response) > 0 {
+				ident.Name = strings.TrimSpace(resp.Response[0].FirstName + " " + resp.Response[0].LastName)
+			}
+			return ident, nil
+		},
+	}
+}
+
+// github - email может быть скрыт в профиле, тогда берём основной подтверждённый из /user/emails
+// verified есть только там же, поэтому email из профиля тоже ищем в этом списке
+func newGitHub(cfg config.OAuthProvider, redirectURL string) Provider {
+	conf := newConfig(cfg, redirectURL, github.Endpoint, []string{"read:user", "user:email"})
+	api := apiURL(cfg, githubAPIURL)
+	return &oauth2Provider{
+		config: staticConfig(conf),
+		identity: func(ctx context.Context, client *http.Client, tok *oauth2.Token) (*Identity, error) {
+			u := struct {
+				ID    int64  `json:"id"`
+				Login string `json:"login"`
+				Name  string `json:"name"`
+				Email string `json:"email"`
+			}{}
+			err := getJSON(ctx, client, api+"/user", &u)
+			if err != nil {
+				return nil, fmt.Errorf("github user err: %v", err)
+			}
+			ident := &Identity{
+				Login: u.Login,
+				Email: u.Email,
+				Name:  u.Name,
+			}
+			if u.ID != 0 {
+				ident.Subject = strconv.FormatInt(u.ID, 10)
+			}
+
+			emails := []struct {
+				Email    string `json:"email"`
+				Primary  bool   `json:"primary"`
+				Verified bool   `json:"verified"`
+			}{}
+			err = getJSON(ctx, client, api+"/user/emails", &emails)
+			if err != nil && ident.Email != "" {
+				// без scope user:email списка нет, email из профиля остаётся неподтверждённым
+				return ident, nil
+			}
+			if err != nil {
+				return nil, fmt.Errorf("github emails err: %v", err)
+			}
+			for _, e := range emails {
+				switch {
+				case ident.Email != "" && e.Email == ident.Email:
+					ident.Verified = e.Verified
+				case ident.Email == "" && e.Primary && e.Verified:
+					ident.Email = e.Email
+					ident.Verified = true
+				}
+			}
+			return ident, nil
+		},
+	}
+}
+
+type oidcDiscovery struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	UserinfoEndpoint      string `json:"userinfo_endpoint"`
+}
+
+// oidcProvider - адреса узнаём из discovery при первом обращении, пользователя - из userinfo
+// id_token не разбираем: userinfo с access-токеном по https даёт то же самое без jwks
+type oidcProvider struct {
+	oauth2Provider
+	cfg         config.OAuthProvider
+	redirectURL string
+
+	mu       sync.Mutex
+	conf     *oauth2.Config
+	userinfo string
+}
+
+func newOIDC(cfg config.OAuthProvider, redirectURL string) Provider {
+	p := &oidcProvider{
+		cfg:         cfg,
+		redirectURL: redirectURL,
+	}
+	p.oauth2Provider.config = p.discover
+	p.oauth2Provider.identity = p.identity
+	return p
+}
+
+func (p *oidcProvider) discover(ctx context.Context) (*oauth2.Config, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.conf != nil {
+		return p.conf, nil
+	}
+
+	issuer := strings.TrimRight(p.cfg.Issuer, "/")
+	doc := &oidcDiscovery{}
+	err := getJSON(ctx, http.DefaultClient, issuer+"/.well-known/openid-configuration", doc)
+	if err != nil {
+		return nil, fmt.Errorf("oidc discovery err: %v", err)
+	}
+	// по спецификации issuer в документе обязан совпадать с тем, у кого спрашивали
+	if strings.TrimRight(doc.Issuer, "/") != issuer {
+		return nil, fmt.Errorf("oidc discovery: issuer mismatch %q", doc.Issuer)
+	}
+	if doc.AuthorizationEndpoint == "" || doc.TokenEndpoint == "" || doc.UserinfoEndpoint == "" {
+		return nil, fmt.Errorf("oidc discovery: missing endpoints")
+	}
+
+	endpoint := oauth2.Endpoint{
+		AuthURL:  doc.AuthorizationEndpoint,
+		TokenURL: doc.TokenEndpoint,
+	}
+	p.conf = newConfig(p.cfg, p.redirectURL, endpoint, []string{"openid", "email", "profile"})
+	p.userinfo = apiURL(p.cfg, doc.UserinfoEndpoint)
+	return p.conf, nil
+}
+
+func (p *oidcProvider) identity(ctx context.Context, client *http.Client, tok *oauth2.Token) (*Identity, error) {
+	info := struct {
+		Sub               string `json:"sub"`
+		Email             string `json:"email"`
+		EmailVerified     *bool  `json:"email_verified"`
+		Name              string `json:"name"`
+		PreferredUsername string `json:"preferred_username"`
+	}{}
+	p.mu.Lock()
+	addr := p.userinfo
+	p.mu.Unlock()
+	err := getJSON(ctx, client, addr, &info)
+	if err != nil {
+		return nil, fmt.Errorf("oidc userinfo err: %v", err)
+	}
+	ident := &Identity{
+		Subject: info.Sub,
+		Login:   info.PreferredUsername,
+		Name:    info.Name,
+	}
+	// явно неподтверждённый email не сохраняем, а без email_verified - сохраняем, но не верим
+	if info.EmailVerified == nil || *info.EmailVerified {
+		ident.Email = info.Email
+	}
+	ident.Verified = ident.Email != "" && info.EmailVerified != nil && *info.EmailVerified
+	return ident, nil
+}
@@ -0,0 +1,68 @@
+package photos
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	"github.com/gofrs/uuid"
+	"go.uber.org/zap"
+
+	"photolist/pkg/blobstorage"
+	"photolist/pkg/logging"
+)
+
+// AvatarPreset - пресет, по которому User.Avatar() отдаёт картинку
+const AvatarPreset = "avatar"
+
+type AvatarRepo interface {
+	SetAvatar(ctx context.Context, userID uint32, name, format string) (string, error)
+}
+
+// AvatarLookup - по имени аватарки находит владельца и формат оригинала
+type AvatarLookup interface {
+	GetAvatar(ctx context.Context, name string) (uint32, string, error)
+}
+
+// Avatars - загрузка аватарок через тот же конвейер, что и у фото
+// картинки маленькие, поэтому превьюшки режутся сразу, без очереди
+type Avatars struct {
+	Repo    AvatarRepo
+	Storage blobstorage.Storage
+	Presets []*Preset
+}
+
+// Upload сохраняет новую аватарку пользователя и удаляет старую, возвращает имя новой
+func (a *Avatars) Upload(ctx context.Context, userID uint32, rawData []byte) (string, error) {
+	imgData, imgInfo, err := PrepareImage(rawData)
+	if err != nil {
+		return "", err
+	}
+
+	avatarUUID, _ := uuid.NewV4()
+	name := avatarUUID.String()
+
+	err = a.Storage.Put(ctx, bytes.NewReader(imgData), name+imgInfo.Format.Ext(),
+		imgInfo.Format.ContentType(), userID)
+	if err != nil {
+		return "", fmt.Errorf("cant save file: %w", err)
+	}
+	err = MakeThumbnails(ctx, a.Storage, bytes.NewReader(imgData), name, imgInfo.Format, userID, a.Presets)
+	if err != nil {
+		RemoveImages(ctx, a.Storage, name)
+		return "", fmt.Errorf("cant make thumbnails: %w", err)
+	}
+
+	old, err := a.Repo.SetAvatar(ctx, userID, name, string(imgInfo.Format))
+	if err != nil {
+		RemoveImages(ctx, a.Storage, name)
+		return "", err
+	}
+	if old != "" {
+		// аватарка уже заменена, мусор в хранилище не повод для ошибки
+		if err := RemoveImages(ctx, a.Storage, old); err != nil {
+			logging.FromContext(ctx).Error("RemoveImages err", zap.String("url", old), zap.Error(err))
+		}
+	}
+	return name, nil
+}
@@ -0,0 +1,251 @@
+package photos
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+)
+
+// разбор exif ровно настолько, чтобы достать ориентацию и затереть gps
+// https://www.media.mit.edu/pia/Research/deepview/exif.html
+
+const (
+	exifTagOrientation = 0x0112
+	exifTagGPSIFD      = 0x8825
+)
+
+var (
+	errBadExif = errors.New("bad exif")
+
+	exifHeader = []byte("Exif\x00\x00")
+
+	// размер одного значения для каждого типа tiff-поля
+	exifTypeSize = map[uint16]uint32{
+		1: 1, 2: 1, 3: 2, 4: 4, 5: 8, 6: 1, 7: 1, 8: 2, 9: 4, 10: 8, 11: 4, 12: 8,
+	}
+)
+
+// errMalformed - магические байты на месте, но структура файла битая
+// для клиента это такой же неподдерживаемый файл
+func errMalformed(format Format) error {
+	return &UnsupportedFormatError{Detected: "malformed " + format.ContentType()}
+}
+
+// stripGPS возвращает копию картинки без gps-данных и exif-ориентацию (0 если её нет)
+// если exif не получилось разобрать - он выкидывается целиком
+func stripGPS(data []byte, format Format) ([]byte, int, error) {
+	switch format {
+	case FormatJPEG:
+		return stripGPSJPEG(data)
+	case FormatPNG:
+		return stripGPSPNG(data)
+	case FormatWebP:
+		return stripGPSWebP(data)
+	}
+	// в gif exif не бывает
+	return data, 0, nil
+}
+
+func stripGPSJPEG(data []byte) ([]byte, int, error) {
+	out := make([]byte, 0, len(data))
+	out = append(out, data[:2]...)
+	orientation := 0
+	i := 2
+	for i+4 <= len(data) {
+		if data[i] != 0xFF {
+			return nil, 0, errMalformed(FormatJPEG)
+		}
+		marker := data[i+1]
+		// дальше идут сами данные картинки, exif всегда раньше
+		if marker == 0xDA || marker == 0xD9 {
+			break
+		}
+		// байт-заполнитель перед маркером
+		if marker == 0xFF {
+			out = append(out, 0xFF)
+			i++
+			continue
+		}
+		if marker == 0x01 || (marker >= 0xD0 && marker <= 0xD7) {
+			out = append(out, data[i:i+2]...)
+			i += 2
+			continue
+		}
+		// длина включает сами 2 байта длины, меньше 2 не бывает
+		length := int(binary.BigEndian.Uint16(data[i+2 : i+4]))
+		end := i + 2 + length
+		if length < 2 || end > len(data) {
+			return nil, 0, errMalformed(FormatJPEG)
+		}
+		segment := data[i:end]
+		i = end
+
+		if marker == 0xE1 && bytes.HasPrefix(segment[4:], exifHeader) {
+			segment = append([]byte(nil), segment...)
+			o, err := scrubExif(segment[4+len(exifHeader):])
+			if err != nil {
+				continue
+			}
+			orientation = o
+		}
+		out = append(out, segment...)
+	}
+	out = append(out, data[i:]...)
+	return out, orientation, nil
+}
+
+func stripGPSPNG(data []byte) ([]byte, int, error) {
+	out := make([]byte, 0, len(data))
+	out = append(out, data[:8]...)
+	orientation := 0
+	i := 8
+	for i+12 <= len(data) {
+		end := i + 12 + int(binary.BigEndian.Uint32(data[i:i+4]))
+		if end > len(data) || end < i {
+			return nil, 0, errMalformed(FormatPNG)
+		}
+		chunk := data[i:end]
+		i = end
+
+		if string(chunk[4:8]) == "eXIf" {
+			chunk = append([]byte(nil), chunk...)
+			o, err := scrubExif(chunk[8 : len(chunk)-4])
+			if err != nil {
+				continue
+			}
+			orientation = o
+			binary.BigEndian.PutUint32(chunk[len(chunk)-4:], crc32.ChecksumIEEE(chunk[4:len(chunk)-4]))
+		}
+		out = append(out, chunk...)
+	}
+	out = append(out, data[i:]...)
+	return out, orientation, nil
+}
+
+func stripGPSWebP(data []byte) ([]byte, int, error) {
+	out := make([]byte, 0, len(data))
+	out = append(out, data[:12]...)
+	orientation := 0
+	droppedExif := false
+	i := 12
+	for i+8 <= len(data) {
+		size := int(binary.LittleEndian.Uint32(data[i+4 : i+8]))
+		end := i + 8 + size + size%2
+		if end > len(data) || end < i {
+			return nil, 0, errMalformed(FormatWebP)
+		}
+		chunk := data[i:end]
+		i = end
+
+		if string(chunk[0:4]) == "EXIF" {
+			chunk = append([]byte(nil), chunk...)
+			tiff := chunk[8 : 8+size]
+			if bytes.HasPrefix(tiff, exifHeader) {
+				tiff = tiff[len(exifHeader):]
+			}
+			o, err := scrubExif(tiff)
+			if err != nil {
+				droppedExif = true
+				continue
+			}
+			orientation = o
+		}
+		out = append(out, chunk...)
+	}
+	out = append(out, data[i:]...)
+
+	binary.LittleEndian.PutUint32(out[4:8], uint32(len(out)-8))
+	if droppedExif && len(out) >= 21 && string(out[12:16]) == "VP8X" {
+		out[20] &^= 0x08 // флаг "есть exif"
+	}
+	return out, orientation, nil
+}
+
+// scrubExif работает с tiff-структурой на месте: обнуляет gps IFD и возвращает ориентацию
+func scrubExif(tiff []byte) (int, error) {
+	if len(tiff) < 8 {
+		return 0, errBadExif
+	}
+	var order binary.ByteOrder
+	switch string(tiff[0:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return 0, errBadExif
+	}
+	if order.Uint16(tiff[2:4]) != 42 {
+		return 0, errBadExif
+	}
+
+	ifd0 := order.Uint32(tiff[4:8])
+	entries, err := exifEntries(tiff, ifd0, order)
+	if err != nil {
+		return 0, err
+	}
+
+	orientation := 0
+	for _, e := range entries {
+		switch order.Uint16(tiff[e : e+2]) {
+		case exifTagOrientation:
+			orientation = int(order.Uint16(tiff[e+8 : e+10]))
+		case exifTagGPSIFD:
+			err = wipeIFD(tiff, order.Uint32(tiff[e+8:e+12]), order)
+			if err != nil {
+				return 0, err
+			}
+		}
+	}
+	return orientation, nil
+}
+
+// exifEntries возвращает смещения всех 12-байтных записей IFD
+func exifEntries(tiff []byte, offset uint32, order binary.ByteOrder) ([]uint32, error) {
+	if uint64(offset)+2 > uint64(len(tiff)) {
+		return nil, errBadExif
+	}
+	n := uint32(order.Uint16(tiff[offset : offset+2]))
+	if uint64(offset)+2+uint64(n)*12 > uint64(len(tiff)) {
+		return nil, errBadExif
+	}
+	res := make([]uint32, n)
+	for k := uint32(0); k < n; k++ {
+		res[k] = offset + 2 + k*12
+	}
+	return res, nil
+}
+
+// wipeIFD затирает все значения IFD и делает его пустым
+func wipeIFD(tiff []byte, offset uint32, order binary.ByteOrder) error {
+	entries, err := exifEntries(tiff, offset, order)
+	if err != nil {
+		return err
+	}
+	for _, e := range entries {
+		typeSize, ok := exifTypeSize[order.Uint16(tiff[e+2:e+4])]
+		size := uint64(typeSize) * uint64(order.Uint32(tiff[e+4:e+8]))
+		// значения больше 4 байт лежат отдельно, в записи только смещение
+		if ok && size > 4 {
+			valOffset := uint64(order.Uint32(tiff[e+8 : e+12]))
+			if valOffset+size > uint64(len(tiff)) {
+				return errBadExif
+			}
+			zero(tiff[valOffset : valOffset+size])
+		}
+		zero(tiff[e : e+12])
+	}
+	// 0 записей, а следующим словом идут нули - значит и следующего IFD нет
+	order.PutUint16(tiff[offset:offset+2], 0)
+	if len(entries) == 0 && uint64(offset)+6 <= uint64(len(tiff)) {
+		zero(tiff[offset+2 : offset+6])
+	}
+	return nil
+}
+
+func zero(b []byte) {
+	for i := range b {
+		b[i] = 0
+	}
+}
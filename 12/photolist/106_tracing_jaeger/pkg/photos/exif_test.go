@@ -0,0 +1,151 @@
+package photos
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"testing"
+)
+
+// gpsMarker - чем заполнены координаты, после очистки таких байт остаться не должно
+const gpsMarker = 0xAB
+
+// testTiff - IFD0 с ориентацией 6 и ссылкой на GPS IFD с одной широтой
+func testTiff() []byte {
+	le := binary.LittleEndian
+	tiff := make([]byte, 80)
+	copy(tiff, "II")
+	le.PutUint16(tiff[2:], 42)
+	le.PutUint32(tiff[4:], 8)
+
+	// IFD0: 2 записи с 8 по 38
+	le.PutUint16(tiff[8:], 2)
+	le.PutUint16(tiff[10:], exifTagOrientation)
+	le.PutUint16(tiff[12:], 3)
+	le.PutUint32(tiff[14:], 1)
+	le.PutUint16(tiff[18:], 6)
+	le.PutUint16(tiff[22:], exifTagGPSIFD)
+	le.PutUint16(tiff[24:], 4)
+	le.PutUint32(tiff[26:], 1)
+	le.PutUint32(tiff[30:], 38)
+
+	// GPS IFD: GPSLatitude, 3 rational = 24 байта по смещению 56
+	le.PutUint16(tiff[38:], 1)
+	le.PutUint16(tiff[40:], 2)
+	le.PutUint16(tiff[42:], 5)
+	le.PutUint32(tiff[44:], 3)
+	le.PutUint32(tiff[48:], 56)
+	for i := 56; i < 80; i++ {
+		tiff[i] = gpsMarker
+	}
+	return tiff
+}
+
+func testJPEG(tiff []byte) []byte {
+	app1 := append(append([]byte(nil), exifHeader...), tiff...)
+	data := []byte{0xFF, 0xD8, 0xFF, 0xE1, 0, 0}
+	binary.BigEndian.PutUint16(data[4:], uint16(len(app1)+2))
+	data = append(data, app1...)
+	return append(data, 0xFF, 0xDA, 1, 2, 3, 0xFF, 0xD9)
+}
+
+func testPNG(tiff []byte) []byte {
+	data := []byte("\x89PNG\r\n\x1a\n")
+	chunk := make([]byte, 8, 12+len(tiff))
+	binary.BigEndian.PutUint32(chunk, uint32(len(tiff)))
+	copy(chunk[4:], "eXIf")
+	chunk = append(chunk, tiff...)
+	chunk = append(chunk, 0, 0, 0, 0)
+	binary.BigEndian.PutUint32(chunk[len(chunk)-4:], crc32.ChecksumIEEE(chunk[4:len(chunk)-4]))
+	return append(data, chunk...)
+}
+
+func testWebP(tiff []byte) []byte {
+	data := []byte("RIFF\x00\x00\x00\x00WEBP")
+	chunk := make([]byte, 8, 8+len(tiff))
+	copy(chunk, "EXIF")
+	binary.LittleEndian.PutUint32(chunk[4:], uint32(len(tiff)))
+	data = append(data, append(chunk, tiff...)...)
+	binary.LittleEndian.PutUint32(data[4:], uint32(len(data)-8))
+	return data
+}
+
+func TestStripGPS(t *testing.T) {
+	cases := []struct {
+		name   string
+		format Format
+		data   []byte
+	}{
+		{"jpeg", FormatJPEG, testJPEG(testTiff())},
+		{"png", FormatPNG, testPNG(testTiff())},
+		{"webp", FormatWebP, testWebP(testTiff())},
+	}
+	for _, c := range cases {
+		src := append([]byte(nil), c.data...)
+		out, orientation, err := stripGPS(c.data, c.format)
+		if err != nil {
+			t.Errorf("[%s] unexpected err: %s", c.name, err)
+			continue
+		}
+		if orientation != 6 {
+			t.Errorf("[%s] bad orientation: %d, expected 6", c.name, orientation)
+		}
+		if bytes.IndexByte(out, gpsMarker) != -1 {
+			t.Errorf("[%s] gps data left in output", c.name)
+		}
+		if len(out) != len(src) {
+			t.Errorf("[%s] size changed: %d, expected %d", c.name, len(out), len(src))
+		}
+		if !bytes.Equal(c.data, src) {
+			t.Errorf("[%s] input was modified", c.name)
+		}
+	}
+}
+
+func TestStripGPSBadExif(t *testing.T) {
+	// нечитаемый exif выкидывается целиком, сама картинка при этом валидна
+	tiff := testTiff()
+	copy(tiff, "XX")
+	out, orientation, err := stripGPS(testJPEG(tiff), FormatJPEG)
+	if err != nil {
+		t.Fatalf("unexpected err: %s", err)
+	}
+	if orientation != 0 {
+		t.Errorf("bad orientation: %d, expected 0", orientation)
+	}
+	if bytes.Contains(out, exifHeader) {
+		t.Errorf("broken exif was not dropped")
+	}
+}
+
+func TestStripGPSMalformed(t *testing.T) {
+	jpegWithLen := func(length uint16) []byte {
+		data := []byte{0xFF, 0xD8, 0xFF, 0xE1, 0, 0, 'E', 'x', 'i', 'f'}
+		binary.BigEndian.PutUint16(data[4:], length)
+		return data
+	}
+	png := testPNG(testTiff())
+	webp := testWebP(testTiff())
+
+	cases := []struct {
+		name   string
+		format Format
+		data   []byte
+	}{
+		{"jpeg zero length", FormatJPEG, jpegWithLen(0)},
+		{"jpeg length 1", FormatJPEG, jpegWithLen(1)},
+		{"jpeg length past end", FormatJPEG, jpegWithLen(200)},
+		{"jpeg truncated", FormatJPEG, testJPEG(testTiff())[:40]},
+		{"jpeg bad marker", FormatJPEG, []byte{0xFF, 0xD8, 0x00, 0xE1, 0, 4, 0, 0}},
+		{"png truncated", FormatPNG, png[:len(png)-10]},
+		{"png huge chunk", FormatPNG, append([]byte("\x89PNG\r\n\x1a\n\xff\xff\xff\xffeXIf"), make([]byte, 8)...)},
+		{"webp truncated", FormatWebP, webp[:len(webp)-10]},
+		{"webp huge chunk", FormatWebP, []byte("RIFF\x00\x00\x00\x00WEBPEXIF\xff\xff\xff\x7f")},
+	}
+	for _, c := range cases {
+		_, _, err := stripGPS(c.data, c.format)
+		if !IsErrUnsupportedFormat(err) {
+			t.Errorf("[%s] expected unsupported format, got %v", c.name, err)
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package photos
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"net/http"
+
+	// регистрируем декодеры для image.Decode / image.DecodeConfig
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+
+	_ "golang.org/x/image/webp"
+)
+
+type Format string
+
+const (
+	FormatJPEG Format = "jpeg"
+	FormatPNG  Format = "png"
+	FormatGIF  Format = "gif"
+	FormatWebP Format = "webp"
+)
+
+// UnsupportedFormatError - загрузили что-то, что не является поддерживаемой картинкой
+type UnsupportedFormatError struct {
+	Detected string // что по мнению http.DetectContentType лежит в файле
+}
+
+func (e *UnsupportedFormatError) Error() string {
+	return fmt.Sprintf("unsupported image format: %s", e.Detected)
+}
+
+func IsErrUnsupportedFormat(err error) bool {
+	_, ok := err.(*UnsupportedFormatError)
+	return ok
+}
+
+// MaxImagePixels - больше декодер съест сотни мегабайт на одну картинку
+const MaxImagePixels = 50 * 1000 * 1000
+
+var errImageTooLarge = errors.New("Image is too large")
+
+func IsErrImageTooLarge(err error) bool {
+	return err == errImageTooLarge
+}
+
+// DetectFormat смотрит на магические байты в начале файла, расширению и content-type от клиента не верим
+func DetectFormat(head []byte) (Format, error) {
+	switch {
+	case bytes.HasPrefix(head, []byte{0xFF, 0xD8, 0xFF}):
+		return FormatJPEG, nil
+	case bytes.HasPrefix(head, []byte("\x89PNG\r\n\x1a\n")):
+		return FormatPNG, nil
+	case bytes.HasPrefix(head, []byte("GIF87a")), bytes.HasPrefix(head, []byte("GIF89a")):
+		return FormatGIF, nil
+	case len(head) >= 12 && bytes.Equal(head[0:4], []byte("RIFF")) && bytes.Equal(head[8:12], []byte("WEBP")):
+		return FormatWebP, nil
+	}
+	return "", &UnsupportedFormatError{Detected: http.DetectContentType(head)}
+}
+
+func (f Format) Ext() string {
+	if f == FormatJPEG {
+		return ".jpg"
+	}
+	return "." + string(f)
+}
+
+func (f Format) ContentType() string {
+	return "image/" + string(f)
+}
+
+// ThumbFormat - в каком формате делать превьюшки
+// webp кодировать нечем, а у gif после ресайза остаётся только первый кадр,
+// поэтому прозрачность сохраняем в png, остальное жмём в jpeg
+func (f Format) ThumbFormat() Format {
+	switch f {
+	case FormatPNG, FormatGIF:
+		return FormatPNG
+	default:
+		return FormatJPEG
+	}
+}
+
+// ImageInfo - то, что узнали о картинке при загрузке
+type ImageInfo struct {
+	Format Format
+	Width  int
+	Height int
+}
+
+// PrepareImage проверяет формат, вычищает gps из exif и считает размеры с учётом ориентации
+// возвращает данные, которые уже можно класть в хранилище
+func PrepareImage(data []byte) ([]byte, *ImageInfo, error) {
+	format, err := DetectFormat(data)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	cleaned, orientation, err := stripGPS(data, format)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// заголовок не читается - файл битый, это ошибка клиента
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(cleaned))
+	if err != nil {
+		return nil, nil, errMalformed(format)
+	}
+	if cfg.Width <= 0 || cfg.Height <= 0 {
+		return nil, nil, errMalformed(format)
+	}
+	if int64(cfg.Width)*int64(cfg.Height) > MaxImagePixels {
+		return nil, nil, errImageTooLarge
+	}
+
+	info := &ImageInfo{
+		Format: format,
+		Width:  cfg.Width,
+		Height: cfg.Height,
+	}
+	// ориентации 5-8 - картинка повёрнута на 90 градусов
+	if orientation >= 5 && orientation <= 8 {
+		info.Width, info.Height = info.Height, info.Width
+	}
+	return cleaned, info, nil
+}
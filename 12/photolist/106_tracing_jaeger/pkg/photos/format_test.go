@@ -0,0 +1,41 @@
+package photos
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"image"
+	"image/png"
+	"testing"
+)
+
+func testPNGSize(width, height uint32) []byte {
+	buf := &bytes.Buffer{}
+	png.Encode(buf, image.NewGray(image.Rect(0, 0, 1, 1)))
+	data := buf.Bytes()
+	// IHDR идёт сразу за сигнатурой: длина, тип, ширина, высота ...
+	binary.BigEndian.PutUint32(data[16:], width)
+	binary.BigEndian.PutUint32(data[20:], height)
+	binary.BigEndian.PutUint32(data[29:], crc32.ChecksumIEEE(data[12:29]))
+	return data
+}
+
+func TestPrepareImage(t *testing.T) {
+	cases := []struct {
+		name    string
+		data    []byte
+		checkFn func(error) bool
+	}{
+		{"ok", testPNGSize(1, 1), func(err error) bool { return err == nil }},
+		{"not an image", []byte("hello, world"), IsErrUnsupportedFormat},
+		{"broken header", []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\x00IEND\xae\x42\x60\x82"), IsErrUnsupportedFormat},
+		{"bad jpeg", []byte{0xFF, 0xD8, 0xFF, 0xDA, 0, 0}, IsErrUnsupportedFormat},
+		{"too large", testPNGSize(10000, 10000), IsErrImageTooLarge},
+	}
+	for _, c := range cases {
+		_, _, err := PrepareImage(c.data)
+		if !c.checkFn(err) {
+			t.Errorf("[%s] unexpected err: %v", c.name, err)
+		}
+	}
+}
@@ -5,36 +5,51 @@ import (
 	// "html/template"
 	"context"
 	// "io"
-	"log"
+	"io/ioutil"
 	"net/http"
 	"strconv"
 	"strings"
 
-	"github.com/gofrs/uuid"
-
+	"photolist/pkg/apierr"
 	"photolist/pkg/blobstorage"
+	"photolist/pkg/logging"
+	"photolist/pkg/notifications"
 	"photolist/pkg/session"
 	"photolist/pkg/user"
 	"photolist/pkg/utils/httputils"
+	"photolist/pkg/utils/pagination"
+
+	"go.uber.org/zap"
 )
 
 type PhotosRepoInterface interface {
-	Add(*Photo) (uint32, error)
-	GetPhotos(uint32, uint32) ([]*Photo, error)
-	Rate(uint32, uint32, int) error
+	Add(context.Context, *Photo) (uint32, error)
+	GetPhotos(context.Context, uint32, uint32, Order, pagination.Page) ([]*Photo, bool, error)
+	GetTimeline(context.Context, uint32, pagination.Page) ([]*Photo, bool, error)
+	Rate(context.Context, uint32, uint32, int) error
+	Delete(context.Context, uint32, uint32) (*Photo, error)
+	UpdateComment(context.Context, uint32, uint32, string) error
+	SetStatus(context.Context, uint32, Status) error
+	GetByURL(context.Context, string) (*Photo, error)
+	GetByID(context.Context, uint32, uint32) (*Photo, error)
+	SetVisibility(context.Context, uint32, uint32, Visibility) error
+	CanView(context.Context, *Photo, uint32) (bool, error)
 }
 
 // -----------------------------
 
 type Templater interface {
-	Render(context.Context, http.ResponseWriter, string, map[string]interface{})
+	Render(http.ResponseWriter, *http.Request, string, map[string]interface{})
 }
 
 type PhotolistHandler struct {
 	PhotosRepo  PhotosRepoInterface
 	Tmpl        Templater
 	UsersRepo   *user.UserRepository
-	BlobStorage *blobstorage.S3Storage
+	BlobStorage blobstorage.Storage
+	Uploader    *Uploader
+	Avatars     *Avatars
+	Notifier    *notifications.Notifier
 }
 
 func (h *PhotolistHandler) ListREST(w http.ResponseWriter, r *http.Request) {
@@ -47,24 +62,22 @@ func (h *PhotolistHandler) ListGQL(w http.ResponseWriter, r *http.Request) {
 
 func (h *PhotolistHandler) List(w http.ResponseWriter, r *http.Request, tmpl string) {
 	sess, _ := session.SessionFromContext(r.Context())
-	CurrentUser, err := h.UsersRepo.GetByID(sess.UserID)
+	CurrentUser, err := h.UsersRepo.GetByID(r.Context(), sess.UserID)
 	if err != nil {
-		log.Println("GetUserByID error", err)
-		http.Error(w, "db error", http.StatusInternalServerError)
+		httputils.RespError(w, r, apierr.Internal(fmt.Errorf("GetUserByID: %w", err)))
 		return
 	}
 	TargetUser := CurrentUser
 
 	login := strings.Replace(r.URL.Path, "/photos/", "", 1)
 	if login != "" {
-		TargetUser, err = h.UsersRepo.GetByLogin(login)
+		TargetUser, err = h.UsersRepo.GetByLogin(r.Context(), login)
 		if user.IsErrUserNotFound(err) {
-			http.Error(w, "User not found", http.StatusBadRequest)
+			httputils.RespError(w, r, apierr.New(apierr.CodeBadRequest, "User not found"))
 			return
 		}
 		if err != nil {
-			log.Println("GetUserByLogin error", err)
-			http.Error(w, "db error", http.StatusInternalServerError)
+			httputils.RespError(w, r, apierr.Internal(fmt.Errorf("GetUserByLogin: %w", err)))
 			return
 		}
 	}
@@ -73,70 +86,133 @@ func (h *PhotolistHandler) List(w http.ResponseWriter, r *http.Request, tmpl str
 		"CurrentUser": CurrentUser,
 		"TargetUser":  TargetUser,
 	}
-	h.Tmpl.Render(r.Context(), w, tmpl, vars)
+	h.Tmpl.Render(w, r, tmpl, vars)
+}
+
+// readFormFile читает файл из multipart-формы
+// битая форма и отсутствие поля - ошибка клиента, а не сервера
+func readFormFile(r *http.Request, field string) ([]byte, error) {
+	err := r.ParseMultipartForm(5 * 1024 * 1025)
+	if err != nil {
+		return nil, apierr.Wrap(apierr.CodeBadRequest, "no file", fmt.Errorf("cant parse form: %w", err))
+	}
+	uploadData, _, err := r.FormFile(field)
+	if err != nil {
+		return nil, apierr.Wrap(apierr.CodeBadRequest, "no file", fmt.Errorf("cant parse file: %w", err))
+	}
+	defer uploadData.Close()
+
+	rawData, err := ioutil.ReadAll(uploadData)
+	if err != nil {
+		return nil, apierr.Internal(fmt.Errorf("cant read file: %w", err))
+	}
+	return rawData, nil
 }
 
 func (h *PhotolistHandler) UploadAPI(w http.ResponseWriter, r *http.Request) {
 	sess, _ := session.SessionFromContext(r.Context())
 
-	r.ParseMultipartForm(5 * 1024 * 1025)
-	uploadData, _, err := r.FormFile("my_file")
+	rawData, err := readFormFile(r, "my_file")
 	if err != nil {
-		httputils.RespJSONError(w, http.StatusInternalServerError, fmt.Errorf("cant parse file: %v", err), "internal")
+		httputils.RespError(w, r, err)
 		return
 	}
-	defer uploadData.Close()
-
-	photoUUID, _ := uuid.NewV4()
-
-	err = h.BlobStorage.Put(uploadData,
-		photoUUID.String()+".jpg", "image/jpeg",
-		sess.UserID)
+	visibility, err := ParseVisibility(r.FormValue("visibility"))
 	if err != nil {
-		httputils.RespJSONError(w, http.StatusInternalServerError, fmt.Errorf("cant save file: %v", err), "internal")
+		httputils.RespError(w, r, apierr.New(apierr.CodeBadRequest, "bad visibility"))
+		return
+	}
+	ph, err := h.Uploader.Upload(r.Context(), sess.UserID, rawData, r.FormValue("comment"), visibility)
+	if IsErrImageTooLarge(err) {
+		httputils.RespError(w, r, apierr.New(apierr.CodeBadRequest, "image is too large"))
+		return
+	}
+	if IsErrUnsupportedFormat(err) {
+		httputils.RespError(w, r, apierr.Wrap(apierr.CodeBadRequest, "unsupported image format", err))
 		return
 	}
-
-	err = MakeThumbnails(h.BlobStorage,
-		uploadData, photoUUID.String(),
-		sess.UserID)
 	if err != nil {
-		httputils.RespJSONError(w, http.StatusInternalServerError, fmt.Errorf("cant save thumbnails: %v", err), "internal")
+		httputils.RespError(w, r, apierr.Internal(err))
 		return
 	}
 
-	comment := r.FormValue("comment")
-	_, err = h.PhotosRepo.Add(&Photo{
-		UserID:  sess.UserID,
-		URL:     photoUUID.String(),
-		Comment: comment,
+	httputils.RespJSON(w, map[string]interface{}{
+		"status":       "ok",
+		"id":           ph.ID,
+		"photo_status": ph.Status,
 	})
+}
+
+// AvatarAPI меняет аватарку текущего пользователя, файл в поле avatar
+func (h *PhotolistHandler) AvatarAPI(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		httputils.RespError(w, r, apierr.New(apierr.CodeMethodNotAllowed, "POST required"))
+		return
+	}
+	sess, _ := session.SessionFromContext(r.Context())
+
+	rawData, err := readFormFile(r, "avatar")
 	if err != nil {
-		httputils.RespJSONError(w, http.StatusInternalServerError, fmt.Errorf("cant store item: %v", err), "internal")
+		httputils.RespError(w, r, err)
+		return
+	}
+	_, err = h.Avatars.Upload(r.Context(), sess.UserID, rawData)
+	if IsErrImageTooLarge(err) {
+		httputils.RespError(w, r, apierr.New(apierr.CodeBadRequest, "image is too large"))
+		return
+	}
+	if IsErrUnsupportedFormat(err) {
+		httputils.RespError(w, r, apierr.Wrap(apierr.CodeBadRequest, "unsupported image format", err))
+		return
+	}
+	if err != nil {
+		httputils.RespError(w, r, apierr.Internal(err))
 		return
 	}
 
+	u, err := h.UsersRepo.GetByID(r.Context(), sess.UserID)
+	if err != nil {
+		httputils.RespError(w, r, apierr.Internal(fmt.Errorf("db error: %w", err)))
+		return
+	}
 	httputils.RespJSON(w, map[string]interface{}{
 		"status": "ok",
+		"avatar": u.Avatar(),
 	})
 }
 
 func (h *PhotolistHandler) ListAPI(w http.ResponseWriter, r *http.Request) {
 	id, err := strconv.Atoi(r.FormValue("uid"))
 	if err != nil {
-		httputils.RespJSONError(w, http.StatusBadRequest, nil, "bad id")
+		httputils.RespError(w, r, apierr.New(apierr.CodeBadRequest, "bad id"))
 		return
 	}
 
+	page, err := pagination.FromQuery(r.FormValue("after"), r.FormValue("limit"))
+	if err != nil {
+		httputils.RespError(w, r, apierr.New(apierr.CodeBadRequest, "bad page"))
+		return
+	}
+	order := OrderNew
+	if r.FormValue("order") == "top" {
+		order = OrderTop
+	}
+
 	sess, _ := session.SessionFromContext(r.Context())
-	items, err := h.PhotosRepo.GetPhotos(uint32(id), sess.UserID)
+	items, hasNext, err := h.PhotosRepo.GetPhotos(r.Context(), uint32(id), sess.UserID, order, page)
 	if err != nil {
-		httputils.RespJSONError(w, http.StatusInternalServerError, fmt.Errorf("cate get photos: %v", err), "internal")
+		httputils.RespError(w, r, apierr.Internal(fmt.Errorf("cate get photos: %w", err)))
 		return
 	}
 
+	next := ""
+	if hasNext {
+		next = items[len(items)-1].Cursor(order)
+	}
 	httputils.RespJSON(w, map[string]interface{}{
 		"photolist": items,
+		"has_next":  hasNext,
+		"next":      next,
 	})
 }
 
@@ -145,7 +221,7 @@ func (h *PhotolistHandler) RateAPI(w http.ResponseWriter, r *http.Request) {
 
 	id, err := strconv.Atoi(r.FormValue("id"))
 	if err != nil {
-		httputils.RespJSONError(w, http.StatusBadRequest, nil, "bad id")
+		httputils.RespError(w, r, apierr.New(apierr.CodeBadRequest, "bad id"))
 		return
 	}
 	vote := r.FormValue("vote")
@@ -156,13 +232,113 @@ func (h *PhotolistHandler) RateAPI(w http.ResponseWriter, r *http.Request) {
 	case "down":
 		rate = -1
 	default:
-		httputils.RespJSONError(w, http.StatusBadRequest, nil, "bad vote")
+		httputils.RespError(w, r, apierr.New(apierr.CodeBadRequest, "bad vote"))
 		return
 	}
 
-	err = h.PhotosRepo.Rate(uint32(id), sess.UserID, rate)
+	err = h.PhotosRepo.Rate(r.Context(), uint32(id), sess.UserID, rate)
+	if IsErrPhotoNotFound(err) {
+		httputils.RespError(w, r, apierr.New(apierr.CodeNotFound, "no photo"))
+		return
+	}
 	if err != nil {
-		httputils.RespJSONError(w, http.StatusInternalServerError, fmt.Errorf("rate db err: %v", err), "internal")
+		httputils.RespError(w, r, apierr.Internal(fmt.Errorf("rate db: %w", err)))
+		return
+	}
+
+	if rate > 0 {
+		ph, err := h.PhotosRepo.GetByID(r.Context(), uint32(id), sess.UserID)
+		if err != nil {
+			logging.FromContext(r.Context()).Error("GetByID err", zap.Error(err))
+		} else {
+			h.Notifier.Liked(r.Context(), ph.UserID, ph.ID, sess.UserID)
+		}
+	}
+
+	httputils.RespJSON(w, map[string]interface{}{
+		"id": id,
+	})
+}
+
+func (h *PhotolistHandler) DeleteAPI(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		httputils.RespError(w, r, apierr.New(apierr.CodeMethodNotAllowed, "POST required"))
+		return
+	}
+	sess, _ := session.SessionFromContext(r.Context())
+
+	id, err := strconv.Atoi(r.FormValue("id"))
+	if err != nil {
+		httputils.RespError(w, r, apierr.New(apierr.CodeBadRequest, "bad id"))
+		return
+	}
+
+	ph, err := h.PhotosRepo.Delete(r.Context(), uint32(id), sess.UserID)
+	switch {
+	case err == nil:
+		// all is ok
+	case IsErrPhotoNotFound(err):
+		httputils.RespError(w, r, apierr.New(apierr.CodeNotFound, "no photo"))
+		return
+	case IsErrNotOwner(err):
+		httputils.RespError(w, r, apierr.New(apierr.CodeForbidden, "not owner"))
+		return
+	default:
+		httputils.RespError(w, r, apierr.Internal(fmt.Errorf("delete db: %w", err)))
+		return
+	}
+
+	// запись в базе уже удалена, поэтому ошибку хранилища только логируем
+	err = RemoveImages(r.Context(), h.BlobStorage, ph.URL)
+	if err != nil {
+		logging.FromContext(r.Context()).Error("RemoveImages err", zap.String("url", ph.URL), zap.Error(err))
+	}
+
+	httputils.RespJSON(w, map[string]interface{}{
+		"id": id,
+	})
+}
+
+// EditAPI меняет подпись (comment) и/или видимость (visibility) фото, не переданные поля не трогает
+func (h *PhotolistHandler) EditAPI(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		httputils.RespError(w, r, apierr.New(apierr.CodeMethodNotAllowed, "POST required"))
+		return
+	}
+	sess, _ := session.SessionFromContext(r.Context())
+
+	id, err := strconv.Atoi(r.FormValue("id"))
+	if err != nil {
+		httputils.RespError(w, r, apierr.New(apierr.CodeBadRequest, "bad id"))
+		return
+	}
+
+	var visibility Visibility
+	if v := r.FormValue("visibility"); v != "" {
+		visibility, err = ParseVisibility(v)
+		if err != nil {
+			httputils.RespError(w, r, apierr.New(apierr.CodeBadRequest, "bad visibility"))
+			return
+		}
+	}
+
+	if _, ok := r.Form["comment"]; ok {
+		err = h.PhotosRepo.UpdateComment(r.Context(), uint32(id), sess.UserID, r.FormValue("comment"))
+	}
+	if err == nil && visibility != "" {
+		err = h.PhotosRepo.SetVisibility(r.Context(), uint32(id), sess.UserID, visibility)
+	}
+	switch {
+	case err == nil:
+		// all is ok
+	case IsErrPhotoNotFound(err):
+		httputils.RespError(w, r, apierr.New(apierr.CodeNotFound, "no photo"))
+		return
+	case IsErrNotOwner(err):
+		httputils.RespError(w, r, apierr.New(apierr.CodeForbidden, "not owner"))
+		return
+	default:
+		httputils.RespError(w, r, apierr.Internal(fmt.Errorf("edit db: %w", err)))
 		return
 	}
 
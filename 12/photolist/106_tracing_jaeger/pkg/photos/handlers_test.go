@@ -0,0 +1,41 @@
+package photos
+
+import (
+	"bytes"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// без файла загрузка должна отвечать 400, а не 500
+func TestUploadAPINoFile(t *testing.T) {
+	body := &bytes.Buffer{}
+	mw := multipart.NewWriter(body)
+	mw.WriteField("comment", "hi")
+	mw.Close()
+
+	cases := []struct {
+		name        string
+		contentType string
+		body        string
+	}{
+		{"no field", mw.FormDataContentType(), body.String()},
+		{"not multipart", "application/x-www-form-urlencoded", "comment=hi"},
+		{"broken form", "multipart/form-data; boundary=xxx", "--xxx\r\nbroken"},
+	}
+	h := &PhotolistHandler{}
+	for _, c := range cases {
+		req := httptest.NewRequest("POST", "/api/v1/photos/upload", strings.NewReader(c.body))
+		req.Header.Set("Content-Type", c.contentType)
+		w := httptest.NewRecorder()
+		h.UploadAPI(w, req)
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("[%s] expected 400, got %d: %s", c.name, w.Code, w.Body.String())
+		}
+		if !strings.Contains(w.Body.String(), "no file") {
+			t.Errorf("[%s] unexpected body: %s", c.name, w.Body.String())
+		}
+	}
+}
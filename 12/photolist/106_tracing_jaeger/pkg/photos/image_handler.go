@@ -0,0 +1,216 @@
+package photos
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"photolist/pkg/apierr"
+	"photolist/pkg/blobstorage"
+	"photolist/pkg/session"
+	"photolist/pkg/user"
+	"photolist/pkg/utils/httputils"
+)
+
+// ImageHandler отдаёт превьюшки по /img/{uuid}/{preset}
+// если варианта ещё нет - режет его из оригинала и кладёт в хранилище
+type ImageHandler struct {
+	PhotosRepo PhotosRepoInterface
+	Avatars    AvatarLookup
+	Storage    blobstorage.Storage
+	Presets    Presets
+	MaxAge     int // секунд для Cache-Control
+
+	mu       sync.Mutex
+	inflight map[string]*thumbCall
+}
+
+// thumbCall - одна нарезка варианта, ждущие получают её ошибку
+type thumbCall struct {
+	wg  sync.WaitGroup
+	err error
+}
+
+func (h *ImageHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	params := strings.Split(strings.TrimPrefix(r.URL.Path, "/img/"), "/")
+	if len(params) != 2 {
+		httputils.RespError(w, r, apierr.New(apierr.CodeNotFound, "Not found"))
+		return
+	}
+	preset, ok := h.Presets[params[1]]
+	if !ok {
+		httputils.RespError(w, r, apierr.New(apierr.CodeNotFound, "Unknown preset"))
+		return
+	}
+
+	ph, ok := h.visible(w, r, params[0])
+	if !ok {
+		return
+	}
+
+	name := ThumbName(ph.URL, ph.Format, preset)
+	info, err := h.Storage.Stat(r.Context(), name)
+	if err == blobstorage.ErrNotFound {
+		err = h.generate(r.Context(), ph, preset, name)
+		if err == nil {
+			info, err = h.Storage.Stat(r.Context(), name)
+		}
+	}
+	if err != nil {
+		httputils.RespError(w, r, apierr.Internal(fmt.Errorf("thumb %s: %w", name, err)))
+		return
+	}
+
+	etag := `"` + name + "-" + strconv.FormatInt(info.ModTime.Unix(), 10) + "-" + strconv.FormatInt(info.Size, 10) + `"`
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Cache-Control", "private, max-age="+strconv.Itoa(h.MaxAge))
+	if r.Header.Get("If-None-Match") == etag {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	body, info, err := h.Storage.Get(r.Context(), name)
+	if err != nil {
+		httputils.RespError(w, r, apierr.Internal(fmt.Errorf("get %s: %w", name, err)))
+		return
+	}
+	defer body.Close()
+	w.Header().Set("Content-Type", ph.Format.ThumbFormat().ContentType())
+	w.Header().Set("Content-Length", strconv.FormatInt(info.Size, 10))
+	io.Copy(w, body)
+}
+
+// visible находит фото или аватарку и проверяет, что текущий пользователь может её видеть
+// если нет - ответ уже отправлен; о закрытых фото не говорим даже то, что они существуют
+func (h *ImageHandler) visible(w http.ResponseWriter, r *http.Request, name string) (*Photo, bool) {
+	ph, err := h.lookup(r.Context(), name)
+	if IsErrPhotoNotFound(err) {
+		httputils.RespError(w, r, apierr.New(apierr.CodeNotFound, "Not found"))
+		return nil, false
+	}
+	if err != nil {
+		httputils.RespError(w, r, apierr.Internal(fmt.Errorf("GetByURL: %w", err)))
+		return nil, false
+	}
+	// AuthMiddleware пускает сюда и без сессии - тогда смотрим как аноним
+	sess, _ := session.SessionFromContext(r.Context())
+	viewerID := uint32(0)
+	if sess != nil {
+		viewerID = sess.UserID
+	}
+	allowed, err := h.PhotosRepo.CanView(r.Context(), ph, viewerID)
+	if err != nil {
+		httputils.RespError(w, r, apierr.Internal(fmt.Errorf("CanView: %w", err)))
+		return nil, false
+	}
+	if !allowed {
+		httputils.RespError(w, r, apierr.New(apierr.CodeNotFound, "Not found"))
+		return nil, false
+	}
+	return ph, true
+}
+
+// ObjectHandler отдаёт объекты хранилища как есть: {Prefix}{uuid}[_preset].ext
+// так у fs и memory открываются старые ссылки под storage.url_prefix, права те же, что у /img/
+type ObjectHandler struct {
+	Images *ImageHandler
+	Prefix string
+}
+
+func (oh *ObjectHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	name := strings.TrimPrefix(r.URL.Path, oh.Prefix)
+	if name == "" || strings.Contains(name, "/") {
+		httputils.RespError(w, r, apierr.New(apierr.CodeNotFound, "Not found"))
+		return
+	}
+	base := name
+	if idx := strings.IndexAny(name, "_."); idx != -1 {
+		base = name[:idx]
+	}
+	if _, ok := oh.Images.visible(w, r, base); !ok {
+		return
+	}
+
+	body, info, err := oh.Images.Storage.Get(r.Context(), name)
+	if err == blobstorage.ErrNotFound {
+		httputils.RespError(w, r, apierr.New(apierr.CodeNotFound, "Not found"))
+		return
+	}
+	if err != nil {
+		httputils.RespError(w, r, apierr.Internal(fmt.Errorf("get %s: %w", name, err)))
+		return
+	}
+	defer body.Close()
+	w.Header().Set("Cache-Control", "private, max-age="+strconv.Itoa(oh.Images.MaxAge))
+	w.Header().Set("Content-Type", info.ContentType)
+	w.Header().Set("Content-Length", strconv.FormatInt(info.Size, 10))
+	io.Copy(w, body)
+}
+
+// lookup ищет картинку среди фото, а потом среди аватарок
+func (h *ImageHandler) lookup(ctx context.Context, name string) (*Photo, error) {
+	ph, err := h.PhotosRepo.GetByURL(ctx, name)
+	if !IsErrPhotoNotFound(err) || h.Avatars == nil {
+		return ph, err
+	}
+	userID, format, err := h.Avatars.GetAvatar(ctx, name)
+	if user.IsErrUserNotFound(err) {
+		return nil, errPhotoNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	// аватарки видны всем
+	return &Photo{
+		UserID:     userID,
+		URL:        name,
+		Format:     Format(format),
+		Visibility: VisibilityPublic,
+	}, nil
+}
+
+// generate режет вариант; параллельные запросы одного и того же варианта ждут первый
+func (h *ImageHandler) generate(ctx context.Context, ph *Photo, preset *Preset, name string) error {
+	h.mu.Lock()
+	if h.inflight == nil {
+		h.inflight = make(map[string]*thumbCall)
+	}
+	if call, ok := h.inflight[name]; ok {
+		h.mu.Unlock()
+		call.wg.Wait()
+		return call.err
+	}
+	call := &thumbCall{}
+	call.wg.Add(1)
+	h.inflight[name] = call
+	h.mu.Unlock()
+
+	defer func() {
+		h.mu.Lock()
+		delete(h.inflight, name)
+		h.mu.Unlock()
+		call.wg.Done()
+	}()
+
+	call.err = h.makeThumb(ctx, ph, preset)
+	return call.err
+}
+
+func (h *ImageHandler) makeThumb(ctx context.Context, ph *Photo, preset *Preset) error {
+	original, _, err := h.Storage.Get(ctx, ph.URL+ph.Format.Ext())
+	if err != nil {
+		return err
+	}
+	data, err := ioutil.ReadAll(original)
+	original.Close()
+	if err != nil {
+		return err
+	}
+	return MakeThumbnails(ctx, h.Storage, bytes.NewReader(data), ph.URL, ph.Format, ph.UserID, []*Preset{preset})
+}
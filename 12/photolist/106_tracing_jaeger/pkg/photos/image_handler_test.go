@@ -0,0 +1,181 @@
+package photos
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	sqlmock "gopkg.in/DATA-DOG/go-sqlmock.v1"
+
+	"photolist/pkg/blobstorage"
+	"photolist/pkg/session"
+)
+
+func TestObjectHandler(t *testing.T) {
+	columns := []string{"id", "user_id", "path", "format", "status", "visibility", "hidden"}
+	st := blobstorage.NewMemoryStorage()
+	st.Put(context.Background(), bytes.NewReader([]byte("original")), "pub.jpg", "image/jpeg", ownerID)
+	st.Put(context.Background(), bytes.NewReader([]byte("thumb")), "pub_feed.jpg", "image/jpeg", ownerID)
+	st.Put(context.Background(), bytes.NewReader([]byte("secret")), "priv.jpg", "image/jpeg", ownerID)
+
+	cases := []struct {
+		name       string
+		path       string
+		viewer     uint32 // 0 - аноним
+		visibility string // "" - до базы не доходит, "none" - фото не найдено
+		status     int
+		body       string
+	}{
+		{"anon public", "/images/pub.jpg", 0, "public", http.StatusOK, "original"},
+		{"anon public thumb", "/images/pub_feed.jpg", 0, "public", http.StatusOK, "thumb"},
+		{"anon private", "/images/priv.jpg", 0, "private", http.StatusNotFound, ""},
+		{"stranger private", "/images/priv.jpg", strangerID, "private", http.StatusNotFound, ""},
+		{"owner private", "/images/priv.jpg", ownerID, "private", http.StatusOK, "secret"},
+		{"no object", "/images/pub_full.jpg", 0, "public", http.StatusNotFound, ""},
+		{"unknown photo", "/images/nope.jpg", ownerID, "none", http.StatusNotFound, ""},
+		{"nested path", "/images/1/pub.jpg", 0, "", http.StatusNotFound, ""},
+	}
+	for _, c := range cases {
+		db, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("cant create mock: %s", err)
+		}
+		switch c.visibility {
+		case "":
+		case "none":
+			mock.ExpectQuery(`SELECT (.+) FROM photos WHERE path = \?`).
+				WillReturnRows(sqlmock.NewRows(columns))
+		default:
+			mock.ExpectQuery(`SELECT (.+) FROM photos WHERE path = \?`).
+				WillReturnRows(sqlmock.NewRows(columns).AddRow(10, ownerID, "x", "jpeg", "ready", c.visibility, 0))
+		}
+		oh := &ObjectHandler{
+			Images: &ImageHandler{PhotosRepo: NewPhotosRepository(db), Storage: st},
+			Prefix: "/images/",
+		}
+
+		req := httptest.NewRequest("GET", c.path, nil)
+		if c.viewer != 0 {
+			req = req.WithContext(session.ContextWithSession(req.Context(), &session.Session{UserID: c.viewer}))
+		}
+		w := httptest.NewRecorder()
+		oh.ServeHTTP(w, req)
+
+		if w.Code != c.status {
+			t.Errorf("[%s] expected status %d, got %d", c.name, c.status, w.Code)
+		}
+		if c.body != "" && w.Body.String() != c.body {
+			t.Errorf("[%s] bad body: %q", c.name, w.Body.String())
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("[%s] there were unfulfilled expectations: %s", c.name, err)
+		}
+		db.Close()
+	}
+}
+
+// fakeSessions - сессия из куки session_id, в ней сразу id пользователя
+type fakeSessions struct {
+	err error
+}
+
+func (fs *fakeSessions) Check(ctx context.Context, r *http.Request) (*session.Session, error) {
+	if fs.err != nil {
+		return nil, fs.err
+	}
+	c, err := r.Cookie("session_id")
+	if err != nil {
+		return nil, session.ErrNoAuth
+	}
+	id, _ := strconv.Atoi(c.Value)
+	return &session.Session{UserID: uint32(id)}, nil
+}
+
+func (fs *fakeSessions) Create(context.Context, http.ResponseWriter, session.UserInterface) error {
+	return nil
+}
+
+func (fs *fakeSessions) DestroyCurrent(context.Context, http.ResponseWriter, *http.Request) error {
+	return nil
+}
+
+func (fs *fakeSessions) DestroyAll(context.Context, http.ResponseWriter, session.UserInterface) error {
+	return nil
+}
+
+// картинки без сессии отдаются как анониму - через настоящий AuthMiddleware, как в main
+func TestImageRoutesAuth(t *testing.T) {
+	columns := []string{"id", "user_id", "path", "format", "status", "visibility", "hidden"}
+	st := blobstorage.NewMemoryStorage()
+	st.Put(context.Background(), bytes.NewReader([]byte("pub thumb")), "pub_feed.jpg", "image/jpeg", ownerID)
+	st.Put(context.Background(), bytes.NewReader([]byte("priv thumb")), "priv_feed.jpg", "image/jpeg", ownerID)
+	st.Put(context.Background(), bytes.NewReader([]byte("original")), "pub.jpg", "image/jpeg", ownerID)
+
+	session.AllowAnonymous("/images/")
+
+	cases := []struct {
+		name       string
+		path       string
+		cookie     string // "" - без сессии
+		checkErr   error
+		visibility string // "" - до базы не доходит
+		status     int
+		body       string
+	}{
+		{"anon public", "/img/pub/feed", "", nil, "public", http.StatusOK, "pub thumb"},
+		{"anon private", "/img/priv/feed", "", nil, "private", http.StatusNotFound, ""},
+		{"owner private", "/img/priv/feed", "1", nil, "private", http.StatusOK, "priv thumb"},
+		{"anon object", "/images/pub.jpg", "", nil, "public", http.StatusOK, "original"},
+		// сломанная сессия - не аноним, а ошибка
+		{"check error", "/img/pub/feed", "1", fmt.Errorf("auth unavailable"), "", http.StatusUnauthorized, ""},
+		// остальные пути без сессии по-прежнему закрыты
+		{"anon api", "/api/v1/photos/list", "", nil, "", http.StatusUnauthorized, ""},
+	}
+	for _, c := range cases {
+		db, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("cant create mock: %s", err)
+		}
+		if c.visibility != "" {
+			name := strings.Split(strings.TrimPrefix(strings.TrimPrefix(c.path, "/img/"), "/images/"), "/")[0]
+			name = strings.TrimSuffix(name, ".jpg")
+			mock.ExpectQuery(`SELECT (.+) FROM photos WHERE path = \?`).
+				WillReturnRows(sqlmock.NewRows(columns).AddRow(10, ownerID, name, "jpeg", "ready", c.visibility, 0))
+		}
+		images := &ImageHandler{
+			PhotosRepo: NewPhotosRepository(db),
+			Storage:    st,
+			Presets:    Presets{"feed": &Preset{Name: "feed", Width: 10, Height: 10, Mode: ModeFill}},
+		}
+		mux := http.NewServeMux()
+		mux.Handle("/img/", images)
+		mux.Handle("/images/", &ObjectHandler{Images: images, Prefix: "/images/"})
+		mux.HandleFunc("/api/v1/photos/list", func(w http.ResponseWriter, r *http.Request) {
+			t.Errorf("[%s] api handler called without session", c.name)
+		})
+		handler := session.AuthMiddleware(&fakeSessions{err: c.checkErr}, mux)
+
+		req := httptest.NewRequest("GET", c.path, nil)
+		if c.cookie != "" {
+			req.AddCookie(&http.Cookie{Name: "session_id", Value: c.cookie})
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+
+		if w.Code != c.status {
+			t.Errorf("[%s] expected status %d, got %d", c.name, c.status, w.Code)
+		}
+		if c.body != "" && w.Body.String() != c.body {
+			t.Errorf("[%s] bad body: %q", c.name, w.Body.String())
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("[%s] there were unfulfilled expectations: %s", c.name, err)
+		}
+		db.Close()
+	}
+}
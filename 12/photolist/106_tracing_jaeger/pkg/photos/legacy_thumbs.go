@@ -0,0 +1,104 @@
+package photos
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"strings"
+
+	"photolist/pkg/blobstorage"
+)
+
+// до пресетов превьюшки назывались по размеру: uuid_32.jpg, uuid_600.jpg
+var legacyThumbPresets = map[string]string{
+	"32":  AvatarPreset,
+	"600": "feed",
+}
+
+// LegacyThumb - превьюшка со старым именем и пресет, которым она заменяется
+type LegacyThumb struct {
+	Name       string // старый объект целиком
+	ObjectName string // имя фото без расширения
+	Original   string // оригинал, "" если его нет
+	Format     Format
+	UserID     uint32
+	Preset     *Preset
+}
+
+// FindLegacyThumbs ищет в хранилище превьюшки со старыми именами
+// пресеты, которых нет в конфиге, пропускаются - резать их всё равно нечем
+func FindLegacyThumbs(ctx context.Context, storage Cleaner, presets Presets) ([]*LegacyThumb, error) {
+	objects, err := storage.List(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+	names := make(map[string]bool, len(objects))
+	for _, obj := range objects {
+		names[obj.Name] = true
+	}
+
+	res := []*LegacyThumb{}
+	for _, obj := range objects {
+		idx := strings.LastIndex(obj.Name, "_")
+		dot := strings.LastIndex(obj.Name, ".")
+		if idx == -1 || dot < idx {
+			continue
+		}
+		preset, ok := presets[legacyThumbPresets[obj.Name[idx+1:dot]]]
+		if !ok {
+			continue
+		}
+		t := &LegacyThumb{
+			Name:       obj.Name,
+			ObjectName: obj.Name[:idx],
+			UserID:     obj.UserID,
+			Preset:     preset,
+		}
+		// формат оригинала по имени не понять, ищем среди соседей
+		for _, f := range []Format{FormatJPEG, FormatPNG, FormatGIF, FormatWebP} {
+			if names[t.ObjectName+f.Ext()] {
+				t.Original = t.ObjectName + f.Ext()
+				t.Format = f
+				break
+			}
+		}
+		if t.Original == "" {
+			t.Format = FormatJPEG
+			if obj.Name[dot:] == FormatPNG.Ext() {
+				t.Format = FormatPNG
+			}
+		}
+		res = append(res, t)
+	}
+	return res, nil
+}
+
+// MigrateLegacyThumb кладёт превьюшку под новым именем и удаляет старую
+// пресет может резать иначе, чем раньше, поэтому при живом оригинале режем заново,
+// а без оригинала переносим старую как есть
+func MigrateLegacyThumb(ctx context.Context, storage blobstorage.Storage, t *LegacyThumb) error {
+	src := t.Name
+	if t.Original != "" {
+		src = t.Original
+	}
+	body, _, err := storage.Get(ctx, src)
+	if err != nil {
+		return err
+	}
+	data, err := ioutil.ReadAll(body)
+	body.Close()
+	if err != nil {
+		return err
+	}
+
+	if t.Original != "" {
+		err = MakeThumbnails(ctx, storage, bytes.NewReader(data), t.ObjectName, t.Format, t.UserID, []*Preset{t.Preset})
+	} else {
+		err = storage.Put(ctx, bytes.NewReader(data), ThumbName(t.ObjectName, t.Format, t.Preset),
+			t.Format.ThumbFormat().ContentType(), t.UserID)
+	}
+	if err != nil {
+		return err
+	}
+	return storage.Delete(ctx, t.Name)
+}
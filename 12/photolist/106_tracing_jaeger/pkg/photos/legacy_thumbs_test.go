@@ -0,0 +1,105 @@
+package photos
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"testing"
+
+	"photolist/pkg/blobstorage"
+)
+
+func TestMigrateLegacyThumbs(t *testing.T) {
+	ctx := context.Background()
+	presets := Presets{
+		AvatarPreset: {Name: AvatarPreset, Width: 32, Height: 32, Mode: ModeFill, Quality: 85},
+		"feed":       {Name: "feed", Width: 600, Height: 600, Mode: ModeFit, Quality: 90},
+	}
+	img := &bytes.Buffer{}
+	jpeg.Encode(img, image.NewGray(image.Rect(0, 0, 64, 64)), nil)
+
+	st := blobstorage.NewMemoryStorage()
+	put := func(name string, data []byte) {
+		st.Put(ctx, bytes.NewReader(data), name, "image/jpeg", ownerID)
+	}
+	// у первого фото есть оригинал, у второго остались только превьюшки
+	put("abc.jpg", img.Bytes())
+	put("abc_32.jpg", []byte("old avatar"))
+	put("abc_600.jpg", []byte("old feed"))
+	put("def_600.jpg", []byte("orphan feed"))
+	// чужие суффиксы не трогаем
+	put("abc_full.jpg", []byte("already migrated"))
+	put("ghi_100.jpg", []byte("unknown size"))
+
+	thumbs, err := FindLegacyThumbs(ctx, st, presets)
+	if err != nil {
+		t.Fatalf("unexpected err: %s", err)
+	}
+	if len(thumbs) != 3 {
+		t.Fatalf("expected 3 legacy thumbs, got %d", len(thumbs))
+	}
+	for _, thumb := range thumbs {
+		if err := MigrateLegacyThumb(ctx, st, thumb); err != nil {
+			t.Errorf("[%s] unexpected err: %s", thumb.Name, err)
+		}
+	}
+
+	cases := []struct {
+		name   string
+		exists bool
+	}{
+		{"abc_32.jpg", false},
+		{"abc_600.jpg", false},
+		{"def_600.jpg", false},
+		{"abc_avatar.jpg", true},
+		{"abc_feed.jpg", true},
+		{"def_feed.jpg", true},
+		{"abc_full.jpg", true},
+		{"ghi_100.jpg", true},
+	}
+	for _, c := range cases {
+		info, err := st.Stat(ctx, c.name)
+		if c.exists != (err == nil) {
+			t.Errorf("[%s] expected exists=%v, got err %v", c.name, c.exists, err)
+			continue
+		}
+		if c.exists && info.UserID != ownerID {
+			t.Errorf("[%s] owner lost: %d", c.name, info.UserID)
+		}
+	}
+
+	// при живом оригинале превьюшка режется заново, без него - переносится как есть
+	body, _, _ := st.Get(ctx, "def_feed.jpg")
+	data := &bytes.Buffer{}
+	data.ReadFrom(body)
+	if data.String() != "orphan feed" {
+		t.Errorf("orphan thumb not moved as is: %q", data.String())
+	}
+	body, _, _ = st.Get(ctx, "abc_avatar.jpg")
+	data.Reset()
+	data.ReadFrom(body)
+	if cfg, _, err := image.DecodeConfig(data); err != nil || cfg.Width != 32 {
+		t.Errorf("avatar not regenerated: %+v %v", cfg, err)
+	}
+}
+
+// все, кто ждал чужую нарезку, получают её ошибку, а не nil
+func TestImageHandlerGenerateError(t *testing.T) {
+	h := &ImageHandler{}
+	call := &thumbCall{}
+	call.wg.Add(1)
+	h.inflight = map[string]*thumbCall{"abc_feed.jpg": call}
+
+	result := make(chan error)
+	go func() {
+		result <- h.generate(context.Background(), &Photo{URL: "abc"}, &Preset{Name: "feed"}, "abc_feed.jpg")
+	}()
+	call.err = fmt.Errorf("cant decode")
+	call.wg.Done()
+
+	if err := <-result; err == nil {
+		t.Errorf("waiter got nil error")
+	}
+}
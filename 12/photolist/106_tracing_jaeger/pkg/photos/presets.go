@@ -0,0 +1,76 @@
+package photos
+
+import (
+	"fmt"
+	"sort"
+
+	"photolist/pkg/config"
+)
+
+const (
+	ModeFill = "fill" // обрезать под точный размер
+	ModeFit  = "fit"  // вписать, сохранив пропорции
+)
+
+// Preset - именованный вариант превьюшки, настраивается в thumbs.presets
+type Preset struct {
+	Name    string
+	Width   int
+	Height  int
+	Mode    string
+	Quality int
+	// Eager - резать сразу после загрузки, остальные делаются по первому запросу в /img/
+	Eager bool
+}
+
+type Presets map[string]*Preset
+
+// NewPresets проверяет пресеты из конфига
+func NewPresets(in map[string]config.ThumbPreset) (Presets, error) {
+	res := make(Presets, len(in))
+	for name, p := range in {
+		if p.Width <= 0 || p.Height <= 0 {
+			return nil, fmt.Errorf("preset %s: bad size %dx%d", name, p.Width, p.Height)
+		}
+		if p.Mode != ModeFill && p.Mode != ModeFit {
+			return nil, fmt.Errorf("preset %s: unknown mode %q", name, p.Mode)
+		}
+		if p.Quality <= 0 || p.Quality > 100 {
+			p.Quality = 95
+		}
+		res[name] = &Preset{
+			Name:    name,
+			Width:   p.Width,
+			Height:  p.Height,
+			Mode:    p.Mode,
+			Quality: p.Quality,
+			Eager:   p.Eager,
+		}
+	}
+	return res, nil
+}
+
+// Eager - пресеты, которые режутся при загрузке, в стабильном порядке
+func (ps Presets) Eager() []*Preset {
+	res := make([]*Preset, 0, len(ps))
+	for _, p := range ps {
+		if p.Eager {
+			res = append(res, p)
+		}
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].Name < res[j].Name
+	})
+	return res
+}
+
+// Only - выбранные пресеты, неизвестные имена пропускаются
+func (ps Presets) Only(names ...string) []*Preset {
+	res := make([]*Preset, 0, len(names))
+	for _, name := range names {
+		if p, ok := ps[name]; ok {
+			res = append(res, p)
+		}
+	}
+	return res
+}
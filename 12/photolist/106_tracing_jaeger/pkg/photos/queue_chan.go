@@ -0,0 +1,98 @@
+package photos
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+var (
+	ErrQueueFull   = errors.New("thumb queue is full")
+	ErrQueueClosed = errors.New("thumb queue is closed")
+)
+
+// ChanQueue - очередь внутри процесса на буферизированном канале
+// задачи не переживают рестарт, для этого есть RabbitQueue
+// канал задач никогда не закрывается - так Publish не может писать в закрытый канал,
+// а о закрытии очереди говорит done
+type ChanQueue struct {
+	jobs    chan *ThumbJob
+	timeout time.Duration
+
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+var _ ThumbQueue = (*ChanQueue)(nil)
+
+func NewChanQueue(size int, publishTimeout time.Duration) *ChanQueue {
+	return &ChanQueue{
+		jobs:    make(chan *ThumbJob, size),
+		timeout: publishTimeout,
+		done:    make(chan struct{}),
+	}
+}
+
+func (q *ChanQueue) Publish(job *ThumbJob) error {
+	select {
+	case <-q.done:
+		return ErrQueueClosed
+	default:
+	}
+
+	timer := time.NewTimer(q.timeout)
+	defer timer.Stop()
+	select {
+	case q.jobs <- job:
+		return nil
+	case <-q.done:
+		return ErrQueueClosed
+	case <-timer.C:
+		return ErrQueueFull
+	}
+}
+
+// Retry - в памяти подождать можно только таймером
+// если к этому времени очередь закрылась, фото останется в processing и его подберёт Requeue при старте
+func (q *ChanQueue) Retry(job *ThumbJob, delay time.Duration) error {
+	select {
+	case <-q.done:
+		return ErrQueueClosed
+	default:
+	}
+	time.AfterFunc(delay, func() {
+		if err := q.Publish(job); err != nil {
+			zap.L().Error("ChanQueue cant retry", zap.Uint32("photo_id", job.PhotoID), zap.Error(err))
+		}
+	})
+	return nil
+}
+
+// Consume после закрытия очереди дорабатывает то, что уже лежит в буфере
+func (q *ChanQueue) Consume(handler func(*ThumbJob) error) error {
+	for {
+		select {
+		case job := <-q.jobs:
+			// повторы делает сам обработчик, тут ошибку отдать некуда
+			handler(job)
+		case <-q.done:
+			for {
+				select {
+				case job := <-q.jobs:
+					handler(job)
+				default:
+					return nil
+				}
+			}
+		}
+	}
+}
+
+func (q *ChanQueue) Close() error {
+	q.closeOnce.Do(func() {
+		close(q.done)
+	})
+	return nil
+}
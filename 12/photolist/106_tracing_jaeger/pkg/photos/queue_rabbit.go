@@ -0,0 +1,148 @@
+package photos
+
+import (
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"github.com/streadway/amqp"
+	"go.uber.org/zap"
+)
+
+const (
+	ThumbQueueName = "photolist_thumbs"
+	// в retry-очереди задачи никто не читает: по истечении expiration
+	// rabbit перекладывает их обратно в основную через dead-letter
+	ThumbRetryQueueName = "photolist_thumbs_retry"
+)
+
+// RabbitQueue - очередь в rabbitmq, задачи переживают рестарт сервиса
+type RabbitQueue struct {
+	conn *amqp.Connection
+	pub  *amqp.Channel
+}
+
+var _ ThumbQueue = (*RabbitQueue)(nil)
+
+func NewRabbitQueue(addr string) (*RabbitQueue, error) {
+	conn, err := amqp.Dial(addr)
+	if err != nil {
+		return nil, err
+	}
+	ch, err := conn.Channel()
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	_, err = ch.QueueDeclare(
+		ThumbQueueName, // name
+		true,           // durable
+		false,          // delete when unused
+		false,          // exclusive
+		false,          // no-wait
+		nil,            // arguments
+	)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	_, err = ch.QueueDeclare(
+		ThumbRetryQueueName, // name
+		true,                // durable
+		false,               // delete when unused
+		false,               // exclusive
+		false,               // no-wait
+		amqp.Table{
+			"x-dead-letter-exchange":    "",
+			"x-dead-letter-routing-key": ThumbQueueName,
+		},
+	)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return &RabbitQueue{
+		conn: conn,
+		pub:  ch,
+	}, nil
+}
+
+func (q *RabbitQueue) Publish(job *ThumbJob) error {
+	return q.publish(ThumbQueueName, job, "")
+}
+
+// Retry кладёт задачу в retry-очередь с expiration, обработчик при этом свободен
+// Consume подтверждает исходное сообщение только после успешной публикации повтора
+func (q *RabbitQueue) Retry(job *ThumbJob, delay time.Duration) error {
+	return q.publish(ThumbRetryQueueName, job, strconv.FormatInt(int64(delay/time.Millisecond), 10))
+}
+
+func (q *RabbitQueue) publish(queue string, job *ThumbJob, expiration string) error {
+	data, err := json.Marshal(job)
+	if err != nil {
+		return err
+	}
+	return q.pub.Publish(
+		"",    // exchange
+		queue, // routing key
+		false, // mandatory
+		false,
+		amqp.Publishing{
+			DeliveryMode: amqp.Persistent,
+			ContentType:  "application/json",
+			Expiration:   expiration,
+			Body:         data,
+		})
+}
+
+// Consume открывает отдельный канал на каждого потребителя, prefetch 1 -
+// так каждый обработчик держит не больше одной задачи
+func (q *RabbitQueue) Consume(handler func(*ThumbJob) error) error {
+	ch, err := q.conn.Channel()
+	if err != nil {
+		return err
+	}
+	defer ch.Close()
+
+	err = ch.Qos(
+		1,     // prefetch count
+		0,     // prefetch size
+		false, // global
+	)
+	if err != nil {
+		return err
+	}
+
+	tasks, err := ch.Consume(
+		ThumbQueueName, // queue
+		"",             // consumer
+		false,          // auto-ack
+		false,          // exclusive
+		false,          // no-local
+		false,          // no-wait
+		nil,            // args
+	)
+	if err != nil {
+		return err
+	}
+
+	for task := range tasks {
+		job := &ThumbJob{}
+		err := json.Unmarshal(task.Body, job)
+		if err != nil {
+			zap.L().Error("RabbitQueue bad task", zap.ByteString("body", task.Body), zap.Error(err))
+			task.Nack(false, false)
+			continue
+		}
+		if err := handler(job); err != nil {
+			task.Nack(false, true)
+			continue
+		}
+		task.Ack(false)
+	}
+	return nil
+}
+
+func (q *RabbitQueue) Close() error {
+	return q.conn.Close()
+}
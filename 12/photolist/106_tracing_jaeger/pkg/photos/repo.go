@@ -1,9 +1,19 @@
 package photos
 
 import (
+	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"strconv"
+
+	"photolist/pkg/utils/dbutils"
+	"photolist/pkg/utils/pagination"
+)
+
+var (
+	errPhotoNotFound = errors.New("No photo found")
+	errNotOwner      = errors.New("Photo belongs to another user")
 )
 
 type Photo struct {
@@ -14,12 +24,49 @@ type Photo struct {
 	Comment string `json:"comment"`
 	Rating  int    `json:"rating"`
 	Liked   bool   `json:"liked"`
+	Format  Format `json:"format"`
+	Width   int    `json:"width"`
+	Height  int    `json:"height"`
+	Status  Status `json:"status"`
+
+	Visibility Visibility `json:"visibility"`
+	// Hidden - скрыто модератором, такое фото видит только владелец
+	Hidden bool `json:"hidden"`
 }
 
+// Status - готовы ли превьюшки
+type Status string
+
+const (
+	StatusProcessing Status = "processing"
+	StatusReady      Status = "ready"
+	StatusFailed     Status = "failed"
+)
+
 func (ph *Photo) Id() string {
 	return strconv.Itoa(int(ph.ID))
 }
 
+// Cursor - непрозрачный курсор на это фото для выбранной сортировки
+func (ph *Photo) Cursor(order Order) string {
+	c := &pagination.Cursor{ID: ph.ID}
+	if order == OrderTop {
+		c.Rating = ph.Rating
+	}
+	return c.Encode()
+}
+
+func (ph *Photo) ThumbExt() string {
+	return ph.Format.ThumbFormat().Ext()
+}
+
+type Order int
+
+const (
+	OrderNew Order = iota // свежие сверху
+	OrderTop              // по рейтингу
+)
+
 type PhotosRepo struct {
 	db *sql.DB
 }
@@ -30,9 +77,15 @@ func NewPhotosRepository(db *sql.DB) *PhotosRepo {
 	}
 }
 
-func (st *PhotosRepo) Add(p *Photo) (uint32, error) {
-	res, err := st.db.Exec("INSERT INTO photos(user_id, path, comment) VALUES(?, ?, ?)",
-		p.UserID, p.URL, p.Comment)
+func (st *PhotosRepo) Add(ctx context.Context, p *Photo) (uint32, error) {
+	if p.Status == "" {
+		p.Status = StatusReady
+	}
+	if p.Visibility == "" {
+		p.Visibility = VisibilityPublic
+	}
+	res, err := st.db.ExecContext(ctx, "INSERT INTO photos(user_id, path, comment, format, width, height, status, visibility) VALUES(?, ?, ?, ?, ?, ?, ?, ?)",
+		p.UserID, p.URL, p.Comment, p.Format, p.Width, p.Height, p.Status, p.Visibility)
 	if err != nil {
 		return 0, err
 	}
@@ -48,31 +101,82 @@ func (st *PhotosRepo) Add(p *Photo) (uint32, error) {
 	return uint32(li), nil
 }
 
-func (st *PhotosRepo) GetByID(photoID, currentUserID uint32) (*Photo, error) {
-	rows := st.db.QueryRow(`SELECT
-		photos.id as id, photos.user_id, path, comment, rating, 
+// GetByID отдаёт фото, если currentUserID может его видеть, иначе - errPhotoNotFound
+func (st *PhotosRepo) GetByID(ctx context.Context, photoID, currentUserID uint32) (*Photo, error) {
+	visible, visibleArgs := VisibleCond(currentUserID)
+	args := append([]interface{}{currentUserID, photoID}, visibleArgs...)
+	rows := st.db.QueryRowContext(ctx, `SELECT
+		photos.id as id, photos.user_id, path, comment, rating, format, width, height, photos.status, photos.visibility, photos.hidden,
 		user_photos_likes.photo_id as is_liked
 	   FROM photos 
 	   LEFT JOIN users ON photos.user_id=users.id
 	   LEFT JOIN user_photos_likes ON user_photos_likes.photo_id=photos.id and user_photos_likes.user_id = ?
-	   WHERE photos.id = ?
-	   ORDER BY id DESC`, currentUserID, photoID)
+	   WHERE photos.id = ? AND `+visible+`
+	   ORDER BY id DESC`, args...)
 	item := &Photo{}
 	var isLiked sql.NullInt64
-	err := rows.Scan(&item.ID, &item.UserID, &item.URL, &item.Comment, &item.Rating, &isLiked)
-	if err != nil {
+	err := rows.Scan(&item.ID, &item.UserID, &item.URL, &item.Comment, &item.Rating,
+		&item.Format, &item.Width, &item.Height, &item.Status, &item.Visibility, &item.Hidden, &isLiked)
+	if err == sql.ErrNoRows {
+		return nil, errPhotoNotFound
+	} else if err != nil {
 		return nil, err
 	}
 	item.Liked = isLiked.Valid
 	return item, nil
 }
 
-func (st *PhotosRepo) GetPhotos(userID, currentUserID uint32) ([]*Photo, error) {
-	photos := make([]*Photo, 0, 10)
+// GetByURL ищет фото по имени объекта в хранилище
+func (st *PhotosRepo) GetByURL(ctx context.Context, url string) (*Photo, error) {
+	item := &Photo{}
+	err := st.db.QueryRowContext(ctx, "SELECT id, user_id, path, format, photos.status, photos.visibility, photos.hidden FROM photos WHERE path = ?", url).
+		Scan(&item.ID, &item.UserID, &item.URL, &item.Format, &item.Status, &item.Visibility, &item.Hidden)
+	if err == sql.ErrNoRows {
+		return nil, errPhotoNotFound
+	} else if err != nil {
+		return nil, err
+	}
+	return item, nil
+}
+
+// GetPhotos возвращает фото пользователя userID, одну страницу
+// второй параметр - есть ли ещё страницы
+func (st *PhotosRepo) GetPhotos(ctx context.Context, userID, currentUserID uint32, order Order, page pagination.Page) ([]*Photo, bool, error) {
+	return st.queryPhotos(ctx, `photos.user_id = ?`, []interface{}{userID}, currentUserID, order, page)
+}
+
+// GetTimeline возвращает ленту - фото самого пользователя и тех, на кого он подписан
+func (st *PhotosRepo) GetTimeline(ctx context.Context, currentUserID uint32, page pagination.Page) ([]*Photo, bool, error) {
+	where := `(photos.user_id = ? OR photos.user_id IN (SELECT follow_id FROM user_follows WHERE user_id = ?))`
+	return st.queryPhotos(ctx, where, []interface{}{currentUserID, currentUserID}, currentUserID, OrderNew, page)
+}
+
+func (st *PhotosRepo) queryPhotos(ctx context.Context, where string, whereArgs []interface{}, currentUserID uint32, order Order, page pagination.Page) ([]*Photo, bool, error) {
+	args := make([]interface{}, 0, len(whereArgs)+8)
+	args = append(args, currentUserID, currentUserID)
+	args = append(args, whereArgs...)
+
+	visible, visibleArgs := VisibleCond(currentUserID)
+	where += " AND " + visible
+	args = append(args, visibleArgs...)
 
-	// TODO add limit, offset
-	rows, err := st.db.Query(`SELECT 
-	photos.id as id, photos.user_id, path, comment, rating, 
+	orderBy := "photos.id DESC"
+	if order == OrderTop {
+		orderBy = "photos.rating DESC, photos.id DESC"
+	}
+	if page.After != nil {
+		if order == OrderTop {
+			where += ` AND (photos.rating < ? OR (photos.rating = ? AND photos.id < ?))`
+			args = append(args, page.After.Rating, page.After.Rating, page.After.ID)
+		} else {
+			where += ` AND photos.id < ?`
+			args = append(args, page.After.ID)
+		}
+	}
+	args = append(args, page.FetchLimit())
+
+	rows, err := st.db.QueryContext(ctx, `SELECT 
+	photos.id as id, photos.user_id, path, comment, rating, format, width, height, photos.status, photos.visibility, photos.hidden,
 		   users.login as user_login, 
 		   user_photos_likes.photo_id as is_liked, 
 		   user_follows.follow_id as is_followed
@@ -80,45 +184,170 @@ func (st *PhotosRepo) GetPhotos(userID, currentUserID uint32) ([]*Photo, error)
 	   LEFT JOIN users ON photos.user_id=users.id
 	   LEFT JOIN user_photos_likes ON user_photos_likes.photo_id=photos.id and user_photos_likes.user_id = ?
 	   LEFT JOIN user_follows ON  user_follows.follow_id=photos.user_id and user_follows.user_id = ?
-	   WHERE photos.user_id = ?
-	   ORDER BY id DESC`, currentUserID, currentUserID, userID)
+	   WHERE `+where+`
+	   ORDER BY `+orderBy+`
+	   LIMIT ?`, args...)
 	if err != nil {
-		return nil, err
+		return nil, false, err
 	}
 	defer rows.Close()
 
+	photos := make([]*Photo, 0, page.FetchLimit())
 	for rows.Next() {
 		item := &Photo{}
 		var isLiked, isFollowed sql.NullInt64
 		var userLogin string
-		err := rows.Scan(&item.ID, &item.UserID, &item.URL, &item.Comment, &item.Rating, &userLogin, &isLiked, &isFollowed)
+		err := rows.Scan(&item.ID, &item.UserID, &item.URL, &item.Comment, &item.Rating,
+			&item.Format, &item.Width, &item.Height, &item.Status, &item.Visibility, &item.Hidden, &userLogin, &isLiked, &isFollowed)
 		if err != nil {
-			return nil, err
+			return nil, false, err
 		}
 		item.Liked = isLiked.Valid
 		// item.Followed = isFollowed.Valid
 		photos = append(photos, item)
 	}
+	if err = rows.Err(); err != nil {
+		return nil, false, err
+	}
+
+	hasNext := page.HasNext(len(photos))
+	if hasNext {
+		photos = photos[:page.Limit]
+	}
+	return photos, hasNext, nil
+}
+
+// Rate ставит (rate >= 0) или снимает лайк, rating меняется в той же транзакции
+// повторный лайк или снятие несуществующего ничего не меняют
+func (st *PhotosRepo) Rate(ctx context.Context, photoID uint32, userID uint32, rate int) error {
+	return dbutils.InTx(ctx, st.db, func(tx *sql.Tx) error {
+		// лайкнуть можно только то, что видно
+		visible, visibleArgs := VisibleCond(userID)
+		var id uint32
+		err := tx.QueryRowContext(ctx, "SELECT id FROM photos WHERE id = ? AND "+visible+" FOR UPDATE",
+			append([]interface{}{photoID}, visibleArgs...)...).Scan(&id)
+		if err == sql.ErrNoRows {
+			return errPhotoNotFound
+		} else if err != nil {
+			return err
+		}
 
-	return photos, nil
+		var res sql.Result
+		delta := 1
+		if rate >= 0 {
+			res, err = tx.ExecContext(ctx, `INSERT INTO user_photos_likes(photo_id, user_id) VALUES(?, ?)`, photoID, userID)
+			if dbutils.IsDuplicate(err) {
+				return nil
+			}
+		} else {
+			delta = -1
+			res, err = tx.ExecContext(ctx, `DELETE FROM user_photos_likes WHERE photo_id = ? AND user_id = ?`, photoID, userID)
+		}
+		if err != nil {
+			return err
+		}
+		aff, _ := res.RowsAffected()
+		// dont update rating twice
+		if aff <= 0 {
+			return nil
+		}
+		_, err = tx.ExecContext(ctx, "UPDATE photos SET rating = rating + ? WHERE id = ?", delta, photoID)
+		return err
+	})
 }
 
-func (st *PhotosRepo) Rate(photoID uint32, userID uint32, rate int) error {
-	var res sql.Result
-	var err error
-	if rate >= 0 {
-		res, err = st.db.Exec(`INSERT IGNORE INTO user_photos_likes(photo_id, user_id) VALUES(?, ?)`, photoID, userID)
-	} else {
-		res, err = st.db.Exec(`DELETE FROM user_photos_likes WHERE photo_id = ? AND user_id = ?`, photoID, userID)
+// ReconcileRatings сверяет rating с user_photos_likes, fix - исправить расхождения
+func (st *PhotosRepo) ReconcileRatings(ctx context.Context, fix bool) ([]*dbutils.Drift, error) {
+	return dbutils.ReconcileCounter(ctx, st.db, dbutils.CounterPhotoRating, fix)
+}
+
+// Delete удаляет фото вместе с лайками, комментариями и уведомлениями, если оно принадлежит userID
+// возвращает удалённое фото, чтобы можно было почистить хранилище
+// невидимое для userID фото - errPhotoNotFound, видимое, но чужое - errNotOwner
+func (st *PhotosRepo) Delete(ctx context.Context, photoID, userID uint32) (*Photo, error) {
+	tx, err := st.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	visible, visibleArgs := VisibleCond(userID)
+	item := &Photo{ID: photoID}
+	err = tx.QueryRowContext(ctx, "SELECT user_id, path, format FROM photos WHERE id = ? AND "+visible+" FOR UPDATE",
+		append([]interface{}{photoID}, visibleArgs...)...).
+		Scan(&item.UserID, &item.URL, &item.Format)
+	if err == sql.ErrNoRows {
+		return nil, errPhotoNotFound
+	} else if err != nil {
+		return nil, err
+	}
+	if item.UserID != userID {
+		return nil, errNotOwner
 	}
+
+	_, err = tx.ExecContext(ctx, "DELETE FROM user_photos_likes WHERE photo_id = ?", photoID)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	aff, _ := res.RowsAffected()
-	// dont update rating twice
-	if aff <= 0 {
-		return nil
+	_, err = tx.ExecContext(ctx, "DELETE FROM photo_comments WHERE photo_id = ?", photoID)
+	if err != nil {
+		return nil, err
+	}
+	_, err = tx.ExecContext(ctx, "DELETE FROM photo_reports WHERE photo_id = ?", photoID)
+	if err != nil {
+		return nil, err
 	}
-	_, err = st.db.Exec("UPDATE photos SET rating = rating + ? WHERE id = ?", rate, photoID)
+	// уведомления о лайках ссылаются на фото, без него они ведут в никуда
+	_, err = tx.ExecContext(ctx, "DELETE FROM notifications WHERE photo_id = ?", photoID)
+	if err != nil {
+		return nil, err
+	}
+	_, err = tx.ExecContext(ctx, "DELETE FROM photos WHERE id = ?", photoID)
+	if err != nil {
+		return nil, err
+	}
+	return item, tx.Commit()
+}
+
+// UpdateComment меняет подпись к фото, если оно принадлежит userID
+func (st *PhotosRepo) UpdateComment(ctx context.Context, photoID, userID uint32, comment string) error {
+	err := st.checkOwner(ctx, photoID, userID)
+	if err != nil {
+		return err
+	}
+	// RowsAffected тут не смотрим - mysql вернёт 0, если подпись не поменялась
+	_, err = st.db.ExecContext(ctx, "UPDATE photos SET comment = ? WHERE id = ? AND user_id = ?",
+		comment, photoID, userID)
+	return err
+}
+
+func IsErrPhotoNotFound(err error) bool {
+	return err == errPhotoNotFound
+}
+
+func IsErrNotOwner(err error) bool {
+	return err == errNotOwner
+}
+
+func (st *PhotosRepo) SetStatus(ctx context.Context, photoID uint32, status Status) error {
+	_, err := st.db.ExecContext(ctx, "UPDATE photos SET status = ? WHERE id = ?", status, photoID)
 	return err
 }
+
+func (st *PhotosRepo) GetProcessing(ctx context.Context) ([]*ThumbJob, error) {
+	rows, err := st.db.QueryContext(ctx, "SELECT id, user_id, path, format FROM photos WHERE status = ?", StatusProcessing)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	jobs := []*ThumbJob{}
+	for rows.Next() {
+		job := &ThumbJob{}
+		err := rows.Scan(&job.PhotoID, &job.UserID, &job.ObjectName, &job.Format)
+		if err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, job)
+	}
+	return jobs, rows.Err()
+}
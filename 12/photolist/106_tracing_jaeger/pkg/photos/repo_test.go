@@ -0,0 +1,136 @@
+package photos
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	sqlmock "gopkg.in/DATA-DOG/go-sqlmock.v1"
+)
+
+func TestDelete(t *testing.T) {
+	selectPhoto := `SELECT user_id, path, format FROM photos WHERE id = \? AND \(photos.user_id = \? OR (.+) FOR UPDATE`
+	photoRow := func() *sqlmock.Rows {
+		return sqlmock.NewRows([]string{"user_id", "path", "format"}).AddRow(ownerID, "abc", "jpeg")
+	}
+	cases := []struct {
+		name    string
+		userID  uint32
+		prepare func(mock sqlmock.Sqlmock)
+		checkFn func(error) bool
+	}{
+		{"ok", ownerID, func(mock sqlmock.Sqlmock) {
+			mock.ExpectBegin()
+			mock.ExpectQuery(selectPhoto).WithArgs(10, ownerID, ownerID).WillReturnRows(photoRow())
+			mock.ExpectExec(`DELETE FROM user_photos_likes`).WithArgs(10).WillReturnResult(sqlmock.NewResult(0, 2))
+			mock.ExpectExec(`DELETE FROM photo_comments`).WithArgs(10).WillReturnResult(sqlmock.NewResult(0, 1))
+			mock.ExpectExec(`DELETE FROM photo_reports`).WithArgs(10).WillReturnResult(sqlmock.NewResult(0, 0))
+			mock.ExpectExec(`DELETE FROM notifications WHERE photo_id = \?`).WithArgs(10).WillReturnResult(sqlmock.NewResult(0, 2))
+			mock.ExpectExec(`DELETE FROM photos WHERE id = \?`).WithArgs(10).WillReturnResult(sqlmock.NewResult(0, 1))
+			mock.ExpectCommit()
+		}, func(err error) bool { return err == nil }},
+		{"not owner", strangerID, func(mock sqlmock.Sqlmock) {
+			mock.ExpectBegin()
+			mock.ExpectQuery(selectPhoto).WithArgs(10, strangerID, strangerID).WillReturnRows(photoRow())
+			mock.ExpectRollback()
+		}, IsErrNotOwner},
+		{"not found", ownerID, func(mock sqlmock.Sqlmock) {
+			mock.ExpectBegin()
+			mock.ExpectQuery(selectPhoto).WithArgs(10, ownerID, ownerID).
+				WillReturnRows(sqlmock.NewRows([]string{"user_id", "path", "format"}))
+			mock.ExpectRollback()
+		}, IsErrPhotoNotFound},
+		// чужое приватное фото для удаляющего не существует, а не "not owner"
+		{"invisible", strangerID, func(mock sqlmock.Sqlmock) {
+			mock.ExpectBegin()
+			mock.ExpectQuery(selectPhoto).WithArgs(10, strangerID, strangerID).
+				WillReturnRows(sqlmock.NewRows([]string{"user_id", "path", "format"}))
+			mock.ExpectRollback()
+		}, IsErrPhotoNotFound},
+		{"notifications error", ownerID, func(mock sqlmock.Sqlmock) {
+			mock.ExpectBegin()
+			mock.ExpectQuery(selectPhoto).WithArgs(10, ownerID, ownerID).WillReturnRows(photoRow())
+			mock.ExpectExec(`DELETE FROM user_photos_likes`).WithArgs(10).WillReturnResult(sqlmock.NewResult(0, 0))
+			mock.ExpectExec(`DELETE FROM photo_comments`).WithArgs(10).WillReturnResult(sqlmock.NewResult(0, 0))
+			mock.ExpectExec(`DELETE FROM photo_reports`).WithArgs(10).WillReturnResult(sqlmock.NewResult(0, 0))
+			mock.ExpectExec(`DELETE FROM notifications`).WithArgs(10).WillReturnError(fmt.Errorf("bad connection"))
+			mock.ExpectRollback()
+		}, func(err error) bool { return err != nil }},
+	}
+	for _, c := range cases {
+		db, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("cant create mock: %s", err)
+		}
+		repo := NewPhotosRepository(db)
+		c.prepare(mock)
+
+		_, err = repo.Delete(context.Background(), 10, c.userID)
+		if !c.checkFn(err) {
+			t.Errorf("[%s] unexpected err: %v", c.name, err)
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("[%s] there were unfulfilled expectations: %s", c.name, err)
+		}
+		db.Close()
+	}
+}
+
+// UpdateComment и SetVisibility проверяют владельца одинаково, через checkOwner
+func TestEditOwner(t *testing.T) {
+	selectOwner := `SELECT user_id FROM photos WHERE id = \? AND \(photos.user_id = \? OR \(photos.hidden = 0`
+	ownerRow := func() *sqlmock.Rows {
+		return sqlmock.NewRows([]string{"user_id"}).AddRow(ownerID)
+	}
+	edits := map[string]struct {
+		call   func(repo *PhotosRepo, userID uint32) error
+		update string
+	}{
+		"comment": {func(repo *PhotosRepo, userID uint32) error {
+			return repo.UpdateComment(context.Background(), 10, userID, "new")
+		}, `UPDATE photos SET comment = \?`},
+		"visibility": {func(repo *PhotosRepo, userID uint32) error {
+			return repo.SetVisibility(context.Background(), 10, userID, VisibilityPrivate)
+		}, `UPDATE photos SET visibility = \?`},
+	}
+	cases := []struct {
+		name    string
+		userID  uint32
+		prepare func(mock sqlmock.Sqlmock, update string)
+		checkFn func(error) bool
+	}{
+		{"ok", ownerID, func(mock sqlmock.Sqlmock, update string) {
+			mock.ExpectQuery(selectOwner).WithArgs(10, ownerID, ownerID).WillReturnRows(ownerRow())
+			mock.ExpectExec(update).WillReturnResult(sqlmock.NewResult(0, 1))
+		}, func(err error) bool { return err == nil }},
+		{"visible, not owner", strangerID, func(mock sqlmock.Sqlmock, update string) {
+			mock.ExpectQuery(selectOwner).WithArgs(10, strangerID, strangerID).WillReturnRows(ownerRow())
+		}, IsErrNotOwner},
+		{"invisible", strangerID, func(mock sqlmock.Sqlmock, update string) {
+			mock.ExpectQuery(selectOwner).WithArgs(10, strangerID, strangerID).
+				WillReturnRows(sqlmock.NewRows([]string{"user_id"}))
+		}, IsErrPhotoNotFound},
+		{"db error", ownerID, func(mock sqlmock.Sqlmock, update string) {
+			mock.ExpectQuery(selectOwner).WithArgs(10, ownerID, ownerID).WillReturnError(fmt.Errorf("bad connection"))
+		}, func(err error) bool { return err != nil && !IsErrPhotoNotFound(err) && !IsErrNotOwner(err) }},
+	}
+	for editName, edit := range edits {
+		for _, c := range cases {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("cant create mock: %s", err)
+			}
+			repo := NewPhotosRepository(db)
+			c.prepare(mock, edit.update)
+
+			err = edit.call(repo, c.userID)
+			if !c.checkFn(err) {
+				t.Errorf("[%s/%s] unexpected err: %v", editName, c.name, err)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("[%s/%s] there were unfulfilled expectations: %s", editName, c.name, err)
+			}
+			db.Close()
+		}
+	}
+}
@@ -0,0 +1,170 @@
+package photos
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"photolist/pkg/utils/dbutils"
+	"photolist/pkg/utils/pagination"
+)
+
+const MaxReportReasonLen = 500
+
+var (
+	errReasonTooLong = errors.New("Report reason too long")
+)
+
+func IsErrReasonTooLong(err error) bool {
+	return err == errReasonTooLong
+}
+
+// ReportStatus - что модератор сделал с жалобой
+type ReportStatus string
+
+const (
+	ReportOpen      ReportStatus = "open"      // ждёт модератора
+	ReportHidden    ReportStatus = "hidden"    // фото скрыто
+	ReportDismissed ReportStatus = "dismissed" // отклонена
+)
+
+// Report - жалоба пользователя на фото
+type Report struct {
+	ID        uint32
+	PhotoID   uint32
+	UserID    uint32
+	Reason    string
+	Status    ReportStatus
+	CreatedAt time.Time
+}
+
+func (rep *Report) Id() string {
+	return strconv.Itoa(int(rep.ID))
+}
+
+func (rep *Report) Cursor() string {
+	return (&pagination.Cursor{ID: rep.ID}).Encode()
+}
+
+// Report - жалоба на фото, пожаловаться можно только на то, что видно
+// повторная жалоба того же пользователя ничего не меняет
+func (st *PhotosRepo) Report(ctx context.Context, photoID, userID uint32, reason string) error {
+	reason = strings.TrimSpace(reason)
+	if utf8.RuneCountInString(reason) > MaxReportReasonLen {
+		return errReasonTooLong
+	}
+
+	visible, visibleArgs := VisibleCond(userID)
+	var id uint32
+	err := st.db.QueryRowContext(ctx, "SELECT id FROM photos WHERE id = ? AND "+visible,
+		append([]interface{}{photoID}, visibleArgs...)...).Scan(&id)
+	if err == sql.ErrNoRows {
+		return errPhotoNotFound
+	} else if err != nil {
+		return err
+	}
+
+	_, err = st.db.ExecContext(ctx, "INSERT INTO photo_reports(photo_id, user_id, reason, status, created_at) VALUES(?, ?, ?, 'open', NOW())",
+		photoID, userID, reason)
+	if dbutils.IsDuplicate(err) {
+		return nil
+	}
+	return err
+}
+
+// ModerationQueue - открытые жалобы, старые сверху
+func (st *PhotosRepo) ModerationQueue(ctx context.Context, page pagination.Page) ([]*Report, bool, error) {
+	q := "SELECT id, photo_id, user_id, reason, status, UNIX_TIMESTAMP(created_at) FROM photo_reports WHERE status = 'open'"
+	args := []interface{}{}
+	if page.After != nil {
+		q += " AND id > ?"
+		args = append(args, page.After.ID)
+	}
+	q += " ORDER BY id LIMIT ?"
+	args = append(args, page.FetchLimit())
+
+	rows, err := st.db.QueryContext(ctx, q, args...)
+	if err != nil {
+		return nil, false, err
+	}
+	defer rows.Close()
+	result := make([]*Report, 0, page.FetchLimit())
+	for rows.Next() {
+		rep := &Report{}
+		var created int64
+		err := rows.Scan(&rep.ID, &rep.PhotoID, &rep.UserID, &rep.Reason, &rep.Status, &created)
+		if err != nil {
+			return nil, false, err
+		}
+		rep.CreatedAt = time.Unix(created, 0)
+		result = append(result, rep)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, false, err
+	}
+
+	hasNext := page.HasNext(len(result))
+	if hasNext {
+		result = result[:page.Limit]
+	}
+	return result, hasNext, nil
+}
+
+// GetForModeration - фото без проверки видимости, только для модераторов
+func (st *PhotosRepo) GetForModeration(ctx context.Context, photoID uint32) (*Photo, error) {
+	item := &Photo{}
+	err := st.db.QueryRowContext(ctx, `SELECT id, user_id, path, comment, rating, format, width, height, status, visibility, hidden
+		FROM photos WHERE id = ?`, photoID).
+		Scan(&item.ID, &item.UserID, &item.URL, &item.Comment, &item.Rating,
+			&item.Format, &item.Width, &item.Height, &item.Status, &item.Visibility, &item.Hidden)
+	if err == sql.ErrNoRows {
+		return nil, errPhotoNotFound
+	} else if err != nil {
+		return nil, err
+	}
+	return item, nil
+}
+
+// SetHidden скрывает фото или возвращает его обратно
+// при скрытии открытые жалобы на фото закрываются
+func (st *PhotosRepo) SetHidden(ctx context.Context, photoID, moderatorID uint32, hidden bool) error {
+	return dbutils.InTx(ctx, st.db, func(tx *sql.Tx) error {
+		res, err := tx.ExecContext(ctx, "UPDATE photos SET hidden = ? WHERE id = ?", hidden, photoID)
+		if err != nil {
+			return err
+		}
+		// mysql вернёт 0 и если фото уже было скрыто, поэтому проверяем отдельно
+		if aff, _ := res.RowsAffected(); aff == 0 {
+			var id uint32
+			err = tx.QueryRowContext(ctx, "SELECT id FROM photos WHERE id = ?", photoID).Scan(&id)
+			if err == sql.ErrNoRows {
+				return errPhotoNotFound
+			} else if err != nil {
+				return err
+			}
+		}
+		if !hidden {
+			return nil
+		}
+		return resolveReports(ctx, tx, photoID, moderatorID, ReportHidden)
+	})
+}
+
+// DismissReports закрывает открытые жалобы на фото без последствий для него
+func (st *PhotosRepo) DismissReports(ctx context.Context, photoID, moderatorID uint32) error {
+	return resolveReports(ctx, st.db, photoID, moderatorID, ReportDismissed)
+}
+
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}
+
+func resolveReports(ctx context.Context, db execer, photoID, moderatorID uint32, status ReportStatus) error {
+	_, err := db.ExecContext(ctx, "UPDATE photo_reports SET status = ?, resolved_by = ?, resolved_at = NOW() WHERE photo_id = ? AND status = 'open'",
+		status, moderatorID, photoID)
+	return err
+}
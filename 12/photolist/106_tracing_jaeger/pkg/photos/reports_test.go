@@ -0,0 +1,206 @@
+package photos
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"photolist/pkg/utils/pagination"
+
+	"github.com/go-sql-driver/mysql"
+	sqlmock "gopkg.in/DATA-DOG/go-sqlmock.v1"
+)
+
+func TestReport(t *testing.T) {
+	cases := []struct {
+		name    string
+		reason  string
+		prepare func(mock sqlmock.Sqlmock)
+		checkFn func(error) bool
+	}{
+		{"ok", "spam", func(mock sqlmock.Sqlmock) {
+			mock.ExpectQuery(`SELECT id FROM photos WHERE id = \? AND`).WithArgs(10, strangerID, strangerID).
+				WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(10))
+			mock.ExpectExec(`INSERT INTO photo_reports`).WithArgs(10, strangerID, "spam").
+				WillReturnResult(sqlmock.NewResult(1, 1))
+		}, func(err error) bool { return err == nil }},
+		{"repeated", "spam", func(mock sqlmock.Sqlmock) {
+			mock.ExpectQuery(`SELECT id FROM photos WHERE id = \? AND`).WithArgs(10, strangerID, strangerID).
+				WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(10))
+			mock.ExpectExec(`INSERT INTO photo_reports`).WithArgs(10, strangerID, "spam").
+				WillReturnError(&mysql.MySQLError{Number: 1062, Message: "Duplicate entry"})
+		}, func(err error) bool { return err == nil }},
+		{"invisible photo", "spam", func(mock sqlmock.Sqlmock) {
+			mock.ExpectQuery(`SELECT id FROM photos WHERE id = \? AND`).WithArgs(10, strangerID, strangerID).
+				WillReturnRows(sqlmock.NewRows([]string{"id"}))
+		}, IsErrPhotoNotFound},
+		{"long reason", strings.Repeat("я", MaxReportReasonLen+1), func(mock sqlmock.Sqlmock) {}, IsErrReasonTooLong},
+		{"db error", "spam", func(mock sqlmock.Sqlmock) {
+			mock.ExpectQuery(`SELECT id FROM photos WHERE id = \? AND`).WithArgs(10, strangerID, strangerID).
+				WillReturnError(fmt.Errorf("bad connection"))
+		}, func(err error) bool { return err != nil && !IsErrPhotoNotFound(err) }},
+	}
+	for _, c := range cases {
+		db, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("cant create mock: %s", err)
+		}
+		repo := NewPhotosRepository(db)
+		c.prepare(mock)
+
+		err = repo.Report(context.Background(), 10, strangerID, c.reason)
+		if !c.checkFn(err) {
+			t.Errorf("[%s] unexpected err: %v", c.name, err)
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("[%s] there were unfulfilled expectations: %s", c.name, err)
+		}
+		db.Close()
+	}
+}
+
+// скрытие закрывает открытые жалобы в той же транзакции
+func TestSetHidden(t *testing.T) {
+	resolve := `UPDATE photo_reports SET status = \?, resolved_by = \?, resolved_at = NOW\(\) WHERE photo_id = \? AND status = 'open'`
+	cases := []struct {
+		name    string
+		hidden  bool
+		prepare func(mock sqlmock.Sqlmock)
+		checkFn func(error) bool
+	}{
+		{"hide", true, func(mock sqlmock.Sqlmock) {
+			mock.ExpectBegin()
+			mock.ExpectExec(`UPDATE photos SET hidden = \? WHERE id = \?`).WithArgs(true, 10).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+			mock.ExpectExec(resolve).WithArgs(ReportHidden, 1, 10).
+				WillReturnResult(sqlmock.NewResult(0, 2))
+			mock.ExpectCommit()
+		}, func(err error) bool { return err == nil }},
+		// уже скрыто: mysql вернёт 0 строк, но фото есть, а новые жалобы всё равно закрываем
+		{"already hidden", true, func(mock sqlmock.Sqlmock) {
+			mock.ExpectBegin()
+			mock.ExpectExec(`UPDATE photos SET hidden = \? WHERE id = \?`).WithArgs(true, 10).
+				WillReturnResult(sqlmock.NewResult(0, 0))
+			mock.ExpectQuery(`SELECT id FROM photos WHERE id = \?`).WithArgs(10).
+				WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(10))
+			mock.ExpectExec(resolve).WithArgs(ReportHidden, 1, 10).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+			mock.ExpectCommit()
+		}, func(err error) bool { return err == nil }},
+		// возврат фото жалобы не трогает
+		{"unhide", false, func(mock sqlmock.Sqlmock) {
+			mock.ExpectBegin()
+			mock.ExpectExec(`UPDATE photos SET hidden = \? WHERE id = \?`).WithArgs(false, 10).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+			mock.ExpectCommit()
+		}, func(err error) bool { return err == nil }},
+		{"not found", true, func(mock sqlmock.Sqlmock) {
+			mock.ExpectBegin()
+			mock.ExpectExec(`UPDATE photos SET hidden = \? WHERE id = \?`).WithArgs(true, 10).
+				WillReturnResult(sqlmock.NewResult(0, 0))
+			mock.ExpectQuery(`SELECT id FROM photos WHERE id = \?`).WithArgs(10).
+				WillReturnRows(sqlmock.NewRows([]string{"id"}))
+			mock.ExpectRollback()
+		}, IsErrPhotoNotFound},
+		{"resolve error", true, func(mock sqlmock.Sqlmock) {
+			mock.ExpectBegin()
+			mock.ExpectExec(`UPDATE photos SET hidden = \? WHERE id = \?`).WithArgs(true, 10).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+			mock.ExpectExec(resolve).WithArgs(ReportHidden, 1, 10).
+				WillReturnError(fmt.Errorf("bad connection"))
+			mock.ExpectRollback()
+		}, func(err error) bool { return err != nil && !IsErrPhotoNotFound(err) }},
+	}
+	for _, c := range cases {
+		db, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("cant create mock: %s", err)
+		}
+		repo := NewPhotosRepository(db)
+		c.prepare(mock)
+
+		err = repo.SetHidden(context.Background(), 10, 1, c.hidden)
+		if !c.checkFn(err) {
+			t.Errorf("[%s] unexpected err: %v", c.name, err)
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("[%s] there were unfulfilled expectations: %s", c.name, err)
+		}
+		db.Close()
+	}
+}
+
+func TestDismissReports(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("cant create mock: %s", err)
+	}
+	defer db.Close()
+	repo := NewPhotosRepository(db)
+
+	// фото не трогаем, только жалобы
+	mock.ExpectExec(`UPDATE photo_reports SET status = \?, resolved_by = \?, resolved_at = NOW\(\) WHERE photo_id = \? AND status = 'open'`).
+		WithArgs(ReportDismissed, 1, 10).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+	if err := repo.DismissReports(context.Background(), 10, 1); err != nil {
+		t.Errorf("unexpected err: %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestModerationQueue(t *testing.T) {
+	columns := []string{"id", "photo_id", "user_id", "reason", "status", "created"}
+	cases := []struct {
+		name        string
+		page        pagination.Page
+		prepare     func(mock sqlmock.Sqlmock)
+		expectedIDs []uint32
+		hasNext     bool
+	}{
+		{"first page", pagination.Page{Limit: 2}, func(mock sqlmock.Sqlmock) {
+			mock.ExpectQuery(`FROM photo_reports WHERE status = 'open' ORDER BY id LIMIT \?`).
+				WithArgs(3).
+				WillReturnRows(sqlmock.NewRows(columns).
+					AddRow(1, 10, 2, "spam", "open", 1600000000).
+					AddRow(4, 11, 3, "", "open", 1600000001).
+					AddRow(5, 10, 3, "nsfw", "open", 1600000002))
+		}, []uint32{1, 4}, true},
+		// старые сверху, поэтому следующая страница - id больше курсора
+		{"after cursor", pagination.Page{Limit: 2, After: &pagination.Cursor{ID: 4}}, func(mock sqlmock.Sqlmock) {
+			mock.ExpectQuery(`FROM photo_reports WHERE status = 'open' AND id > \? ORDER BY id LIMIT \?`).
+				WithArgs(4, 3).
+				WillReturnRows(sqlmock.NewRows(columns).
+					AddRow(5, 10, 3, "nsfw", "open", 1600000002))
+		}, []uint32{5}, false},
+	}
+	for _, c := range cases {
+		db, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("cant create mock: %s", err)
+		}
+		repo := NewPhotosRepository(db)
+		c.prepare(mock)
+
+		result, hasNext, err := repo.ModerationQueue(context.Background(), c.page)
+		if err != nil {
+			t.Errorf("[%s] unexpected err: %v", c.name, err)
+		}
+		if hasNext != c.hasNext {
+			t.Errorf("[%s] expected hasNext %v, got %v", c.name, c.hasNext, hasNext)
+		}
+		ids := make([]uint32, 0, len(result))
+		for _, rep := range result {
+			ids = append(ids, rep.ID)
+		}
+		if fmt.Sprint(ids) != fmt.Sprint(c.expectedIDs) {
+			t.Errorf("[%s] expected %v, got %v", c.name, c.expectedIDs, ids)
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("[%s] there were unfulfilled expectations: %s", c.name, err)
+		}
+		db.Close()
+	}
+}
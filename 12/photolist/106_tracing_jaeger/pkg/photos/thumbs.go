@@ -0,0 +1,124 @@
+package photos
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"sync"
+	"time"
+
+	"photolist/pkg/blobstorage"
+
+	"go.uber.org/zap"
+)
+
+// ThumbJob - задача на нарезку eager-превьюшек для одного фото
+type ThumbJob struct {
+	PhotoID    uint32 `json:"photo_id"`
+	UserID     uint32 `json:"user_id"`
+	ObjectName string `json:"object"`
+	Format     Format `json:"format"`
+	Attempt    int    `json:"attempt"`
+}
+
+// ThumbQueue - очередь задач на превьюшки
+// есть реализация на канале внутри процесса и на rabbitmq, как в 6/database/rabbit
+type ThumbQueue interface {
+	Publish(*ThumbJob) error
+	// Retry - отдать задачу снова через delay; если вернул nil, исходную задачу можно подтверждать
+	Retry(job *ThumbJob, delay time.Duration) error
+	// Consume блокируется и отдаёт задачи в handler, пока очередь не закроют
+	// если handler вернул ошибку - задача считается не обработанной
+	Consume(handler func(*ThumbJob) error) error
+	Close() error
+}
+
+type ThumbRepo interface {
+	SetStatus(context.Context, uint32, Status) error
+	// GetProcessing - фото, превьюшки для которых так и не доделали
+	GetProcessing(context.Context) ([]*ThumbJob, error)
+}
+
+// ThumbWorker забирает задачи из очереди и режет превьюшки
+// Concurrency задаёт, сколько картинок обрабатывается одновременно
+type ThumbWorker struct {
+	Queue       ThumbQueue
+	Storage     blobstorage.Storage
+	Repo        ThumbRepo
+	Presets     Presets
+	Concurrency int
+	MaxAttempts int
+	// пауза перед повтором, умножается на номер попытки
+	RetryDelay time.Duration
+}
+
+// Run запускает Concurrency обработчиков и ждёт, пока очередь не закроется
+func (tw *ThumbWorker) Run() {
+	wg := &sync.WaitGroup{}
+	for i := 0; i < tw.Concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := tw.Queue.Consume(tw.handle)
+			if err != nil {
+				zap.L().Error("ThumbWorker consume err", zap.Error(err))
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func (tw *ThumbWorker) handle(job *ThumbJob) error {
+	// задача из очереди живёт дольше запроса на загрузку, его ctx тут уже нет
+	ctx := context.Background()
+	err := tw.process(ctx, job)
+	if err == nil {
+		return tw.Repo.SetStatus(ctx, job.PhotoID, StatusReady)
+	}
+
+	job.Attempt++
+	zap.L().Warn("ThumbWorker err", zap.Uint32("photo_id", job.PhotoID), zap.Int("attempt", job.Attempt), zap.Error(err))
+	if job.Attempt >= tw.MaxAttempts {
+		return tw.Repo.SetStatus(ctx, job.PhotoID, StatusFailed)
+	}
+
+	// повторяем позже, чтобы не занимать обработчик на время паузы
+	// если повтор не поставился - возвращаем ошибку, и очередь не подтвердит задачу
+	err = tw.Queue.Retry(job, tw.RetryDelay*time.Duration(job.Attempt))
+	if err != nil {
+		zap.L().Error("ThumbWorker cant requeue", zap.Uint32("photo_id", job.PhotoID), zap.Error(err))
+	}
+	return err
+}
+
+// Requeue при старте заново ставит задачи для фото, застрявших в processing:
+// очередь в памяти теряет их при рестарте, а публикация могла упасть до записи в rabbit
+// превьюшки режутся идемпотентно, поэтому лишний дубль задачи не страшен
+func (tw *ThumbWorker) Requeue(ctx context.Context) error {
+	jobs, err := tw.Repo.GetProcessing(ctx)
+	if err != nil {
+		return err
+	}
+	for _, job := range jobs {
+		if err := tw.Queue.Publish(job); err != nil {
+			return err
+		}
+	}
+	if len(jobs) > 0 {
+		zap.L().Info("ThumbWorker requeued", zap.Int("jobs", len(jobs)))
+	}
+	return nil
+}
+
+func (tw *ThumbWorker) process(ctx context.Context, job *ThumbJob) error {
+	original, _, err := tw.Storage.Get(ctx, job.ObjectName+job.Format.Ext())
+	if err != nil {
+		return err
+	}
+	data, err := ioutil.ReadAll(original)
+	original.Close()
+	if err != nil {
+		return err
+	}
+	return MakeThumbnails(ctx, tw.Storage, bytes.NewReader(data), job.ObjectName, job.Format, job.UserID, tw.Presets.Eager())
+}
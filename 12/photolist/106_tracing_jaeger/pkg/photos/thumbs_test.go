@@ -0,0 +1,135 @@
+package photos
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"photolist/pkg/blobstorage"
+)
+
+func TestChanQueue(t *testing.T) {
+	q := NewChanQueue(1, 20*time.Millisecond)
+	if err := q.Publish(&ThumbJob{PhotoID: 1}); err != nil {
+		t.Fatalf("unexpected err: %s", err)
+	}
+	if err := q.Publish(&ThumbJob{PhotoID: 2}); err != ErrQueueFull {
+		t.Errorf("expected ErrQueueFull, got %v", err)
+	}
+
+	// ждущий Publish не мешает закрыть очередь
+	published := make(chan error)
+	go func() {
+		published <- q.Publish(&ThumbJob{PhotoID: 3})
+	}()
+	q.Close()
+	if err := <-published; err != ErrQueueClosed && err != ErrQueueFull {
+		t.Errorf("expected closed queue, got %v", err)
+	}
+	if err := q.Publish(&ThumbJob{PhotoID: 4}); err != ErrQueueClosed {
+		t.Errorf("expected ErrQueueClosed, got %v", err)
+	}
+
+	// то, что лежало в буфере, обрабатывается и после закрытия
+	got := []uint32{}
+	q.Consume(func(job *ThumbJob) error {
+		got = append(got, job.PhotoID)
+		return nil
+	})
+	if len(got) != 1 || got[0] != 1 {
+		t.Errorf("expected job 1 drained, got %v", got)
+	}
+}
+
+type fakeThumbQueue struct {
+	mu        sync.Mutex
+	published []*ThumbJob
+	retried   []*ThumbJob
+	retryErr  error
+}
+
+func (q *fakeThumbQueue) Publish(job *ThumbJob) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.published = append(q.published, job)
+	return nil
+}
+
+func (q *fakeThumbQueue) Retry(job *ThumbJob, delay time.Duration) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.retryErr != nil {
+		return q.retryErr
+	}
+	q.retried = append(q.retried, job)
+	return nil
+}
+
+func (q *fakeThumbQueue) Consume(handler func(*ThumbJob) error) error { return nil }
+func (q *fakeThumbQueue) Close() error                                { return nil }
+
+type fakeThumbRepo struct {
+	statuses   map[uint32]Status
+	processing []*ThumbJob
+}
+
+func (r *fakeThumbRepo) SetStatus(_ context.Context, id uint32, status Status) error {
+	r.statuses[id] = status
+	return nil
+}
+
+func (r *fakeThumbRepo) GetProcessing(context.Context) ([]*ThumbJob, error) {
+	return r.processing, nil
+}
+
+func TestThumbWorkerRetry(t *testing.T) {
+	cases := []struct {
+		name     string
+		attempt  int
+		retryErr error
+		ok       bool
+		retried  int
+		status   Status
+	}{
+		{"retry", 0, nil, true, 1, ""},
+		{"last attempt", 2, nil, true, 0, StatusFailed},
+		// ошибка уходит в очередь, чтобы та не подтверждала задачу
+		{"retry failed", 0, fmt.Errorf("channel closed"), false, 0, ""},
+	}
+	for _, c := range cases {
+		q := &fakeThumbQueue{retryErr: c.retryErr}
+		repo := &fakeThumbRepo{statuses: map[uint32]Status{}}
+		tw := &ThumbWorker{
+			Queue:       q,
+			Storage:     blobstorage.NewMemoryStorage(), // оригинала нет - обработка падает
+			Repo:        repo,
+			MaxAttempts: 3,
+			RetryDelay:  time.Millisecond,
+		}
+
+		err := tw.handle(&ThumbJob{PhotoID: 10, ObjectName: "abc", Format: FormatJPEG, Attempt: c.attempt})
+		if c.ok != (err == nil) {
+			t.Errorf("[%s] unexpected err: %v", c.name, err)
+		}
+		if len(q.retried) != c.retried {
+			t.Errorf("[%s] expected %d retries, got %d", c.name, c.retried, len(q.retried))
+		}
+		if repo.statuses[10] != c.status {
+			t.Errorf("[%s] bad status: %q, expected %q", c.name, repo.statuses[10], c.status)
+		}
+	}
+}
+
+func TestThumbWorkerRequeue(t *testing.T) {
+	q := &fakeThumbQueue{}
+	repo := &fakeThumbRepo{processing: []*ThumbJob{{PhotoID: 1}, {PhotoID: 2}}}
+	tw := &ThumbWorker{Queue: q, Repo: repo}
+	if err := tw.Requeue(context.Background()); err != nil {
+		t.Fatalf("unexpected err: %s", err)
+	}
+	if len(q.published) != 2 {
+		t.Errorf("expected 2 jobs requeued, got %d", len(q.published))
+	}
+}
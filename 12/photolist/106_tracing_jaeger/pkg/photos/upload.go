@@ -0,0 +1,67 @@
+package photos
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	"github.com/gofrs/uuid"
+	"go.uber.org/zap"
+
+	"photolist/pkg/logging"
+)
+
+// Uploader - общая логика загрузки для rest и graphql
+// оригинал сохраняется сразу, превьюшки режутся в фоне через ThumbQueue
+type Uploader struct {
+	Repo    PhotosRepoInterface
+	Storage Putter
+	Queue   ThumbQueue
+}
+
+func (u *Uploader) Upload(ctx context.Context, userID uint32, rawData []byte, comment string, visibility Visibility) (*Photo, error) {
+	imgData, imgInfo, err := PrepareImage(rawData)
+	if err != nil {
+		return nil, err
+	}
+
+	photoUUID, _ := uuid.NewV4()
+
+	err = u.Storage.Put(ctx, bytes.NewReader(imgData),
+		photoUUID.String()+imgInfo.Format.Ext(), imgInfo.Format.ContentType(),
+		userID)
+	if err != nil {
+		return nil, fmt.Errorf("cant save file: %w", err)
+	}
+
+	ph := &Photo{
+		UserID:  userID,
+		URL:     photoUUID.String(),
+		Comment: comment,
+		Format:  imgInfo.Format,
+		Width:   imgInfo.Width,
+		Height:  imgInfo.Height,
+		Status:  StatusProcessing,
+
+		Visibility: visibility,
+	}
+	ph.ID, err = u.Repo.Add(ctx, ph)
+	if err != nil {
+		return nil, fmt.Errorf("cant store item: %w", err)
+	}
+
+	err = u.Queue.Publish(&ThumbJob{
+		PhotoID:    ph.ID,
+		UserID:     userID,
+		ObjectName: ph.URL,
+		Format:     ph.Format,
+	})
+	if err != nil {
+		logging.FromContext(ctx).Error("cant queue thumbnails", zap.Uint32("photo_id", ph.ID), zap.Error(err))
+		ph.Status = StatusFailed
+		if err := u.Repo.SetStatus(ctx, ph.ID, StatusFailed); err != nil {
+			logging.FromContext(ctx).Error("cant set photo status", zap.Uint32("photo_id", ph.ID), zap.Error(err))
+		}
+	}
+	return ph, nil
+}
@@ -1,43 +1,70 @@
 package photos
 
 import (
+	"context"
 	"crypto/md5"
 	"encoding/hex"
 	"fmt"
 	// "image/jpeg"
 	"bytes"
+	"image"
 	"io"
 	"math/rand"
 	"os"
-	"strconv"
 
 	"github.com/disintegration/imaging"
+
+	"photolist/pkg/blobstorage"
 )
 
 var (
-	sizes = []int{
-		32,
-		600,
-	}
 	letterRunes = []rune("abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ")
 )
 
 type Putter interface {
-	Put(io.ReadSeeker, string, string, uint32) error
+	Put(context.Context, io.ReadSeeker, string, string, uint32) error
+}
+
+type Cleaner interface {
+	List(context.Context, string) ([]*blobstorage.ObjectInfo, error)
+	Delete(context.Context, string) error
 }
 
-func MakeThumbnails(storage Putter, source io.ReadSeeker, objectName string, userID uint32) error {
-	dst := bytes.NewBuffer(make([]byte, 0, 250*1024*1024))
-	for _, size := range sizes {
+// ThumbName - имя превьюшки в хранилище
+func ThumbName(objectName string, format Format, preset *Preset) string {
+	return objectName + "_" + preset.Name + format.ThumbFormat().Ext()
+}
+
+// RemoveImages удаляет оригинал и все превьюшки фото, в том числе сделанные по запросу
+// пытается удалить всё, даже если на каком-то объекте была ошибка
+func RemoveImages(ctx context.Context, storage Cleaner, objectName string) error {
+	objects, err := storage.List(ctx, objectName)
+	if err != nil {
+		return err
+	}
+	var firstErr error
+	for _, obj := range objects {
+		err := storage.Delete(ctx, obj.Name)
+		if err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+func MakeThumbnails(ctx context.Context, storage Putter, source io.ReadSeeker, objectName string, format Format, userID uint32, presets []*Preset) error {
+	dst := &bytes.Buffer{}
+	thumbFormat := format.ThumbFormat()
+	for _, preset := range presets {
 		source.Seek(0, io.SeekStart)
 		dst.Reset()
-		err := ResizeImageV2(source, dst, size)
+		err := ResizeImageV2(source, dst, preset, thumbFormat)
 		if err != nil {
 			return err
 		}
 		resizedImg := bytes.NewReader(dst.Bytes())
-		err = storage.Put(resizedImg,
-			objectName+"_"+strconv.Itoa(size)+".jpg", "image/jpeg",
+		err = storage.Put(ctx, resizedImg,
+			ThumbName(objectName, format, preset), thumbFormat.ContentType(),
 			userID)
 		if err != nil {
 			return err
@@ -46,14 +73,31 @@ func MakeThumbnails(storage Putter, source io.ReadSeeker, objectName string, use
 	return nil
 }
 
-func ResizeImageV2(source io.Reader, dst io.Writer, size int) error {
-	srcImage, err := imaging.Decode(source)
+var imagingFormats = map[Format]imaging.Format{
+	FormatJPEG: imaging.JPEG,
+	FormatPNG:  imaging.PNG,
+	FormatGIF:  imaging.GIF,
+}
+
+// ResizeImageV2 учитывает exif-ориентацию и кодирует результат в format
+func ResizeImageV2(source io.Reader, dst io.Writer, preset *Preset, format Format) error {
+	imgFormat, ok := imagingFormats[format]
+	if !ok {
+		return fmt.Errorf("cant encode to %s", format)
+	}
+
+	srcImage, err := imaging.Decode(source, imaging.AutoOrientation(true))
 	if err != nil {
 		return fmt.Errorf("failed to open image: %w\n", err)
 	}
 
-	dstImageFill := imaging.Fill(srcImage, size, size, imaging.Center, imaging.Lanczos)
-	err = imaging.Encode(dst, dstImageFill, imaging.JPEG)
+	var dstImage image.Image
+	if preset.Mode == ModeFit {
+		dstImage = imaging.Fit(srcImage, preset.Width, preset.Height, imaging.Lanczos)
+	} else {
+		dstImage = imaging.Fill(srcImage, preset.Width, preset.Height, imaging.Center, imaging.Lanczos)
+	}
+	err = imaging.Encode(dst, dstImage, imgFormat, imaging.JPEGQuality(preset.Quality))
 	if err != nil {
 		return fmt.Errorf("failed to write message: %w\n", err)
 	}
@@ -99,17 +143,6 @@ func SaveFile(in io.Reader) (string, error) {
 	return md5Sum, nil
 }
 
-func MakeThumbnails0(realFile, md5Sum string) error {
-	for _, size := range sizes {
-		resizedPath := fmt.Sprintf("./images/%s_%d.jpg", md5Sum, size)
-		err := ResizeImage(realFile, resizedPath, size)
-		if err != nil {
-			return err
-		}
-	}
-	return nil
-}
-
 //  не очень эффективно - каждый раз вычитываем файл
 func ResizeImage(originalPath string, resizedPath string, size int) error {
 	srcImage, err := imaging.Open(originalPath)
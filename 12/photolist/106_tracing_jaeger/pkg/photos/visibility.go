@@ -0,0 +1,114 @@
+package photos
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+// Visibility - кому видно фото
+type Visibility string
+
+const (
+	VisibilityPublic    Visibility = "public"    // всем
+	VisibilityFollowers Visibility = "followers" // владельцу и подписчикам
+	VisibilityPrivate   Visibility = "private"   // только владельцу
+)
+
+// ParseVisibility - пустая строка означает public
+func ParseVisibility(in string) (Visibility, error) {
+	switch v := Visibility(strings.ToLower(in)); v {
+	case "":
+		return VisibilityPublic, nil
+	case VisibilityPublic, VisibilityFollowers, VisibilityPrivate:
+		return v, nil
+	}
+	return "", fmt.Errorf("bad visibility %q", in)
+}
+
+// CanView - основное правило доступа, viewerID == 0 - анонимный пользователь
+// sql-версия того же правила - в VisibleCond, они должны совпадать
+func CanView(v Visibility, ownerID, viewerID uint32, viewerFollowsOwner bool) bool {
+	if viewerID != 0 && viewerID == ownerID {
+		return true
+	}
+	switch v {
+	case VisibilityPublic:
+		return true
+	case VisibilityFollowers:
+		return viewerID != 0 && viewerFollowsOwner
+	}
+	return false
+}
+
+// VisibleCond - условие для WHERE, оставляющее только фото, которые может видеть viewerID
+// скрытые модератором фото видит только владелец
+// таблица фото в запросе должна называться photos
+func VisibleCond(viewerID uint32) (string, []interface{}) {
+	return `(photos.user_id = ? OR (photos.hidden = 0 AND (photos.visibility = 'public' OR 
+		(photos.visibility = 'followers' AND EXISTS(
+			SELECT 1 FROM user_follows WHERE user_follows.user_id = ? AND user_follows.follow_id = photos.user_id)))))`,
+		[]interface{}{viewerID, viewerID}
+}
+
+// CanView проверяет доступ к уже загруженному фото, в базу ходит только для followers
+func (st *PhotosRepo) CanView(ctx context.Context, ph *Photo, viewerID uint32) (bool, error) {
+	if ph.Hidden {
+		return viewerID != 0 && viewerID == ph.UserID, nil
+	}
+	if ph.Visibility != VisibilityFollowers || viewerID == 0 || viewerID == ph.UserID {
+		return CanView(ph.Visibility, ph.UserID, viewerID, false), nil
+	}
+	var cnt int
+	err := st.db.QueryRowContext(ctx, "SELECT count(*) FROM user_follows WHERE user_id = ? AND follow_id = ?",
+		viewerID, ph.UserID).Scan(&cnt)
+	if err != nil {
+		return false, err
+	}
+	return CanView(ph.Visibility, ph.UserID, viewerID, cnt > 0), nil
+}
+
+// CanViewImage - проверка для отдачи картинки по имени в хранилище
+// неизвестное имя или чужой ownerID - false, дальше решать вызывающему
+func (st *PhotosRepo) CanViewImage(ctx context.Context, ownerID uint32, objectName string, viewerID uint32) (bool, error) {
+	ph, err := st.GetByURL(ctx, objectName)
+	if err == errPhotoNotFound {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	if ph.UserID != ownerID {
+		return false, nil
+	}
+	return st.CanView(ctx, ph, viewerID)
+}
+
+// checkOwner - фото, которое userID не видит, для него не существует: errPhotoNotFound,
+// видимое, но чужое - errNotOwner
+func (st *PhotosRepo) checkOwner(ctx context.Context, photoID, userID uint32) error {
+	visible, visibleArgs := VisibleCond(userID)
+	var ownerID uint32
+	err := st.db.QueryRowContext(ctx, "SELECT user_id FROM photos WHERE id = ? AND "+visible,
+		append([]interface{}{photoID}, visibleArgs...)...).Scan(&ownerID)
+	if err == sql.ErrNoRows {
+		return errPhotoNotFound
+	} else if err != nil {
+		return err
+	}
+	if ownerID != userID {
+		return errNotOwner
+	}
+	return nil
+}
+
+// SetVisibility меняет видимость фото, если оно принадлежит userID
+func (st *PhotosRepo) SetVisibility(ctx context.Context, photoID, userID uint32, v Visibility) error {
+	err := st.checkOwner(ctx, photoID, userID)
+	if err != nil {
+		return err
+	}
+	_, err = st.db.ExecContext(ctx, "UPDATE photos SET visibility = ? WHERE id = ?", v, photoID)
+	return err
+}
@@ -0,0 +1,193 @@
+package photos
+
+import (
+	"context"
+	"testing"
+
+	sqlmock "gopkg.in/DATA-DOG/go-sqlmock.v1"
+)
+
+/*
+	go test -v -run Visib ./pkg/photos/
+*/
+
+const (
+	ownerID    uint32 = 1
+	followerID uint32 = 2
+	strangerID uint32 = 3
+	anonID     uint32 = 0
+)
+
+type visibilityCase struct {
+	name       string
+	viewerID   uint32
+	follows    bool
+	visibility Visibility
+	expected   bool
+}
+
+// все сочетания зрителя и видимости
+var visibilityCases = []visibilityCase{
+	{"owner/public", ownerID, false, VisibilityPublic, true},
+	{"owner/followers", ownerID, false, VisibilityFollowers, true},
+	{"owner/private", ownerID, false, VisibilityPrivate, true},
+
+	{"follower/public", followerID, true, VisibilityPublic, true},
+	{"follower/followers", followerID, true, VisibilityFollowers, true},
+	{"follower/private", followerID, true, VisibilityPrivate, false},
+
+	{"stranger/public", strangerID, false, VisibilityPublic, true},
+	{"stranger/followers", strangerID, false, VisibilityFollowers, false},
+	{"stranger/private", strangerID, false, VisibilityPrivate, false},
+
+	{"anon/public", anonID, false, VisibilityPublic, true},
+	{"anon/followers", anonID, false, VisibilityFollowers, false},
+	{"anon/private", anonID, false, VisibilityPrivate, false},
+}
+
+func TestCanView(t *testing.T) {
+	for _, c := range visibilityCases {
+		got := CanView(c.visibility, ownerID, c.viewerID, c.follows)
+		if got != c.expected {
+			t.Errorf("[%s] expected %v, got %v", c.name, c.expected, got)
+		}
+	}
+
+	// незаполненная видимость - ничего не показываем никому, кроме владельца
+	if CanView("", ownerID, strangerID, true) {
+		t.Errorf("empty visibility must not be visible to others")
+	}
+	// анонимный пользователь не может оказаться владельцем фото с user_id = 0
+	if CanView(VisibilityPrivate, anonID, anonID, false) {
+		t.Errorf("anonymous viewer must not own photos")
+	}
+}
+
+func TestPhotosRepoCanView(t *testing.T) {
+	for _, c := range visibilityCases {
+		db, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("cant create mock: %s", err)
+		}
+		repo := NewPhotosRepository(db)
+
+		// в базу за подпиской ходим только когда без неё не решить
+		if c.visibility == VisibilityFollowers && c.viewerID != anonID && c.viewerID != ownerID {
+			cnt := 0
+			if c.follows {
+				cnt = 1
+			}
+			mock.ExpectQuery(`SELECT count\(\*\) FROM user_follows`).
+				WithArgs(c.viewerID, ownerID).
+				WillReturnRows(sqlmock.NewRows([]string{"cnt"}).AddRow(cnt))
+		}
+
+		ph := &Photo{ID: 10, UserID: ownerID, Visibility: c.visibility}
+		got, err := repo.CanView(context.Background(), ph, c.viewerID)
+		if err != nil {
+			t.Errorf("[%s] unexpected err: %s", c.name, err)
+		}
+		if got != c.expected {
+			t.Errorf("[%s] expected %v, got %v", c.name, c.expected, got)
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("[%s] there were unfulfilled expectations: %s", c.name, err)
+		}
+		db.Close()
+	}
+}
+
+func TestPhotosRepoCanViewImage(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("cant create mock: %s", err)
+	}
+	defer db.Close()
+	repo := NewPhotosRepository(db)
+	columns := []string{"id", "user_id", "path", "format", "status", "visibility", "hidden"}
+
+	// чужой owner_id в пути - отказ, даже для публичного фото
+	mock.ExpectQuery(`SELECT (.+) FROM photos WHERE path = \?`).
+		WithArgs("uuid").
+		WillReturnRows(sqlmock.NewRows(columns).AddRow(10, ownerID, "uuid", "jpeg", "ready", "public", 0))
+	ok, err := repo.CanViewImage(context.Background(), strangerID, "uuid", strangerID)
+	if err != nil || ok {
+		t.Errorf("expected false for foreign owner, got %v %v", ok, err)
+	}
+
+	// неизвестное имя - не ошибка, просто false
+	mock.ExpectQuery(`SELECT (.+) FROM photos WHERE path = \?`).
+		WithArgs("unknown").
+		WillReturnRows(sqlmock.NewRows(columns))
+	ok, err = repo.CanViewImage(context.Background(), ownerID, "unknown", ownerID)
+	if err != nil || ok {
+		t.Errorf("expected false for unknown image, got %v %v", ok, err)
+	}
+
+	// followers-фото для подписчика
+	mock.ExpectQuery(`SELECT (.+) FROM photos WHERE path = \?`).
+		WithArgs("uuid").
+		WillReturnRows(sqlmock.NewRows(columns).AddRow(10, ownerID, "uuid", "jpeg", "ready", "followers", 0))
+	mock.ExpectQuery(`SELECT count\(\*\) FROM user_follows`).
+		WithArgs(followerID, ownerID).
+		WillReturnRows(sqlmock.NewRows([]string{"cnt"}).AddRow(1))
+	ok, err = repo.CanViewImage(context.Background(), ownerID, "uuid", followerID)
+	if err != nil || !ok {
+		t.Errorf("expected true for follower, got %v %v", ok, err)
+	}
+
+	// скрытое модератором публичное фото - только владельцу
+	for _, viewerID := range []uint32{ownerID, strangerID} {
+		mock.ExpectQuery(`SELECT (.+) FROM photos WHERE path = \?`).
+			WithArgs("uuid").
+			WillReturnRows(sqlmock.NewRows(columns).AddRow(10, ownerID, "uuid", "jpeg", "ready", "public", 1))
+		ok, err = repo.CanViewImage(context.Background(), ownerID, "uuid", viewerID)
+		if err != nil || ok != (viewerID == ownerID) {
+			t.Errorf("[hidden] unexpected result for viewer %d: %v %v", viewerID, ok, err)
+		}
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+// GetByID должен передавать в запрос условие видимости и прятать недоступное фото как несуществующее
+func TestPhotosRepoGetByIDHidden(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("cant create mock: %s", err)
+	}
+	defer db.Close()
+	repo := NewPhotosRepository(db)
+
+	mock.ExpectQuery(`SELECT (.+) FROM photos (.+) WHERE photos.id = \? AND \(photos.user_id = \? OR \(photos.hidden = 0 AND \(photos.visibility = 'public'`).
+		WithArgs(strangerID, 10, strangerID, strangerID).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	_, err = repo.GetByID(context.Background(), 10, strangerID)
+	if !IsErrPhotoNotFound(err) {
+		t.Errorf("expected errPhotoNotFound, got %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestParseVisibility(t *testing.T) {
+	cases := map[string]Visibility{
+		"":          VisibilityPublic,
+		"public":    VisibilityPublic,
+		"FOLLOWERS": VisibilityFollowers,
+		"private":   VisibilityPrivate,
+	}
+	for in, expected := range cases {
+		got, err := ParseVisibility(in)
+		if err != nil || got != expected {
+			t.Errorf("[%q] expected %s, got %s %v", in, expected, got, err)
+		}
+	}
+	if _, err := ParseVisibility("friends"); err == nil {
+		t.Errorf("expected error for unknown visibility")
+	}
+}
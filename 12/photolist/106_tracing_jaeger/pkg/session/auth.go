@@ -4,47 +4,147 @@ import (
 	"context"
 	"database/sql"
 
-	"photolist/pkg/utils/randutils"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 type AuthService struct {
 	DB *sql.DB
 }
 
+func (as *AuthService) store() *DBStore {
+	return NewDBStore(as.DB, deviceTTL)
+}
+
+// ошибки хранилища передаём кодами grpc, на клиенте они превращаются обратно
+func toStatus(err error) error {
+	switch err {
+	case nil:
+		return nil
+	case ErrNoAuth:
+		return status.Error(codes.Unauthenticated, err.Error())
+	case errTokenReused:
+		return status.Error(codes.PermissionDenied, err.Error())
+	case errDeviceNotFound:
+		return status.Error(codes.NotFound, err.Error())
+	}
+	return err
+}
+
+func fromStatus(err error) error {
+	switch status.Code(err) {
+	case codes.OK:
+		return nil
+	case codes.Unauthenticated:
+		return ErrNoAuth
+	case codes.PermissionDenied:
+		return errTokenReused
+	case codes.NotFound:
+		return errDeviceNotFound
+	}
+	return err
+}
+
+func deviceToAuth(dev *Device, token string) *AuthSession {
+	return &AuthSession{
+		ID:     dev.ID,
+		UserID: dev.UserID,
+		Token:  token,
+	}
+}
+
 func (as *AuthService) Check(ctx context.Context, c *AuthCheckIn) (*AuthSession, error) {
-	sess := &AuthSession{}
-	row := as.DB.QueryRow(`SELECT user_id FROM sessions WHERE id = ?`, c.GetSessKey())
-	err := row.Scan(&sess.UserID)
-	if err == sql.ErrNoRows {
-		// log.Println("CheckSession no rows")
-		return nil, ErrNoAuth
-	} else if err != nil {
-		// log.Println("CheckSession err:", err)
-		return nil, err
+	dev, err := as.store().Verify(ctx, c.GetSessKey())
+	if err != nil {
+		return nil, toStatus(err)
 	}
-	return sess, nil
+	return deviceToAuth(dev, ""), nil
 }
 
 func (as *AuthService) Create(ctx context.Context, u *AuthUserIn) (*AuthSession, error) {
-	sessID := randutils.RandStringRunes(32)
-	_, err := as.DB.Exec("INSERT INTO sessions(id, user_id) VALUES(?, ?)", sessID, u.GetUserID())
+	dev, token, err := as.store().Start(ctx, u.GetUserID(), u.GetVer(), ClientInfo{
+		UserAgent: u.GetUserAgent(),
+		IP:        u.GetIP(),
+	})
 	if err != nil {
 		return nil, err
 	}
-	return &AuthSession{
-		ID:     sessID,
-		UserID: u.GetUserID(),
-	}, nil
+	return deviceToAuth(dev, token), nil
+}
+
+func (as *AuthService) Refresh(ctx context.Context, in *AuthRefreshIn) (*AuthSession, error) {
+	dev, token, err := as.store().Rotate(ctx, in.GetToken(), ClientInfo{
+		UserAgent: in.GetUserAgent(),
+		IP:        in.GetIP(),
+	})
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return deviceToAuth(dev, token), nil
 }
 
 func (as *AuthService) DestroyCurrent(ctx context.Context, s *AuthSession) (*AuthNothing, error) {
-	_, err := as.DB.Exec("DELETE FROM sessions WHERE id = ?",
-		s.GetID())
-	return &AuthNothing{}, err
+	err := as.store().Revoke(ctx, s.GetUserID(), s.GetID())
+	return &AuthNothing{}, toStatus(err)
 }
 
 func (as *AuthService) DestroyAll(ctx context.Context, u *AuthUserIn) (*AuthNothing, error) {
-	_, err := as.DB.Exec("DELETE FROM sessions WHERE user_id = ?",
-		u.GetUserID())
+	err := as.store().RevokeAll(ctx, u.GetUserID())
 	return &AuthNothing{}, err
 }
+
+func (as *AuthService) List(ctx context.Context, u *AuthUserIn) (*AuthDeviceList, error) {
+	devices, err := as.store().List(ctx, u.GetUserID())
+	if err != nil {
+		return nil, err
+	}
+	res := &AuthDeviceList{
+		Devices: make([]*AuthDevice, 0, len(devices)),
+	}
+	for _, d := range devices {
+		res.Devices = append(res.Devices, &AuthDevice{
+			ID:        d.ID,
+			UserID:    d.UserID,
+			UserAgent: d.UserAgent,
+			IP:        d.IP,
+			Created:   d.CreatedAt.Unix(),
+			LastSeen:  d.LastSeen.Unix(),
+		})
+	}
+	return res, nil
+}
+
+func (as *AuthService) Revoke(ctx context.Context, in *AuthRevokeIn) (*AuthNothing, error) {
+	err := as.store().Revoke(ctx, in.GetUserID(), in.GetID())
+	return &AuthNothing{}, toStatus(err)
+}
+
+func (as *AuthService) StartPending(ctx context.Context, u *AuthUserIn) (*AuthSession, error) {
+	token, err := as.store().StartPending(ctx, u.GetUserID(), u.GetVer(), ClientInfo{
+		UserAgent: u.GetUserAgent(),
+		IP:        u.GetIP(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &AuthSession{UserID: u.GetUserID(), Token: token}, nil
+}
+
+func (as *AuthService) CheckPending(ctx context.Context, c *AuthCheckIn) (*AuthSession, error) {
+	userID, err := as.store().Pending(ctx, c.GetSessKey())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &AuthSession{UserID: userID}, nil
+}
+
+func (as *AuthService) ConfirmPending(ctx context.Context, in *AuthRefreshIn) (*AuthSession, error) {
+	dev, token, err := as.store().Confirm(ctx, in.GetToken(), ClientInfo{
+		UserAgent: in.GetUserAgent(),
+		IP:        in.GetIP(),
+	})
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return deviceToAuth(dev, token), nil
+}
@@ -25,9 +25,11 @@ var _ = math.Inf
 const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
 
 type AuthSession struct {
-	ID                   string   `protobuf:"bytes,1,opt,name=ID,proto3" json:"ID,omitempty"`
-	UserID               uint32   `protobuf:"varint,2,opt,name=UserID,proto3" json:"UserID,omitempty"`
-	Ver                  int32    `protobuf:"varint,3,opt,name=Ver,proto3" json:"Ver,omitempty"`
+	ID     string `protobuf:"bytes,1,opt,name=ID,proto3" json:"ID,omitempty"`
+	UserID uint32 `protobuf:"varint,2,opt,name=UserID,proto3" json:"UserID,omitempty"`
+	Ver    int32  `protobuf:"varint,3,opt,name=Ver,proto3" json:"Ver,omitempty"`
+	// токен устройства, отдаётся только при Create и Refresh
+	Token                string   `protobuf:"bytes,4,opt,name=Token,proto3" json:"Token,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -79,9 +81,18 @@ func (m *AuthSession) GetVer() int32 {
 	return 0
 }
 
+func (m *AuthSession) GetToken() string {
+	if m != nil {
+		return m.Token
+	}
+	return ""
+}
+
 type AuthUserIn struct {
 	UserID               uint32   `protobuf:"varint,1,opt,name=UserID,proto3" json:"UserID,omitempty"`
 	Ver                  int32    `protobuf:"varint,2,opt,name=Ver,proto3" json:"Ver,omitempty"`
+	UserAgent            string   `protobuf:"bytes,3,opt,name=UserAgent,proto3" json:"UserAgent,omitempty"`
+	IP                   string   `protobuf:"bytes,4,opt,name=IP,proto3" json:"IP,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -126,6 +137,20 @@ func (m *AuthUserIn) GetVer() int32 {
 	return 0
 }
 
+func (m *AuthUserIn) GetUserAgent() string {
+	if m != nil {
+		return m.UserAgent
+	}
+	return ""
+}
+
+func (m *AuthUserIn) GetIP() string {
+	if m != nil {
+		return m.IP
+	}
+	return ""
+}
+
 type AuthCheckIn struct {
 	SessKey              string   `protobuf:"bytes,1,opt,name=SessKey,proto3" json:"SessKey,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
@@ -165,6 +190,226 @@ func (m *AuthCheckIn) GetSessKey() string {
 	return ""
 }
 
+type AuthRefreshIn struct {
+	Token                string   `protobuf:"bytes,1,opt,name=Token,proto3" json:"Token,omitempty"`
+	UserAgent            string   `protobuf:"bytes,2,opt,name=UserAgent,proto3" json:"UserAgent,omitempty"`
+	IP                   string   `protobuf:"bytes,3,opt,name=IP,proto3" json:"IP,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *AuthRefreshIn) Reset()         { *m = AuthRefreshIn{} }
+func (m *AuthRefreshIn) String() string { return proto.CompactTextString(m) }
+func (*AuthRefreshIn) ProtoMessage()    {}
+func (*AuthRefreshIn) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8bbd6f3875b0e874, []int{3}
+}
+
+func (m *AuthRefreshIn) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AuthRefreshIn.Unmarshal(m, b)
+}
+func (m *AuthRefreshIn) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AuthRefreshIn.Marshal(b, m, deterministic)
+}
+func (m *AuthRefreshIn) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AuthRefreshIn.Merge(m, src)
+}
+func (m *AuthRefreshIn) XXX_Size() int {
+	return xxx_messageInfo_AuthRefreshIn.Size(m)
+}
+func (m *AuthRefreshIn) XXX_DiscardUnknown() {
+	xxx_messageInfo_AuthRefreshIn.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AuthRefreshIn proto.InternalMessageInfo
+
+func (m *AuthRefreshIn) GetToken() string {
+	if m != nil {
+		return m.Token
+	}
+	return ""
+}
+
+func (m *AuthRefreshIn) GetUserAgent() string {
+	if m != nil {
+		return m.UserAgent
+	}
+	return ""
+}
+
+func (m *AuthRefreshIn) GetIP() string {
+	if m != nil {
+		return m.IP
+	}
+	return ""
+}
+
+type AuthDevice struct {
+	ID                   string   `protobuf:"bytes,1,opt,name=ID,proto3" json:"ID,omitempty"`
+	UserID               uint32   `protobuf:"varint,2,opt,name=UserID,proto3" json:"UserID,omitempty"`
+	UserAgent            string   `protobuf:"bytes,3,opt,name=UserAgent,proto3" json:"UserAgent,omitempty"`
+	IP                   string   `protobuf:"bytes,4,opt,name=IP,proto3" json:"IP,omitempty"`
+	Created              int64    `protobuf:"varint,5,opt,name=Created,proto3" json:"Created,omitempty"`
+	LastSeen             int64    `protobuf:"varint,6,opt,name=LastSeen,proto3" json:"LastSeen,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *AuthDevice) Reset()         { *m = AuthDevice{} }
+func (m *AuthDevice) String() string { return proto.CompactTextString(m) }
+func (*AuthDevice) ProtoMessage()    {}
+func (*AuthDevice) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8bbd6f3875b0e874, []int{4}
+}
+
+func (m *AuthDevice) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AuthDevice.Unmarshal(m, b)
+}
+func (m *AuthDevice) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AuthDevice.Marshal(b, m, deterministic)
+}
+func (m *AuthDevice) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AuthDevice.Merge(m, src)
+}
+func (m *AuthDevice) XXX_Size() int {
+	return xxx_messageInfo_AuthDevice.Size(m)
+}
+func (m *AuthDevice) XXX_DiscardUnknown() {
+	xxx_messageInfo_AuthDevice.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AuthDevice proto.InternalMessageInfo
+
+func (m *AuthDevice) GetID() string {
+	if m != nil {
+		return m.ID
+	}
+	return ""
+}
+
+func (m *AuthDevice) GetUserID() uint32 {
+	if m != nil {
+		return m.UserID
+	}
+	return 0
+}
+
+func (m *AuthDevice) GetUserAgent() string {
+	if m != nil {
+		return m.UserAgent
+	}
+	return ""
+}
+
+func (m *AuthDevice) GetIP() string {
+	if m != nil {
+		return m.IP
+	}
+	return ""
+}
+
+func (m *AuthDevice) GetCreated() int64 {
+	if m != nil {
+		return m.Created
+	}
+	return 0
+}
+
+func (m *AuthDevice) GetLastSeen() int64 {
+	if m != nil {
+		return m.LastSeen
+	}
+	return 0
+}
+
+type AuthDeviceList struct {
+	Devices              []*AuthDevice `protobuf:"bytes,1,rep,name=Devices,proto3" json:"Devices,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
+	XXX_unrecognized     []byte        `json:"-"`
+	XXX_sizecache        int32         `json:"-"`
+}
+
+func (m *AuthDeviceList) Reset()         { *m = AuthDeviceList{} }
+func (m *AuthDeviceList) String() string { return proto.CompactTextString(m) }
+func (*AuthDeviceList) ProtoMessage()    {}
+func (*AuthDeviceList) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8bbd6f3875b0e874, []int{5}
+}
+
+func (m *AuthDeviceList) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AuthDeviceList.Unmarshal(m, b)
+}
+func (m *AuthDeviceList) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AuthDeviceList.Marshal(b, m, deterministic)
+}
+func (m *AuthDeviceList) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AuthDeviceList.Merge(m, src)
+}
+func (m *AuthDeviceList) XXX_Size() int {
+	return xxx_messageInfo_AuthDeviceList.Size(m)
+}
+func (m *AuthDeviceList) XXX_DiscardUnknown() {
+	xxx_messageInfo_AuthDeviceList.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AuthDeviceList proto.InternalMessageInfo
+
+func (m *AuthDeviceList) GetDevices() []*AuthDevice {
+	if m != nil {
+		return m.Devices
+	}
+	return nil
+}
+
+type AuthRevokeIn struct {
+	UserID               uint32   `protobuf:"varint,1,opt,name=UserID,proto3" json:"UserID,omitempty"`
+	ID                   string   `protobuf:"bytes,2,opt,name=ID,proto3" json:"ID,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *AuthRevokeIn) Reset()         { *m = AuthRevokeIn{} }
+func (m *AuthRevokeIn) String() string { return proto.CompactTextString(m) }
+func (*AuthRevokeIn) ProtoMessage()    {}
+func (*AuthRevokeIn) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8bbd6f3875b0e874, []int{6}
+}
+
+func (m *AuthRevokeIn) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AuthRevokeIn.Unmarshal(m, b)
+}
+func (m *AuthRevokeIn) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AuthRevokeIn.Marshal(b, m, deterministic)
+}
+func (m *AuthRevokeIn) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AuthRevokeIn.Merge(m, src)
+}
+func (m *AuthRevokeIn) XXX_Size() int {
+	return xxx_messageInfo_AuthRevokeIn.Size(m)
+}
+func (m *AuthRevokeIn) XXX_DiscardUnknown() {
+	xxx_messageInfo_AuthRevokeIn.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AuthRevokeIn proto.InternalMessageInfo
+
+func (m *AuthRevokeIn) GetUserID() uint32 {
+	if m != nil {
+		return m.UserID
+	}
+	return 0
+}
+
+func (m *AuthRevokeIn) GetID() string {
+	if m != nil {
+		return m.ID
+	}
+	return ""
+}
+
 type AuthNothing struct {
 	Nothing              bool     `protobuf:"varint,1,opt,name=Nothing,proto3" json:"Nothing,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
@@ -176,7 +421,7 @@ func (m *AuthNothing) Reset()         { *m = AuthNothing{} }
 func (m *AuthNothing) String() string { return proto.CompactTextString(m) }
 func (*AuthNothing) ProtoMessage()    {}
 func (*AuthNothing) Descriptor() ([]byte, []int) {
-	return fileDescriptor_8bbd6f3875b0e874, []int{3}
+	return fileDescriptor_8bbd6f3875b0e874, []int{7}
 }
 
 func (m *AuthNothing) XXX_Unmarshal(b []byte) error {
@@ -208,30 +453,47 @@ func init() {
 	proto.RegisterType((*AuthSession)(nil), "session.AuthSession")
 	proto.RegisterType((*AuthUserIn)(nil), "session.AuthUserIn")
 	proto.RegisterType((*AuthCheckIn)(nil), "session.AuthCheckIn")
+	proto.RegisterType((*AuthRefreshIn)(nil), "session.AuthRefreshIn")
+	proto.RegisterType((*AuthDevice)(nil), "session.AuthDevice")
+	proto.RegisterType((*AuthDeviceList)(nil), "session.AuthDeviceList")
+	proto.RegisterType((*AuthRevokeIn)(nil), "session.AuthRevokeIn")
 	proto.RegisterType((*AuthNothing)(nil), "session.AuthNothing")
 }
 
 func init() { proto.RegisterFile("auth.proto", fileDescriptor_8bbd6f3875b0e874) }
 
 var fileDescriptor_8bbd6f3875b0e874 = []byte{
-	// 259 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xe2, 0xe2, 0x4a, 0x2c, 0x2d, 0xc9,
-	0xd0, 0x2b, 0x28, 0xca, 0x2f, 0xc9, 0x17, 0x62, 0x2f, 0x4e, 0x2d, 0x2e, 0xce, 0xcc, 0xcf, 0x53,
-	0x72, 0xe7, 0xe2, 0x76, 0x2c, 0x2d, 0xc9, 0x08, 0x86, 0x70, 0x85, 0xf8, 0xb8, 0x98, 0x3c, 0x5d,
-	0x24, 0x18, 0x15, 0x18, 0x35, 0x38, 0x83, 0x98, 0x3c, 0x5d, 0x84, 0xc4, 0xb8, 0xd8, 0x42, 0x8b,
-	0x53, 0x8b, 0x3c, 0x5d, 0x24, 0x98, 0x14, 0x18, 0x35, 0x78, 0x83, 0xa0, 0x3c, 0x21, 0x01, 0x2e,
-	0xe6, 0xb0, 0xd4, 0x22, 0x09, 0x66, 0x05, 0x46, 0x0d, 0xd6, 0x20, 0x10, 0x53, 0xc9, 0x8c, 0x8b,
-	0x0b, 0x64, 0x10, 0x58, 0x3e, 0x0f, 0x49, 0x1f, 0x23, 0x36, 0x7d, 0x4c, 0x08, 0x7d, 0xea, 0x10,
-	0x07, 0x38, 0x67, 0xa4, 0x26, 0x67, 0x7b, 0xe6, 0x09, 0x49, 0x70, 0xb1, 0x83, 0xdc, 0xe2, 0x9d,
-	0x5a, 0x09, 0x75, 0x05, 0x8c, 0x0b, 0x53, 0xe8, 0x97, 0x5f, 0x92, 0x91, 0x99, 0x97, 0x0e, 0x52,
-	0x08, 0x65, 0x82, 0x15, 0x72, 0x04, 0xc1, 0xb8, 0x46, 0xef, 0x19, 0xb9, 0x58, 0x40, 0x2a, 0x85,
-	0x4c, 0xb9, 0x58, 0xc1, 0xc6, 0x0a, 0x89, 0xe8, 0x41, 0xbd, 0xab, 0x87, 0x64, 0x95, 0x14, 0xaa,
-	0x28, 0x34, 0x04, 0x94, 0x18, 0x84, 0x4c, 0xb9, 0xd8, 0x9c, 0x8b, 0x52, 0x13, 0x4b, 0x52, 0x85,
-	0x84, 0x51, 0x54, 0x40, 0xbc, 0x86, 0x53, 0x9b, 0x1d, 0x17, 0x9f, 0x4b, 0x6a, 0x71, 0x49, 0x51,
-	0x7e, 0xa5, 0x73, 0x69, 0x51, 0x51, 0x6a, 0x5e, 0x89, 0x10, 0x56, 0x95, 0x68, 0xfa, 0xa1, 0x8e,
-	0x56, 0x62, 0x10, 0xb2, 0xe4, 0xe2, 0x82, 0xea, 0x77, 0xcc, 0xc9, 0x21, 0xc6, 0x6a, 0xb8, 0xd6,
-	0x24, 0x36, 0x70, 0xa4, 0x1a, 0x03, 0x02, 0x00, 0x00, 0xff, 0xff, 0xc4, 0xd0, 0xe5, 0x69, 0xe2,
-	0x01, 0x00, 0x00,
+	// 476 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x54, 0x4d, 0x6f, 0xd3, 0x40,
+	0x10, 0xed, 0xda, 0x89, 0xd3, 0x4c, 0xd3, 0x08, 0x2d, 0xa5, 0x58, 0x15, 0x07, 0xcb, 0x17, 0x7c,
+	0x21, 0x87, 0xa2, 0x16, 0x21, 0x10, 0x10, 0xc5, 0x17, 0x8b, 0x0a, 0x45, 0x0e, 0x70, 0xe3, 0x10,
+	0x9a, 0x69, 0x6d, 0xa5, 0xac, 0xd1, 0xee, 0xa6, 0x52, 0xff, 0x09, 0x3f, 0x8d, 0x9f, 0x83, 0xf6,
+	0x2b, 0xae, 0x2b, 0x07, 0xd5, 0x37, 0x3f, 0x7b, 0xde, 0xcc, 0x7b, 0xf3, 0x46, 0x06, 0x58, 0x6e,
+	0x64, 0x31, 0xf9, 0xcd, 0x2b, 0x59, 0xd1, 0x81, 0x40, 0x21, 0xca, 0x8a, 0xc5, 0x3f, 0xe0, 0x60,
+	0xba, 0x91, 0xc5, 0xc2, 0x40, 0x3a, 0x06, 0x2f, 0x4b, 0x43, 0x12, 0x91, 0x64, 0x98, 0x7b, 0x59,
+	0x4a, 0x8f, 0x21, 0xf8, 0x26, 0x90, 0x67, 0x69, 0xe8, 0x45, 0x24, 0x39, 0xcc, 0x2d, 0xa2, 0x4f,
+	0xc0, 0xff, 0x8e, 0x3c, 0xf4, 0x23, 0x92, 0xf4, 0x73, 0xf5, 0x48, 0x8f, 0xa0, 0xff, 0xb5, 0x5a,
+	0x23, 0x0b, 0x7b, 0x9a, 0x6c, 0x40, 0xbc, 0x02, 0x50, 0xed, 0x35, 0x8b, 0xdd, 0xeb, 0x46, 0xda,
+	0xba, 0x79, 0x75, 0xb7, 0x17, 0x30, 0x54, 0xdf, 0xa6, 0xd7, 0xc8, 0xa4, 0x9e, 0x32, 0xcc, 0xeb,
+	0x17, 0x5a, 0xe5, 0xdc, 0x0e, 0xf2, 0xb2, 0x79, 0xfc, 0xd2, 0x98, 0x98, 0x15, 0x78, 0xb9, 0xce,
+	0x18, 0x0d, 0x61, 0xa0, 0xfc, 0x7c, 0xc6, 0x3b, 0xeb, 0xc4, 0xc1, 0x78, 0x01, 0x87, 0xaa, 0x30,
+	0xc7, 0x2b, 0x8e, 0xa2, 0xc8, 0x58, 0xad, 0x9a, 0xdc, 0x53, 0xdd, 0x9c, 0xee, 0xb5, 0x4f, 0xf7,
+	0xb7, 0xd3, 0xff, 0x10, 0x63, 0x32, 0xc5, 0xdb, 0xf2, 0x12, 0x1f, 0xbd, 0xc2, 0x4e, 0x16, 0x95,
+	0xa7, 0x19, 0xc7, 0xa5, 0xc4, 0x55, 0xd8, 0x8f, 0x48, 0xe2, 0xe7, 0x0e, 0xd2, 0x13, 0xd8, 0xbf,
+	0x58, 0x0a, 0xb9, 0x40, 0x64, 0x61, 0xa0, 0x3f, 0x6d, 0x71, 0xfc, 0x11, 0xc6, 0xb5, 0xb2, 0x8b,
+	0x52, 0x48, 0xfa, 0x0a, 0x06, 0x06, 0x89, 0x90, 0x44, 0x7e, 0x72, 0x70, 0xfa, 0x74, 0x62, 0x4f,
+	0x61, 0x52, 0x57, 0xe6, 0xae, 0x26, 0x3e, 0x87, 0x91, 0x59, 0xd8, 0x6d, 0xb5, 0xc6, 0xff, 0x24,
+	0x68, 0x4c, 0x7b, 0xce, 0xb4, 0x4b, 0xe4, 0x4b, 0x25, 0x8b, 0x92, 0x5d, 0x2b, 0xf5, 0xf6, 0x51,
+	0xf3, 0xf6, 0x73, 0x07, 0x4f, 0xff, 0xf6, 0xa0, 0xa7, 0x2a, 0xe9, 0x19, 0xf4, 0x75, 0x7e, 0xf4,
+	0xa8, 0x21, 0xc8, 0x66, 0x7a, 0xd2, 0x7c, 0x6b, 0xcf, 0x35, 0xde, 0xa3, 0x67, 0x10, 0x98, 0x45,
+	0xd0, 0xa6, 0x11, 0x73, 0x71, 0x3b, 0x69, 0x6f, 0x61, 0x60, 0x8f, 0x80, 0x1e, 0x37, 0x4a, 0xb6,
+	0xa7, 0xb1, 0x93, 0xfa, 0x01, 0xc6, 0x29, 0x0a, 0xc9, 0xab, 0xbb, 0xd9, 0x86, 0x73, 0x95, 0x55,
+	0x6b, 0xe5, 0x03, 0xbe, 0xf5, 0xab, 0x47, 0x83, 0xe5, 0x4f, 0x6f, 0x6e, 0x1e, 0xa3, 0xba, 0xa6,
+	0x9e, 0x43, 0x4f, 0x87, 0xd8, 0x4a, 0x7a, 0xde, 0x12, 0xa4, 0xaa, 0x8e, 0xf7, 0xe8, 0x1b, 0x08,
+	0x4c, 0x82, 0xf4, 0xd9, 0x03, 0xb3, 0x26, 0xd6, 0x9d, 0x03, 0xdf, 0xc1, 0x68, 0x21, 0x97, 0x5c,
+	0xce, 0x91, 0xad, 0x54, 0x8e, 0x9d, 0x76, 0xfc, 0x1e, 0x46, 0x3a, 0x3d, 0x47, 0xee, 0x16, 0xec,
+	0x27, 0x18, 0xcf, 0x2a, 0x76, 0x55, 0xf2, 0x5f, 0x8e, 0xdf, 0x31, 0xa8, 0x9f, 0x81, 0xfe, 0xd5,
+	0xbd, 0xfe, 0x37, 0x00, 0x56, 0x3b, 0x4b, 0x2f, 0xf8, 0x04, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -248,8 +510,15 @@ const _ = grpc.SupportPackageIsVersion4
 type AuthClient interface {
 	Check(ctx context.Context, in *AuthCheckIn, opts ...grpc.CallOption) (*AuthSession, error)
 	Create(ctx context.Context, in *AuthUserIn, opts ...grpc.CallOption) (*AuthSession, error)
+	Refresh(ctx context.Context, in *AuthRefreshIn, opts ...grpc.CallOption) (*AuthSession, error)
 	DestroyCurrent(ctx context.Context, in *AuthSession, opts ...grpc.CallOption) (*AuthNothing, error)
 	DestroyAll(ctx context.Context, in *AuthUserIn, opts ...grpc.CallOption) (*AuthNothing, error)
+	List(ctx context.Context, in *AuthUserIn, opts ...grpc.CallOption) (*AuthDeviceList, error)
+	Revoke(ctx context.Context, in *AuthRevokeIn, opts ...grpc.CallOption) (*AuthNothing, error)
+	// вход со вторым фактором: токен ожидания в AuthSession.Token
+	StartPending(ctx context.Context, in *AuthUserIn, opts ...grpc.CallOption) (*AuthSession, error)
+	CheckPending(ctx context.Context, in *AuthCheckIn, opts ...grpc.CallOption) (*AuthSession, error)
+	ConfirmPending(ctx context.Context, in *AuthRefreshIn, opts ...grpc.CallOption) (*AuthSession, error)
 }
 
 type authClient struct {
@@ -278,6 +547,15 @@ func (c *authClient) Create(ctx context.Context, in *AuthUserIn, opts ...grpc.Ca
 	return out, nil
 }
 
+func (c *authClient) Refresh(ctx context.Context, in *AuthRefreshIn, opts ...grpc.CallOption) (*AuthSession, error) {
+	out := new(AuthSession)
+	err := c.cc.Invoke(ctx, "/session.Auth/Refresh", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *authClient) DestroyCurrent(ctx context.Context, in *AuthSession, opts ...grpc.CallOption) (*AuthNothing, error) {
 	out := new(AuthNothing)
 	err := c.cc.Invoke(ctx, "/session.Auth/DestroyCurrent", in, out, opts...)
@@ -296,12 +574,64 @@ func (c *authClient) DestroyAll(ctx context.Context, in *AuthUserIn, opts ...grp
 	return out, nil
 }
 
+func (c *authClient) List(ctx context.Context, in *AuthUserIn, opts ...grpc.CallOption) (*AuthDeviceList, error) {
+	out := new(AuthDeviceList)
+	err := c.cc.Invoke(ctx, "/session.Auth/List", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authClient) Revoke(ctx context.Context, in *AuthRevokeIn, opts ...grpc.CallOption) (*AuthNothing, error) {
+	out := new(AuthNothing)
+	err := c.cc.Invoke(ctx, "/session.Auth/Revoke", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authClient) StartPending(ctx context.Context, in *AuthUserIn, opts ...grpc.CallOption) (*AuthSession, error) {
+	out := new(AuthSession)
+	err := c.cc.Invoke(ctx, "/session.Auth/StartPending", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authClient) CheckPending(ctx context.Context, in *AuthCheckIn, opts ...grpc.CallOption) (*AuthSession, error) {
+	out := new(AuthSession)
+	err := c.cc.Invoke(ctx, "/session.Auth/CheckPending", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authClient) ConfirmPending(ctx context.Context, in *AuthRefreshIn, opts ...grpc.CallOption) (*AuthSession, error) {
+	out := new(AuthSession)
+	err := c.cc.Invoke(ctx, "/session.Auth/ConfirmPending", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // AuthServer is the server API for Auth service.
 type AuthServer interface {
 	Check(context.Context, *AuthCheckIn) (*AuthSession, error)
 	Create(context.Context, *AuthUserIn) (*AuthSession, error)
+	Refresh(context.Context, *AuthRefreshIn) (*AuthSession, error)
 	DestroyCurrent(context.Context, *AuthSession) (*AuthNothing, error)
 	DestroyAll(context.Context, *AuthUserIn) (*AuthNothing, error)
+	List(context.Context, *AuthUserIn) (*AuthDeviceList, error)
+	Revoke(context.Context, *AuthRevokeIn) (*AuthNothing, error)
+	// вход со вторым фактором: токен ожидания в AuthSession.Token
+	StartPending(context.Context, *AuthUserIn) (*AuthSession, error)
+	CheckPending(context.Context, *AuthCheckIn) (*AuthSession, error)
+	ConfirmPending(context.Context, *AuthRefreshIn) (*AuthSession, error)
 }
 
 // UnimplementedAuthServer can be embedded to have forward compatible implementations.
@@ -314,12 +644,30 @@ func (*UnimplementedAuthServer) Check(ctx context.Context, req *AuthCheckIn) (*A
 func (*UnimplementedAuthServer) Create(ctx context.Context, req *AuthUserIn) (*AuthSession, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Create not implemented")
 }
+func (*UnimplementedAuthServer) Refresh(ctx context.Context, req *AuthRefreshIn) (*AuthSession, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Refresh not implemented")
+}
 func (*UnimplementedAuthServer) DestroyCurrent(ctx context.Context, req *AuthSession) (*AuthNothing, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method DestroyCurrent not implemented")
 }
 func (*UnimplementedAuthServer) DestroyAll(ctx context.Context, req *AuthUserIn) (*AuthNothing, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method DestroyAll not implemented")
 }
+func (*UnimplementedAuthServer) List(ctx context.Context, req *AuthUserIn) (*AuthDeviceList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method List not implemented")
+}
+func (*UnimplementedAuthServer) Revoke(ctx context.Context, req *AuthRevokeIn) (*AuthNothing, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Revoke not implemented")
+}
+func (*UnimplementedAuthServer) StartPending(ctx context.Context, req *AuthUserIn) (*AuthSession, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method StartPending not implemented")
+}
+func (*UnimplementedAuthServer) CheckPending(ctx context.Context, req *AuthCheckIn) (*AuthSession, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CheckPending not implemented")
+}
+func (*UnimplementedAuthServer) ConfirmPending(ctx context.Context, req *AuthRefreshIn) (*AuthSession, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ConfirmPending not implemented")
+}
 
 func RegisterAuthServer(s *grpc.Server, srv AuthServer) {
 	s.RegisterService(&_Auth_serviceDesc, srv)
@@ -361,6 +709,24 @@ func _Auth_Create_Handler(srv interface{}, ctx context.Context, dec func(interfa
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Auth_Refresh_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AuthRefreshIn)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthServer).Refresh(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/session.Auth/Refresh",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthServer).Refresh(ctx, req.(*AuthRefreshIn))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Auth_DestroyCurrent_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(AuthSession)
 	if err := dec(in); err != nil {
@@ -397,6 +763,96 @@ func _Auth_DestroyAll_Handler(srv interface{}, ctx context.Context, dec func(int
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Auth_List_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AuthUserIn)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthServer).List(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/session.Auth/List",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthServer).List(ctx, req.(*AuthUserIn))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Auth_Revoke_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AuthRevokeIn)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthServer).Revoke(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/session.Auth/Revoke",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthServer).Revoke(ctx, req.(*AuthRevokeIn))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Auth_StartPending_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AuthUserIn)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthServer).StartPending(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/session.Auth/StartPending",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthServer).StartPending(ctx, req.(*AuthUserIn))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Auth_CheckPending_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AuthCheckIn)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthServer).CheckPending(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/session.Auth/CheckPending",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthServer).CheckPending(ctx, req.(*AuthCheckIn))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Auth_ConfirmPending_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AuthRefreshIn)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthServer).ConfirmPending(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/session.Auth/ConfirmPending",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthServer).ConfirmPending(ctx, req.(*AuthRefreshIn))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _Auth_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "session.Auth",
 	HandlerType: (*AuthServer)(nil),
@@ -409,6 +865,10 @@ var _Auth_serviceDesc = grpc.ServiceDesc{
 			MethodName: "Create",
 			Handler:    _Auth_Create_Handler,
 		},
+		{
+			MethodName: "Refresh",
+			Handler:    _Auth_Refresh_Handler,
+		},
 		{
 			MethodName: "DestroyCurrent",
 			Handler:    _Auth_DestroyCurrent_Handler,
@@ -417,6 +877,26 @@ var _Auth_serviceDesc = grpc.ServiceDesc{
 			MethodName: "DestroyAll",
 			Handler:    _Auth_DestroyAll_Handler,
 		},
+		{
+			MethodName: "List",
+			Handler:    _Auth_List_Handler,
+		},
+		{
+			MethodName: "Revoke",
+			Handler:    _Auth_Revoke_Handler,
+		},
+		{
+			MethodName: "StartPending",
+			Handler:    _Auth_StartPending_Handler,
+		},
+		{
+			MethodName: "CheckPending",
+			Handler:    _Auth_CheckPending_Handler,
+		},
+		{
+			MethodName: "ConfirmPending",
+			Handler:    _Auth_ConfirmPending_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "auth.proto",
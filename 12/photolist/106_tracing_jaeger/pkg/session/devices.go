@@ -0,0 +1,168 @@
+package session
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"photolist/pkg/logging"
+	"photolist/pkg/utils/httputils"
+)
+
+// Device - одна сессия пользователя (браузер, телефон)
+// ID публичный, по нему сессию можно отозвать, но не войти
+type Device struct {
+	ID        string    `json:"id"`
+	UserID    uint32    `json:"-"`
+	UserAgent string    `json:"user_agent"`
+	IP        string    `json:"ip"`
+	CreatedAt time.Time `json:"created_at"`
+	LastSeen  time.Time `json:"last_seen"`
+	Current   bool      `json:"current"`
+}
+
+// DeviceManager - сессии, которые можно посмотреть и отозвать по одной
+// stateless jwt-сессии его не реализуют
+type DeviceManager interface {
+	List(ctx context.Context, userID uint32) ([]*Device, error)
+	Revoke(ctx context.Context, userID uint32, id string) error
+}
+
+// Refresher - менеджер умеет продлевать сессию без логина, когда Check не прошёл
+type Refresher interface {
+	Refresh(context.Context, http.ResponseWriter, *http.Request) (*Session, error)
+}
+
+// DeviceStore - хранилище сессий устройств: в базе напрямую или через grpc-сервис auth
+// токен устройства имеет вид id.gen.mac, gen растёт при каждой ротации
+// ver пользователя запоминается при входе, его увеличение (смена пароля) убивает все сессии
+// StartPending/Pending/Confirm - вход со вторым фактором, см. TwoStep
+type DeviceStore interface {
+	Start(ctx context.Context, userID uint32, ver int32, client ClientInfo) (*Device, string, error)
+	StartPending(ctx context.Context, userID uint32, ver int32, client ClientInfo) (string, error)
+	Pending(ctx context.Context, token string) (uint32, error)
+	Confirm(ctx context.Context, token string, client ClientInfo) (*Device, string, error)
+	Verify(ctx context.Context, token string) (*Device, error)
+	Rotate(ctx context.Context, token string, client ClientInfo) (*Device, string, error)
+	List(ctx context.Context, userID uint32) ([]*Device, error)
+	Revoke(ctx context.Context, userID uint32, id string) error
+	RevokeAll(ctx context.Context, userID uint32) error
+}
+
+var (
+	errDeviceNotFound = errors.New("session not found")
+	errTokenReused    = errors.New("refresh token reused")
+)
+
+func IsErrDeviceNotFound(err error) bool {
+	return err == errDeviceNotFound
+}
+
+func IsErrTokenReused(err error) bool {
+	return err == errTokenReused
+}
+
+// ClientInfo - откуда пришёл пользователь, записывается в сессию
+type ClientInfo struct {
+	UserAgent string
+	IP        string
+}
+
+const clientKey ctxKey = 2
+
+func clientFromRequest(r *http.Request) ClientInfo {
+	ua := r.UserAgent()
+	if len(ua) > 255 {
+		ua = ua[:255]
+	}
+	return ClientInfo{
+		UserAgent: ua,
+		IP:        httputils.ClientIP(r),
+	}
+}
+
+func clientFromContext(ctx context.Context) ClientInfo {
+	client, _ := ctx.Value(clientKey).(ClientInfo)
+	return client
+}
+
+// сколько живёт сессия устройства без активности
+const deviceTTL = 90 * 24 * time.Hour
+
+func setCookie(w http.ResponseWriter, name, value string, ttl time.Duration) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     name,
+		Value:    value,
+		Expires:  time.Now().Add(ttl),
+		Path:     "/",
+		HttpOnly: true,
+		SameSite: cookieSameSite,
+	})
+}
+
+func clearCookie(w http.ResponseWriter, name string) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     name,
+		Expires:  time.Now().AddDate(0, 0, -1),
+		Path:     "/",
+		HttpOnly: true,
+		SameSite: cookieSameSite,
+	})
+}
+
+// deviceSessions - в куке лежит токен устройства, без ротации
+// общая часть SessionsDB и SessionsGRPC, отличаются они только хранилищем
+type deviceSessions struct {
+	store DeviceStore
+}
+
+func (ds *deviceSessions) Check(ctx context.Context, r *http.Request) (*Session, error) {
+	sessionCookie, err := r.Cookie(cookieName)
+	if err == http.ErrNoCookie {
+		logging.FromContext(r.Context()).Debug("CheckSession no cookie")
+		return nil, ErrNoAuth
+	}
+	dev, err := ds.store.Verify(ctx, sessionCookie.Value)
+	if err != nil {
+		return nil, err
+	}
+	return &Session{
+		ID:     dev.ID,
+		UserID: dev.UserID,
+	}, nil
+}
+
+func (ds *deviceSessions) Create(ctx context.Context, w http.ResponseWriter, user UserInterface) error {
+	_, token, err := ds.store.Start(ctx, user.GetID(), user.GetVer(), clientFromContext(ctx))
+	if err != nil {
+		return err
+	}
+	setCookie(w, cookieName, token, deviceTTL)
+	return nil
+}
+
+func (ds *deviceSessions) DestroyCurrent(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	clearCookie(w, cookieName)
+	sess, err := SessionFromContext(r.Context())
+	if err != nil {
+		return nil
+	}
+	err = ds.store.Revoke(ctx, sess.UserID, sess.ID)
+	if err != nil && err != errDeviceNotFound {
+		return err
+	}
+	return nil
+}
+
+func (ds *deviceSessions) DestroyAll(ctx context.Context, w http.ResponseWriter, user UserInterface) error {
+	return ds.store.RevokeAll(ctx, user.GetID())
+}
+
+func (ds *deviceSessions) List(ctx context.Context, userID uint32) ([]*Device, error) {
+	return ds.store.List(ctx, userID)
+}
+
+func (ds *deviceSessions) Revoke(ctx context.Context, userID uint32, id string) error {
+	return ds.store.Revoke(ctx, userID, id)
+}
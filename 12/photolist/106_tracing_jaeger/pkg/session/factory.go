@@ -0,0 +1,71 @@
+package session
+
+import (
+	"database/sql"
+	"fmt"
+
+	"photolist/pkg/config"
+)
+
+// New выбирает менеджер сессий по session.type из конфига
+// store нужен grpc и refresh, его создаёт NewStore, а закрывает и проверяет в /readyz вызывающий
+func New(cfg *config.Config, db *sql.DB, store DeviceStore) (SessionManager, error) {
+	if err := cfg.CheckMode(); err != nil {
+		return nil, err
+	}
+	switch cfg.Session.Type {
+	case "db":
+		return NewSessionsDB(db), nil
+	case "grpc":
+		if store == nil {
+			return nil, fmt.Errorf("session type grpc needs a device store")
+		}
+		return NewSessionsGRPC(store), nil
+	case "jwt", "jwt_ver", "refresh":
+		// остальные подписывают куку сами
+	default:
+		return nil, fmt.Errorf("unknown session type %q", cfg.Session.Type)
+	}
+
+	if err := cfg.CheckSecret("session.secret", cfg.Session.Secret); err != nil {
+		return nil, err
+	}
+	switch cfg.Session.Type {
+	case "jwt":
+		return NewSessionsJWT(cfg.Session.Secret), nil
+	case "jwt_ver":
+		return NewSessionsJWTVer(cfg.Session.Secret, db), nil
+	}
+
+	if cfg.Session.AccessTTL <= 0 {
+		return nil, fmt.Errorf("session.access_ttl must be positive")
+	}
+	if store == nil {
+		return nil, fmt.Errorf("session type refresh needs a device store")
+	}
+	return NewSessionsRefresh(cfg.Session.Secret, db, store, cfg.Session.AccessTTL), nil
+}
+
+// NewStore - хранилище сессий устройств для New: у grpc это всегда сервис auth,
+// у refresh - по session.store, остальным оно не нужно и возвращается nil
+func NewStore(cfg *config.Config, db *sql.DB) (DeviceStore, error) {
+	storeType := cfg.Session.Store
+	switch cfg.Session.Type {
+	case "grpc":
+		storeType = "grpc"
+	case "refresh":
+	default:
+		return nil, nil
+	}
+
+	switch storeType {
+	case "db", "":
+		return NewDBStore(db, deviceTTL), nil
+	case "grpc":
+		if cfg.Session.GRPCAddr == "" {
+			return nil, fmt.Errorf("session.grpc_addr is empty")
+		}
+		return NewStoreGRPC(cfg.Session.GRPCAddr)
+	}
+	return nil, fmt.Errorf("unknown session store %q", storeType)
+}
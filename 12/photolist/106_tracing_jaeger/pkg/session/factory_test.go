@@ -0,0 +1,121 @@
+package session
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"photolist/pkg/config"
+)
+
+func testConfig(mode, typ, store string) *config.Config {
+	cfg := &config.Config{Mode: mode}
+	cfg.Session.Type = typ
+	cfg.Session.Store = store
+	cfg.Session.Secret = "secret"
+	cfg.Session.AccessTTL = time.Minute
+	cfg.Session.GRPCAddr = "127.0.0.1:8081"
+	return cfg
+}
+
+func TestNew(t *testing.T) {
+	defaultSecret := config.Defaults["session"].(map[string]string)["secret"]
+	cases := []struct {
+		name      string
+		cfg       *config.Config
+		withStore bool
+		expected  interface{}
+		err       string
+	}{
+		{"db", testConfig("dev", "db", ""), false, &SessionsDB{}, ""},
+		{"jwt", testConfig("dev", "jwt", ""), false, &SessionsJWT{}, ""},
+		{"jwt_ver", testConfig("dev", "jwt_ver", ""), false, &SessionsJWTVer{}, ""},
+		{"refresh", testConfig("dev", "refresh", "db"), true, &SessionsRefresh{}, ""},
+		{"grpc", testConfig("dev", "grpc", ""), true, &SessionsGRPC{}, ""},
+		{"refresh without store", testConfig("dev", "refresh", "db"), false, nil, "needs a device store"},
+		{"grpc without store", testConfig("dev", "grpc", ""), false, nil, "needs a device store"},
+		{"unknown type", testConfig("dev", "cookie", ""), false, nil, "unknown session type"},
+		{"unknown mode", testConfig("prod", "db", ""), false, nil, "unknown mode"},
+		{"default secret in production", func() *config.Config {
+			cfg := testConfig(config.ModeProduction, "jwt", "")
+			cfg.Session.Secret = defaultSecret
+			return cfg
+		}(), false, nil, "default value"},
+		{"no access ttl", func() *config.Config {
+			cfg := testConfig("dev", "refresh", "db")
+			cfg.Session.AccessTTL = 0
+			return cfg
+		}(), true, nil, "access_ttl"},
+	}
+	for _, c := range cases {
+		var store DeviceStore
+		if c.withStore {
+			store = NewDBStore(nil, deviceTTL)
+		}
+		sm, err := New(c.cfg, nil, store)
+		if c.err != "" {
+			if err == nil || !strings.Contains(err.Error(), c.err) {
+				t.Errorf("[%s] expected err %q, got %v", c.name, c.err, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("[%s] unexpected err: %v", c.name, err)
+			continue
+		}
+		if fmt.Sprintf("%T", sm) != fmt.Sprintf("%T", c.expected) {
+			t.Errorf("[%s] expected %T, got %T", c.name, c.expected, sm)
+		}
+	}
+}
+
+func TestNewStore(t *testing.T) {
+	cases := []struct {
+		name     string
+		cfg      *config.Config
+		expected interface{}
+		err      string
+	}{
+		{"db sessions need no store", testConfig("dev", "db", "grpc"), nil, ""},
+		{"jwt_ver needs no store", testConfig("dev", "jwt_ver", ""), nil, ""},
+		{"refresh in db", testConfig("dev", "refresh", "db"), &DBStore{}, ""},
+		{"refresh default", testConfig("dev", "refresh", ""), &DBStore{}, ""},
+		{"refresh in auth", testConfig("dev", "refresh", "grpc"), &StoreGRPC{}, ""},
+		{"grpc always auth", testConfig("dev", "grpc", "db"), &StoreGRPC{}, ""},
+		{"unknown store", testConfig("dev", "refresh", "redis"), nil, "unknown session store"},
+		{"no grpc addr", func() *config.Config {
+			cfg := testConfig("dev", "grpc", "")
+			cfg.Session.GRPCAddr = ""
+			return cfg
+		}(), nil, "grpc_addr is empty"},
+	}
+	for _, c := range cases {
+		store, err := NewStore(c.cfg, nil)
+		if c.err != "" {
+			if err == nil || !strings.Contains(err.Error(), c.err) {
+				t.Errorf("[%s] expected err %q, got %v", c.name, c.err, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("[%s] unexpected err: %v", c.name, err)
+			continue
+		}
+		if c.expected == nil {
+			if store != nil {
+				t.Errorf("[%s] expected no store, got %T", c.name, store)
+			}
+			continue
+		}
+		if fmt.Sprintf("%T", store) != fmt.Sprintf("%T", c.expected) {
+			t.Errorf("[%s] expected %T, got %T", c.name, c.expected, store)
+		}
+		// соединение с auth создаёт NewStore, закрывать его - вызывающему
+		if grpcStore, ok := store.(*StoreGRPC); ok {
+			if err := grpcStore.Close(); err != nil {
+				t.Errorf("[%s] close err: %v", c.name, err)
+			}
+		}
+	}
+}
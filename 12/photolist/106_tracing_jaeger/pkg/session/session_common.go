@@ -4,9 +4,12 @@ import (
 	"context"
 	"errors"
 	"net/http"
+	"strings"
 
-	"github.com/opentracing/opentracing-go"
-	"github.com/opentracing/opentracing-go/ext"
+	"photolist/pkg/apierr"
+	"photolist/pkg/logging"
+	"photolist/pkg/tracing"
+	"photolist/pkg/utils/httputils"
 )
 
 type Session struct {
@@ -44,6 +47,9 @@ const sessionKey ctxKey = 1
 
 const cookieName = "session_id"
 
+// Lax - куки сессии не уходят с чужих POST-форм, но переход по ссылке на сайт остаётся залогиненным
+const cookieSameSite = http.SameSiteLaxMode
+
 var (
 	ErrNoAuth = errors.New("No session found")
 )
@@ -56,33 +62,69 @@ func SessionFromContext(ctx context.Context) (*Session, error) {
 	return sess, nil
 }
 
+// ContextWithSession - то же, что делает AuthMiddleware, для тестов и внутренних вызовов
+func ContextWithSession(ctx context.Context, sess *Session) context.Context {
+	return context.WithValue(ctx, sessionKey, sess)
+}
+
 var (
 	noAuthUrls = map[string]struct{}{
-		"/user/login_oauth": struct{}{},
-		"/user/login":       struct{}{},
-		"/user/reg":         struct{}{},
-		"/":                 struct{}{},
+		"/user/login_oauth":  struct{}{},
+		"/user/login":        struct{}{},
+		"/user/login/2fa":    struct{}{},
+		"/user/reg":          struct{}{},
+		"/user/forgot":       struct{}{},
+		"/user/reset":        struct{}{},
+		"/user/verify_email": struct{}{},
+		"/":                  struct{}{},
 	}
+	// тут сессия не обязательна: без неё запрос идёт дальше анонимным, права проверяет сам хендлер
+	optionalAuthPrefixes = []string{"/img/"}
 )
 
+// AllowAnonymous добавляет префиксы, где сессия не обязательна, вызывается при старте до запуска сервера
+func AllowAnonymous(prefixes ...string) {
+	optionalAuthPrefixes = append(optionalAuthPrefixes, prefixes...)
+}
+
+func isOptionalAuth(path string) bool {
+	for _, prefix := range optionalAuthPrefixes {
+		if strings.HasPrefix(path, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
 func AuthMiddleware(sm SessionManager, next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		ctx := r.Context()
+		// на логине сессии ещё нет, а браузер и ip для неё уже нужны
+		ctx := context.WithValue(r.Context(), clientKey, clientFromRequest(r))
 
 		if _, ok := noAuthUrls[r.URL.Path]; ok {
-			next.ServeHTTP(w, r)
+			next.ServeHTTP(w, r.WithContext(ctx))
+			return
+		}
+		// запросы к базе или auth при проверке сессии попадают в этот спан
+		authCtx, span := tracing.StartChild(ctx, "auth")
+		sess, err := sm.Check(authCtx, r)
+		// куки в ответ на websocket-апгрейд не доходят до браузера,
+		// поэтому ротировать там нельзя - новый refresh-токен потеряется
+		refresher, ok := sm.(Refresher)
+		if err == ErrNoAuth && ok && r.Header.Get("Upgrade") == "" {
+			sess, err = refresher.Refresh(authCtx, w, r)
+		}
+		span.End()
+		if err == ErrNoAuth && isOptionalAuth(r.URL.Path) {
+			next.ServeHTTP(w, r.WithContext(ctx))
 			return
 		}
-		span, _ := opentracing.StartSpanFromContext(ctx, "auth")
-		ext.SpanKind.Set(span, "server")
-		ext.Component.Set(span, "auth")
-		sess, err := sm.Check(ctx, r)
-		span.Finish()
 		if err != nil {
-			http.Error(w, "No auth", http.StatusUnauthorized)
+			httputils.RespError(w, r, apierr.New(apierr.CodeUnauthenticated, "No auth"))
 			return
 		}
-		ctx = context.WithValue(ctx, sessionKey, sess)
+		ctx = ContextWithSession(ctx, sess)
+		logging.AddFields(ctx, logging.UserID(sess.UserID))
 		next.ServeHTTP(w, r.WithContext(ctx))
 	})
 }
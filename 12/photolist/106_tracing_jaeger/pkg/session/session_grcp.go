@@ -3,124 +3,214 @@ package session
 import (
 	"context"
 	"fmt"
-	"log"
-	"net/http"
+	"sync"
 	"time"
 
-	"github.com/opentracing/opentracing-go"
-	"github.com/opentracing/opentracing-go/ext"
+	grpc_prometheus "github.com/grpc-ecosystem/go-grpc-prometheus"
 	"google.golang.org/grpc"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 	"google.golang.org/grpc/metadata"
 
 	"photolist/pkg/middleware"
-	"photolist/pkg/utils/traceutils"
+	"photolist/pkg/tracing"
 )
 
 var (
 	_ SessionManager = (*SessionsGRPC)(nil)
+	_ DeviceManager  = (*SessionsGRPC)(nil)
+	_ DeviceStore    = (*StoreGRPC)(nil)
 )
 
+// AuthServiceName - полное имя сервиса из auth.proto, под ним auth отдаёт grpc health
+const AuthServiceName = "session.Auth"
+
+// SessionsGRPC - как SessionsDB, только сессии проверяет сервис auth
 type SessionsGRPC struct {
+	deviceSessions
+}
+
+// store - обычно StoreGRPC, соединение с auth закрывает тот, кто его создал
+func NewSessionsGRPC(store DeviceStore) *SessionsGRPC {
+	return &SessionsGRPC{
+		deviceSessions: deviceSessions{
+			store: store,
+		},
+	}
+}
+
+// ctxWithRequestID - спан и traceparent ставит tracing.UnaryClientInterceptor, тут только X-Request-ID
+func ctxWithRequestID(ctx context.Context) context.Context {
+	return metadata.AppendToOutgoingContext(ctx, "X-Request-ID", middleware.RequestIDFromContext(ctx))
+}
+
+// StoreGRPC - сессии устройств хранит сервис auth
+type StoreGRPC struct {
+	conn   *grpc.ClientConn
 	client AuthClient
+	health healthpb.HealthClient
 }
 
-func NewSessionsGRPC(addr string) (*SessionsGRPC, error) {
-	grcpConn, err := grpc.Dial(addr, grpc.WithInsecure())
+// гистограмма времени вызовов у grpc_prometheus выключена по умолчанию,
+// включаем тут, чтобы её получил любой main, который ходит в auth
+var enableClientHistogram sync.Once
+
+func NewStoreGRPC(addr string) (*StoreGRPC, error) {
+	enableClientHistogram.Do(func() {
+		grpc_prometheus.EnableClientHandlingTimeHistogram()
+	})
+	grcpConn, err := grpc.Dial(addr,
+		grpc.WithInsecure(),
+		// метрики клиента auth: счётчики и гистограмма grpc_client_handling_seconds
+		grpc.WithChainUnaryInterceptor(
+			grpc_prometheus.UnaryClientInterceptor,
+			tracing.UnaryClientInterceptor,
+		),
+	)
 	if err != nil {
 		return nil, fmt.Errorf("cant connect to grpc")
 	}
-	return &SessionsGRPC{
+	return &StoreGRPC{
+		conn:   grcpConn,
 		client: NewAuthClient(grcpConn),
+		health: healthpb.NewHealthClient(grcpConn),
 	}, nil
 }
 
-func ctxWithTrace(ctx context.Context, opName string) (opentracing.Span, context.Context) {
-	span, newCtx := opentracing.StartSpanFromContext(ctx, opName)
+// Ping спрашивает у auth стандартный grpc health, для /readyz
+func (st *StoreGRPC) Ping(ctx context.Context) error {
+	resp, err := st.health.Check(ctx, &healthpb.HealthCheckRequest{Service: AuthServiceName})
+	if err != nil {
+		return err
+	}
+	if resp.GetStatus() != healthpb.HealthCheckResponse_SERVING {
+		return fmt.Errorf("auth status %s", resp.GetStatus())
+	}
+	return nil
+}
 
-	md := metadata.Pairs("X-Request-ID", middleware.RequestIDFromContext(ctx))
-	ext.Component.Set(span, "grpc-session")
+func (st *StoreGRPC) Close() error {
+	return st.conn.Close()
+}
 
-	mdWriter := traceutils.MetadataReaderWriter{md}
-	opentracing.GlobalTracer().Inject(
-		span.Context(),
-		opentracing.HTTPHeaders,
-		mdWriter,
-	)
-	log.Println("----- ctxWithTrace md", md)
-	return span, metadata.NewOutgoingContext(newCtx, md)
+func authToDevice(s *AuthSession) *Device {
+	return &Device{
+		ID:     s.GetID(),
+		UserID: s.GetUserID(),
+	}
 }
 
-func (sm *SessionsGRPC) Check(ctx context.Context, r *http.Request) (*Session, error) {
-	sp, grpcCtx := ctxWithTrace(ctx, "Session.Check")
-	defer sp.Finish()
+func (st *StoreGRPC) Start(ctx context.Context, userID uint32, ver int32, client ClientInfo) (*Device, string, error) {
+	grpcCtx := ctxWithRequestID(ctx)
 
-	sessionCookie, err := r.Cookie(cookieName)
-	if err == http.ErrNoCookie {
-		log.Println("CheckSession no cookie")
-		return nil, ErrNoAuth
+	authSess, err := st.client.Create(grpcCtx, &AuthUserIn{
+		UserID:    userID,
+		Ver:       ver,
+		UserAgent: client.UserAgent,
+		IP:        client.IP,
+	})
+	if err != nil {
+		return nil, "", fromStatus(err)
 	}
+	return authToDevice(authSess), authSess.GetToken(), nil
+}
+
+func (st *StoreGRPC) Verify(ctx context.Context, token string) (*Device, error) {
+	grpcCtx := ctxWithRequestID(ctx)
 
-	authSess, err := sm.client.Check(grpcCtx, &AuthCheckIn{SessKey: sessionCookie.Value})
+	authSess, err := st.client.Check(grpcCtx, &AuthCheckIn{SessKey: token})
 	if err != nil {
-		return nil, err
+		return nil, fromStatus(err)
 	}
-
-	return &Session{
-		ID:     authSess.GetID(),
-		UserID: authSess.GetUserID(),
-	}, nil
+	return authToDevice(authSess), nil
 }
 
-func (sm *SessionsGRPC) Create(ctx context.Context, w http.ResponseWriter, user UserInterface) error {
-	sp, grpcCtx := ctxWithTrace(ctx, "Session.Create")
-	defer sp.Finish()
+func (st *StoreGRPC) Rotate(ctx context.Context, token string, client ClientInfo) (*Device, string, error) {
+	grpcCtx := ctxWithRequestID(ctx)
 
-	authSess, err := sm.client.Create(grpcCtx, &AuthUserIn{
-		UserID: user.GetID(),
-		Ver:    user.GetVer(),
+	authSess, err := st.client.Refresh(grpcCtx, &AuthRefreshIn{
+		Token:     token,
+		UserAgent: client.UserAgent,
+		IP:        client.IP,
 	})
 	if err != nil {
-		return err
-	}
-	cookie := &http.Cookie{
-		Name:    cookieName,
-		Value:   authSess.GetID(),
-		Expires: time.Now().Add(90 * 24 * time.Hour),
-		Path:    "/",
+		return nil, "", fromStatus(err)
 	}
-	http.SetCookie(w, cookie)
-	return nil
+	return authToDevice(authSess), authSess.GetToken(), nil
 }
 
-func (sm *SessionsGRPC) DestroyCurrent(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
-	sp, grpcCtx := ctxWithTrace(ctx, "Session.DestroyCurrent")
-	defer sp.Finish()
+func (st *StoreGRPC) List(ctx context.Context, userID uint32) ([]*Device, error) {
+	grpcCtx := ctxWithRequestID(ctx)
 
-	cookie := http.Cookie{
-		Name:    cookieName,
-		Expires: time.Now().AddDate(0, 0, -1),
-		Path:    "/",
+	list, err := st.client.List(grpcCtx, &AuthUserIn{UserID: userID})
+	if err != nil {
+		return nil, fromStatus(err)
+	}
+	res := make([]*Device, 0, len(list.GetDevices()))
+	for _, d := range list.GetDevices() {
+		res = append(res, &Device{
+			ID:        d.GetID(),
+			UserID:    d.GetUserID(),
+			UserAgent: d.GetUserAgent(),
+			IP:        d.GetIP(),
+			CreatedAt: time.Unix(d.GetCreated(), 0),
+			LastSeen:  time.Unix(d.GetLastSeen(), 0),
+		})
 	}
-	http.SetCookie(w, &cookie)
-	_, err := sm.client.DestroyCurrent(grpcCtx, &AuthSession{
-		ID: cookie.Value,
+	return res, nil
+}
+
+func (st *StoreGRPC) Revoke(ctx context.Context, userID uint32, id string) error {
+	grpcCtx := ctxWithRequestID(ctx)
+
+	_, err := st.client.Revoke(grpcCtx, &AuthRevokeIn{
+		UserID: userID,
+		ID:     id,
 	})
-	return err
+	return fromStatus(err)
+}
+
+func (st *StoreGRPC) RevokeAll(ctx context.Context, userID uint32) error {
+	grpcCtx := ctxWithRequestID(ctx)
+
+	_, err := st.client.DestroyAll(grpcCtx, &AuthUserIn{UserID: userID})
+	return fromStatus(err)
 }
 
-func (sm *SessionsGRPC) DestroyAll(ctx context.Context, w http.ResponseWriter, user UserInterface) error {
-	sp, grpcCtx := ctxWithTrace(ctx, "Session.DestroyAll")
-	defer sp.Finish()
+func (st *StoreGRPC) StartPending(ctx context.Context, userID uint32, ver int32, client ClientInfo) (string, error) {
+	grpcCtx := ctxWithRequestID(ctx)
 
-	cookie := http.Cookie{
-		Name:    cookieName,
-		Expires: time.Now().AddDate(0, 0, -1),
-		Path:    "/",
+	authSess, err := st.client.StartPending(grpcCtx, &AuthUserIn{
+		UserID:    userID,
+		Ver:       ver,
+		UserAgent: client.UserAgent,
+		IP:        client.IP,
+	})
+	if err != nil {
+		return "", fromStatus(err)
+	}
+	return authSess.GetToken(), nil
+}
+
+func (st *StoreGRPC) Pending(ctx context.Context, token string) (uint32, error) {
+	grpcCtx := ctxWithRequestID(ctx)
+
+	authSess, err := st.client.CheckPending(grpcCtx, &AuthCheckIn{SessKey: token})
+	if err != nil {
+		return 0, fromStatus(err)
 	}
-	http.SetCookie(w, &cookie)
-	_, err := sm.client.DestroyAll(grpcCtx, &AuthUserIn{
-		UserID: user.GetID(),
-		Ver:    user.GetVer(),
+	return authSess.GetUserID(), nil
+}
+
+func (st *StoreGRPC) Confirm(ctx context.Context, token string, client ClientInfo) (*Device, string, error) {
+	grpcCtx := ctxWithRequestID(ctx)
+
+	authSess, err := st.client.ConfirmPending(grpcCtx, &AuthRefreshIn{
+		Token:     token,
+		UserAgent: client.UserAgent,
+		IP:        client.IP,
 	})
-	return err
+	if err != nil {
+		return nil, "", fromStatus(err)
+	}
+	return authToDevice(authSess), authSess.GetToken(), nil
 }
@@ -3,10 +3,10 @@ package session
 import (
 	"context"
 	"fmt"
-	"log"
 	"net/http"
 	"time"
 
+	"photolist/pkg/logging"
 	"photolist/pkg/utils/randutils"
 
 	jwt "github.com/dgrijalva/jwt-go"
@@ -42,7 +42,7 @@ func (sm *SessionsJWT) parseSecretGetter(token *jwt.Token) (interface{}, error)
 func (sm *SessionsJWT) Check(ctx context.Context, r *http.Request) (*Session, error) {
 	sessionCookie, err := r.Cookie(cookieName)
 	if err == http.ErrNoCookie {
-		log.Println("CheckSession no cookie")
+		logging.FromContext(r.Context()).Debug("CheckSession no cookie")
 		return nil, ErrNoAuth
 	}
 
@@ -74,10 +74,11 @@ func (sm *SessionsJWT) Create(ctx context.Context, w http.ResponseWriter, user U
 	sessVal, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, data).SignedString(sm.Secret)
 
 	cookie := &http.Cookie{
-		Name:    cookieName,
-		Value:   sessVal,
-		Expires: time.Now().Add(90 * 24 * time.Hour),
-		Path:    "/",
+		Name:     cookieName,
+		Value:    sessVal,
+		Expires:  time.Now().Add(90 * 24 * time.Hour),
+		Path:     "/",
+		SameSite: cookieSameSite,
 	}
 	http.SetCookie(w, cookie)
 	return nil
@@ -85,9 +86,10 @@ func (sm *SessionsJWT) Create(ctx context.Context, w http.ResponseWriter, user U
 
 func (sm *SessionsJWT) DestroyCurrent(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
 	cookie := http.Cookie{
-		Name:    cookieName,
-		Expires: time.Now().AddDate(0, 0, -1),
-		Path:    "/",
+		Name:     cookieName,
+		Expires:  time.Now().AddDate(0, 0, -1),
+		Path:     "/",
+		SameSite: cookieSameSite,
 	}
 	http.SetCookie(w, &cookie)
 
@@ -4,13 +4,14 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
-	"log"
 	"net/http"
 	"time"
 
+	"photolist/pkg/logging"
 	"photolist/pkg/utils/randutils"
 
 	jwt "github.com/dgrijalva/jwt-go"
+	"go.uber.org/zap"
 )
 
 var (
@@ -46,7 +47,7 @@ func (sm *SessionsJWTVer) parseSecretGetter(token *jwt.Token) (interface{}, erro
 func (sm *SessionsJWTVer) Check(ctx context.Context, r *http.Request) (*Session, error) {
 	sessionCookie, err := r.Cookie(cookieName)
 	if err == http.ErrNoCookie {
-		log.Println("CheckSession no cookie")
+		logging.FromContext(r.Context()).Debug("CheckSession no cookie")
 		return nil, ErrNoAuth
 	}
 
@@ -61,18 +62,18 @@ func (sm *SessionsJWTVer) Check(ctx context.Context, r *http.Request) (*Session,
 	}
 
 	var ver int32
-	row := sm.DB.QueryRow(`SELECT ver FROM users WHERE id = ?`, payload.UserID)
+	row := sm.DB.QueryRowContext(ctx, `SELECT ver FROM users WHERE id = ?`, payload.UserID)
 	err = row.Scan(&ver)
 	if err == sql.ErrNoRows {
-		log.Println("CheckSession no rows")
+		logging.FromContext(r.Context()).Debug("CheckSession no rows")
 		return nil, ErrNoAuth
 	} else if err != nil {
-		log.Println("CheckSession err:", err)
+		logging.FromContext(ctx).Error("CheckSession err", zap.Error(err))
 		return nil, err
 	}
 
 	if payload.Ver != ver {
-		log.Println("CheckSession invalid version, sess", payload.Ver, "user", ver)
+		logging.FromContext(r.Context()).Info("CheckSession invalid version", zap.Int32("sess_ver", payload.Ver), zap.Int32("user_ver", ver))
 		return nil, ErrNoAuth
 	}
 
@@ -95,10 +96,11 @@ func (sm *SessionsJWTVer) Create(ctx context.Context, w http.ResponseWriter, use
 	sessVal, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, data).SignedString(sm.Secret)
 
 	cookie := &http.Cookie{
-		Name:    cookieName,
-		Value:   sessVal,
-		Expires: time.Now().Add(90 * 24 * time.Hour),
-		Path:    "/",
+		Name:     cookieName,
+		Value:    sessVal,
+		Expires:  time.Now().Add(90 * 24 * time.Hour),
+		Path:     "/",
+		SameSite: cookieSameSite,
 	}
 	http.SetCookie(w, cookie)
 	return nil
@@ -106,9 +108,10 @@ func (sm *SessionsJWTVer) Create(ctx context.Context, w http.ResponseWriter, use
 
 func (sm *SessionsJWTVer) DestroyCurrent(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
 	cookie := http.Cookie{
-		Name:    cookieName,
-		Expires: time.Now().AddDate(0, 0, -1),
-		Path:    "/",
+		Name:     cookieName,
+		Expires:  time.Now().AddDate(0, 0, -1),
+		Path:     "/",
+		SameSite: cookieSameSite,
 	}
 	http.SetCookie(w, &cookie)
 
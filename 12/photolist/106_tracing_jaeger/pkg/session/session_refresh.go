@@ -0,0 +1,184 @@
+package session
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"time"
+
+	jwt "github.com/dgrijalva/jwt-go"
+	"go.uber.org/zap"
+
+	"photolist/pkg/logging"
+)
+
+var (
+	_ SessionManager = (*SessionsRefresh)(nil)
+	_ DeviceManager  = (*SessionsRefresh)(nil)
+	_ Refresher      = (*SessionsRefresh)(nil)
+)
+
+const refreshCookieName = "refresh_token"
+
+// SessionsRefresh - короткоживущий jwt в session_id и refresh-токен устройства в отдельной куке
+// jwt проверяется по подписи и ver пользователя (один запрос по первичному ключу, как в SessionsJWTVer),
+// а когда протух - меняем refresh-токен на новый и выдаём новый jwt
+// смена пароля и бан действуют сразу, отзыв одного устройства - не позже чем через AccessTTL
+type SessionsRefresh struct {
+	Secret    []byte
+	DB        *sql.DB
+	Store     DeviceStore
+	AccessTTL time.Duration
+}
+
+type SessionRefreshClaims struct {
+	UserID uint32 `json:"uid"`
+	Ver    int32  `json:"ver,omitempty"`
+	jwt.StandardClaims
+}
+
+func NewSessionsRefresh(secret string, db *sql.DB, store DeviceStore, accessTTL time.Duration) *SessionsRefresh {
+	return &SessionsRefresh{
+		Secret:    []byte(secret),
+		DB:        db,
+		Store:     store,
+		AccessTTL: accessTTL,
+	}
+}
+
+// userVer - текущий ver пользователя, удалённый пользователь - ErrNoAuth
+func (sm *SessionsRefresh) userVer(ctx context.Context, userID uint32) (int32, error) {
+	var ver int32
+	err := sm.DB.QueryRowContext(ctx, "SELECT ver FROM users WHERE id = ?", userID).Scan(&ver)
+	if err == sql.ErrNoRows {
+		return 0, ErrNoAuth
+	}
+	return ver, err
+}
+
+func (sm *SessionsRefresh) parseSecretGetter(token *jwt.Token) (interface{}, error) {
+	method, ok := token.Method.(*jwt.SigningMethodHMAC)
+	if !ok || method.Alg() != "HS256" {
+		return nil, fmt.Errorf("bad sign method")
+	}
+	return sm.Secret, nil
+}
+
+func (sm *SessionsRefresh) Check(ctx context.Context, r *http.Request) (*Session, error) {
+	sessionCookie, err := r.Cookie(cookieName)
+	if err == http.ErrNoCookie {
+		return nil, ErrNoAuth
+	}
+
+	payload := &SessionRefreshClaims{}
+	_, err = jwt.ParseWithClaims(sessionCookie.Value, payload, sm.parseSecretGetter)
+	if err != nil {
+		// протухший токен - обычная ситуация, дальше будет Refresh
+		return nil, ErrNoAuth
+	}
+
+	ver, err := sm.userVer(ctx, payload.UserID)
+	if err != nil && err != ErrNoAuth {
+		logging.FromContext(ctx).Error("CheckSession err", zap.Error(err))
+		return nil, err
+	}
+	// пароль сменили или пользователя забанили - Refresh тоже не пройдёт, ver устройства устарел
+	if err == ErrNoAuth || payload.Ver != ver {
+		return nil, ErrNoAuth
+	}
+
+	return &Session{
+		ID:     payload.Id,
+		UserID: payload.UserID,
+	}, nil
+}
+
+func (sm *SessionsRefresh) issueAccess(w http.ResponseWriter, dev *Device, ver int32) *Session {
+	data := SessionRefreshClaims{
+		UserID: dev.UserID,
+		Ver:    ver,
+		StandardClaims: jwt.StandardClaims{
+			ExpiresAt: time.Now().Add(sm.AccessTTL).Unix(),
+			IssuedAt:  time.Now().Unix(),
+			Id:        dev.ID,
+		},
+	}
+	sessVal, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, data).SignedString(sm.Secret)
+	setCookie(w, cookieName, sessVal, sm.AccessTTL)
+	return &Session{
+		ID:     dev.ID,
+		UserID: dev.UserID,
+	}
+}
+
+func (sm *SessionsRefresh) Refresh(ctx context.Context, w http.ResponseWriter, r *http.Request) (*Session, error) {
+	refreshCookie, err := r.Cookie(refreshCookieName)
+	if err == http.ErrNoCookie {
+		return nil, ErrNoAuth
+	}
+
+	dev, token, err := sm.Store.Rotate(ctx, refreshCookie.Value, clientFromContext(ctx))
+	if IsErrTokenReused(err) || err == ErrNoAuth {
+		clearCookie(w, cookieName)
+		clearCookie(w, refreshCookieName)
+		return nil, ErrNoAuth
+	}
+	if err != nil {
+		logging.FromContext(ctx).Error("refresh session err", zap.Error(err))
+		return nil, err
+	}
+	// Rotate уже сверил ver устройства с пользовательским, тут берём его для jwt
+	ver, err := sm.userVer(ctx, dev.UserID)
+	if err == ErrNoAuth {
+		clearCookie(w, cookieName)
+		clearCookie(w, refreshCookieName)
+		return nil, ErrNoAuth
+	}
+	if err != nil {
+		logging.FromContext(ctx).Error("refresh session err", zap.Error(err))
+		return nil, err
+	}
+
+	// пустой токен - ротацию уже сделал параллельный запрос, его кука и победит
+	if token != "" {
+		setCookie(w, refreshCookieName, token, deviceTTL)
+	}
+	return sm.issueAccess(w, dev, ver), nil
+}
+
+func (sm *SessionsRefresh) Create(ctx context.Context, w http.ResponseWriter, user UserInterface) error {
+	dev, token, err := sm.Store.Start(ctx, user.GetID(), user.GetVer(), clientFromContext(ctx))
+	if err != nil {
+		return err
+	}
+	setCookie(w, refreshCookieName, token, deviceTTL)
+	sm.issueAccess(w, dev, user.GetVer())
+	return nil
+}
+
+func (sm *SessionsRefresh) DestroyCurrent(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	clearCookie(w, cookieName)
+	clearCookie(w, refreshCookieName)
+	sess, err := SessionFromContext(r.Context())
+	if err != nil {
+		return nil
+	}
+	err = sm.Store.Revoke(ctx, sess.UserID, sess.ID)
+	if err != nil && err != errDeviceNotFound {
+		return err
+	}
+	return nil
+}
+
+func (sm *SessionsRefresh) DestroyAll(ctx context.Context, w http.ResponseWriter, user UserInterface) error {
+	return sm.Store.RevokeAll(ctx, user.GetID())
+}
+
+func (sm *SessionsRefresh) List(ctx context.Context, userID uint32) ([]*Device, error) {
+	return sm.Store.List(ctx, userID)
+}
+
+func (sm *SessionsRefresh) Revoke(ctx context.Context, userID uint32, id string) error {
+	return sm.Store.Revoke(ctx, userID, id)
+}
@@ -0,0 +1,168 @@
+package session
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	sqlmock "gopkg.in/DATA-DOG/go-sqlmock.v1"
+)
+
+func responseCookies(w *httptest.ResponseRecorder) map[string]*http.Cookie {
+	res := map[string]*http.Cookie{}
+	for _, c := range w.Result().Cookies() {
+		res[c.Name] = c
+	}
+	return res
+}
+
+func verRows(ver int32) *sqlmock.Rows {
+	return sqlmock.NewRows([]string{"ver"}).AddRow(ver)
+}
+
+func TestSessionsRefresh(t *testing.T) {
+	now := time.Now()
+	token := makeToken(testSeed, testSession, 0)
+	selectVer := `SELECT ver FROM users WHERE id = \?`
+
+	cases := []struct {
+		name    string
+		cookie  string
+		prepare func(mock sqlmock.Sqlmock)
+		ok      bool
+		refresh bool // выдан новый refresh-токен
+		cleared bool // куки стёрты
+	}{
+		{"ok", token, func(mock sqlmock.Sqlmock) {
+			mock.ExpectBegin()
+			mock.ExpectQuery(`FOR UPDATE`).WillReturnRows(deviceRows(0, now.Add(-time.Hour), now, 0))
+			mock.ExpectExec(`UPDATE sessions SET gen`).WillReturnResult(sqlmock.NewResult(0, 1))
+			mock.ExpectCommit()
+			// ver для нового jwt и проверка этого jwt
+			mock.ExpectQuery(selectVer).WithArgs(1).WillReturnRows(verRows(0))
+			mock.ExpectQuery(selectVer).WithArgs(1).WillReturnRows(verRows(0))
+		}, true, true, false},
+		// параллельный запрос уже поменял токен - выдаём только jwt
+		{"concurrent", token, func(mock sqlmock.Sqlmock) {
+			mock.ExpectBegin()
+			mock.ExpectQuery(`FOR UPDATE`).WillReturnRows(deviceRows(1, now, now, 0))
+			mock.ExpectCommit()
+			mock.ExpectQuery(selectVer).WithArgs(1).WillReturnRows(verRows(0))
+			mock.ExpectQuery(selectVer).WithArgs(1).WillReturnRows(verRows(0))
+		}, true, false, false},
+		{"reused", token, func(mock sqlmock.Sqlmock) {
+			mock.ExpectBegin()
+			mock.ExpectQuery(`FOR UPDATE`).WillReturnRows(deviceRows(2, now.Add(-time.Hour), now, 0))
+			mock.ExpectExec(`DELETE FROM sessions WHERE id = \?`).WithArgs(testSession).WillReturnResult(sqlmock.NewResult(0, 1))
+			mock.ExpectCommit()
+		}, false, false, true},
+		{"expired", token, func(mock sqlmock.Sqlmock) {
+			mock.ExpectBegin()
+			mock.ExpectQuery(`FOR UPDATE`).WillReturnRows(deviceRows(0, now.Add(-100*24*time.Hour), now.Add(-100*24*time.Hour), 0))
+			mock.ExpectExec(`DELETE FROM sessions WHERE id = \?`).WithArgs(testSession).WillReturnResult(sqlmock.NewResult(0, 1))
+			mock.ExpectCommit()
+		}, false, false, true},
+		{"no cookie", "", func(mock sqlmock.Sqlmock) {}, false, false, false},
+	}
+	for _, c := range cases {
+		db, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("cant create mock: %s", err)
+		}
+		sm := NewSessionsRefresh("secret", db, NewDBStore(db, deviceTTL), time.Minute)
+		c.prepare(mock)
+
+		r := httptest.NewRequest("GET", "/", nil)
+		if c.cookie != "" {
+			r.AddCookie(&http.Cookie{Name: refreshCookieName, Value: c.cookie})
+		}
+		w := httptest.NewRecorder()
+		sess, err := sm.Refresh(context.Background(), w, r)
+		if c.ok != (err == nil) {
+			t.Errorf("[%s] unexpected err: %v", c.name, err)
+		}
+		if !c.ok && err != ErrNoAuth {
+			t.Errorf("[%s] expected ErrNoAuth, got %v", c.name, err)
+		}
+
+		cookies := responseCookies(w)
+		if c.ok {
+			if sess == nil || sess.UserID != 1 || sess.ID != testSession {
+				t.Errorf("[%s] bad session: %+v", c.name, sess)
+			}
+			// новый jwt проходит Check
+			check := httptest.NewRequest("GET", "/", nil)
+			if access, ok := cookies[cookieName]; ok {
+				check.AddCookie(access)
+			}
+			if s, err := sm.Check(context.Background(), check); err != nil || s.UserID != 1 {
+				t.Errorf("[%s] access token rejected: %v", c.name, err)
+			}
+		}
+		if _, ok := cookies[refreshCookieName]; ok != (c.refresh || c.cleared) {
+			t.Errorf("[%s] unexpected refresh cookie: %v", c.name, cookies[refreshCookieName])
+		}
+		if c.refresh && cookies[refreshCookieName].Value == c.cookie {
+			t.Errorf("[%s] refresh token not rotated", c.name)
+		}
+		if c.cleared && (cookies[refreshCookieName].Value != "" || cookies[cookieName] == nil) {
+			t.Errorf("[%s] cookies not cleared", c.name)
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("[%s] there were unfulfilled expectations: %s", c.name, err)
+		}
+		db.Close()
+	}
+}
+
+// jwt с устаревшим ver не принимается сразу, не дожидаясь AccessTTL
+func TestSessionsRefreshCheck(t *testing.T) {
+	selectVer := `SELECT ver FROM users WHERE id = \?`
+	cases := []struct {
+		name     string
+		tokenVer int32
+		prepare  func(mock sqlmock.Sqlmock)
+		checkFn  func(error) bool
+	}{
+		{"ok", 2, func(mock sqlmock.Sqlmock) {
+			mock.ExpectQuery(selectVer).WithArgs(1).WillReturnRows(verRows(2))
+		}, func(err error) bool { return err == nil }},
+		{"ver changed", 2, func(mock sqlmock.Sqlmock) {
+			mock.ExpectQuery(selectVer).WithArgs(1).WillReturnRows(verRows(3))
+		}, func(err error) bool { return err == ErrNoAuth }},
+		{"user deleted", 2, func(mock sqlmock.Sqlmock) {
+			mock.ExpectQuery(selectVer).WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"ver"}))
+		}, func(err error) bool { return err == ErrNoAuth }},
+		{"db error", 2, func(mock sqlmock.Sqlmock) {
+			mock.ExpectQuery(selectVer).WithArgs(1).WillReturnError(fmt.Errorf("bad connection"))
+		}, func(err error) bool { return err != nil && err != ErrNoAuth }},
+	}
+	for _, c := range cases {
+		db, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("cant create mock: %s", err)
+		}
+		sm := NewSessionsRefresh("secret", db, NewDBStore(db, deviceTTL), time.Minute)
+		c.prepare(mock)
+
+		w := httptest.NewRecorder()
+		sm.issueAccess(w, &Device{ID: testSession, UserID: 1}, c.tokenVer)
+		r := httptest.NewRequest("GET", "/", nil)
+		r.AddCookie(responseCookies(w)[cookieName])
+
+		sess, err := sm.Check(context.Background(), r)
+		if !c.checkFn(err) {
+			t.Errorf("[%s] unexpected err: %v", c.name, err)
+		}
+		if err == nil && (sess.UserID != 1 || sess.ID != testSession) {
+			t.Errorf("[%s] bad session: %+v", c.name, sess)
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("[%s] there were unfulfilled expectations: %s", c.name, err)
+		}
+		db.Close()
+	}
+}
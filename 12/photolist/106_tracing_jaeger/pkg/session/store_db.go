@@ -0,0 +1,365 @@
+package session
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"strconv"
+	"strings"
+	"time"
+
+	"photolist/pkg/logging"
+	"photolist/pkg/utils/dbutils"
+	"photolist/pkg/utils/randutils"
+
+	"go.uber.org/zap"
+)
+
+var (
+	_ DeviceStore = (*DBStore)(nil)
+)
+
+const (
+	// last_seen_at обновляем не чаще раза в минуту, чтобы не писать в базу на каждый запрос
+	touchInterval = time.Minute
+	// параллельные запросы со старым токеном сразу после ротации - не кража,
+	// а несколько вкладок или xhr, которые пришли одновременно
+	rotateGrace = 30 * time.Second
+)
+
+// DBStore - сессии устройств в таблице sessions
+// в базе лежит только seed, из него через hmac считается токен любого поколения,
+// поэтому повторное использование старого токена можно отличить от подделки
+type DBStore struct {
+	DB  *sql.DB
+	TTL time.Duration // сколько живёт сессия без активности
+}
+
+func NewDBStore(db *sql.DB, ttl time.Duration) *DBStore {
+	return &DBStore{
+		DB:  db,
+		TTL: ttl,
+	}
+}
+
+type deviceRow struct {
+	Device
+	seed      []byte
+	gen       uint32
+	rotatedAt time.Time
+	ver       int32 // ver пользователя на момент входа
+	userVer   int32 // текущий
+}
+
+func tokenMAC(seed []byte, id string, gen uint32) string {
+	mac := hmac.New(sha256.New, seed)
+	mac.Write([]byte(id + "." + strconv.FormatUint(uint64(gen), 10)))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func makeToken(seed []byte, id string, gen uint32) string {
+	return id + "." + strconv.FormatUint(uint64(gen), 10) + "." + tokenMAC(seed, id, gen)
+}
+
+func parseToken(token string) (string, uint32, string, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return "", 0, "", ErrNoAuth
+	}
+	gen, err := strconv.ParseUint(parts[1], 10, 32)
+	if err != nil {
+		return "", 0, "", ErrNoAuth
+	}
+	return parts[0], uint32(gen), parts[2], nil
+}
+
+func (r *deviceRow) validMAC(gen uint32, mac string) bool {
+	return hmac.Equal([]byte(tokenMAC(r.seed, r.ID, gen)), []byte(mac))
+}
+
+// expired - сессия протухла или пользователь с тех пор сменил пароль (ver вырос)
+func (r *deviceRow) expired(ttl time.Duration) bool {
+	return r.ver != r.userVer || (ttl > 0 && time.Since(r.LastSeen) > ttl)
+}
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+const selectDevices = `SELECT s.id, s.user_id, s.seed, s.gen, UNIX_TIMESTAMP(s.rotated_at), s.user_agent, s.ip,
+	UNIX_TIMESTAMP(s.created_at), UNIX_TIMESTAMP(s.last_seen_at), s.ver, u.ver
+	FROM sessions s JOIN users u ON u.id = s.user_id`
+
+func scanDevice(row rowScanner) (*deviceRow, error) {
+	d := &deviceRow{}
+	var rotated, created, lastSeen int64
+	err := row.Scan(&d.ID, &d.UserID, &d.seed, &d.gen, &rotated, &d.UserAgent, &d.IP, &created, &lastSeen, &d.ver, &d.userVer)
+	if err != nil {
+		return nil, err
+	}
+	d.rotatedAt = time.Unix(rotated, 0)
+	d.CreatedAt = time.Unix(created, 0)
+	d.LastSeen = time.Unix(lastSeen, 0)
+	return d, nil
+}
+
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}
+
+func insertDevice(ctx context.Context, db execer, userID uint32, ver int32, client ClientInfo) (*Device, string, error) {
+	id := randutils.RandStringRunes(32)
+	seed := randutils.RandCryptBytes(32)
+	_, err := db.ExecContext(ctx, "INSERT INTO sessions(id, user_id, ver, seed, gen, rotated_at, user_agent, ip, created_at, last_seen_at) VALUES(?, ?, ?, ?, 0, NOW(), ?, ?, NOW(), NOW())",
+		id, userID, ver, seed, client.UserAgent, client.IP)
+	if err != nil {
+		return nil, "", err
+	}
+	now := time.Now()
+	dev := &Device{
+		ID:        id,
+		UserID:    userID,
+		UserAgent: client.UserAgent,
+		IP:        client.IP,
+		CreatedAt: now,
+		LastSeen:  now,
+	}
+	return dev, makeToken(seed, id, 0), nil
+}
+
+func (st *DBStore) Start(ctx context.Context, userID uint32, ver int32, client ClientInfo) (*Device, string, error) {
+	return insertDevice(ctx, st.DB, userID, ver, client)
+}
+
+// Verify проверяет токен без ротации - так работают обычные сессии в куке
+func (st *DBStore) Verify(ctx context.Context, token string) (*Device, error) {
+	id, gen, mac, err := parseToken(token)
+	if err != nil {
+		return nil, err
+	}
+	d, err := scanDevice(st.DB.QueryRowContext(ctx, selectDevices+" WHERE s.id = ?", id))
+	if err == sql.ErrNoRows {
+		return nil, ErrNoAuth
+	} else if err != nil {
+		return nil, err
+	}
+	if gen != d.gen || !d.validMAC(gen, mac) {
+		return nil, ErrNoAuth
+	}
+	if d.expired(st.TTL) {
+		st.DB.ExecContext(ctx, "DELETE FROM sessions WHERE id = ?", id)
+		return nil, ErrNoAuth
+	}
+	if time.Since(d.LastSeen) > touchInterval {
+		_, err = st.DB.ExecContext(ctx, "UPDATE sessions SET last_seen_at = NOW() WHERE id = ?", id)
+		if err != nil {
+			logging.FromContext(ctx).Error("session touch err", zap.Error(err))
+		}
+	}
+	return &d.Device, nil
+}
+
+// Rotate меняет токен на следующий
+// если пришёл валидный, но уже использованный токен - его украли (или украли текущий),
+// сессия удаляется целиком и войти заново придётся обоим
+// пустой новый токен значит, что ротацию только что сделал параллельный запрос
+func (st *DBStore) Rotate(ctx context.Context, token string, client ClientInfo) (*Device, string, error) {
+	id, gen, mac, err := parseToken(token)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var (
+		dev      *Device
+		newToken string
+		dead     error // сессию удалили, почему
+	)
+	err = dbutils.InTx(ctx, st.DB, func(tx *sql.Tx) error {
+		d, err := scanDevice(tx.QueryRowContext(ctx, selectDevices+" WHERE s.id = ? FOR UPDATE", id))
+		if err == sql.ErrNoRows {
+			return ErrNoAuth
+		} else if err != nil {
+			return err
+		}
+		if gen > d.gen || !d.validMAC(gen, mac) {
+			return ErrNoAuth
+		}
+
+		switch {
+		case d.expired(st.TTL):
+			dead = ErrNoAuth
+		case gen == d.gen:
+			d.gen++
+			_, err = tx.ExecContext(ctx, "UPDATE sessions SET gen = ?, rotated_at = NOW(), last_seen_at = NOW(), user_agent = ?, ip = ? WHERE id = ?",
+				d.gen, client.UserAgent, client.IP, id)
+			if err != nil {
+				return err
+			}
+			d.UserAgent, d.IP, d.LastSeen = client.UserAgent, client.IP, time.Now()
+			newToken = makeToken(d.seed, id, d.gen)
+		case gen+1 == d.gen && time.Since(d.rotatedAt) < rotateGrace:
+			// новый токен уже ушёл параллельному запросу
+		default:
+			logging.FromContext(ctx).Warn("refresh token reuse", zap.String("session_id", id), logging.UserID(d.UserID), zap.Uint32("gen", gen), zap.Uint32("current_gen", d.gen))
+			dead = errTokenReused
+		}
+		if dead != nil {
+			_, err = tx.ExecContext(ctx, "DELETE FROM sessions WHERE id = ?", id)
+			return err
+		}
+		dev = &d.Device
+		return nil
+	})
+	if err != nil {
+		return nil, "", err
+	}
+	if dead != nil {
+		return nil, "", dead
+	}
+	return dev, newToken, nil
+}
+
+func (st *DBStore) List(ctx context.Context, userID uint32) ([]*Device, error) {
+	rows, err := st.DB.QueryContext(ctx, selectDevices+" WHERE s.user_id = ? ORDER BY s.last_seen_at DESC", userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	res := []*Device{}
+	for rows.Next() {
+		d, err := scanDevice(rows)
+		if err != nil {
+			return nil, err
+		}
+		if d.expired(st.TTL) {
+			continue
+		}
+		res = append(res, &d.Device)
+	}
+	return res, rows.Err()
+}
+
+func (st *DBStore) Revoke(ctx context.Context, userID uint32, id string) error {
+	result, err := st.DB.ExecContext(ctx, "DELETE FROM sessions WHERE id = ? AND user_id = ?", id, userID)
+	if err != nil {
+		return err
+	}
+	affected, _ := result.RowsAffected()
+	if affected == 0 {
+		return errDeviceNotFound
+	}
+	return nil
+}
+
+func (st *DBStore) RevokeAll(ctx context.Context, userID uint32) error {
+	result, err := st.DB.ExecContext(ctx, "DELETE FROM sessions WHERE user_id = ?", userID)
+	if err != nil {
+		return err
+	}
+	affected, _ := result.RowsAffected()
+	logging.FromContext(ctx).Info("destroyed sessions", zap.Int64("count", affected), logging.UserID(userID))
+	return nil
+}
+
+// входы, которые ждут второй фактор, лежат отдельно от sessions,
+// чтобы Verify и Rotate не могли их принять за настоящую сессию
+type pendingRow struct {
+	userID   uint32
+	ver      int32
+	userVer  int32
+	seed     []byte
+	attempts int
+	created  time.Time
+}
+
+const selectPending = `SELECT p.user_id, p.ver, u.ver, p.seed, p.attempts, UNIX_TIMESTAMP(p.created_at)
+	FROM pending_logins p JOIN users u ON u.id = p.user_id WHERE p.id = ?`
+
+func (st *DBStore) StartPending(ctx context.Context, userID uint32, ver int32, client ClientInfo) (string, error) {
+	id := randutils.RandStringRunes(32)
+	seed := randutils.RandCryptBytes(32)
+	_, err := st.DB.ExecContext(ctx, "INSERT INTO pending_logins(id, user_id, ver, seed, attempts, user_agent, ip, created_at) VALUES(?, ?, ?, ?, 0, ?, ?, NOW())",
+		id, userID, ver, seed, client.UserAgent, client.IP)
+	if err != nil {
+		return "", err
+	}
+	return makeToken(seed, id, 0), nil
+}
+
+func (st *DBStore) loadPending(ctx context.Context, q func(context.Context, string, ...interface{}) *sql.Row, token string, lock string) (string, *pendingRow, error) {
+	id, gen, mac, err := parseToken(token)
+	if err != nil {
+		return "", nil, err
+	}
+	p := &pendingRow{}
+	var created int64
+	err = q(ctx, selectPending+lock, id).Scan(&p.userID, &p.ver, &p.userVer, &p.seed, &p.attempts, &created)
+	if err == sql.ErrNoRows {
+		return "", nil, ErrNoAuth
+	} else if err != nil {
+		return "", nil, err
+	}
+	p.created = time.Unix(created, 0)
+	if gen != 0 || !hmac.Equal([]byte(tokenMAC(p.seed, id, 0)), []byte(mac)) {
+		return "", nil, ErrNoAuth
+	}
+	return id, p, nil
+}
+
+// Pending возвращает пользователя, чей вход ждёт код, и засчитывает попытку
+// протухший, исчерпавший попытки или устаревший по ver вход удаляется
+func (st *DBStore) Pending(ctx context.Context, token string) (uint32, error) {
+	id, p, err := st.loadPending(ctx, st.DB.QueryRowContext, token, "")
+	if err != nil {
+		return 0, err
+	}
+	if time.Since(p.created) > pendingTTL || p.ver != p.userVer {
+		st.DB.ExecContext(ctx, "DELETE FROM pending_logins WHERE id = ?", id)
+		return 0, ErrNoAuth
+	}
+	// проверка и увеличение одним запросом, чтобы параллельные попытки не проскочили лимит
+	result, err := st.DB.ExecContext(ctx, "UPDATE pending_logins SET attempts = attempts + 1 WHERE id = ? AND attempts < ?",
+		id, maxPendingAttempts)
+	if err != nil {
+		return 0, err
+	}
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		logging.FromContext(ctx).Warn("too many 2fa attempts", logging.UserID(p.userID))
+		st.DB.ExecContext(ctx, "DELETE FROM pending_logins WHERE id = ?", id)
+		return 0, ErrNoAuth
+	}
+	return p.userID, nil
+}
+
+// Confirm - код проверен, вход ожидания превращается в сессию устройства
+// токен ожидания одноразовый: строка удаляется в той же транзакции
+func (st *DBStore) Confirm(ctx context.Context, token string, client ClientInfo) (*Device, string, error) {
+	var (
+		dev      *Device
+		newToken string
+	)
+	err := dbutils.InTx(ctx, st.DB, func(tx *sql.Tx) error {
+		id, p, err := st.loadPending(ctx, tx.QueryRowContext, token, " FOR UPDATE")
+		if err != nil {
+			return err
+		}
+		_, err = tx.ExecContext(ctx, "DELETE FROM pending_logins WHERE id = ?", id)
+		if err != nil {
+			return err
+		}
+		if time.Since(p.created) > pendingTTL || p.ver != p.userVer {
+			return nil
+		}
+		dev, newToken, err = insertDevice(ctx, tx, p.userID, p.ver, client)
+		return err
+	})
+	if err != nil {
+		return nil, "", err
+	}
+	if dev == nil {
+		return nil, "", ErrNoAuth
+	}
+	return dev, newToken, nil
+}
@@ -0,0 +1,183 @@
+package session
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	sqlmock "gopkg.in/DATA-DOG/go-sqlmock.v1"
+)
+
+var (
+	testSeed    = []byte("0123456789abcdef0123456789abcdef")
+	testSession = "sess1"
+)
+
+func deviceColumns() []string {
+	return []string{"id", "user_id", "seed", "gen", "rotated_at", "user_agent", "ip", "created_at", "last_seen_at", "ver", "u.ver"}
+}
+
+// deviceRows - сессия пользователя 1 текущего поколения gen
+func deviceRows(gen uint32, rotatedAt, lastSeen time.Time, userVer int32) *sqlmock.Rows {
+	return sqlmock.NewRows(deviceColumns()).
+		AddRow(testSession, 1, testSeed, gen, rotatedAt.Unix(), "firefox", "127.0.0.1", lastSeen.Add(-time.Hour).Unix(), lastSeen.Unix(), 0, userVer)
+}
+
+func TestDBStoreRotate(t *testing.T) {
+	now := time.Now()
+	selectQ := `SELECT (.+) FROM sessions s JOIN users u ON u.id = s.user_id WHERE s.id = \? FOR UPDATE`
+
+	cases := []struct {
+		name     string
+		token    string
+		prepare  func(mock sqlmock.Sqlmock)
+		newToken string // "-" - токен не выдаётся
+		checkFn  func(error) bool
+	}{
+		{"ok", makeToken(testSeed, testSession, 0), func(mock sqlmock.Sqlmock) {
+			mock.ExpectBegin()
+			mock.ExpectQuery(selectQ).WithArgs(testSession).WillReturnRows(deviceRows(0, now.Add(-time.Hour), now, 0))
+			mock.ExpectExec(`UPDATE sessions SET gen = \?, rotated_at = NOW\(\)`).
+				WithArgs(1, "chrome", "10.0.0.1", testSession).WillReturnResult(sqlmock.NewResult(0, 1))
+			mock.ExpectCommit()
+		}, makeToken(testSeed, testSession, 1), func(err error) bool { return err == nil }},
+
+		// токен, который уже поменяли давно, - кража: удаляем всю сессию устройства
+		{"reused", makeToken(testSeed, testSession, 1), func(mock sqlmock.Sqlmock) {
+			mock.ExpectBegin()
+			mock.ExpectQuery(selectQ).WithArgs(testSession).WillReturnRows(deviceRows(3, now.Add(-time.Hour), now, 0))
+			mock.ExpectExec(`DELETE FROM sessions WHERE id = \?`).WithArgs(testSession).WillReturnResult(sqlmock.NewResult(0, 1))
+			mock.ExpectCommit()
+		}, "-", IsErrTokenReused},
+
+		// предыдущий токен сразу после ротации - параллельный запрос, не кража
+		{"concurrent", makeToken(testSeed, testSession, 0), func(mock sqlmock.Sqlmock) {
+			mock.ExpectBegin()
+			mock.ExpectQuery(selectQ).WithArgs(testSession).WillReturnRows(deviceRows(1, now, now, 0))
+			mock.ExpectCommit()
+		}, "", func(err error) bool { return err == nil }},
+
+		// тот же случай, но окно прошло
+		{"previous after grace", makeToken(testSeed, testSession, 0), func(mock sqlmock.Sqlmock) {
+			mock.ExpectBegin()
+			mock.ExpectQuery(selectQ).WithArgs(testSession).WillReturnRows(deviceRows(1, now.Add(-2*rotateGrace), now, 0))
+			mock.ExpectExec(`DELETE FROM sessions WHERE id = \?`).WithArgs(testSession).WillReturnResult(sqlmock.NewResult(0, 1))
+			mock.ExpectCommit()
+		}, "-", IsErrTokenReused},
+
+		{"expired", makeToken(testSeed, testSession, 0), func(mock sqlmock.Sqlmock) {
+			mock.ExpectBegin()
+			mock.ExpectQuery(selectQ).WithArgs(testSession).WillReturnRows(deviceRows(0, now.Add(-48*time.Hour), now.Add(-48*time.Hour), 0))
+			mock.ExpectExec(`DELETE FROM sessions WHERE id = \?`).WithArgs(testSession).WillReturnResult(sqlmock.NewResult(0, 1))
+			mock.ExpectCommit()
+		}, "-", func(err error) bool { return err == ErrNoAuth }},
+
+		// пароль сменили - ver пользователя вырос
+		{"password changed", makeToken(testSeed, testSession, 0), func(mock sqlmock.Sqlmock) {
+			mock.ExpectBegin()
+			mock.ExpectQuery(selectQ).WithArgs(testSession).WillReturnRows(deviceRows(0, now, now, 1))
+			mock.ExpectExec(`DELETE FROM sessions WHERE id = \?`).WithArgs(testSession).WillReturnResult(sqlmock.NewResult(0, 1))
+			mock.ExpectCommit()
+		}, "-", func(err error) bool { return err == ErrNoAuth }},
+
+		{"forged mac", testSession + ".0.forged", func(mock sqlmock.Sqlmock) {
+			mock.ExpectBegin()
+			mock.ExpectQuery(selectQ).WithArgs(testSession).WillReturnRows(deviceRows(0, now, now, 0))
+			mock.ExpectRollback()
+		}, "-", func(err error) bool { return err == ErrNoAuth }},
+
+		{"future gen", makeToken(testSeed, testSession, 5), func(mock sqlmock.Sqlmock) {
+			mock.ExpectBegin()
+			mock.ExpectQuery(selectQ).WithArgs(testSession).WillReturnRows(deviceRows(0, now, now, 0))
+			mock.ExpectRollback()
+		}, "-", func(err error) bool { return err == ErrNoAuth }},
+
+		{"no session", makeToken(testSeed, testSession, 0), func(mock sqlmock.Sqlmock) {
+			mock.ExpectBegin()
+			mock.ExpectQuery(selectQ).WithArgs(testSession).WillReturnRows(sqlmock.NewRows(deviceColumns()))
+			mock.ExpectRollback()
+		}, "-", func(err error) bool { return err == ErrNoAuth }},
+
+		{"bad token", "garbage", func(mock sqlmock.Sqlmock) {}, "-", func(err error) bool { return err == ErrNoAuth }},
+
+		{"db error", makeToken(testSeed, testSession, 0), func(mock sqlmock.Sqlmock) {
+			mock.ExpectBegin()
+			mock.ExpectQuery(selectQ).WithArgs(testSession).WillReturnRows(deviceRows(0, now.Add(-time.Hour), now, 0))
+			mock.ExpectExec(`UPDATE sessions SET gen`).WillReturnError(fmt.Errorf("deadlock"))
+			mock.ExpectRollback()
+		}, "-", func(err error) bool { return err != nil && err != ErrNoAuth }},
+	}
+	for _, c := range cases {
+		db, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("cant create mock: %s", err)
+		}
+		st := NewDBStore(db, 24*time.Hour)
+		c.prepare(mock)
+
+		dev, token, err := st.Rotate(context.Background(), c.token, ClientInfo{UserAgent: "chrome", IP: "10.0.0.1"})
+		if !c.checkFn(err) {
+			t.Errorf("[%s] unexpected err: %v", c.name, err)
+		}
+		if c.newToken != "-" {
+			if token != c.newToken {
+				t.Errorf("[%s] bad token: %q, expected %q", c.name, token, c.newToken)
+			}
+			if dev == nil || dev.ID != testSession || dev.UserID != 1 {
+				t.Errorf("[%s] bad device: %+v", c.name, dev)
+			}
+		} else if dev != nil || token != "" {
+			t.Errorf("[%s] expected no session, got %+v %q", c.name, dev, token)
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("[%s] there were unfulfilled expectations: %s", c.name, err)
+		}
+		db.Close()
+	}
+}
+
+// два запроса с одним токеном: FOR UPDATE выстраивает их друг за другом,
+// первый получает новый токен, второй - ту же сессию без нового токена
+func TestDBStoreRotateConcurrent(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("cant create mock: %s", err)
+	}
+	defer db.Close()
+	st := NewDBStore(db, 24*time.Hour)
+	now := time.Now()
+	token := makeToken(testSeed, testSession, 0)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`FOR UPDATE`).WithArgs(testSession).WillReturnRows(deviceRows(0, now.Add(-time.Hour), now, 0))
+	mock.ExpectExec(`UPDATE sessions SET gen`).WithArgs(1, "", "", testSession).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	mock.ExpectBegin()
+	mock.ExpectQuery(`FOR UPDATE`).WithArgs(testSession).WillReturnRows(deviceRows(1, now, now, 0))
+	mock.ExpectCommit()
+
+	_, first, err := st.Rotate(context.Background(), token, ClientInfo{})
+	if err != nil || first == "" {
+		t.Fatalf("first rotate: %q %v", first, err)
+	}
+	dev, second, err := st.Rotate(context.Background(), token, ClientInfo{})
+	if err != nil || dev == nil {
+		t.Fatalf("second rotate: %v", err)
+	}
+	if second != "" {
+		t.Errorf("second rotate must not issue a token, got %q", second)
+	}
+
+	// а вот новый токен первого запроса продолжает работать
+	mock.ExpectBegin()
+	mock.ExpectQuery(`FOR UPDATE`).WithArgs(testSession).WillReturnRows(deviceRows(1, now, now, 0))
+	mock.ExpectExec(`UPDATE sessions SET gen`).WithArgs(2, "", "", testSession).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	if _, third, err := st.Rotate(context.Background(), first, ClientInfo{}); err != nil || third == "" {
+		t.Errorf("rotated token rejected: %q %v", third, err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
@@ -0,0 +1,110 @@
+package session
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+var (
+	_ TwoStep = (*SessionsDB)(nil)
+	_ TwoStep = (*SessionsGRPC)(nil)
+	_ TwoStep = (*SessionsRefresh)(nil)
+)
+
+// TwoStep - вход со вторым фактором
+// после пароля выдаётся только короткоживущий токен ожидания, с ним нельзя ничего, кроме ввода кода
+// настоящая сессия появляется в Confirm, когда код уже проверен
+// jwt-сессии без хранилища его не реализуют
+type TwoStep interface {
+	CreatePending(ctx context.Context, w http.ResponseWriter, user UserInterface) error
+	// Pending - чей вход ждёт код, каждый вызов считается попыткой
+	Pending(ctx context.Context, r *http.Request) (uint32, error)
+	Confirm(ctx context.Context, w http.ResponseWriter, r *http.Request) error
+}
+
+const (
+	pendingCookieName = "login_pending"
+	// кука нужна только на странице ввода кода
+	pendingCookiePath = "/user/login"
+	pendingTTL        = 5 * time.Minute
+	// после стольких неверных кодов придётся снова вводить пароль
+	maxPendingAttempts = 5
+)
+
+func setPendingCookie(w http.ResponseWriter, token string, maxAge int) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     pendingCookieName,
+		Value:    token,
+		Path:     pendingCookiePath,
+		MaxAge:   maxAge,
+		HttpOnly: true,
+		SameSite: http.SameSiteStrictMode,
+	})
+}
+
+func startPending(ctx context.Context, w http.ResponseWriter, store DeviceStore, user UserInterface) error {
+	token, err := store.StartPending(ctx, user.GetID(), user.GetVer(), clientFromContext(ctx))
+	if err != nil {
+		return err
+	}
+	setPendingCookie(w, token, int(pendingTTL.Seconds()))
+	return nil
+}
+
+func pendingUser(ctx context.Context, r *http.Request, store DeviceStore) (uint32, error) {
+	c, err := r.Cookie(pendingCookieName)
+	if err != nil {
+		return 0, ErrNoAuth
+	}
+	return store.Pending(ctx, c.Value)
+}
+
+// confirmPending меняет токен ожидания на сессию устройства, кука ожидания больше не нужна
+func confirmPending(ctx context.Context, w http.ResponseWriter, r *http.Request, store DeviceStore) (*Device, string, error) {
+	c, err := r.Cookie(pendingCookieName)
+	if err != nil {
+		return nil, "", ErrNoAuth
+	}
+	setPendingCookie(w, "", -1)
+	return store.Confirm(ctx, c.Value, clientFromContext(ctx))
+}
+
+func (ds *deviceSessions) CreatePending(ctx context.Context, w http.ResponseWriter, user UserInterface) error {
+	return startPending(ctx, w, ds.store, user)
+}
+
+func (ds *deviceSessions) Pending(ctx context.Context, r *http.Request) (uint32, error) {
+	return pendingUser(ctx, r, ds.store)
+}
+
+func (ds *deviceSessions) Confirm(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	_, token, err := confirmPending(ctx, w, r, ds.store)
+	if err != nil {
+		return err
+	}
+	setCookie(w, cookieName, token, deviceTTL)
+	return nil
+}
+
+func (sm *SessionsRefresh) CreatePending(ctx context.Context, w http.ResponseWriter, user UserInterface) error {
+	return startPending(ctx, w, sm.Store, user)
+}
+
+func (sm *SessionsRefresh) Pending(ctx context.Context, r *http.Request) (uint32, error) {
+	return pendingUser(ctx, r, sm.Store)
+}
+
+func (sm *SessionsRefresh) Confirm(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	dev, token, err := confirmPending(ctx, w, r, sm.Store)
+	if err != nil {
+		return err
+	}
+	ver, err := sm.userVer(ctx, dev.UserID)
+	if err != nil {
+		return err
+	}
+	setCookie(w, refreshCookieName, token, deviceTTL)
+	sm.issueAccess(w, dev, ver)
+	return nil
+}
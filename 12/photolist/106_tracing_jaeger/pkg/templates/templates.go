@@ -1,7 +1,7 @@
 package templates
 
 import (
-	"context"
+	"fmt"
 	"html/template"
 	"log"
 	"net/http"
@@ -9,7 +9,9 @@ import (
 
 	"github.com/shurcooL/httpfs/html/vfstemplate"
 
+	"photolist/pkg/apierr"
 	"photolist/pkg/session"
+	"photolist/pkg/utils/httputils"
 )
 
 type TokenManager interface {
@@ -34,7 +36,8 @@ func NewTemplates(assets http.FileSystem, tm TokenManager) *MyTemplate {
 	}
 }
 
-func (tpl *MyTemplate) Render(ctx context.Context, w http.ResponseWriter, tmplName string, data map[string]interface{}) {
+func (tpl *MyTemplate) Render(w http.ResponseWriter, r *http.Request, tmplName string, data map[string]interface{}) {
+	ctx := r.Context()
 	if data == nil {
 		data = make(map[string]interface{}, 3)
 	}
@@ -46,8 +49,7 @@ func (tpl *MyTemplate) Render(ctx context.Context, w http.ResponseWriter, tmplNa
 
 		token, err := tpl.Tokens.Create(sess, time.Now().Add(24*time.Hour).Unix())
 		if err != nil {
-			log.Println("csrf token creation error:", err)
-			http.Error(w, "internal error", http.StatusInternalServerError)
+			httputils.RespError(w, r, apierr.Internal(fmt.Errorf("csrf token creation error: %w", err)))
 			return
 		}
 
@@ -58,8 +60,7 @@ func (tpl *MyTemplate) Render(ctx context.Context, w http.ResponseWriter, tmplNa
 
 	err = tpl.Tmpl.ExecuteTemplate(w, tmplName, data)
 	if err != nil {
-		log.Println("cant execute template", err)
-		http.Error(w, "template error", http.StatusInternalServerError)
+		httputils.RespError(w, r, apierr.Internal(fmt.Errorf("cant execute template %s: %w", tmplName, err)))
 		return
 	}
 }
@@ -0,0 +1,41 @@
+package token
+
+import (
+	"fmt"
+
+	"photolist/pkg/config"
+)
+
+var (
+	_ TokenManager = (*JwtToken)(nil)
+	_ TokenManager = (*HashToken)(nil)
+	_ TokenManager = (*CryptToken)(nil)
+)
+
+// New выбирает csrf-токены по token.type из конфига
+func New(cfg *config.Config) (TokenManager, error) {
+	if err := cfg.CheckMode(); err != nil {
+		return nil, err
+	}
+	switch cfg.Token.Type {
+	case "jwt", "hmac", "aes":
+	default:
+		return nil, fmt.Errorf("unknown token type %q", cfg.Token.Type)
+	}
+	if err := cfg.CheckSecret("token.secret", cfg.Token.Secret); err != nil {
+		return nil, err
+	}
+
+	switch cfg.Token.Type {
+	case "hmac":
+		return NewHMACHashToken(cfg.Token.Secret)
+	case "aes":
+		// ключ aes - ровно 16, 24 или 32 байта
+		tokens, err := NewAesCryptHashToken(cfg.Token.Secret)
+		if err != nil {
+			return nil, err
+		}
+		return tokens, nil
+	}
+	return NewJwtToken(cfg.Token.Secret)
+}
@@ -0,0 +1,49 @@
+package token
+
+import (
+	"strings"
+	"testing"
+
+	"photolist/pkg/config"
+)
+
+func TestNew(t *testing.T) {
+	defaultSecret := config.Defaults["token"].(map[string]interface{})["secret"].(string)
+	cases := []struct {
+		name   string
+		mode   string
+		typ    string
+		secret string
+		err    string
+	}{
+		{"jwt", "dev", "jwt", "secret", ""},
+		{"hmac", "dev", "hmac", "secret", ""},
+		{"aes", "dev", "aes", strings.Repeat("k", 32), ""},
+		{"aes bad key", "dev", "aes", "short", "cypher problem"},
+		{"unknown type", "dev", "md5", "secret", "unknown token type"},
+		{"empty secret", "dev", "jwt", "", "token.secret is empty"},
+		{"default in dev", "dev", "jwt", defaultSecret, ""},
+		{"default in production", config.ModeProduction, "jwt", defaultSecret, "default value"},
+		{"own in production", config.ModeProduction, "jwt", "my own secret", ""},
+		{"unknown mode", "prod", "jwt", defaultSecret, "unknown mode"},
+	}
+	for _, c := range cases {
+		cfg := &config.Config{Mode: c.mode}
+		cfg.Token.Type = c.typ
+		cfg.Token.Secret = c.secret
+
+		tm, err := New(cfg)
+		if c.err == "" {
+			if err != nil || tm == nil {
+				t.Errorf("[%s] unexpected err: %v", c.name, err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), c.err) {
+			t.Errorf("[%s] expected err %q, got %v", c.name, c.err, err)
+		}
+		if tm != nil {
+			t.Errorf("[%s] expected nil manager on error, got %#v", c.name, tm)
+		}
+	}
+}
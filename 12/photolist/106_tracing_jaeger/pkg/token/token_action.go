@@ -0,0 +1,93 @@
+package token
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	PurposeResetPassword = "reset"
+	PurposeVerifyEmail   = "verify"
+)
+
+var (
+	errBadToken = errors.New("bad token")
+)
+
+// IsErrBadToken - ссылка из письма испорчена, протухла или уже использована
+func IsErrBadToken(err error) bool {
+	return err == errBadToken || err == errorTokenExpired
+}
+
+// ActionTokens - подписанные ссылки из писем, без сессии
+// в подпись входит binding - то, что меняется после действия:
+// для сброса пароля это ver пользователя, для подтверждения - сам email
+// поэтому токен сам становится одноразовым и в базе его хранить не надо
+type ActionTokens struct {
+	Secret []byte
+}
+
+func NewActionTokens(secret string) *ActionTokens {
+	return &ActionTokens{Secret: []byte(secret)}
+}
+
+func (at *ActionTokens) mac(purpose string, userID uint32, exp int64, binding string) []byte {
+	h := hmac.New(sha256.New, at.Secret)
+	fmt.Fprintf(h, "%s:%d:%d:%s", purpose, userID, exp, binding)
+	return h.Sum(nil)
+}
+
+// Create - токен вида uid.exp.mac
+func (at *ActionTokens) Create(purpose string, userID uint32, binding string, ttl time.Duration) string {
+	exp := time.Now().Add(ttl).Unix()
+	return strconv.FormatUint(uint64(userID), 10) + "." +
+		strconv.FormatInt(exp, 10) + "." +
+		base64.RawURLEncoding.EncodeToString(at.mac(purpose, userID, exp, binding))
+}
+
+// UserID достаёт пользователя из токена, чтобы подгрузить его binding
+// подпись тут ещё не проверена, это делает Check
+func (at *ActionTokens) UserID(token string) (uint32, error) {
+	userID, _, _, err := parseActionToken(token)
+	return userID, err
+}
+
+func (at *ActionTokens) Check(purpose, token, binding string) error {
+	userID, exp, mac, err := parseActionToken(token)
+	if err != nil {
+		return err
+	}
+	if exp < time.Now().Unix() {
+		return errorTokenExpired
+	}
+	if !hmac.Equal(mac, at.mac(purpose, userID, exp, binding)) {
+		return errBadToken
+	}
+	return nil
+}
+
+func parseActionToken(token string) (uint32, int64, []byte, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return 0, 0, nil, errBadToken
+	}
+	userID, err := strconv.ParseUint(parts[0], 10, 32)
+	if err != nil {
+		return 0, 0, nil, errBadToken
+	}
+	exp, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return 0, 0, nil, errBadToken
+	}
+	mac, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return 0, 0, nil, errBadToken
+	}
+	return uint32(userID), exp, mac, nil
+}
@@ -0,0 +1,44 @@
+package token
+
+import (
+	"testing"
+	"time"
+)
+
+func TestActionTokens(t *testing.T) {
+	at := NewActionTokens("secret")
+	valid := at.Create(PurposeResetPassword, 7, "3", time.Hour)
+	expired := at.Create(PurposeResetPassword, 7, "3", -time.Minute)
+	otherKey := NewActionTokens("other").Create(PurposeResetPassword, 7, "3", time.Hour)
+
+	cases := []struct {
+		name    string
+		purpose string
+		token   string
+		binding string
+		ok      bool
+	}{
+		{"valid", PurposeResetPassword, valid, "3", true},
+		{"used (ver changed)", PurposeResetPassword, valid, "4", false},
+		{"other purpose", PurposeVerifyEmail, valid, "3", false},
+		{"expired", PurposeResetPassword, expired, "3", false},
+		{"other secret", PurposeResetPassword, otherKey, "3", false},
+		{"other user", PurposeResetPassword, "8" + valid[1:], "3", false},
+		{"garbage", PurposeResetPassword, "not-a-token", "3", false},
+		{"empty", PurposeResetPassword, "", "3", false},
+	}
+	for _, c := range cases {
+		err := at.Check(c.purpose, c.token, c.binding)
+		if c.ok && err != nil {
+			t.Errorf("[%s] unexpected err: %v", c.name, err)
+		}
+		if !c.ok && !IsErrBadToken(err) {
+			t.Errorf("[%s] expected bad token, got %v", c.name, err)
+		}
+	}
+
+	uid, err := at.UserID(valid)
+	if err != nil || uid != 7 {
+		t.Errorf("[user id] expected 7, got %d, err %v", uid, err)
+	}
+}
@@ -1,12 +1,18 @@
 package token
 
 import (
+	"context"
 	"errors"
-	"log"
 	"net/http"
+	"net/url"
 	"strings"
 
+	"photolist/pkg/apierr"
+	"photolist/pkg/logging"
 	"photolist/pkg/session"
+	"photolist/pkg/utils/httputils"
+
+	"go.uber.org/zap"
 )
 
 type TokenManager interface {
@@ -20,17 +26,52 @@ var (
 		"/user/login":       struct{}{},
 		"/user/reg":         struct{}{},
 		"/api/v1/token":     struct{}{},
+		// сессии ещё нет, кука ожидания кода SameSite=Strict и ходит только сюда
+		"/user/login/2fa": struct{}{},
+		// сессии там нет, от подделки защищает сам токен из письма
+		"/user/forgot": struct{}{},
+		"/user/reset":  struct{}{},
+	}
 
-		// TODO enable csrf for graphql after playground done
-		// "/playgroud": struct{}{},
-		"/graphql": struct{}{},
+	// в graphql запросы на чтение ходят и без токена,
+	// тут только запоминаем результат проверки, а мутации режет graphql.MutationCSRF
+	gqlUrls = map[string]struct{}{
+		"/graphql":  struct{}{},
+		"/graphql/": struct{}{},
 	}
 
 	errorTokenExpired = errors.New("token expired")
 )
 
+type ctxKey int
+
+const csrfValidKey ctxKey = 1
+
+// CSRFValid - запрос пришёл с правильным csrf-token в заголовке
+func CSRFValid(ctx context.Context) bool {
+	valid, _ := ctx.Value(csrfValidKey).(bool)
+	return valid
+}
+
+// Valid проверяет токен для сессии из контекста, без сессии токен не бывает правильным
+func Valid(ctx context.Context, tm TokenManager, token string) bool {
+	sess, err := session.SessionFromContext(ctx)
+	if err != nil || token == "" {
+		return false
+	}
+	valid, _ := tm.Check(sess, token)
+	return valid
+}
+
 func CsrfTokenMiddleware(tm TokenManager, next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := gqlUrls[r.URL.Path]; ok {
+			valid := Valid(r.Context(), tm, r.Header.Get("csrf-token"))
+			ctx := context.WithValue(r.Context(), csrfValidKey, valid)
+			next.ServeHTTP(w, r.WithContext(ctx))
+			return
+		}
+
 		_, skip := noCSRFUrls[r.URL.Path]
 		isAPI := strings.HasPrefix(r.URL.Path, "/api")
 		skip = skip || (!isAPI && r.Method == http.MethodGet) // check all api and regular forms
@@ -55,22 +96,64 @@ func CsrfTokenMiddleware(tm TokenManager, next http.Handler) http.Handler {
 		}
 
 		if err == errorTokenExpired {
-			log.Println("csrf token expired,", sess)
-			if isAPI {
-				w.Header().Add("Content-Type", "application/json")
-				http.Error(w, `{"error": "token expired"}`, http.StatusForbidden)
-			} else {
-				http.Error(w, "Token expired", http.StatusForbidden)
-			}
+			logging.FromContext(r.Context()).Info("csrf token expired")
+			httputils.RespError(w, r, apierr.New(apierr.CodeCSRF, "token expired"))
 			return
 		}
 
-		log.Println("bad token", tokenValid, err, sess)
-		if isAPI {
-			w.Header().Add("Content-Type", "application/json")
-			http.Error(w, `{"error": "bad token"}`, http.StatusForbidden)
-		} else {
-			http.Error(w, "Bad token", http.StatusForbidden)
+		httputils.RespError(w, r, apierr.Wrap(apierr.CodeCSRF, "bad token", err))
+	})
+}
+
+var safeMethods = map[string]struct{}{
+	http.MethodGet:     struct{}{},
+	http.MethodHead:    struct{}{},
+	http.MethodOptions: struct{}{},
+}
+
+// OriginMiddleware - второй рубеж после токена: изменяющий запрос должен прийти со своего сайта
+// Origin браузер ставит на любой кросс-доменный POST, Referer смотрим если Origin нет
+// без обоих заголовков пропускаем - так ходят не-браузерные клиенты, от них защищает токен
+// свой сайт - Host запроса (за nginx он должен пробрасываться) или trusted из конфига
+func OriginMiddleware(trusted []string, next http.Handler) http.Handler {
+	allowed := make(map[string]struct{}, len(trusted))
+	for _, origin := range trusted {
+		u, err := url.Parse(origin)
+		if err != nil || u.Host == "" {
+			zap.L().Warn("bad trusted origin", zap.String("origin", origin))
+			continue
+		}
+		allowed[strings.ToLower(u.Scheme+"://"+u.Host)] = struct{}{}
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := safeMethods[r.Method]; ok {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		source := r.Header.Get("Origin")
+		if source == "" {
+			source = r.Header.Get("Referer")
 		}
+		if source == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		// "null" от sandbox-iframe и file:// не распарсится в хост и тоже будет отклонён
+		u, err := url.Parse(source)
+		if err == nil && u.Host != "" {
+			if strings.EqualFold(u.Host, r.Host) {
+				next.ServeHTTP(w, r)
+				return
+			}
+			if _, ok := allowed[strings.ToLower(u.Scheme+"://"+u.Host)]; ok {
+				next.ServeHTTP(w, r)
+				return
+			}
+		}
+
+		logging.FromContext(r.Context()).Warn("bad origin", zap.String("origin", source), zap.String("host", r.Host))
+		httputils.RespError(w, r, apierr.New(apierr.CodeCSRF, "bad origin"))
 	})
 }
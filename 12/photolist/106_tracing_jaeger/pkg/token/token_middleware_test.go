@@ -0,0 +1,124 @@
+package token
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"photolist/pkg/session"
+)
+
+// csrfChain - как в main: Origin снаружи, потом сессия, потом токен
+// ответ 200 и в теле результат token.CSRFValid
+func csrfChain(tm TokenManager, sess *session.Session) http.Handler {
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if CSRFValid(r.Context()) {
+			w.Write([]byte("csrf ok"))
+		}
+	})
+	handler = CsrfTokenMiddleware(tm, handler)
+	withSession := handler
+	handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		withSession.ServeHTTP(w, r.WithContext(session.ContextWithSession(r.Context(), sess)))
+	})
+	return OriginMiddleware([]string{"https://partner.example.com/"}, handler)
+}
+
+func TestCrossOriginForms(t *testing.T) {
+	sess := &session.Session{ID: "sid", UserID: 7}
+	tm, _ := NewJwtToken("secret")
+	csrfToken, _ := tm.Create(sess, time.Now().Add(time.Hour).Unix())
+	otherToken, _ := tm.Create(&session.Session{ID: "other", UserID: 8}, time.Now().Add(time.Hour).Unix())
+	handler := csrfChain(tm, sess)
+
+	cases := []struct {
+		name    string
+		method  string
+		path    string
+		token   string
+		origin  string
+		referer string
+		status  int
+	}{
+		{"same origin form", "POST", "/user/change_pass", csrfToken, "http://photolist.local", "", 200},
+		{"same origin without token", "POST", "/user/change_pass", "", "http://photolist.local", "", 403},
+		{"token of other session", "POST", "/user/change_pass", otherToken, "http://photolist.local", "", 403},
+		{"cross origin form with token", "POST", "/user/change_pass", csrfToken, "https://evil.example.com", "", 403},
+		{"cross origin login", "POST", "/user/login", "", "https://evil.example.com", "", 403},
+		{"same origin login", "POST", "/user/login", "", "http://photolist.local", "", 200},
+		{"cross origin referer", "POST", "/user/change_pass", csrfToken, "", "https://evil.example.com/page.html", 403},
+		{"same origin referer", "POST", "/user/change_pass", csrfToken, "", "http://photolist.local/user/profile", 200},
+		{"null origin", "POST", "/user/login", "", "null", "", 403},
+		{"lookalike host", "POST", "/user/login", "", "http://photolist.local.evil.example.com", "", 403},
+		{"trusted origin", "POST", "/user/change_pass", csrfToken, "https://partner.example.com", "", 200},
+		{"trusted host, other scheme", "POST", "/user/change_pass", csrfToken, "http://partner.example.com", "", 403},
+		{"no origin and referer", "POST", "/user/change_pass", csrfToken, "", "", 200},
+		{"cross origin get", "GET", "/photos/", "", "https://evil.example.com", "", 200},
+	}
+	for _, item := range cases {
+		form := url.Values{}
+		if item.token != "" {
+			form.Set("csrf-token", item.token)
+		}
+		r := httptest.NewRequest(item.method, "http://photolist.local"+item.path, strings.NewReader(form.Encode()))
+		r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		if item.origin != "" {
+			r.Header.Set("Origin", item.origin)
+		}
+		if item.referer != "" {
+			r.Header.Set("Referer", item.referer)
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		if w.Code != item.status {
+			t.Errorf("[%s] unexpected status: got %d, expected %d", item.name, w.Code, item.status)
+		}
+	}
+}
+
+func TestGraphqlCSRFHeader(t *testing.T) {
+	sess := &session.Session{ID: "sid", UserID: 7}
+	tm, _ := NewJwtToken("secret")
+	csrfToken, _ := tm.Create(sess, time.Now().Add(time.Hour).Unix())
+	handler := csrfChain(tm, sess)
+
+	cases := []struct {
+		name   string
+		header string
+		valid  bool
+	}{
+		{"with header", csrfToken, true},
+		{"without header", "", false},
+		{"bad header", "garbage", false},
+	}
+	for _, item := range cases {
+		r := httptest.NewRequest("POST", "http://photolist.local/graphql", strings.NewReader(`{"query": "{ me { id } }"}`))
+		r.Header.Set("Content-Type", "application/json")
+		r.Header.Set("Origin", "http://photolist.local")
+		if item.header != "" {
+			r.Header.Set("csrf-token", item.header)
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		// запросы без токена пропускаются дальше, отказывает только graphql.MutationCSRF
+		if w.Code != 200 {
+			t.Errorf("[%s] unexpected status: %d", item.name, w.Code)
+		}
+		if valid := w.Body.String() == "csrf ok"; valid != item.valid {
+			t.Errorf("[%s] unexpected csrf flag: got %v, expected %v", item.name, valid, item.valid)
+		}
+	}
+
+	// а с чужого сайта graphql не доходит даже до проверки токена
+	r := httptest.NewRequest("POST", "http://photolist.local/graphql", strings.NewReader(`{"query": "mutation { x }"}`))
+	r.Header.Set("Origin", "https://evil.example.com")
+	r.Header.Set("csrf-token", csrfToken)
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+	if w.Code != http.StatusForbidden {
+		t.Errorf("[cross origin graphql] unexpected status: %d", w.Code)
+	}
+}
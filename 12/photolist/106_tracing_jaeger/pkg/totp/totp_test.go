@@ -0,0 +1,86 @@
+package totp
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// тестовые векторы из приложений RFC 4226 и RFC 6238 (sha1)
+var rfcKey = []byte("12345678901234567890")
+
+func TestHOTP(t *testing.T) {
+	expected := []string{"755224", "287082", "359152", "969429", "338314",
+		"254676", "287922", "162583", "399871", "520489"}
+	for i, code := range expected {
+		if got := hotp(rfcKey, uint64(i), 6); got != code {
+			t.Errorf("[%d] expected %s, got %s", i, code, got)
+		}
+	}
+}
+
+func TestTOTPVectors(t *testing.T) {
+	cases := []struct {
+		unix int64
+		code string
+	}{
+		{59, "94287082"},
+		{1111111109, "07081804"},
+		{1111111111, "14050471"},
+		{1234567890, "89005924"},
+		{2000000000, "69279037"},
+		{20000000000, "65353130"},
+	}
+	for _, c := range cases {
+		got := hotp(rfcKey, uint64(Step(time.Unix(c.unix, 0))), 8)
+		if got != c.code {
+			t.Errorf("[%d] expected %s, got %s", c.unix, c.code, got)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	secret := NewSecret()
+	now := time.Unix(1570000000, 0)
+	code, err := Code(secret, now)
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+
+	cases := []struct {
+		name string
+		at   time.Time
+		code string
+		ok   bool
+	}{
+		{"now", now, code, true},
+		{"clock behind", now.Add(-Period * time.Second), code, true},
+		{"clock ahead", now.Add(Period * time.Second), code, true},
+		{"too late", now.Add(2 * Period * time.Second), code, false},
+		{"wrong", now, "000000", code == "000000"},
+		{"short", now, code[:5], false},
+	}
+	for _, c := range cases {
+		step, ok := Validate(secret, c.code, c.at)
+		if ok != c.ok {
+			t.Errorf("[%s] expected %v, got %v", c.name, c.ok, ok)
+		}
+		if ok && step != Step(now) {
+			t.Errorf("[%s] expected step %d, got %d", c.name, Step(now), step)
+		}
+	}
+
+	// приложения иногда показывают секрет в нижнем регистре
+	if _, ok := Validate(strings.ToLower(secret), code, now); !ok {
+		t.Errorf("[lowercase] secret not accepted")
+	}
+}
+
+func TestURI(t *testing.T) {
+	uri := URI("photolist", "user@example.com", "JBSWY3DPEHPK3PXP")
+	if !strings.HasPrefix(uri, "otpauth://totp/photolist:user@example.com?") ||
+		!strings.Contains(uri, "secret=JBSWY3DPEHPK3PXP") ||
+		!strings.Contains(uri, "issuer=photolist") {
+		t.Errorf("bad uri %s", uri)
+	}
+}
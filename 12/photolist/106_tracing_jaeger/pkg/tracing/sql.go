@@ -0,0 +1,192 @@
+package tracing
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"strings"
+
+	semconv "go.opentelemetry.io/otel/semconv/v1.4.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// OpenDB - как sql.Open, только каждый запрос с ctx становится дочерним спаном
+// sql.Open("mysql", dsn) -> tracing.OpenDB(&mysql.MySQLDriver{}, dsn)
+func OpenDB(drv driver.Driver, dsn string) *sql.DB {
+	return sql.OpenDB(&connector{dsn: dsn, drv: drv})
+}
+
+type connector struct {
+	dsn string
+	drv driver.Driver
+}
+
+func (c *connector) Connect(ctx context.Context) (driver.Conn, error) {
+	conn, err := c.drv.Open(c.dsn)
+	if err != nil {
+		return nil, err
+	}
+	return &tracedConn{Conn: conn}, nil
+}
+
+func (c *connector) Driver() driver.Driver {
+	return c.drv
+}
+
+func startQuery(ctx context.Context, query string) (context.Context, trace.Span) {
+	// имя спана - только операция, сам запрос в атрибуте: у спанов должно быть мало разных имён
+	op := query
+	if idx := strings.IndexAny(query, " \n\t"); idx > 0 {
+		op = query[:idx]
+	}
+	return StartChild(ctx, "sql "+strings.ToUpper(op),
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(semconv.DBSystemMySQL, semconv.DBStatementKey.String(query)),
+	)
+}
+
+// endQuery - driver.ErrSkip не ошибка, database/sql просто пойдёт через Prepare
+func endQuery(span trace.Span, err error) {
+	if err == driver.ErrSkip {
+		err = nil
+	}
+	End(span, err)
+}
+
+// tracedConn оборачивает соединение драйвера
+// mysql умеет все context-интерфейсы, их и прокидываем, без них database/sql откатился бы на старые методы
+type tracedConn struct {
+	driver.Conn
+}
+
+var (
+	_ driver.ExecerContext      = (*tracedConn)(nil)
+	_ driver.QueryerContext     = (*tracedConn)(nil)
+	_ driver.ConnPrepareContext = (*tracedConn)(nil)
+	_ driver.ConnBeginTx        = (*tracedConn)(nil)
+	_ driver.Pinger             = (*tracedConn)(nil)
+	_ driver.NamedValueChecker  = (*tracedConn)(nil)
+	_ driver.SessionResetter    = (*tracedConn)(nil)
+)
+
+func (c *tracedConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	execer, ok := c.Conn.(driver.ExecerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+	ctx, span := startQuery(ctx, query)
+	res, err := execer.ExecContext(ctx, query, args)
+	endQuery(span, err)
+	return res, err
+}
+
+func (c *tracedConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	queryer, ok := c.Conn.(driver.QueryerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+	ctx, span := startQuery(ctx, query)
+	rows, err := queryer.QueryContext(ctx, query, args)
+	endQuery(span, err)
+	return rows, err
+}
+
+func (c *tracedConn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	var (
+		stmt driver.Stmt
+		err  error
+	)
+	if preparer, ok := c.Conn.(driver.ConnPrepareContext); ok {
+		stmt, err = preparer.PrepareContext(ctx, query)
+	} else {
+		stmt, err = c.Conn.Prepare(query)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &tracedStmt{Stmt: stmt, query: query}, nil
+}
+
+func (c *tracedConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	if beginner, ok := c.Conn.(driver.ConnBeginTx); ok {
+		return beginner.BeginTx(ctx, opts)
+	}
+	return c.Conn.Begin()
+}
+
+func (c *tracedConn) Ping(ctx context.Context) error {
+	if pinger, ok := c.Conn.(driver.Pinger); ok {
+		return pinger.Ping(ctx)
+	}
+	return nil
+}
+
+func (c *tracedConn) CheckNamedValue(nv *driver.NamedValue) error {
+	if checker, ok := c.Conn.(driver.NamedValueChecker); ok {
+		return checker.CheckNamedValue(nv)
+	}
+	return driver.ErrSkip
+}
+
+func (c *tracedConn) ResetSession(ctx context.Context) error {
+	if resetter, ok := c.Conn.(driver.SessionResetter); ok {
+		return resetter.ResetSession(ctx)
+	}
+	return nil
+}
+
+// tracedStmt - подготовленные запросы, сюда попадаем, если драйвер вернул ErrSkip
+type tracedStmt struct {
+	driver.Stmt
+	query string
+}
+
+var (
+	_ driver.StmtExecContext  = (*tracedStmt)(nil)
+	_ driver.StmtQueryContext = (*tracedStmt)(nil)
+)
+
+func (s *tracedStmt) ExecContext(ctx context.Context, args []driver.NamedValue) (driver.Result, error) {
+	ctx, span := startQuery(ctx, s.query)
+	if execer, ok := s.Stmt.(driver.StmtExecContext); ok {
+		res, err := execer.ExecContext(ctx, args)
+		endQuery(span, err)
+		return res, err
+	}
+	values, err := namedToValues(args)
+	if err != nil {
+		endQuery(span, err)
+		return nil, err
+	}
+	res, err := s.Stmt.Exec(values)
+	endQuery(span, err)
+	return res, err
+}
+
+func (s *tracedStmt) QueryContext(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
+	ctx, span := startQuery(ctx, s.query)
+	if queryer, ok := s.Stmt.(driver.StmtQueryContext); ok {
+		rows, err := queryer.QueryContext(ctx, args)
+		endQuery(span, err)
+		return rows, err
+	}
+	values, err := namedToValues(args)
+	if err != nil {
+		endQuery(span, err)
+		return nil, err
+	}
+	rows, err := s.Stmt.Query(values)
+	endQuery(span, err)
+	return rows, err
+}
+
+func namedToValues(args []driver.NamedValue) ([]driver.Value, error) {
+	values := make([]driver.Value, len(args))
+	for i, arg := range args {
+		if arg.Name != "" {
+			return nil, driver.ErrSkip
+		}
+		values[i] = arg.Value
+	}
+	return values, nil
+}
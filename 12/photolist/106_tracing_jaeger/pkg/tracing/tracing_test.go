@@ -0,0 +1,167 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	sqlmock "gopkg.in/DATA-DOG/go-sqlmock.v1"
+)
+
+func setupRecorder() *tracetest.SpanRecorder {
+	rec := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(rec)))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	return rec
+}
+
+func TestSQLSpans(t *testing.T) {
+	cases := []struct {
+		name      string
+		withTrace bool
+		err       error
+		spanName  string
+	}{
+		{"child span", true, nil, "sql SELECT"},
+		{"error in status", true, fmt.Errorf("bad query"), "sql SELECT"},
+		{"no parent - no span", false, nil, ""},
+	}
+	for i, c := range cases {
+		rec := setupRecorder()
+		dsn := fmt.Sprintf("tracing_%d", i)
+		mockDB, mock, err := sqlmock.NewWithDSN(dsn)
+		if err != nil {
+			t.Fatalf("cant create mock: %s", err)
+		}
+		db := OpenDB(mockDB.Driver(), dsn)
+
+		q := mock.ExpectQuery("SELECT id FROM photos").WithArgs(1)
+		if c.err != nil {
+			q.WillReturnError(c.err)
+		} else {
+			q.WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+		}
+
+		ctx := context.Background()
+		var parent trace.Span
+		if c.withTrace {
+			ctx, parent = Tracer().Start(ctx, "request")
+		}
+		var id int
+		db.QueryRowContext(ctx, "SELECT id FROM photos WHERE id = ?", 1).Scan(&id)
+		if parent != nil {
+			parent.End()
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("[%s] unmet expectations: %s", c.name, err)
+		}
+		db.Close()
+		mockDB.Close()
+
+		var found sdktrace.ReadOnlySpan
+		for _, s := range rec.Ended() {
+			if s.Name() != "request" {
+				found = s
+			}
+		}
+		if c.spanName == "" {
+			if found != nil {
+				t.Errorf("[%s] expected no sql span, got %s", c.name, found.Name())
+			}
+			continue
+		}
+		if found == nil {
+			t.Errorf("[%s] no sql span", c.name)
+			continue
+		}
+		if found.Name() != c.spanName {
+			t.Errorf("[%s] bad span name: %s", c.name, found.Name())
+		}
+		if found.Parent().SpanID() != parent.SpanContext().SpanID() {
+			t.Errorf("[%s] sql span is not a child of request", c.name)
+		}
+		wantStatus := codes.Unset
+		if c.err != nil {
+			wantStatus = codes.Error
+		}
+		if found.Status().Code != wantStatus {
+			t.Errorf("[%s] bad status: %v", c.name, found.Status())
+		}
+	}
+}
+
+func TestHTTPPropagation(t *testing.T) {
+	setupRecorder()
+
+	var serverCtx trace.SpanContext
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, span := StartHTTPServer(r, "HTTP GET")
+		serverCtx = span.SpanContext()
+		span.End()
+	}))
+	defer ts.Close()
+
+	ctx, parent := Tracer().Start(context.Background(), "client")
+	req, _ := http.NewRequest(http.MethodGet, ts.URL, nil)
+	client := &http.Client{Transport: &Transport{}}
+	resp, err := client.Do(req.WithContext(ctx))
+	if err != nil {
+		t.Fatalf("request err: %s", err)
+	}
+	resp.Body.Close()
+	parent.End()
+
+	if serverCtx.TraceID() != parent.SpanContext().TraceID() {
+		t.Errorf("trace not propagated: client %s, server %s", parent.SpanContext().TraceID(), serverCtx.TraceID())
+	}
+	if req.Header.Get("traceparent") != "" {
+		t.Errorf("transport changed original request")
+	}
+}
+
+func TestGRPCPropagation(t *testing.T) {
+	rec := setupRecorder()
+
+	ctx, parent := Tracer().Start(context.Background(), "client")
+	ctx = metadata.AppendToOutgoingContext(ctx, "x-request-id", "req1")
+
+	var outgoing metadata.MD
+	invoker := func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		outgoing, _ = metadata.FromOutgoingContext(ctx)
+		return nil
+	}
+	err := UnaryClientInterceptor(ctx, "/session.Auth/Check", nil, nil, nil, invoker)
+	if err != nil {
+		t.Fatalf("client err: %s", err)
+	}
+	if len(outgoing.Get("traceparent")) == 0 || len(outgoing.Get("x-request-id")) == 0 {
+		t.Fatalf("bad outgoing metadata: %v", outgoing)
+	}
+
+	var serverCtx trace.SpanContext
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		serverCtx = trace.SpanContextFromContext(ctx)
+		return nil, nil
+	}
+	info := &grpc.UnaryServerInfo{FullMethod: "/session.Auth/Check"}
+	UnaryServerInterceptor(metadata.NewIncomingContext(context.Background(), outgoing), nil, info, handler)
+	parent.End()
+
+	if serverCtx.TraceID() != parent.SpanContext().TraceID() {
+		t.Errorf("trace not propagated: client %s, server %s", parent.SpanContext().TraceID(), serverCtx.TraceID())
+	}
+	// client -> /session.Auth/Check (клиентский) -> /session.Auth/Check (серверный)
+	if n := len(rec.Ended()); n != 3 {
+		t.Errorf("expected 3 spans, got %d", n)
+	}
+}
@@ -0,0 +1,123 @@
+package user
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math/rand"
+	"regexp"
+	"unicode/utf8"
+
+	"photolist/pkg/oauth"
+	"photolist/pkg/utils/dbutils"
+	"photolist/pkg/utils/randutils"
+)
+
+var (
+	errIdentityLinked = errors.New("External account linked to another user")
+
+	badLoginCharsRE = regexp.MustCompile(`[^\w\-\.]+`)
+)
+
+func IsErrIdentityLinked(err error) bool {
+	return err == errIdentityLinked
+}
+
+// GetByIdentity - пользователь, к которому привязан внешний аккаунт
+func (repo *UserRepository) GetByIdentity(ctx context.Context, provider, subject string) (*User, error) {
+	row := repo.db.QueryRowContext(ctx, `SELECT u.id, u.login, u.email, u.ver, u.display_name, u.bio, u.avatar
+		FROM user_identities i JOIN users u ON u.id = i.user_id
+		WHERE i.provider = ? AND i.subject = ?`, provider, subject)
+	return parseRowToUser(row)
+}
+
+// LinkIdentity привязывает внешний аккаунт к уже существующему пользователю
+// повторная привязка к тому же пользователю - не ошибка
+func (repo *UserRepository) LinkIdentity(ctx context.Context, userID uint32, ident *oauth.Identity) error {
+	_, err := repo.db.ExecContext(ctx, "INSERT INTO user_identities(provider, subject, user_id, email, email_verified, created_at) VALUES(?, ?, ?, ?, ?, NOW())",
+		ident.Provider, ident.Subject, userID, ident.Email, ident.Verified)
+	if !dbutils.IsDuplicate(err) {
+		return err
+	}
+	var ownerID uint32
+	err = repo.db.QueryRowContext(ctx, "SELECT user_id FROM user_identities WHERE provider = ? AND subject = ?",
+		ident.Provider, ident.Subject).Scan(&ownerID)
+	if err != nil {
+		return err
+	}
+	if ownerID != userID {
+		return errIdentityLinked
+	}
+	return nil
+}
+
+// CreateWithIdentity заводит нового пользователя под внешний аккаунт
+// по email к существующим пользователям не привязываем, даже подтверждённому провайдером
+// email берём только подтверждённый и свободный, иначе ставим заглушку - чужой адрес не займёт никто
+func (repo *UserRepository) CreateWithIdentity(ctx context.Context, ident *oauth.Identity) (*User, error) {
+	base := ident.Login
+	if base == "" {
+		base = ident.Subject
+	}
+	base = ident.Provider + "_" + badLoginCharsRE.ReplaceAllString(base, "_")
+
+	displayName := ident.Name
+	if utf8.RuneCountInString(displayName) > MaxDisplayNameLen {
+		displayName = string([]rune(displayName)[:MaxDisplayNameLen])
+	}
+	// пароля у такого пользователя нет, случайный хеш просто не даст войти по паролю
+	pass, err := repo.Passwords.Hash(randutils.RandStringRunes(50))
+	if err != nil {
+		return nil, err
+	}
+
+	user := &User{
+		DisplayName: displayName,
+	}
+	err = dbutils.InTx(ctx, repo.db, func(tx *sql.Tx) error {
+		user.Email = ident.Email
+		taken := 0
+		if ident.Verified && user.Email != "" {
+			err := tx.QueryRowContext(ctx, "SELECT COUNT(*) FROM users WHERE email = ?", user.Email).Scan(&taken)
+			if err != nil {
+				return err
+			}
+		}
+		verified := ident.Verified && user.Email != "" && taken == 0
+		if !verified {
+			user.Email = fmt.Sprintf("%s.%s@oauth.invalid", ident.Provider, ident.Subject)
+		}
+
+		// логин мог уже кто-то занять, пробуем с суффиксом
+		var result sql.Result
+		var err error
+		for i := 0; i < 5; i++ {
+			user.Login = base
+			if i > 0 {
+				user.Login = fmt.Sprintf("%s_%d", base, 1000+rand.Intn(9000))
+			}
+			result, err = tx.ExecContext(ctx, "INSERT INTO users(login, email, password, display_name, email_verified) VALUES(?, ?, ?, ?, ?)",
+				user.Login, user.Email, pass, user.DisplayName, verified)
+			if !dbutils.IsDuplicate(err) {
+				break
+			}
+		}
+		if err != nil {
+			return fmt.Errorf("insert error: %v", err)
+		}
+		uid, err := result.LastInsertId()
+		if err != nil {
+			return fmt.Errorf("LastInsertId err: %v", err)
+		}
+		user.ID = uint32(uid)
+
+		_, err = tx.ExecContext(ctx, "INSERT INTO user_identities(provider, subject, user_id, email, email_verified, created_at) VALUES(?, ?, ?, ?, ?, NOW())",
+			ident.Provider, ident.Subject, user.ID, ident.Email, ident.Verified)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return user, nil
+}
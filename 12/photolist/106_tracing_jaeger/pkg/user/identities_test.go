@@ -0,0 +1,58 @@
+package user
+
+import (
+	"context"
+	"testing"
+
+	sqlmock "gopkg.in/DATA-DOG/go-sqlmock.v1"
+
+	"photolist/pkg/oauth"
+)
+
+// email пользователя берётся у провайдера, только если тот его подтвердил и адрес свободен
+func TestCreateWithIdentity(t *testing.T) {
+	placeholder := "github.7@oauth.invalid"
+	cases := []struct {
+		name     string
+		verified bool
+		taken    int // -1 - проверки занятости нет
+		email    string
+	}{
+		{"verified free", true, 0, "octo@example.com"},
+		{"verified taken", true, 1, placeholder},
+		{"unverified", false, -1, placeholder},
+	}
+	for _, c := range cases {
+		db, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("cant create mock: %s", err)
+		}
+		repo := NewUsersRepository(db)
+		repo.Passwords, _ = NewPasswordHasher(testPasswordConfig("argon2id"))
+		ident := &oauth.Identity{Provider: "github", Subject: "7", Login: "octo", Email: "octo@example.com", Verified: c.verified}
+
+		mock.ExpectBegin()
+		if c.taken >= 0 {
+			mock.ExpectQuery(`SELECT COUNT\(\*\) FROM users WHERE email = \?`).WithArgs("octo@example.com").
+				WillReturnRows(sqlmock.NewRows([]string{"cnt"}).AddRow(c.taken))
+		}
+		mock.ExpectExec(`INSERT INTO users\(login, email, password, display_name, email_verified\)`).
+			WithArgs("github_octo", c.email, sqlmock.AnyArg(), "", c.email != placeholder).
+			WillReturnResult(sqlmock.NewResult(5, 1))
+		mock.ExpectExec(`INSERT INTO user_identities\(provider, subject, user_id, email, email_verified, created_at\)`).
+			WithArgs("github", "7", 5, "octo@example.com", c.verified).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		u, err := repo.CreateWithIdentity(context.Background(), ident)
+		if err != nil {
+			t.Errorf("[%s] unexpected err: %v", c.name, err)
+		} else if u.ID != 5 || u.Email != c.email {
+			t.Errorf("[%s] bad user: %+v", c.name, u)
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("[%s] there were unfulfilled expectations: %s", c.name, err)
+		}
+		db.Close()
+	}
+}
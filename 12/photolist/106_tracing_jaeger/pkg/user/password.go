@@ -0,0 +1,180 @@
+package user
+
+import (
+	"bytes"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+	"golang.org/x/crypto/scrypt"
+
+	"photolist/pkg/config"
+)
+
+// PasswordHasher - хеши паролей в формате PHC: $алгоритм$параметры$соль$хеш
+// https://github.com/P-H-C/phc-string-format/blob/master/phc-sf-spec.md
+// параметры лежат в самом хеше, поэтому их можно менять в конфиге:
+// старые хеши продолжают проверяться, а при входе пересчитываются с новыми
+type PasswordHasher struct {
+	cfg config.PasswordConfig
+}
+
+const (
+	passSaltLen = 16
+	passKeyLen  = 32
+
+	// до PHC хранили salt(8) + argon2id(t=1, m=64MB, p=4) сырыми байтами
+	legacySaltLen = 8
+	legacyHashLen = legacySaltLen + passKeyLen
+)
+
+var b64 = base64.RawStdEncoding
+
+// DefaultPasswordConfig - те же параметры argon2id, что были у сырых хешей
+func DefaultPasswordConfig() config.PasswordConfig {
+	cfg := config.PasswordConfig{Algorithm: "argon2id"}
+	cfg.Argon2.Time = 1
+	cfg.Argon2.Memory = 64 * 1024
+	cfg.Argon2.Threads = 4
+	cfg.Bcrypt.Cost = 12
+	cfg.Scrypt.LN = 15
+	cfg.Scrypt.R = 8
+	cfg.Scrypt.P = 1
+	return cfg
+}
+
+func NewPasswordHasher(cfg config.PasswordConfig) (*PasswordHasher, error) {
+	switch cfg.Algorithm {
+	case "argon2id":
+		if cfg.Argon2.Time == 0 || cfg.Argon2.Memory == 0 || cfg.Argon2.Threads == 0 {
+			return nil, fmt.Errorf("argon2id: time, memory and threads are required")
+		}
+	case "bcrypt":
+		if cfg.Bcrypt.Cost < bcrypt.MinCost || cfg.Bcrypt.Cost > bcrypt.MaxCost {
+			return nil, fmt.Errorf("bcrypt: cost must be in [%d, %d]", bcrypt.MinCost, bcrypt.MaxCost)
+		}
+	case "scrypt":
+		if cfg.Scrypt.LN <= 0 || cfg.Scrypt.LN > 30 || cfg.Scrypt.R <= 0 || cfg.Scrypt.P <= 0 {
+			return nil, fmt.Errorf("scrypt: bad ln, r or p")
+		}
+	default:
+		return nil, fmt.Errorf("unknown password algorithm %q", cfg.Algorithm)
+	}
+	return &PasswordHasher{cfg: cfg}, nil
+}
+
+// Hash - новый хеш с текущими параметрами
+func (ph *PasswordHasher) Hash(pass string) ([]byte, error) {
+	switch ph.cfg.Algorithm {
+	case "bcrypt":
+		return bcrypt.GenerateFromPassword([]byte(pass), ph.cfg.Bcrypt.Cost)
+	case "scrypt":
+		p := ph.cfg.Scrypt
+		salt := makeSalt(passSaltLen)
+		key, err := scrypt.Key([]byte(pass), salt, 1<<uint(p.LN), p.R, p.P, passKeyLen)
+		if err != nil {
+			return nil, err
+		}
+		return []byte(fmt.Sprintf("$scrypt$ln=%d,r=%d,p=%d$%s$%s",
+			p.LN, p.R, p.P, b64.EncodeToString(salt), b64.EncodeToString(key))), nil
+	}
+	p := ph.cfg.Argon2
+	salt := makeSalt(passSaltLen)
+	key := argon2.IDKey([]byte(pass), salt, p.Time, p.Memory, p.Threads, passKeyLen)
+	return []byte(fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2.Version, p.Memory, p.Time, p.Threads, b64.EncodeToString(salt), b64.EncodeToString(key))), nil
+}
+
+// Verify проверяет пароль, второй результат - хеш устарел и его надо пересчитать
+// сравнение всегда за постоянное время
+func (ph *PasswordHasher) Verify(hash []byte, pass string) (bool, bool) {
+	switch {
+	case bytes.HasPrefix(hash, []byte("$argon2id$")):
+		return ph.verifyArgon2(hash, pass)
+	case bytes.HasPrefix(hash, []byte("$scrypt$")):
+		return ph.verifyScrypt(hash, pass)
+	case bytes.HasPrefix(hash, []byte("$2")):
+		if bcrypt.CompareHashAndPassword(hash, []byte(pass)) != nil {
+			return false, false
+		}
+		cost, _ := bcrypt.Cost(hash)
+		return true, ph.cfg.Algorithm != "bcrypt" || cost != ph.cfg.Bcrypt.Cost
+	case len(hash) == legacyHashLen:
+		salt := hash[:legacySaltLen]
+		ok := subtle.ConstantTimeCompare(legacyHashPass(pass, salt), hash) == 1
+		return ok, ok
+	}
+	return false, false
+}
+
+// parsePHC - $alg$[v=..$]params$salt$hash, параметры - key=value через запятую
+func parsePHC(hash []byte, alg string) (map[string]int, []byte, []byte, bool) {
+	parts := strings.Split(string(hash), "$")
+	// ["", alg, (v=..), params, salt, hash]
+	if len(parts) < 5 || parts[1] != alg {
+		return nil, nil, nil, false
+	}
+	params := map[string]int{}
+	for _, part := range parts[2 : len(parts)-2] {
+		for _, kv := range strings.Split(part, ",") {
+			eq := strings.IndexByte(kv, '=')
+			if eq < 0 {
+				return nil, nil, nil, false
+			}
+			v, err := strconv.Atoi(kv[eq+1:])
+			if err != nil {
+				return nil, nil, nil, false
+			}
+			params[kv[:eq]] = v
+		}
+	}
+	salt, err := b64.DecodeString(parts[len(parts)-2])
+	if err != nil {
+		return nil, nil, nil, false
+	}
+	key, err := b64.DecodeString(parts[len(parts)-1])
+	if err != nil || len(key) == 0 {
+		return nil, nil, nil, false
+	}
+	return params, salt, key, true
+}
+
+func (ph *PasswordHasher) verifyArgon2(hash []byte, pass string) (bool, bool) {
+	params, salt, key, ok := parsePHC(hash, "argon2id")
+	if !ok || params["v"] != argon2.Version || params["t"] <= 0 || params["m"] <= 0 || params["p"] <= 0 || params["p"] > 255 {
+		return false, false
+	}
+	t, m, p := uint32(params["t"]), uint32(params["m"]), uint8(params["p"])
+	calc := argon2.IDKey([]byte(pass), salt, t, m, p, uint32(len(key)))
+	if subtle.ConstantTimeCompare(calc, key) != 1 {
+		return false, false
+	}
+	cur := ph.cfg.Argon2
+	return true, ph.cfg.Algorithm != "argon2id" || t != cur.Time || m != cur.Memory || p != cur.Threads ||
+		len(salt) < passSaltLen || len(key) != passKeyLen
+}
+
+func (ph *PasswordHasher) verifyScrypt(hash []byte, pass string) (bool, bool) {
+	params, salt, key, ok := parsePHC(hash, "scrypt")
+	if !ok || params["ln"] <= 0 || params["ln"] > 30 {
+		return false, false
+	}
+	calc, err := scrypt.Key([]byte(pass), salt, 1<<uint(params["ln"]), params["r"], params["p"], len(key))
+	if err != nil || subtle.ConstantTimeCompare(calc, key) != 1 {
+		return false, false
+	}
+	cur := ph.cfg.Scrypt
+	return true, ph.cfg.Algorithm != "scrypt" || params["ln"] != cur.LN || params["r"] != cur.R || params["p"] != cur.P ||
+		len(salt) < passSaltLen || len(key) != passKeyLen
+}
+
+// legacyHashPass - старый формат без параметров, остался только для проверки
+func legacyHashPass(plainPassword string, salt []byte) []byte {
+	hashedPass := argon2.IDKey([]byte(plainPassword), salt, 1, 64*1024, 4, passKeyLen)
+	res := append([]byte{}, salt...)
+	return append(res, hashedPass...)
+}
@@ -0,0 +1,129 @@
+package user
+
+import (
+	"context"
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	sqlmock "gopkg.in/DATA-DOG/go-sqlmock.v1"
+
+	"photolist/pkg/config"
+)
+
+// хеш golangcourse/love из db_init.sql, старый формат
+const legacyHashHex = "7362415A62716D7072AC09EAE839A4A1C95E73EC5FA3FC6EACE4D2C78BF4BF1C6906789B557F8C55"
+
+func testPasswordConfig(alg string) config.PasswordConfig {
+	cfg := DefaultPasswordConfig()
+	cfg.Algorithm = alg
+	// тесты не должны ждать настоящих параметров
+	cfg.Argon2.Memory = 1024
+	cfg.Bcrypt.Cost = 4
+	cfg.Scrypt.LN = 10
+	return cfg
+}
+
+func TestPasswordHasher(t *testing.T) {
+	for _, alg := range []string{"argon2id", "bcrypt", "scrypt"} {
+		ph, err := NewPasswordHasher(testPasswordConfig(alg))
+		if err != nil {
+			t.Fatalf("[%s] unexpected err: %v", alg, err)
+		}
+		hash, err := ph.Hash("love")
+		if err != nil {
+			t.Fatalf("[%s] hash err: %v", alg, err)
+		}
+		if !strings.HasPrefix(string(hash), "$") {
+			t.Errorf("[%s] not a PHC string: %s", alg, hash)
+		}
+		if ok, rehash := ph.Verify(hash, "love"); !ok || rehash {
+			t.Errorf("[%s] expected valid current hash, got ok=%v rehash=%v", alg, ok, rehash)
+		}
+		if ok, _ := ph.Verify(hash, "nolove"); ok {
+			t.Errorf("[%s] wrong password accepted", alg)
+		}
+		other, _ := ph.Hash("love")
+		if string(other) == string(hash) {
+			t.Errorf("[%s] same salt twice", alg)
+		}
+	}
+}
+
+func TestPasswordUpgrade(t *testing.T) {
+	legacy, _ := hex.DecodeString(legacyHashHex)
+	argonOld, _ := NewPasswordHasher(testPasswordConfig("argon2id"))
+	oldHash, _ := argonOld.Hash("love")
+
+	stronger := testPasswordConfig("argon2id")
+	stronger.Argon2.Time = 2
+	cases := []struct {
+		name   string
+		cfg    config.PasswordConfig
+		hash   []byte
+		pass   string
+		ok     bool
+		rehash bool
+	}{
+		{"legacy", testPasswordConfig("argon2id"), legacy, "love", true, true},
+		{"legacy bad pass", testPasswordConfig("argon2id"), legacy, "nolove", false, false},
+		{"params changed", stronger, oldHash, "love", true, true},
+		{"algorithm changed", testPasswordConfig("bcrypt"), oldHash, "love", true, true},
+		{"garbage", testPasswordConfig("argon2id"), []byte("$argon2id$v=19$m=x$$"), "love", false, false},
+	}
+	for _, c := range cases {
+		ph, _ := NewPasswordHasher(c.cfg)
+		ok, rehash := ph.Verify(c.hash, c.pass)
+		if ok != c.ok || rehash != c.rehash {
+			t.Errorf("[%s] expected ok=%v rehash=%v, got %v %v", c.name, c.ok, c.rehash, ok, rehash)
+		}
+	}
+}
+
+func TestNewPasswordHasherErrors(t *testing.T) {
+	bad := []config.PasswordConfig{
+		{Algorithm: "md5"},
+		testPasswordConfig("bcrypt"),
+		testPasswordConfig("argon2id"),
+	}
+	bad[1].Bcrypt.Cost = 100
+	bad[2].Argon2.Threads = 0
+	for i, cfg := range bad {
+		if _, err := NewPasswordHasher(cfg); err == nil {
+			t.Errorf("[%d] expected error", i)
+		}
+	}
+}
+
+// вход со старым хешем пересчитывает его, не трогая ver
+func TestLoginRehash(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("cant create mock: %s", err)
+	}
+	defer db.Close()
+
+	legacy, _ := hex.DecodeString(legacyHashHex)
+	repo := NewUsersRepository(db)
+	repo.Passwords, _ = NewPasswordHasher(testPasswordConfig("argon2id"))
+
+	mock.ExpectQuery(`SELECT id, login, ver, password FROM users WHERE login = \?`).WithArgs("golangcourse").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "login", "ver", "password"}).AddRow(1, "golangcourse", 0, legacy))
+	mock.ExpectExec(`UPDATE users SET password = \? WHERE id = \? AND password = \?`).
+		WithArgs(sqlmock.AnyArg(), 1, legacy).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	user, err := repo.CheckPasswordByLogin(context.Background(), "golangcourse", "love")
+	if err != nil || user.ID != 1 {
+		t.Errorf("expected login, got %v %v", user, err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestMakeSalt(t *testing.T) {
+	if len(makeSalt(16)) != 16 || len(makeSalt(8)) != 8 {
+		t.Errorf("makeSalt ignores length")
+	}
+}
@@ -0,0 +1,58 @@
+package user
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+)
+
+// Role - роль пользователя, админу доступно всё, что и остальным
+type Role string
+
+const (
+	RoleUser  Role = "user"
+	RoleAdmin Role = "admin"
+)
+
+var (
+	errCantBanAdmin = errors.New("Admin cant be banned")
+)
+
+func IsErrCantBanAdmin(err error) bool {
+	return err == errCantBanAdmin
+}
+
+// Allows - можно ли с этой ролью то, что требует need
+func (r Role) Allows(need Role) bool {
+	return r == RoleAdmin || r == need
+}
+
+// GetRole - роль и бан пользователя одним запросом
+func (repo *UserRepository) GetRole(ctx context.Context, userID uint32) (Role, bool, error) {
+	var (
+		role   Role
+		banned bool
+	)
+	err := repo.db.QueryRowContext(ctx, "SELECT role, banned FROM users WHERE id = ?", userID).Scan(&role, &banned)
+	if err == sql.ErrNoRows {
+		return "", false, errUserNotFound
+	} else if err != nil {
+		return "", false, err
+	}
+	return role, banned, nil
+}
+
+// SetBanned банит пользователя или снимает бан
+// ver растёт в обоих случаях, при бане это гасит его jwt-сессии,
+// сессии устройств (refresh) отзывает вызывающий через SessionManager.DestroyAll
+func (repo *UserRepository) SetBanned(ctx context.Context, userID uint32, banned bool) error {
+	role, _, err := repo.GetRole(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if banned && role == RoleAdmin {
+		return errCantBanAdmin
+	}
+	_, err = repo.db.ExecContext(ctx, "UPDATE users SET banned = ?, ver = ver + 1 WHERE id = ?", banned, userID)
+	return err
+}
@@ -0,0 +1,119 @@
+package user
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	sqlmock "gopkg.in/DATA-DOG/go-sqlmock.v1"
+)
+
+func TestRoleAllows(t *testing.T) {
+	cases := []struct {
+		role, need Role
+		expected   bool
+	}{
+		{RoleUser, RoleUser, true},
+		{RoleUser, RoleAdmin, false},
+		{RoleAdmin, RoleAdmin, true},
+		{RoleAdmin, RoleUser, true},
+		{"", RoleUser, false},
+	}
+	for _, c := range cases {
+		if got := c.role.Allows(c.need); got != c.expected {
+			t.Errorf("[%s/%s] expected %v, got %v", c.role, c.need, c.expected, got)
+		}
+	}
+}
+
+// бан поднимает ver, чтобы SessionsJWTVer перестал принимать старые куки
+func TestSetBanned(t *testing.T) {
+	cases := []struct {
+		name    string
+		role    string
+		banned  bool
+		checkFn func(error) bool
+	}{
+		{"ban user", "user", true, func(err error) bool { return err == nil }},
+		{"unban user", "user", false, func(err error) bool { return err == nil }},
+		{"ban admin", "admin", true, IsErrCantBanAdmin},
+		// снять бан с админа можно, запрет только на бан
+		{"unban admin", "admin", false, func(err error) bool { return err == nil }},
+	}
+	for _, c := range cases {
+		db, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("cant create mock: %s", err)
+		}
+		repo := NewUsersRepository(db)
+
+		mock.ExpectQuery(`SELECT role, banned FROM users WHERE id = \?`).WithArgs(3).
+			WillReturnRows(sqlmock.NewRows([]string{"role", "banned"}).AddRow(c.role, !c.banned))
+		if c.role != "admin" || !c.banned {
+			mock.ExpectExec(`UPDATE users SET banned = \?, ver = ver \+ 1 WHERE id = \?`).
+				WithArgs(c.banned, 3).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+		}
+
+		err = repo.SetBanned(context.Background(), 3, c.banned)
+		if !c.checkFn(err) {
+			t.Errorf("[%s] unexpected err: %v", c.name, err)
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("[%s] there were unfulfilled expectations: %s", c.name, err)
+		}
+		db.Close()
+	}
+
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("cant create mock: %s", err)
+	}
+	defer db.Close()
+	mock.ExpectQuery(`SELECT role, banned FROM users WHERE id = \?`).WithArgs(3).
+		WillReturnRows(sqlmock.NewRows([]string{"role", "banned"}))
+	err = NewUsersRepository(db).SetBanned(context.Background(), 3, true)
+	if !IsErrUserNotFound(err) {
+		t.Errorf("[not found] expected errUserNotFound, got %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("[not found] there were unfulfilled expectations: %s", err)
+	}
+}
+
+// правильный пароль забаненному не помогает
+func TestLoginBanned(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("cant create mock: %s", err)
+	}
+	defer db.Close()
+
+	repo := NewUsersRepository(db)
+	hash, _ := repo.Passwords.Hash("love")
+	mock.ExpectQuery(`SELECT id, login, ver, password FROM users WHERE login = \?`).WithArgs("user").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "login", "ver", "password"}).
+			AddRow(3, "user", 1, hash))
+	mock.ExpectQuery(`SELECT role, banned FROM users WHERE id = \?`).WithArgs(3).
+		WillReturnRows(sqlmock.NewRows([]string{"role", "banned"}).AddRow("user", true))
+
+	sm := &fakeTwoStep{}
+	uh := &UserHandler{
+		Sessions:  sm,
+		UsersRepo: repo,
+	}
+	w := httptest.NewRecorder()
+	uh.Login(w, postForm("/user/login", url.Values{"login": {"user"}, "password": {"love"}}))
+
+	if w.Code != http.StatusForbidden {
+		t.Errorf("expected 403, got %d", w.Code)
+	}
+	if len(sm.created) != 0 {
+		t.Errorf("expected no pending logins, got %v", sm.created)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
@@ -0,0 +1,210 @@
+package user
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base32"
+	"errors"
+	"strings"
+	"time"
+
+	"photolist/pkg/totp"
+	"photolist/pkg/utils/dbutils"
+	"photolist/pkg/utils/randutils"
+)
+
+var (
+	errNoTOTP      = errors.New("2FA is not set up")
+	errTOTPEnabled = errors.New("2FA already enabled")
+	errBadCode     = errors.New("Bad 2FA code")
+)
+
+func IsErrBadCode(err error) bool {
+	return err == errBadCode
+}
+
+const recoveryCodesCount = 10
+
+// коды восстановления случайные и длинные, поэтому хватает sha256 без соли
+func hashRecoveryCode(code string) []byte {
+	sum := sha256.Sum256([]byte(normalizeCode(code)))
+	return sum[:]
+}
+
+func normalizeCode(code string) string {
+	code = strings.ToLower(code)
+	code = strings.Replace(code, "-", "", -1)
+	return strings.Replace(code, " ", "", -1)
+}
+
+func makeRecoveryCodes() []string {
+	enc := base32.StdEncoding.WithPadding(base32.NoPadding)
+	res := make([]string, recoveryCodesCount)
+	for i := range res {
+		c := strings.ToLower(enc.EncodeToString(randutils.RandCryptBytes(7)))[:10]
+		res[i] = c[:5] + "-" + c[5:]
+	}
+	return res
+}
+
+func isTOTPCode(code string) bool {
+	if len(code) != totp.Digits {
+		return false
+	}
+	for _, c := range code {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func (repo *UserRepository) totpState(ctx context.Context, userID uint32) (string, bool, error) {
+	var (
+		secret  string
+		enabled bool
+	)
+	err := repo.db.QueryRowContext(ctx, "SELECT secret, enabled FROM user_totp WHERE user_id = ?", userID).
+		Scan(&secret, &enabled)
+	if err == sql.ErrNoRows {
+		return "", false, errNoTOTP
+	}
+	return secret, enabled, err
+}
+
+// TOTPEnabled - нужен ли пользователю второй шаг при входе
+func (repo *UserRepository) TOTPEnabled(ctx context.Context, userID uint32) (bool, error) {
+	_, enabled, err := repo.totpState(ctx, userID)
+	if err == errNoTOTP {
+		return false, nil
+	}
+	return enabled, err
+}
+
+// SetupTOTP выдаёт секрет для подключения приложения
+// пока 2fa не включена, секрет тот же при каждом вызове - обновление страницы не сбивает уже отсканированный qr
+func (repo *UserRepository) SetupTOTP(ctx context.Context, userID uint32) (string, error) {
+	secret, enabled, err := repo.totpState(ctx, userID)
+	switch {
+	case err == errNoTOTP:
+		secret = totp.NewSecret()
+		_, err = repo.db.ExecContext(ctx, "INSERT INTO user_totp(user_id, secret, enabled, last_step, created_at) VALUES(?, ?, 0, 0, NOW())",
+			userID, secret)
+		return secret, err
+	case err != nil:
+		return "", err
+	case enabled:
+		return "", errTOTPEnabled
+	}
+	return secret, nil
+}
+
+func replaceRecoveryCodes(ctx context.Context, tx *sql.Tx, userID uint32) ([]string, error) {
+	_, err := tx.ExecContext(ctx, "DELETE FROM user_recovery_codes WHERE user_id = ?", userID)
+	if err != nil {
+		return nil, err
+	}
+	codes := makeRecoveryCodes()
+	for _, c := range codes {
+		_, err = tx.ExecContext(ctx, "INSERT INTO user_recovery_codes(user_id, code_hash) VALUES(?, ?)", userID, hashRecoveryCode(c))
+		if err != nil {
+			return nil, err
+		}
+	}
+	return codes, nil
+}
+
+// EnableTOTP включает 2fa, если код из приложения подошёл к секрету из SetupTOTP
+// возвращает коды восстановления - показать их можно только сейчас, в базе лежат хеши
+func (repo *UserRepository) EnableTOTP(ctx context.Context, userID uint32, code string) ([]string, error) {
+	var codes []string
+	err := dbutils.InTx(ctx, repo.db, func(tx *sql.Tx) error {
+		var (
+			secret  string
+			enabled bool
+		)
+		err := tx.QueryRowContext(ctx, "SELECT secret, enabled FROM user_totp WHERE user_id = ? FOR UPDATE", userID).
+			Scan(&secret, &enabled)
+		if err == sql.ErrNoRows {
+			return errNoTOTP
+		} else if err != nil {
+			return err
+		}
+		if enabled {
+			return errTOTPEnabled
+		}
+		step, ok := totp.Validate(secret, code, time.Now())
+		if !ok {
+			return errBadCode
+		}
+		_, err = tx.ExecContext(ctx, "UPDATE user_totp SET enabled = 1, last_step = ? WHERE user_id = ?", step, userID)
+		if err != nil {
+			return err
+		}
+		codes, err = replaceRecoveryCodes(ctx, tx, userID)
+		return err
+	})
+	return codes, err
+}
+
+// RegenerateRecoveryCodes - новые коды восстановления, старые перестают работать
+func (repo *UserRepository) RegenerateRecoveryCodes(ctx context.Context, userID uint32) ([]string, error) {
+	var codes []string
+	err := dbutils.InTx(ctx, repo.db, func(tx *sql.Tx) error {
+		var err error
+		codes, err = replaceRecoveryCodes(ctx, tx, userID)
+		return err
+	})
+	return codes, err
+}
+
+func (repo *UserRepository) DisableTOTP(ctx context.Context, userID uint32) error {
+	return dbutils.InTx(ctx, repo.db, func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, "DELETE FROM user_totp WHERE user_id = ?", userID)
+		if err != nil {
+			return err
+		}
+		_, err = tx.ExecContext(ctx, "DELETE FROM user_recovery_codes WHERE user_id = ?", userID)
+		return err
+	})
+}
+
+// CheckSecondFactor принимает код из приложения или код восстановления
+// оба одноразовые: для totp запоминается шаг, код восстановления удаляется
+func (repo *UserRepository) CheckSecondFactor(ctx context.Context, userID uint32, code string) error {
+	code = normalizeCode(code)
+	if !isTOTPCode(code) {
+		result, err := repo.db.ExecContext(ctx, "DELETE FROM user_recovery_codes WHERE user_id = ? AND code_hash = ?",
+			userID, hashRecoveryCode(code))
+		if err != nil {
+			return err
+		}
+		if affected, _ := result.RowsAffected(); affected == 0 {
+			return errBadCode
+		}
+		return nil
+	}
+
+	secret, enabled, err := repo.totpState(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if !enabled {
+		return errNoTOTP
+	}
+	step, ok := totp.Validate(secret, code, time.Now())
+	if !ok {
+		return errBadCode
+	}
+	// условие в запросе, а не в коде - два параллельных входа с одним кодом не пройдут оба
+	result, err := repo.db.ExecContext(ctx, "UPDATE user_totp SET last_step = ? WHERE user_id = ? AND last_step < ?",
+		step, userID, step)
+	if err != nil {
+		return err
+	}
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return errBadCode
+	}
+	return nil
+}
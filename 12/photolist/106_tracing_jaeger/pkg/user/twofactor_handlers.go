@@ -0,0 +1,187 @@
+package user
+
+import (
+	"fmt"
+	"net/http"
+
+	"photolist/pkg/apierr"
+	"photolist/pkg/logging"
+	"photolist/pkg/session"
+	"photolist/pkg/totp"
+	"photolist/pkg/utils/httputils"
+)
+
+const totpIssuer = "photolist"
+
+// startSession - пароль (или oauth) уже проверен, забаненных дальше не пускаем
+// если у пользователя включена 2fa, сессии ещё нет - только ожидание кода
+func (uh *UserHandler) startSession(w http.ResponseWriter, r *http.Request, user *User) {
+	// бан проверяем тут, а не в сессиях - так он действует и на вход через oauth
+	_, banned, err := uh.UsersRepo.GetRole(r.Context(), user.ID)
+	if err != nil {
+		httputils.RespError(w, r, apierr.Internal(fmt.Errorf("db error: %w", err)))
+		return
+	}
+	if banned {
+		httputils.RespError(w, r, apierr.New(apierr.CodeForbidden, "User banned"))
+		return
+	}
+
+	enabled, err := uh.UsersRepo.TOTPEnabled(r.Context(), user.ID)
+	if err != nil {
+		httputils.RespError(w, r, apierr.Internal(fmt.Errorf("db error: %w", err)))
+		return
+	}
+	if !enabled {
+		uh.Sessions.Create(r.Context(), w, user)
+		http.Redirect(w, r, "/photos/", http.StatusFound)
+		return
+	}
+
+	ts, ok := uh.Sessions.(session.TwoStep)
+	if !ok {
+		// пускать без второго фактора нельзя, а сессии без хранилища его не умеют
+		logging.FromContext(r.Context()).Error("session manager doesnt support 2fa", logging.UserID(user.ID))
+		httputils.RespError(w, r, apierr.New(apierr.CodeNotImplemented, "2FA login not supported"))
+		return
+	}
+	err = ts.CreatePending(r.Context(), w, user)
+	if err != nil {
+		httputils.RespError(w, r, apierr.Internal(fmt.Errorf("create pending login: %w", err)))
+		return
+	}
+	http.Redirect(w, r, "/user/login/2fa", http.StatusFound)
+}
+
+// LoginSecondFactor - второй шаг входа, код из приложения или код восстановления
+func (uh *UserHandler) LoginSecondFactor(w http.ResponseWriter, r *http.Request) {
+	ts, ok := uh.Sessions.(session.TwoStep)
+	if !ok {
+		httputils.RespError(w, r, apierr.New(apierr.CodeNotImplemented, "2FA login not supported"))
+		return
+	}
+	if r.Method != http.MethodPost {
+		uh.Tmpl.Render(w, r, "login_2fa.html", nil)
+		return
+	}
+
+	userID, err := ts.Pending(r.Context(), r)
+	if err == session.ErrNoAuth {
+		// протух или кончились попытки - начинаем с пароля
+		http.Redirect(w, r, "/user/login", http.StatusFound)
+		return
+	}
+	if err != nil {
+		httputils.RespError(w, r, apierr.Internal(fmt.Errorf("check pending login: %w", err)))
+		return
+	}
+
+	err = uh.UsersRepo.CheckSecondFactor(r.Context(), userID, r.FormValue("code"))
+	switch {
+	case err == nil:
+		// all is ok
+	case err == errBadCode || err == errNoTOTP:
+		httputils.RespError(w, r, apierr.New(apierr.CodeBadRequest, "Bad code"))
+	default:
+		httputils.RespError(w, r, apierr.Internal(fmt.Errorf("db error: %w", err)))
+	}
+	if err != nil {
+		return
+	}
+
+	err = ts.Confirm(r.Context(), w, r)
+	if err == session.ErrNoAuth {
+		http.Redirect(w, r, "/user/login", http.StatusFound)
+		return
+	}
+	if err != nil {
+		httputils.RespError(w, r, apierr.Internal(fmt.Errorf("confirm login: %w", err)))
+		return
+	}
+	http.Redirect(w, r, "/photos/", http.StatusFound)
+}
+
+// TwoFactorPage - настройка 2fa
+// GET показывает qr для приложения или, если уже включено, формы отключения и новых кодов
+// POST action=enable с кодом включает, disable и codes требуют пароль
+func (uh *UserHandler) TwoFactorPage(w http.ResponseWriter, r *http.Request) {
+	sess, _ := session.SessionFromContext(r.Context())
+	user, err := uh.UsersRepo.GetByID(r.Context(), sess.UserID)
+	if err != nil {
+		httputils.RespError(w, r, apierr.Internal(fmt.Errorf("db error: %w", err)))
+		return
+	}
+
+	if r.Method == http.MethodPost {
+		uh.twoFactorAction(w, r, user)
+		return
+	}
+
+	enabled, err := uh.UsersRepo.TOTPEnabled(r.Context(), user.ID)
+	if err != nil {
+		httputils.RespError(w, r, apierr.Internal(fmt.Errorf("db error: %w", err)))
+		return
+	}
+	if enabled {
+		uh.Tmpl.Render(w, r, "twofactor.html", map[string]interface{}{
+			"Enabled": true,
+		})
+		return
+	}
+
+	secret, err := uh.UsersRepo.SetupTOTP(r.Context(), user.ID)
+	if err != nil {
+		httputils.RespError(w, r, apierr.Internal(fmt.Errorf("setup totp: %w", err)))
+		return
+	}
+	uh.Tmpl.Render(w, r, "twofactor.html", map[string]interface{}{
+		"Secret": secret,
+		"URI":    totp.URI(totpIssuer, user.Login, secret),
+	})
+}
+
+func (uh *UserHandler) twoFactorAction(w http.ResponseWriter, r *http.Request, user *User) {
+	action := r.FormValue("action")
+	if action == "disable" || action == "codes" {
+		_, err := uh.UsersRepo.CheckPasswordByUserID(r.Context(), user.ID, r.FormValue("password"))
+		if err != nil {
+			httputils.RespError(w, r, apierr.New(apierr.CodeBadRequest, "Bad pass"))
+			return
+		}
+	}
+
+	var (
+		codes []string
+		err   error
+	)
+	switch action {
+	case "enable":
+		codes, err = uh.UsersRepo.EnableTOTP(r.Context(), user.ID, r.FormValue("code"))
+	case "codes":
+		codes, err = uh.UsersRepo.RegenerateRecoveryCodes(r.Context(), user.ID)
+	case "disable":
+		err = uh.UsersRepo.DisableTOTP(r.Context(), user.ID)
+		if err == nil {
+			http.Redirect(w, r, "/user/2fa", http.StatusFound)
+			return
+		}
+	default:
+		httputils.RespError(w, r, apierr.New(apierr.CodeBadRequest, "Unknown action"))
+		return
+	}
+
+	switch err {
+	case nil:
+		// коды показываем один раз, сразу в ответе
+		uh.Tmpl.Render(w, r, "twofactor.html", map[string]interface{}{
+			"Enabled":       true,
+			"RecoveryCodes": codes,
+		})
+	case errBadCode:
+		httputils.RespError(w, r, apierr.New(apierr.CodeBadRequest, "Bad code"))
+	case errNoTOTP, errTOTPEnabled:
+		http.Redirect(w, r, "/user/2fa", http.StatusFound)
+	default:
+		httputils.RespError(w, r, apierr.Internal(fmt.Errorf("2fa: %w", err)))
+	}
+}
@@ -0,0 +1,180 @@
+package user
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	sqlmock "gopkg.in/DATA-DOG/go-sqlmock.v1"
+
+	"photolist/pkg/session"
+	"photolist/pkg/totp"
+)
+
+// fakeTwoStep - вход ждёт код для userID, пока pending не сброшен
+type fakeTwoStep struct {
+	fakeSessions
+	pending   uint32
+	created   []uint32
+	confirmed bool
+}
+
+func (fs *fakeTwoStep) CreatePending(ctx context.Context, w http.ResponseWriter, u session.UserInterface) error {
+	fs.created = append(fs.created, u.GetID())
+	return nil
+}
+
+func (fs *fakeTwoStep) Pending(context.Context, *http.Request) (uint32, error) {
+	if fs.pending == 0 {
+		return 0, session.ErrNoAuth
+	}
+	return fs.pending, nil
+}
+
+func (fs *fakeTwoStep) Confirm(context.Context, http.ResponseWriter, *http.Request) error {
+	fs.confirmed = true
+	return nil
+}
+
+func TestLoginSecondFactor(t *testing.T) {
+	secret := totp.NewSecret()
+	code, _ := totp.Code(secret, time.Now())
+	step := totp.Step(time.Now())
+
+	cases := []struct {
+		name     string
+		pending  uint32
+		code     string
+		mock     func(mock sqlmock.Sqlmock)
+		status   int
+		location string
+	}{
+		{
+			name:    "totp ok",
+			pending: 3,
+			code:    code,
+			mock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`SELECT secret, enabled FROM user_totp WHERE user_id = \?`).WithArgs(3).
+					WillReturnRows(sqlmock.NewRows([]string{"secret", "enabled"}).AddRow(secret, true))
+				mock.ExpectExec(`UPDATE user_totp SET last_step = \? WHERE user_id = \? AND last_step < \?`).
+					WithArgs(sqlmock.AnyArg(), 3, sqlmock.AnyArg()).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			status:   http.StatusFound,
+			location: "/photos/",
+		},
+		{
+			name:    "totp replay",
+			pending: 3,
+			code:    code,
+			mock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`SELECT secret, enabled FROM user_totp WHERE user_id = \?`).WithArgs(3).
+					WillReturnRows(sqlmock.NewRows([]string{"secret", "enabled"}).AddRow(secret, true))
+				// шаг уже использован - last_step не меньше
+				mock.ExpectExec(`UPDATE user_totp SET last_step = \? WHERE user_id = \? AND last_step < \?`).
+					WithArgs(step, 3, step).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			status: http.StatusBadRequest,
+		},
+		{
+			name:    "recovery code",
+			pending: 3,
+			code:    "ABCDE-fghij",
+			mock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(`DELETE FROM user_recovery_codes WHERE user_id = \? AND code_hash = \?`).
+					WithArgs(3, hashRecoveryCode("abcdefghij")).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			status:   http.StatusFound,
+			location: "/photos/",
+		},
+		{
+			name:    "unknown recovery code",
+			pending: 3,
+			code:    "zzzzz-zzzzz",
+			mock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(`DELETE FROM user_recovery_codes WHERE user_id = \? AND code_hash = \?`).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			status: http.StatusBadRequest,
+		},
+		{
+			name:     "no pending login",
+			code:     code,
+			mock:     func(sqlmock.Sqlmock) {},
+			status:   http.StatusFound,
+			location: "/user/login",
+		},
+	}
+
+	for _, c := range cases {
+		db, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("cant create mock: %s", err)
+		}
+		c.mock(mock)
+
+		sm := &fakeTwoStep{pending: c.pending}
+		uh := &UserHandler{
+			Sessions:  sm,
+			UsersRepo: NewUsersRepository(db),
+		}
+		w := httptest.NewRecorder()
+		uh.LoginSecondFactor(w, postForm("/user/login/2fa", url.Values{"code": {c.code}}))
+
+		if w.Code != c.status {
+			t.Errorf("[%s] expected status %d, got %d", c.name, c.status, w.Code)
+		}
+		if c.location != "" && w.Header().Get("Location") != c.location {
+			t.Errorf("[%s] expected redirect to %s, got %s", c.name, c.location, w.Header().Get("Location"))
+		}
+		if sm.confirmed != (c.location == "/photos/") {
+			t.Errorf("[%s] unexpected confirm %v", c.name, sm.confirmed)
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("[%s] there were unfulfilled expectations: %s", c.name, err)
+		}
+		db.Close()
+	}
+}
+
+// с включённой 2fa пароль даёт только ожидание кода, без сессии
+func TestLoginWithTOTPEnabled(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("cant create mock: %s", err)
+	}
+	defer db.Close()
+
+	repo := NewUsersRepository(db)
+	hash, _ := repo.Passwords.Hash("love")
+	mock.ExpectQuery(`SELECT id, login, ver, password FROM users WHERE login = \?`).WithArgs("user").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "login", "ver", "password"}).
+			AddRow(3, "user", 0, hash))
+	mock.ExpectQuery(`SELECT role, banned FROM users WHERE id = \?`).WithArgs(3).
+		WillReturnRows(sqlmock.NewRows([]string{"role", "banned"}).AddRow("user", false))
+	mock.ExpectQuery(`SELECT secret, enabled FROM user_totp WHERE user_id = \?`).WithArgs(3).
+		WillReturnRows(sqlmock.NewRows([]string{"secret", "enabled"}).AddRow("JBSWY3DPEHPK3PXP", true))
+
+	sm := &fakeTwoStep{}
+	uh := &UserHandler{
+		Sessions:  sm,
+		UsersRepo: repo,
+	}
+	w := httptest.NewRecorder()
+	uh.Login(w, postForm("/user/login", url.Values{"login": {"user"}, "password": {"love"}}))
+
+	if w.Code != http.StatusFound || w.Header().Get("Location") != "/user/login/2fa" {
+		t.Errorf("expected redirect to 2fa, got %d %s", w.Code, w.Header().Get("Location"))
+	}
+	if len(sm.created) != 1 || sm.created[0] != 3 {
+		t.Errorf("expected pending login for user 3, got %v", sm.created)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
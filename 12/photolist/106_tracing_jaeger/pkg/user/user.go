@@ -2,14 +2,20 @@ package user
 
 import (
 	"strconv"
+
+	"photolist/pkg/utils/pagination"
 )
 
 type User struct {
-	ID       uint32
-	Login    string `gqlgen:"name"`
-	Email    string
-	Ver      int32
-	Followed *bool
+	ID          uint32
+	Login       string `gqlgen:"name"`
+	Email       string
+	Ver         int32
+	Followed    *bool
+	DisplayName string
+	Bio         string
+	// AvatarName - имя аватарки в хранилище, пусто если не загружали
+	AvatarName string
 }
 
 func (u *User) GetID() uint32 {
@@ -24,10 +30,19 @@ func (u *User) Id() string {
 	return strconv.Itoa(int(u.ID))
 }
 
+// Cursor - непрозрачный курсор на пользователя в списках подписок
+func (u *User) Cursor() string {
+	return (&pagination.Cursor{ID: u.ID}).Encode()
+}
+
 func (u *User) Name() string {
 	return u.Login
 }
 
+// Avatar отдаётся через /img/, превьюшка режется по пресету avatar
 func (u *User) Avatar() string {
-	return "https://via.placeholder.com/80"
+	if u.AvatarName == "" {
+		return "https://via.placeholder.com/80"
+	}
+	return "/img/" + u.AvatarName + "/avatar"
 }
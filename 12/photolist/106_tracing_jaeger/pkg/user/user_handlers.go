@@ -3,46 +3,47 @@ package user
 import (
 	"context"
 	"fmt"
-	"log"
 	"net/http"
 	"regexp"
 	"strconv"
 	"strings"
 
-	"golang.org/x/oauth2"
-	"golang.org/x/oauth2/vk"
-
 	"github.com/asaskevich/govalidator"
-
+	"go.uber.org/zap"
+
+	"photolist/pkg/apierr"
+	"photolist/pkg/logging"
+	"photolist/pkg/mailer"
+	"photolist/pkg/middleware"
+	"photolist/pkg/notifications"
+	"photolist/pkg/oauth"
 	"photolist/pkg/session"
+	"photolist/pkg/token"
 	"photolist/pkg/utils/httputils"
-	"photolist/pkg/utils/randutils"
-)
-
-const (
-	VK_APP_ID  = "7065390"
-	VK_APP_KEY = "cQZe3Vvo4mHotmetUdXK"
-	// куда идти с токеном за информацией
-	VK_API_URL = "https://api.vk.com/method/users.get?fields=photo_50&access_token=%s&v=5.52"
-	// куда идти для получения токена
-	VK_AUTH_URL = "https://oauth.vk.com/authorize?client_id=7065390&redirect_uri=http://localhost:8080/user/login_oauth&response_type=code&scope=email"
+	"photolist/pkg/utils/pagination"
 )
 
-type VKOauthResp struct {
-	Response []struct {
-		FirstName string `json:"first_name"`
-		Photo     string `json:"photo_50"`
-	}
+type Templater interface {
+	Render(http.ResponseWriter, *http.Request, string, map[string]interface{})
 }
 
-type Templater interface {
-	Render(context.Context, http.ResponseWriter, string, map[string]interface{})
+// ImageACL - проверка доступа к фото по имени в хранилище, реализуется в photos
+// ownerID из url тоже сверяется, чтобы нельзя было подставить чужой путь
+type ImageACL interface {
+	CanViewImage(ctx context.Context, ownerID uint32, objectName string, viewerID uint32) (bool, error)
 }
 
 type UserHandler struct {
 	Tmpl      Templater
 	Sessions  session.SessionManager
 	UsersRepo *UserRepository
+	Notifier  *notifications.Notifier
+	Images    ImageACL
+	OAuth     *oauth.Registry
+	Mailer    mailer.Mailer
+	Actions   *token.ActionTokens
+	BaseURL   string // адрес сайта для ссылок в письмах
+	Lockout   *middleware.Lockout
 }
 
 var (
@@ -51,8 +52,8 @@ var (
 
 func (uh *UserHandler) Login(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
-		uh.Tmpl.Render(r.Context(), w, "login.html", map[string]interface{}{
-			"VKAuthURL": VK_AUTH_URL,
+		uh.Tmpl.Render(w, r, "login.html", map[string]interface{}{
+			"OAuthProviders": uh.OAuth.Names(),
 		})
 		return
 	}
@@ -60,75 +61,107 @@ func (uh *UserHandler) Login(w http.ResponseWriter, r *http.Request) {
 	login := r.FormValue("login")
 	pass := r.FormValue("password")
 
-	user, err := uh.UsersRepo.CheckPasswordByLogin(login, pass)
+	// пока заблокирован, пароль даже не проверяем - иначе перебор продолжится, просто без ответа
+	if wait := uh.Lockout.Attempt(r.Context(), login); wait > 0 {
+		middleware.TooManyRequests(w, r, wait)
+		return
+	}
+
+	user, err := uh.UsersRepo.CheckPasswordByLogin(r.Context(), login, pass)
 	switch err {
 	case nil:
-		// all is ok
+		uh.Lockout.Reset(r.Context(), login)
 	case errUserNotFound:
-		http.Error(w, "No user", http.StatusBadRequest)
+		httputils.RespError(w, r, apierr.New(apierr.CodeBadRequest, "No user"))
 	case errBadPass:
-		http.Error(w, "Bad pass", http.StatusBadRequest)
+		httputils.RespError(w, r, apierr.New(apierr.CodeBadRequest, "Bad pass"))
 	default:
-		http.Error(w, "Db err", http.StatusInternalServerError)
+		httputils.RespError(w, r, apierr.Internal(err))
 	}
 	if err != nil {
 		return
 	}
 
-	uh.Sessions.Create(r.Context(), w, user)
-	http.Redirect(w, r, "/photos/", http.StatusFound)
+	uh.startSession(w, r, user)
 }
 
+// LoginOauth - вход через внешний аккаунт
+// ?provider=name отправляет к провайдеру, а он возвращает сюда же с code и state
+// если пользователь уже залогинен - аккаунт привязывается к нему
 func (uh *UserHandler) LoginOauth(w http.ResponseWriter, r *http.Request) {
-	code := r.FormValue("code")
-	if code == "" {
-		http.Error(w, "no ouath code", http.StatusBadRequest)
+	if r.FormValue("state") == "" {
+		redirectURL, err := uh.OAuth.Begin(w, r, r.FormValue("provider"))
+		if oauth.IsErrUnknownProvider(err) {
+			httputils.RespError(w, r, apierr.New(apierr.CodeBadRequest, "unknown oauth provider"))
+			return
+		}
+		if err != nil {
+			httputils.RespError(w, r, apierr.Wrap(apierr.CodeUnavailable, "oauth provider unavailable", err))
+			return
+		}
+		http.Redirect(w, r, redirectURL, http.StatusFound)
 		return
 	}
 
-	conf := oauth2.Config{
-		ClientID:     VK_APP_ID,
-		ClientSecret: VK_APP_KEY,
-		RedirectURL:  "http://localhost:8080/user/login_oauth",
-		Endpoint:     vk.Endpoint,
+	ident, err := uh.OAuth.Complete(w, r)
+	switch {
+	case err == nil:
+		// all is ok
+	case oauth.IsErrBadState(err), oauth.IsErrUnknownProvider(err):
+		httputils.RespError(w, r, apierr.New(apierr.CodeBadRequest, "bad oauth state"))
+	case oauth.IsErrDenied(err):
+		http.Redirect(w, r, "/user/login", http.StatusFound)
+	default:
+		httputils.RespError(w, r, apierr.Wrap(apierr.CodeUnavailable, "cannot get oauth token", err))
 	}
-
-	token, err := conf.Exchange(r.Context(), code)
 	if err != nil {
-		log.Println("exchange err", err)
-		http.Error(w, "cannot get oauth token", http.StatusInternalServerError)
 		return
 	}
+	uh.loginIdentity(w, r, ident)
+}
 
-	emailRaw := token.Extra("email")
-	email := ""
-	okEmail := true
-	if emailRaw != nil {
-		email, okEmail = emailRaw.(string)
-	}
-	userIDraw, okID := token.Extra("user_id").(float64)
-	if !okEmail || !okID {
-		log.Printf("cant convert data: UID: %T %v, Email: %T %v", token.Extra("user_id"), token.Extra("user_id"), token.Extra("email"), token.Extra("email"))
-		http.Error(w, "internal error", http.StatusInternalServerError)
+// loginIdentity привязывает внешний аккаунт к текущему пользователю, а без сессии - входит под ним
+func (uh *UserHandler) loginIdentity(w http.ResponseWriter, r *http.Request, ident *oauth.Identity) {
+	// /user/login_oauth без AuthMiddleware, поэтому сессию проверяем и продлеваем сами -
+	// иначе с протухшим jwt привязка молча превратится во вход под внешним аккаунтом
+	sess, err := uh.Sessions.Check(r.Context(), r)
+	if refresher, ok := uh.Sessions.(session.Refresher); ok && err == session.ErrNoAuth {
+		sess, err = refresher.Refresh(r.Context(), w, r)
+		if err != nil && err != session.ErrNoAuth {
+			httputils.RespError(w, r, apierr.Internal(fmt.Errorf("refresh session: %w", err)))
+			return
+		}
+	}
+	if err == nil {
+		err = uh.UsersRepo.LinkIdentity(r.Context(), sess.UserID, ident)
+		if IsErrIdentityLinked(err) {
+			httputils.RespError(w, r, apierr.New(apierr.CodeConflict, "Account linked to another user"))
+			return
+		}
+		if err != nil {
+			httputils.RespError(w, r, apierr.Internal(fmt.Errorf("db error: %w", err)))
+			return
+		}
+		http.Redirect(w, r, "/photos/", http.StatusFound)
 		return
 	}
 
-	login := "vk" + strconv.Itoa(int(userIDraw))
-	pass := randutils.RandStringRunes(50)
-	user, err := uh.UsersRepo.Create(login, email, pass)
-	if err != nil && err != errUserExists {
-		log.Println("db err", err)
-		http.Error(w, "Db err", http.StatusInternalServerError)
+	user, err := uh.UsersRepo.GetByIdentity(r.Context(), ident.Provider, ident.Subject)
+	if err == errUserNotFound {
+		user, err = uh.UsersRepo.CreateWithIdentity(r.Context(), ident)
+	}
+	if err != nil {
+		httputils.RespError(w, r, apierr.Internal(fmt.Errorf("db error: %w", err)))
 		return
 	}
 
-	uh.Sessions.Create(r.Context(), w, user)
-	http.Redirect(w, r, "/photos/", http.StatusFound)
+	// внешний аккаунт заменяет только пароль, второй фактор всё равно спрашиваем
+	uh.startSession(w, r, user)
 }
 
 func (uh *UserHandler) Reg(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
-		uh.Tmpl.Render(r.Context(), w, "reg.html", nil)
+		uh.Tmpl.Render(w, r, "reg.html", nil)
 		return
 	}
 
@@ -137,29 +170,34 @@ func (uh *UserHandler) Reg(w http.ResponseWriter, r *http.Request) {
 	email := r.FormValue("email")
 
 	if !govalidator.IsEmail(email) {
-		http.Error(w, "Bad email", http.StatusBadRequest)
+		httputils.RespError(w, r, apierr.New(apierr.CodeBadRequest, "Bad email"))
 		return
 	}
 
 	if !loginRE.MatchString(login) {
-		http.Error(w, "Bad login", http.StatusBadRequest)
+		httputils.RespError(w, r, apierr.New(apierr.CodeBadRequest, "Bad login"))
 		return
 	}
 
-	user, err := uh.UsersRepo.Create(login, email, pass)
+	user, err := uh.UsersRepo.Create(r.Context(), login, email, pass)
 	switch err {
 	case nil:
 		// all is ok
 	case errUserExists:
-		http.Error(w, "Looks like user exists", http.StatusBadRequest)
+		httputils.RespError(w, r, apierr.New(apierr.CodeConflict, "Looks like user exists"))
 	default:
-		log.Println("db err", err)
-		http.Error(w, "Db err", http.StatusInternalServerError)
+		httputils.RespError(w, r, apierr.Internal(fmt.Errorf("db error: %w", err)))
 	}
 	if err != nil {
 		return
 	}
 
+	err = uh.sendVerifyEmail(r.Context(), user)
+	if err != nil {
+		// зарегистрироваться это не мешает, письмо можно запросить ещё раз
+		logging.FromContext(r.Context()).Error("send verify email err", zap.Error(err))
+	}
+
 	uh.Sessions.Create(r.Context(), w, user)
 	http.Redirect(w, r, "/photos/", http.StatusFound)
 }
@@ -171,26 +209,25 @@ func (uh *UserHandler) Logout(w http.ResponseWriter, r *http.Request) {
 
 func (uh *UserHandler) ChangePassword(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
-		uh.Tmpl.Render(r.Context(), w, "change_pass.html", nil)
+		uh.Tmpl.Render(w, r, "change_pass.html", nil)
 		return
 	}
 
 	if r.FormValue("pass1") == "" || r.FormValue("pass1") != r.FormValue("pass2") {
-		http.Error(w, "New password mistmatch", http.StatusBadRequest)
+		httputils.RespError(w, r, apierr.New(apierr.CodeBadRequest, "New password mistmatch"))
 		return
 	}
 
 	sess, _ := session.SessionFromContext(r.Context())
-	user, err := uh.UsersRepo.CheckPasswordByUserID(sess.UserID, r.FormValue("old_password"))
+	user, err := uh.UsersRepo.CheckPasswordByUserID(r.Context(), sess.UserID, r.FormValue("old_password"))
 	if err != nil {
-		http.Error(w, "Bad pass", http.StatusBadRequest)
+		httputils.RespError(w, r, apierr.New(apierr.CodeBadRequest, "Bad pass"))
 		return
 	}
 
-	err = uh.UsersRepo.UpdatePassword(user.ID, r.FormValue("pass1"))
+	err = uh.UsersRepo.UpdatePassword(r.Context(), user.ID, r.FormValue("pass1"))
 	if err != nil {
-		log.Println("update password error", err)
-		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		httputils.RespError(w, r, apierr.Internal(fmt.Errorf("update password: %w", err)))
 		return
 	}
 	user.Ver++ // во избежание рейсов лучше подгрузить из базы
@@ -204,16 +241,16 @@ func (uh *UserHandler) FollowAPI(w http.ResponseWriter, r *http.Request) {
 	sess, _ := session.SessionFromContext(r.Context())
 	id, err := strconv.Atoi(r.FormValue("id"))
 	if err != nil {
-		httputils.RespJSONError(w, http.StatusBadRequest, nil, "bad id")
+		httputils.RespError(w, r, apierr.New(apierr.CodeBadRequest, "bad id"))
 		return
 	}
-	folUser, err := uh.UsersRepo.GetByID(uint32(id))
+	folUser, err := uh.UsersRepo.GetByID(r.Context(), uint32(id))
 	if err == errUserNotFound {
-		httputils.RespJSONError(w, http.StatusBadRequest, nil, "no user")
+		httputils.RespError(w, r, apierr.New(apierr.CodeBadRequest, "no user"))
 		return
 	}
 	if err != nil {
-		httputils.RespJSONError(w, http.StatusInternalServerError, fmt.Errorf("db error: %v", err), "internal")
+		httputils.RespError(w, r, apierr.Internal(fmt.Errorf("db error: %w", err)))
 		return
 	}
 
@@ -222,11 +259,14 @@ func (uh *UserHandler) FollowAPI(w http.ResponseWriter, r *http.Request) {
 		rate = -1
 	}
 
-	err = uh.UsersRepo.Follow(folUser.ID, sess.UserID, rate)
+	err = uh.UsersRepo.Follow(r.Context(), folUser.ID, sess.UserID, rate)
 	if err != nil {
-		httputils.RespJSONError(w, http.StatusInternalServerError, fmt.Errorf("db error: %v", err), "internal")
+		httputils.RespError(w, r, apierr.Internal(fmt.Errorf("db error: %w", err)))
 		return
 	}
+	if rate > 0 {
+		uh.Notifier.Followed(r.Context(), folUser.ID, sess.UserID)
+	}
 	httputils.RespJSON(w, map[string]interface{}{
 		"id": id,
 	})
@@ -239,10 +279,15 @@ type UserResp struct {
 }
 
 func (uh *UserHandler) FollowingAPI(w http.ResponseWriter, r *http.Request) {
+	page, err := pagination.FromQuery(r.FormValue("after"), r.FormValue("limit"))
+	if err != nil {
+		httputils.RespError(w, r, apierr.New(apierr.CodeBadRequest, "bad page"))
+		return
+	}
 	sess, _ := session.SessionFromContext(r.Context())
-	users, err := uh.UsersRepo.GetFollowedUsers(sess.UserID)
+	users, hasNext, err := uh.UsersRepo.GetFollowedUsers(r.Context(), sess.UserID, page)
 	if err != nil {
-		httputils.RespJSONError(w, http.StatusInternalServerError, fmt.Errorf("db error: %v", err), "internal")
+		httputils.RespError(w, r, apierr.Internal(fmt.Errorf("db error: %w", err)))
 		return
 	}
 	result := make([]*UserResp, 0, len(users))
@@ -252,17 +297,28 @@ func (uh *UserHandler) FollowingAPI(w http.ResponseWriter, r *http.Request) {
 			Login: u.Login,
 		})
 	}
+	next := ""
+	if hasNext {
+		next = users[len(users)-1].Cursor()
+	}
 	httputils.RespJSON(w, map[string]interface{}{
 		"users":    result,
 		"followed": true,
+		"has_next": hasNext,
+		"next":     next,
 	})
 }
 
 func (uh *UserHandler) RecomendsAPI(w http.ResponseWriter, r *http.Request) {
+	page, err := pagination.FromQuery(r.FormValue("after"), r.FormValue("limit"))
+	if err != nil {
+		httputils.RespError(w, r, apierr.New(apierr.CodeBadRequest, "bad page"))
+		return
+	}
 	sess, _ := session.SessionFromContext(r.Context())
-	users, err := uh.UsersRepo.GetRecomendedUsers(sess.UserID)
+	users, hasNext, err := uh.UsersRepo.GetRecomendedUsers(r.Context(), sess.UserID, page)
 	if err != nil {
-		httputils.RespJSONError(w, http.StatusInternalServerError, fmt.Errorf("db error: %v", err), "internal")
+		httputils.RespError(w, r, apierr.Internal(fmt.Errorf("db error: %w", err)))
 		return
 	}
 	result := make([]*UserResp, 0, len(users))
@@ -272,54 +328,208 @@ func (uh *UserHandler) RecomendsAPI(w http.ResponseWriter, r *http.Request) {
 			Login: u.Login,
 		})
 	}
+	next := ""
+	if hasNext {
+		next = users[len(users)-1].Cursor()
+	}
 	httputils.RespJSON(w, map[string]interface{}{
 		"users":    result,
 		"followed": false,
+		"has_next": hasNext,
+		"next":     next,
 	})
 }
 
+type ProfileResp struct {
+	ID          uint32 `json:"id"`
+	Login       string `json:"login"`
+	DisplayName string `json:"display_name"`
+	Bio         string `json:"bio"`
+	Avatar      string `json:"avatar"`
+}
+
+// ProfileAPI на GET отдаёт профиль текущего пользователя, на POST меняет display_name и bio
+// не переданные поля остаются как были
+func (uh *UserHandler) ProfileAPI(w http.ResponseWriter, r *http.Request) {
+	sess, _ := session.SessionFromContext(r.Context())
+	u, err := uh.UsersRepo.GetByID(r.Context(), sess.UserID)
+	if err != nil {
+		httputils.RespError(w, r, apierr.Internal(fmt.Errorf("db error: %w", err)))
+		return
+	}
+
+	if r.Method == http.MethodPost {
+		r.ParseForm()
+		if _, ok := r.PostForm["display_name"]; ok {
+			u.DisplayName = strings.TrimSpace(r.PostForm.Get("display_name"))
+		}
+		if _, ok := r.PostForm["bio"]; ok {
+			u.Bio = strings.TrimSpace(r.PostForm.Get("bio"))
+		}
+		err = uh.UsersRepo.UpdateProfile(r.Context(), u.ID, u.DisplayName, u.Bio)
+		if IsErrBadProfile(err) {
+			httputils.RespError(w, r, apierr.New(apierr.CodeBadRequest, err.Error()))
+			return
+		}
+		if err != nil {
+			httputils.RespError(w, r, apierr.Internal(fmt.Errorf("db error: %w", err)))
+			return
+		}
+	}
+
+	httputils.RespJSON(w, &ProfileResp{
+		ID:          u.ID,
+		Login:       u.Login,
+		DisplayName: u.DisplayName,
+		Bio:         u.Bio,
+		Avatar:      u.Avatar(),
+	})
+}
+
+// devices - сессии текущего пользователя, текущая помечена
+func devices(ctx context.Context, dm session.DeviceManager) ([]*session.Device, error) {
+	sess, _ := session.SessionFromContext(ctx)
+	list, err := dm.List(ctx, sess.UserID)
+	if err != nil {
+		return nil, err
+	}
+	for _, d := range list {
+		d.Current = d.ID == sess.ID
+	}
+	return list, nil
+}
+
+// SessionsPage - мои активные сессии, POST с id разлогинивает устройство
+// stateless jwt-сессии перечислить нельзя, для них 501
+func (uh *UserHandler) SessionsPage(w http.ResponseWriter, r *http.Request) {
+	dm, ok := uh.Sessions.(session.DeviceManager)
+	if !ok {
+		httputils.RespError(w, r, apierr.New(apierr.CodeNotImplemented, "Sessions list not supported"))
+		return
+	}
+
+	if r.Method == http.MethodPost {
+		sess, _ := session.SessionFromContext(r.Context())
+		id := r.FormValue("id")
+		if id == sess.ID {
+			uh.Logout(w, r)
+			return
+		}
+		err := dm.Revoke(r.Context(), sess.UserID, id)
+		if err != nil && !session.IsErrDeviceNotFound(err) {
+			httputils.RespError(w, r, apierr.Internal(fmt.Errorf("revoke session: %w", err)))
+			return
+		}
+		http.Redirect(w, r, "/user/sessions", http.StatusFound)
+		return
+	}
+
+	list, err := devices(r.Context(), dm)
+	if err != nil {
+		httputils.RespError(w, r, apierr.Internal(fmt.Errorf("list sessions: %w", err)))
+		return
+	}
+	uh.Tmpl.Render(w, r, "sessions.html", map[string]interface{}{
+		"Devices": list,
+	})
+}
+
+// SessionsAPI на GET отдаёт устройства пользователя, на POST отзывает устройство по id
+func (uh *UserHandler) SessionsAPI(w http.ResponseWriter, r *http.Request) {
+	dm, ok := uh.Sessions.(session.DeviceManager)
+	if !ok {
+		httputils.RespError(w, r, apierr.New(apierr.CodeNotImplemented, "not supported"))
+		return
+	}
+
+	if r.Method == http.MethodPost {
+		sess, _ := session.SessionFromContext(r.Context())
+		id := r.FormValue("id")
+		if id == sess.ID {
+			httputils.RespError(w, r, apierr.New(apierr.CodeBadRequest, "use logout for current session"))
+			return
+		}
+		err := dm.Revoke(r.Context(), sess.UserID, id)
+		if session.IsErrDeviceNotFound(err) {
+			httputils.RespError(w, r, apierr.New(apierr.CodeNotFound, "no session"))
+			return
+		}
+		if err != nil {
+			httputils.RespError(w, r, apierr.Internal(fmt.Errorf("revoke session: %w", err)))
+			return
+		}
+		httputils.RespJSON(w, map[string]interface{}{
+			"id": id,
+		})
+		return
+	}
+
+	list, err := devices(r.Context(), dm)
+	if err != nil {
+		httputils.RespError(w, r, apierr.Internal(fmt.Errorf("list sessions: %w", err)))
+		return
+	}
+	httputils.RespJSON(w, map[string]interface{}{
+		"sessions": list,
+	})
+}
+
+// InternalImagesAuth - nginx через auth_request спрашивает, можно ли отдать картинку
+// X-Original-URI: /images/{owner_id}/{uuid}[_preset].ext
 func (uh *UserHandler) InternalImagesAuth(w http.ResponseWriter, r *http.Request) {
 	params := strings.Split(r.Header.Get("X-Original-URI"), "/")
 	if len(params) != 4 {
-		log.Println("bad params:", params)
-		http.Error(w, "No auth", http.StatusForbidden)
+		logging.FromContext(r.Context()).Warn("bad params", zap.Strings("params", params))
+		httputils.RespError(w, r, apierr.New(apierr.CodeForbidden, "No auth"))
 		return
 	}
 	// log.Println("InternalImagesAuth params", params)
 
+	// без сессии смотрим как аноним - публичные фото видны всем, как и в /img/
+	viewerID := uint32(0)
 	sess, err := uh.Sessions.Check(r.Context(), r)
-	if err != nil {
-		log.Println("bad params:", err)
-		http.Error(w, "Bad params", http.StatusForbidden)
+	switch {
+	case err == nil:
+		viewerID = sess.UserID
+	case err != session.ErrNoAuth:
+		httputils.RespError(w, r, apierr.Wrap(apierr.CodeForbidden, "Bad params", err))
 		return
 	}
-	// log.Println("InternalImagesAuth sess", sess)
 
-	targetUserID, err := strconv.Atoi(params[2])
+	ownerID, err := strconv.Atoi(params[2])
 	if err != nil {
-		log.Println("bad uid:", err, params[2])
-		http.Error(w, "No auth", http.StatusForbidden)
-		return
-	}
-
-	if sess.UserID == uint32(targetUserID) {
-		// 200 OK
+		logging.FromContext(r.Context()).Warn("bad uid", zap.String("uid", params[2]), zap.Error(err))
+		httputils.RespError(w, r, apierr.New(apierr.CodeForbidden, "No auth"))
 		return
 	}
+	name := imageNameFromFile(params[3])
 
-	followed, err := uh.UsersRepo.IsFollowed(uint32(targetUserID), sess.UserID)
+	allowed, err := uh.Images.CanViewImage(r.Context(), uint32(ownerID), name, viewerID)
 	if err != nil {
-		log.Println("IsFollowed err:", err)
-		http.Error(w, "Internal", http.StatusForbidden)
+		// nginx в auth_request понимает только 2xx, 401 и 403, поэтому не 500
+		logging.FromContext(r.Context()).Error("CanViewImage err", zap.Error(err))
+		httputils.RespError(w, r, apierr.New(apierr.CodeForbidden, "Internal"))
 		return
 	}
+	if !allowed {
+		// не фото - может быть аватаркой, они видны всем
+		avatarOwner, _, err := uh.UsersRepo.GetAvatar(r.Context(), name)
+		allowed = err == nil && avatarOwner == uint32(ownerID)
+	}
 
-	if !followed {
+	if !allowed {
 		// no logs required - regular situation
-		// log.Println("IsFollowed false", uint32(targetUserID), sess.UserID)
-		http.Error(w, "Forbidden", http.StatusForbidden)
+		httputils.RespError(w, r, apierr.New(apierr.CodeForbidden, "Forbidden"))
 		return
 	}
 
 	// 200 OK
 }
+
+// imageNameFromFile - имя объекта без пресета и расширения: uuid_feed.jpg -> uuid
+func imageNameFromFile(file string) string {
+	if idx := strings.IndexAny(file, "_."); idx != -1 {
+		return file[:idx]
+	}
+	return file
+}
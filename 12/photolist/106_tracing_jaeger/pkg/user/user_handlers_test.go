@@ -0,0 +1,194 @@
+package user
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	sqlmock "gopkg.in/DATA-DOG/go-sqlmock.v1"
+
+	"photolist/pkg/oauth"
+	"photolist/pkg/session"
+)
+
+type fakeSessions struct {
+	sess      *session.Session
+	destroyed []uint32 // для кого звали DestroyAll
+}
+
+func (fs *fakeSessions) Check(context.Context, *http.Request) (*session.Session, error) {
+	if fs.sess == nil {
+		return nil, session.ErrNoAuth
+	}
+	return fs.sess, nil
+}
+func (fs *fakeSessions) Create(context.Context, http.ResponseWriter, session.UserInterface) error {
+	return nil
+}
+func (fs *fakeSessions) DestroyCurrent(context.Context, http.ResponseWriter, *http.Request) error {
+	return nil
+}
+func (fs *fakeSessions) DestroyAll(ctx context.Context, w http.ResponseWriter, u session.UserInterface) error {
+	fs.destroyed = append(fs.destroyed, u.GetID())
+	return nil
+}
+
+// fakeACL разрешает сочетания {owner, name, viewer} из allowed
+type fakeACL struct {
+	allowed map[[3]interface{}]bool
+}
+
+func (acl *fakeACL) CanViewImage(_ context.Context, ownerID uint32, name string, viewerID uint32) (bool, error) {
+	return acl.allowed[[3]interface{}{ownerID, name, viewerID}], nil
+}
+
+func TestInternalImagesAuth(t *testing.T) {
+	acl := &fakeACL{allowed: map[[3]interface{}]bool{
+		{uint32(1), "photo", uint32(1)}: true,
+		{uint32(1), "photo", uint32(2)}: true,
+		{uint32(1), "photo", uint32(0)}: true,
+	}}
+
+	cases := []struct {
+		name   string
+		uri    string
+		viewer uint32 // 0 - нет сессии
+		avatar bool   // ожидается запрос аватарки
+		avaOwn uint32 // владелец найденной аватарки, 0 - не найдена
+		status int
+	}{
+		{"owner", "/images/1/photo_feed.jpg", 1, false, 0, http.StatusOK},
+		{"allowed viewer", "/images/1/photo.png", 2, false, 0, http.StatusOK},
+		{"denied viewer", "/images/1/photo_full.jpg", 3, true, 0, http.StatusForbidden},
+		{"avatar", "/images/1/ava_avatar.jpg", 3, true, 1, http.StatusOK},
+		{"avatar foreign owner", "/images/2/ava_avatar.jpg", 3, true, 1, http.StatusForbidden},
+		// аноним видит то же, что и через /img/
+		{"no session public", "/images/1/photo_feed.jpg", 0, false, 0, http.StatusOK},
+		{"no session private", "/images/1/secret_feed.jpg", 0, true, 0, http.StatusForbidden},
+		{"no session avatar", "/images/1/ava_avatar.jpg", 0, true, 1, http.StatusOK},
+		{"bad uri", "/images/photo_feed.jpg", 1, false, 0, http.StatusForbidden},
+		{"bad owner", "/images/x/photo_feed.jpg", 1, false, 0, http.StatusForbidden},
+	}
+
+	for _, c := range cases {
+		db, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("cant create mock: %s", err)
+		}
+		if c.avatar {
+			rows := sqlmock.NewRows([]string{"id", "avatar_format"})
+			if c.avaOwn != 0 {
+				rows.AddRow(c.avaOwn, "jpeg")
+			}
+			mock.ExpectQuery(`SELECT id, avatar_format FROM users WHERE avatar = \?`).
+				WillReturnRows(rows)
+		}
+
+		sm := &fakeSessions{}
+		if c.viewer != 0 {
+			sm.sess = &session.Session{UserID: c.viewer, ID: "sess"}
+		}
+		uh := &UserHandler{
+			Sessions:  sm,
+			UsersRepo: NewUsersRepository(db),
+			Images:    acl,
+		}
+
+		req := httptest.NewRequest("GET", "/api/v1/internal/images/auth", nil)
+		req.Header.Set("X-Original-URI", c.uri)
+		w := httptest.NewRecorder()
+		uh.InternalImagesAuth(w, req)
+
+		if w.Code != c.status {
+			t.Errorf("[%s] expected status %d, got %d", c.name, c.status, w.Code)
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("[%s] there were unfulfilled expectations: %s", c.name, err)
+		}
+		db.Close()
+	}
+}
+
+func TestImageNameFromFile(t *testing.T) {
+	cases := map[string]string{
+		"uuid_feed.jpg":  "uuid",
+		"uuid.png":       "uuid",
+		"uuid":           "uuid",
+		"a-b-c_full.jpg": "a-b-c",
+	}
+	for in, expected := range cases {
+		if got := imageNameFromFile(in); got != expected {
+			t.Errorf("[%s] expected %s, got %s", in, expected, got)
+		}
+	}
+}
+
+// fakeRefreshSessions - jwt протух, сессию можно только продлить по refresh-токену
+type fakeRefreshSessions struct {
+	fakeSessions
+	refreshed  *session.Session
+	refreshErr error
+}
+
+func (fs *fakeRefreshSessions) Refresh(context.Context, http.ResponseWriter, *http.Request) (*session.Session, error) {
+	if fs.refreshErr != nil {
+		return nil, fs.refreshErr
+	}
+	if fs.refreshed == nil {
+		return nil, session.ErrNoAuth
+	}
+	return fs.refreshed, nil
+}
+
+func TestLoginIdentity(t *testing.T) {
+	ident := &oauth.Identity{Provider: "github", Subject: "7", Email: "octo@example.com", Verified: true}
+	link := func(mock sqlmock.Sqlmock) {
+		mock.ExpectExec(`INSERT INTO user_identities`).WithArgs("github", "7", 1, "octo@example.com", true).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+	}
+	cases := []struct {
+		name     string
+		sm       session.SessionManager
+		prepare  func(mock sqlmock.Sqlmock)
+		status   int
+		location string
+	}{
+		{"link with session", &fakeSessions{sess: &session.Session{UserID: 1}}, link, http.StatusFound, "/photos/"},
+		{"link after refresh", &fakeRefreshSessions{refreshed: &session.Session{UserID: 1}}, link, http.StatusFound, "/photos/"},
+		// ни сессии, ни refresh-токена - это вход, до привязки не доходит
+		{"no session", &fakeRefreshSessions{}, func(mock sqlmock.Sqlmock) {
+			mock.ExpectQuery(`FROM user_identities i JOIN users u`).WithArgs("github", "7").
+				WillReturnError(fmt.Errorf("bad connection"))
+		}, http.StatusInternalServerError, ""},
+		{"refresh error", &fakeRefreshSessions{refreshErr: fmt.Errorf("auth unavailable")},
+			func(mock sqlmock.Sqlmock) {}, http.StatusInternalServerError, ""},
+	}
+	for _, c := range cases {
+		db, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("cant create mock: %s", err)
+		}
+		c.prepare(mock)
+		uh := &UserHandler{
+			Sessions:  c.sm,
+			UsersRepo: NewUsersRepository(db),
+		}
+
+		req := httptest.NewRequest("GET", "/user/login_oauth?state=x", nil)
+		w := httptest.NewRecorder()
+		uh.loginIdentity(w, req, ident)
+
+		if w.Code != c.status {
+			t.Errorf("[%s] expected status %d, got %d", c.name, c.status, w.Code)
+		}
+		if c.location != "" && w.Header().Get("Location") != c.location {
+			t.Errorf("[%s] expected redirect to %s, got %s", c.name, c.location, w.Header().Get("Location"))
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("[%s] there were unfulfilled expectations: %s", c.name, err)
+		}
+		db.Close()
+	}
+}
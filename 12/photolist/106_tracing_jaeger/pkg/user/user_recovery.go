@@ -0,0 +1,176 @@
+package user
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"photolist/pkg/apierr"
+	"photolist/pkg/logging"
+	"photolist/pkg/mailer"
+	"photolist/pkg/session"
+	"photolist/pkg/token"
+	"photolist/pkg/utils/httputils"
+)
+
+const (
+	resetTokenTTL  = time.Hour
+	verifyTokenTTL = 72 * time.Hour
+)
+
+func (uh *UserHandler) actionLink(path, tok string) string {
+	return uh.BaseURL + path + "?token=" + url.QueryEscape(tok)
+}
+
+func (uh *UserHandler) sendVerifyEmail(ctx context.Context, u *User) error {
+	tok := uh.Actions.Create(token.PurposeVerifyEmail, u.ID, u.Email, verifyTokenTTL)
+	return uh.Mailer.Send(ctx, &mailer.Message{
+		To:      u.Email,
+		Subject: "Confirm your email",
+		Body: fmt.Sprintf("Hi, %s!\n\nPlease confirm your email address:\n%s\n",
+			u.Login, uh.actionLink("/user/verify_email", tok)),
+	})
+}
+
+// ForgotPassword на POST отправляет ссылку для сброса пароля
+// ответ одинаковый, есть такой email или нет - чтобы по форме нельзя было перебирать адреса,
+// поэтому и ошибку отправки только логируем
+// ссылка уходит только на подтверждённый email
+func (uh *UserHandler) ForgotPassword(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		uh.Tmpl.Render(w, r, "forgot.html", nil)
+		return
+	}
+
+	u, err := uh.UsersRepo.GetByVerifiedEmail(r.Context(), strings.TrimSpace(r.FormValue("email")))
+	switch err {
+	case nil:
+		// ver входит в подпись, после смены пароля ссылка перестанет работать
+		tok := uh.Actions.Create(token.PurposeResetPassword, u.ID, strconv.Itoa(int(u.Ver)), resetTokenTTL)
+		err = uh.Mailer.Send(r.Context(), &mailer.Message{
+			To:      u.Email,
+			Subject: "Password reset",
+			Body: fmt.Sprintf("Hi, %s!\n\nSomeone asked to reset your password. If it was you, follow the link:\n%s\n\nThe link is valid for %s.\n",
+				u.Login, uh.actionLink("/user/reset", tok), resetTokenTTL),
+		})
+		if err != nil {
+			logging.FromContext(r.Context()).Error("send reset email err", zap.Uint32("user_id", u.ID), zap.Error(err))
+		}
+	case errUserNotFound:
+		// nothing to do
+	default:
+		httputils.RespError(w, r, apierr.Internal(fmt.Errorf("db error: %w", err)))
+		return
+	}
+
+	uh.Tmpl.Render(w, r, "forgot.html", map[string]interface{}{
+		"Sent": true,
+	})
+}
+
+// userByToken проверяет подпись токена против текущих данных пользователя
+func (uh *UserHandler) userByToken(ctx context.Context, purpose, tok string, binding func(*User) string) (*User, error) {
+	userID, err := uh.Actions.UserID(tok)
+	if err != nil {
+		return nil, err
+	}
+	u, err := uh.UsersRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	err = uh.Actions.Check(purpose, tok, binding(u))
+	if err != nil {
+		return nil, err
+	}
+	return u, nil
+}
+
+func resetBinding(u *User) string {
+	return strconv.Itoa(int(u.Ver))
+}
+
+func verifyBinding(u *User) string {
+	return u.Email
+}
+
+// ResetPassword - переход по ссылке из письма, старый пароль не нужен
+// после сброса все сессии пользователя закрываются, войти надо заново
+func (uh *UserHandler) ResetPassword(w http.ResponseWriter, r *http.Request) {
+	tok := r.FormValue("token")
+	u, err := uh.userByToken(r.Context(), token.PurposeResetPassword, tok, resetBinding)
+	if token.IsErrBadToken(err) || err == errUserNotFound {
+		httputils.RespError(w, r, apierr.New(apierr.CodeBadRequest, "Link is expired or already used"))
+		return
+	}
+	if err != nil {
+		httputils.RespError(w, r, apierr.Internal(fmt.Errorf("db error: %w", err)))
+		return
+	}
+
+	if r.Method != http.MethodPost {
+		uh.Tmpl.Render(w, r, "reset.html", map[string]interface{}{
+			"Token": tok,
+		})
+		return
+	}
+
+	if r.FormValue("pass1") == "" || r.FormValue("pass1") != r.FormValue("pass2") {
+		httputils.RespError(w, r, apierr.New(apierr.CodeBadRequest, "New password mistmatch"))
+		return
+	}
+
+	err = uh.UsersRepo.UpdatePassword(r.Context(), u.ID, r.FormValue("pass1"))
+	if err != nil {
+		httputils.RespError(w, r, apierr.Internal(fmt.Errorf("update password: %w", err)))
+		return
+	}
+	u.Ver++
+
+	uh.Sessions.DestroyAll(r.Context(), w, u)
+	http.Redirect(w, r, "/user/login", http.StatusFound)
+}
+
+// VerifyEmail - переход по ссылке подтверждения из письма
+func (uh *UserHandler) VerifyEmail(w http.ResponseWriter, r *http.Request) {
+	u, err := uh.userByToken(r.Context(), token.PurposeVerifyEmail, r.FormValue("token"), verifyBinding)
+	if token.IsErrBadToken(err) || err == errUserNotFound {
+		httputils.RespError(w, r, apierr.New(apierr.CodeBadRequest, "Link is expired or invalid"))
+		return
+	}
+	if err == nil {
+		err = uh.UsersRepo.SetEmailVerified(r.Context(), u.ID, u.Email)
+	}
+	if err != nil {
+		httputils.RespError(w, r, apierr.Internal(fmt.Errorf("db error: %w", err)))
+		return
+	}
+	http.Redirect(w, r, "/photos/", http.StatusFound)
+}
+
+// VerifyEmailAPI - отправить письмо с подтверждением ещё раз
+func (uh *UserHandler) VerifyEmailAPI(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		httputils.RespError(w, r, apierr.New(apierr.CodeMethodNotAllowed, "bad method"))
+		return
+	}
+	sess, _ := session.SessionFromContext(r.Context())
+	u, err := uh.UsersRepo.GetByID(r.Context(), sess.UserID)
+	if err != nil {
+		httputils.RespError(w, r, apierr.Internal(fmt.Errorf("db error: %w", err)))
+		return
+	}
+	err = uh.sendVerifyEmail(r.Context(), u)
+	if err != nil {
+		httputils.RespError(w, r, apierr.Wrap(apierr.CodeInternal, "cant send email", err))
+		return
+	}
+	httputils.RespJSON(w, map[string]interface{}{
+		"email": u.Email,
+	})
+}
@@ -0,0 +1,210 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"regexp"
+	"strings"
+	"testing"
+
+	sqlmock "gopkg.in/DATA-DOG/go-sqlmock.v1"
+
+	"photolist/pkg/mailer"
+	"photolist/pkg/mailer/mailtest"
+	"photolist/pkg/token"
+)
+
+type fakeTmpl struct {
+	rendered string
+	data     map[string]interface{}
+}
+
+func (ft *fakeTmpl) Render(w http.ResponseWriter, r *http.Request, name string, data map[string]interface{}) {
+	ft.rendered = name
+	ft.data = data
+}
+
+var userColumns = []string{"id", "login", "email", "ver", "display_name", "bio", "avatar"}
+
+func postForm(path string, form url.Values) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	return req
+}
+
+// TestResetPassword - письмо уходит через настоящий smtp, ссылка из него меняет пароль,
+// а второй раз уже не срабатывает, потому что ver вырос
+func TestResetPassword(t *testing.T) {
+	srv, err := mailtest.NewServer()
+	if err != nil {
+		t.Fatalf("cant start smtp: %v", err)
+	}
+	defer srv.Close()
+
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("cant create mock: %s", err)
+	}
+	defer db.Close()
+
+	sm := &fakeSessions{}
+	tmpl := &fakeTmpl{}
+	uh := &UserHandler{
+		Tmpl:      tmpl,
+		Sessions:  sm,
+		UsersRepo: NewUsersRepository(db),
+		Mailer:    &mailer.SMTPMailer{Addr: srv.Addr, From: "noreply@photolist.test"},
+		Actions:   token.NewActionTokens("secret"),
+		BaseURL:   "http://photolist.test",
+	}
+
+	// неизвестный email - ответ тот же, письма нет
+	mock.ExpectQuery(`SELECT id, login, email, ver, display_name, bio, avatar FROM users WHERE email = \? AND email_verified = 1`).
+		WithArgs("nobody@example.com").
+		WillReturnRows(sqlmock.NewRows(userColumns))
+	w := httptest.NewRecorder()
+	uh.ForgotPassword(w, postForm("/user/forgot", url.Values{"email": {"nobody@example.com"}}))
+	if w.Code != http.StatusOK || tmpl.rendered != "forgot.html" || tmpl.data["Sent"] != true {
+		t.Errorf("[unknown email] bad response %d %s %v", w.Code, tmpl.rendered, tmpl.data)
+	}
+	if len(srv.Messages()) != 0 {
+		t.Errorf("[unknown email] expected no mail")
+	}
+
+	mock.ExpectQuery(`SELECT id, login, email, ver, display_name, bio, avatar FROM users WHERE email = \? AND email_verified = 1`).
+		WithArgs("user@example.com").
+		WillReturnRows(sqlmock.NewRows(userColumns).AddRow(3, "user", "user@example.com", 2, "", "", ""))
+	w = httptest.NewRecorder()
+	uh.ForgotPassword(w, postForm("/user/forgot", url.Values{"email": {"user@example.com"}}))
+	if w.Code != http.StatusOK {
+		t.Fatalf("[forgot] bad status %d", w.Code)
+	}
+	msgs := srv.Messages()
+	if len(msgs) != 1 || msgs[0].To[0] != "user@example.com" {
+		t.Fatalf("[forgot] expected mail to user, got %+v", msgs)
+	}
+	link := regexp.MustCompile(`http://photolist\.test/user/reset\?token=(\S+)`).FindStringSubmatch(msgs[0].Data)
+	if link == nil {
+		t.Fatalf("[forgot] no reset link in mail:\n%s", msgs[0].Data)
+	}
+	resetToken, _ := url.QueryUnescape(link[1])
+
+	byID := `SELECT id, login, email, ver, display_name, bio, avatar FROM users WHERE id = \?`
+
+	mock.ExpectQuery(byID).WithArgs(3).
+		WillReturnRows(sqlmock.NewRows(userColumns).AddRow(3, "user", "user@example.com", 2, "", "", ""))
+	w = httptest.NewRecorder()
+	uh.ResetPassword(w, postForm("/user/reset", url.Values{"token": {resetToken}, "pass1": {"new"}, "pass2": {"other"}}))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("[mismatch] expected 400, got %d", w.Code)
+	}
+
+	mock.ExpectQuery(byID).WithArgs(3).
+		WillReturnRows(sqlmock.NewRows(userColumns).AddRow(3, "user", "user@example.com", 2, "", "", ""))
+	mock.ExpectExec(`UPDATE users SET password = \?, ver = ver \+ 1 WHERE id = \?`).
+		WithArgs(sqlmock.AnyArg(), 3).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	w = httptest.NewRecorder()
+	uh.ResetPassword(w, postForm("/user/reset", url.Values{"token": {resetToken}, "pass1": {"new"}, "pass2": {"new"}}))
+	if w.Code != http.StatusFound || w.Header().Get("Location") != "/user/login" {
+		t.Errorf("[reset] expected redirect to login, got %d %s", w.Code, w.Header().Get("Location"))
+	}
+	if len(sm.destroyed) != 1 || sm.destroyed[0] != 3 {
+		t.Errorf("[reset] expected sessions of user 3 destroyed, got %v", sm.destroyed)
+	}
+
+	// ver уже 3 - ссылка больше не работает
+	mock.ExpectQuery(byID).WithArgs(3).
+		WillReturnRows(sqlmock.NewRows(userColumns).AddRow(3, "user", "user@example.com", 3, "", "", ""))
+	w = httptest.NewRecorder()
+	uh.ResetPassword(w, postForm("/user/reset", url.Values{"token": {resetToken}, "pass1": {"again"}, "pass2": {"again"}}))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("[reuse] expected 400, got %d", w.Code)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+type failMailer struct{}
+
+func (failMailer) Send(ctx context.Context, msg *mailer.Message) error {
+	return errors.New("smtp is down")
+}
+
+// TestForgotPasswordMailError - ошибка отправки не должна отличать существующий адрес от несуществующего
+func TestForgotPasswordMailError(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("cant create mock: %s", err)
+	}
+	defer db.Close()
+
+	tmpl := &fakeTmpl{}
+	uh := &UserHandler{
+		Tmpl:      tmpl,
+		UsersRepo: NewUsersRepository(db),
+		Mailer:    failMailer{},
+		Actions:   token.NewActionTokens("secret"),
+		BaseURL:   "http://photolist.test",
+	}
+
+	mock.ExpectQuery(`SELECT id, login, email, ver, display_name, bio, avatar FROM users WHERE email = \? AND email_verified = 1`).
+		WithArgs("user@example.com").
+		WillReturnRows(sqlmock.NewRows(userColumns).AddRow(3, "user", "user@example.com", 2, "", "", ""))
+	w := httptest.NewRecorder()
+	uh.ForgotPassword(w, postForm("/user/forgot", url.Values{"email": {"user@example.com"}}))
+	if w.Code != http.StatusOK || tmpl.rendered != "forgot.html" || tmpl.data["Sent"] != true {
+		t.Errorf("[mail error] bad response %d %s %v", w.Code, tmpl.rendered, tmpl.data)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestVerifyEmail(t *testing.T) {
+	actions := token.NewActionTokens("secret")
+	valid := actions.Create(token.PurposeVerifyEmail, 3, "user@example.com", verifyTokenTTL)
+
+	cases := []struct {
+		name   string
+		token  string
+		email  string // текущий email пользователя
+		status int
+	}{
+		{"ok", valid, "user@example.com", http.StatusFound},
+		{"email changed", valid, "new@example.com", http.StatusBadRequest},
+		{"garbage", "3.1.xxx", "user@example.com", http.StatusBadRequest},
+	}
+	for _, c := range cases {
+		db, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("cant create mock: %s", err)
+		}
+		mock.ExpectQuery(`SELECT id, login, email, ver, display_name, bio, avatar FROM users WHERE id = \?`).
+			WillReturnRows(sqlmock.NewRows(userColumns).AddRow(3, "user", c.email, 0, "", "", ""))
+		if c.status == http.StatusFound {
+			mock.ExpectExec(`UPDATE users SET email_verified = 1 WHERE id = \? AND email = \?`).
+				WithArgs(3, c.email).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+		}
+
+		uh := &UserHandler{
+			UsersRepo: NewUsersRepository(db),
+			Actions:   actions,
+		}
+		w := httptest.NewRecorder()
+		uh.VerifyEmail(w, httptest.NewRequest(http.MethodGet, "/user/verify_email?token="+url.QueryEscape(c.token), nil))
+		if w.Code != c.status {
+			t.Errorf("[%s] expected status %d, got %d", c.name, c.status, w.Code)
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("[%s] there were unfulfilled expectations: %s", c.name, err)
+		}
+		db.Close()
+	}
+}
@@ -1,34 +1,50 @@
 package user
 
 import (
-	"bytes"
+	"context"
 	"crypto/rand"
 	"database/sql"
 	"errors"
 	"fmt"
-	"log"
 	"strings"
+	"unicode/utf8"
 
-	"golang.org/x/crypto/argon2"
+	"photolist/pkg/logging"
+	"photolist/pkg/utils/dbutils"
+	"photolist/pkg/utils/pagination"
+
+	"go.uber.org/zap"
 )
 
 var (
 	errUserNotFound = errors.New("No user record found")
 	errBadPass      = errors.New("Bad password")
 	errUserExists   = errors.New("User Exists")
+
+	errDisplayNameTooLong = errors.New("Display name too long")
+	errBioTooLong         = errors.New("Bio too long")
+)
+
+const (
+	MaxDisplayNameLen = 64
+	MaxBioLen         = 1000
 )
 
 type UserRepository struct {
 	db *sql.DB
+	// по умолчанию DefaultPasswordConfig, из конфига ставится в main
+	Passwords *PasswordHasher
 }
 
 func NewUsersRepository(db *sql.DB) *UserRepository {
+	passwords, _ := NewPasswordHasher(DefaultPasswordConfig())
 	return &UserRepository{
-		db: db,
+		db:        db,
+		Passwords: passwords,
 	}
 }
 
-func (repo *UserRepository) LookupByIDs(currUserID uint32, ids []uint32) ([]*User, []error) {
+func (repo *UserRepository) LookupByIDs(ctx context.Context, currUserID uint32, ids []uint32) ([]*User, []error) {
 	// fortunately for me - almost direct copy-paste from https://gqlgen.com/reference/dataloaders/
 
 	placeholders := make([]string, len(ids))
@@ -39,12 +55,12 @@ func (repo *UserRepository) LookupByIDs(currUserID uint32, ids []uint32) ([]*Use
 		args = append(args, ids[i])
 	}
 
-	q := `SELECT id, login, user_follows.follow_id FROM users 
+	q := `SELECT id, login, display_name, bio, avatar, user_follows.follow_id FROM users 
 	LEFT JOIN user_follows ON user_follows.follow_id=users.id and user_follows.user_id = ?
 	WHERE users.id IN (` + strings.Join(placeholders, ",") + ")"
-	res, err := repo.db.Query(q, args...)
+	res, err := repo.db.QueryContext(ctx, q, args...)
 	if err != nil {
-		log.Println("LookupByIDs query err:", err)
+		logging.FromContext(ctx).Error("LookupByIDs query err", zap.Error(err))
 		return nil, []error{err}
 	}
 	defer res.Close()
@@ -53,7 +69,7 @@ func (repo *UserRepository) LookupByIDs(currUserID uint32, ids []uint32) ([]*Use
 	for res.Next() {
 		user := &User{}
 		var isFollowed sql.NullInt64
-		err := res.Scan(&user.ID, &user.Login, &isFollowed)
+		err := res.Scan(&user.ID, &user.Login, &user.DisplayName, &user.Bio, &user.AvatarName, &isFollowed)
 		if err != nil {
 			return nil, []error{err}
 		}
@@ -68,9 +84,11 @@ func (repo *UserRepository) LookupByIDs(currUserID uint32, ids []uint32) ([]*Use
 	return output, nil
 }
 
-func (repo *UserRepository) Create(login, email, passIn string) (*User, error) {
-	salt := makeSalt(8)
-	pass := repo.hashPass(passIn, salt)
+func (repo *UserRepository) Create(ctx context.Context, login, email, passIn string) (*User, error) {
+	pass, err := repo.Passwords.Hash(passIn)
+	if err != nil {
+		return nil, err
+	}
 
 	user := &User{
 		ID:    0,
@@ -78,7 +96,7 @@ func (repo *UserRepository) Create(login, email, passIn string) (*User, error) {
 		Email: email,
 	}
 
-	err := repo.db.QueryRow("SELECT id, ver, login FROM users WHERE email = ? OR login = ?", email, login).
+	err = repo.db.QueryRowContext(ctx, "SELECT id, ver, login FROM users WHERE email = ? OR login = ?", email, login).
 		Scan(&user.ID, &user.Ver, &user.Login)
 	if err != nil && err != sql.ErrNoRows {
 		return nil, fmt.Errorf("db error: %v", err)
@@ -87,7 +105,7 @@ func (repo *UserRepository) Create(login, email, passIn string) (*User, error) {
 		return user, errUserExists
 	}
 
-	result, err := repo.db.Exec("INSERT INTO users(login, email, password) VALUES(?, ?, ?)", login, email, pass)
+	result, err := repo.db.ExecContext(ctx, "INSERT INTO users(login, email, password) VALUES(?, ?, ?)", login, email, pass)
 	if err != nil {
 		return nil, fmt.Errorf("insert error: %v", err)
 	}
@@ -101,11 +119,12 @@ func (repo *UserRepository) Create(login, email, passIn string) (*User, error) {
 		return nil, fmt.Errorf("LastInsertId err: %v", err)
 	}
 	user.ID = uint32(uid)
+	user.Login = login
 
 	return user, nil
 }
 
-func (repo *UserRepository) passwordIsValid(pass string, row *sql.Row) (*User, error) {
+func (repo *UserRepository) passwordIsValid(ctx context.Context, pass string, row *sql.Row) (*User, error) {
 	var (
 		dbPass []byte
 		user   = &User{}
@@ -117,56 +136,83 @@ func (repo *UserRepository) passwordIsValid(pass string, row *sql.Row) (*User, e
 		return nil, err
 	}
 
-	salt := dbPass[0:8]
-	if !bytes.Equal(repo.hashPass(pass, salt), dbPass) {
+	ok, rehash := repo.Passwords.Verify(dbPass, pass)
+	if !ok {
 		return nil, errBadPass
 	}
+	if rehash {
+		repo.rehashPassword(ctx, user.ID, dbPass, pass)
+	}
 	return user, nil
 }
 
-func (repo *UserRepository) GetByLogin(login string) (*User, error) {
-	row := repo.db.QueryRow("SELECT id, login, email, ver FROM users WHERE login = ?", login)
+// rehashPassword - хеш со старыми параметрами меняем на новый, пока пароль в руках
+// ver не трогаем - пароль тот же, и только если хеш с тех пор не поменяли
+// ошибка не мешает входу, пересчитаем в следующий раз
+func (repo *UserRepository) rehashPassword(ctx context.Context, userID uint32, oldHash []byte, pass string) {
+	newHash, err := repo.Passwords.Hash(pass)
+	if err != nil {
+		logging.FromContext(ctx).Error("rehash password err", zap.Error(err))
+		return
+	}
+	_, err = repo.db.ExecContext(ctx, "UPDATE users SET password = ? WHERE id = ? AND password = ?", newHash, userID, oldHash)
+	if err != nil {
+		logging.FromContext(ctx).Error("rehash password err", zap.Error(err))
+	}
+}
+
+func (repo *UserRepository) GetByLogin(ctx context.Context, login string) (*User, error) {
+	row := repo.db.QueryRowContext(ctx, "SELECT id, login, email, ver, display_name, bio, avatar FROM users WHERE login = ?", login)
 	return parseRowToUser(row)
 }
 
-func (repo *UserRepository) GetByID(id uint32) (*User, error) {
-	row := repo.db.QueryRow("SELECT id, login, email, ver FROM users WHERE id = ?", id)
+// GetByVerifiedEmail ищет только по подтверждённому email - на неподтверждённый адрес ссылки не шлём
+func (repo *UserRepository) GetByVerifiedEmail(ctx context.Context, email string) (*User, error) {
+	row := repo.db.QueryRowContext(ctx, "SELECT id, login, email, ver, display_name, bio, avatar FROM users WHERE email = ? AND email_verified = 1", email)
 	return parseRowToUser(row)
 }
 
-func (repo *UserRepository) CheckPasswordByUserID(uid uint32, pass string) (*User, error) {
-	row := repo.db.QueryRow("SELECT id, login, ver, password FROM users WHERE id = ?", uid)
-	return repo.passwordIsValid(pass, row)
+func (repo *UserRepository) GetByID(ctx context.Context, id uint32) (*User, error) {
+	row := repo.db.QueryRowContext(ctx, "SELECT id, login, email, ver, display_name, bio, avatar FROM users WHERE id = ?", id)
+	return parseRowToUser(row)
 }
 
-func (repo *UserRepository) CheckPasswordByLogin(login, pass string) (*User, error) {
-	row := repo.db.QueryRow("SELECT id, login, ver, password FROM users WHERE login = ?", login)
-	return repo.passwordIsValid(pass, row)
+func (repo *UserRepository) CheckPasswordByUserID(ctx context.Context, uid uint32, pass string) (*User, error) {
+	row := repo.db.QueryRowContext(ctx, "SELECT id, login, ver, password FROM users WHERE id = ?", uid)
+	return repo.passwordIsValid(ctx, pass, row)
 }
 
-func (repo *UserRepository) UpdatePassword(userID uint32, pass string) error {
-	salt := makeSalt(8)
-	passHash := repo.hashPass(pass, salt)
-	_, err := repo.db.Exec("UPDATE users SET password = ?, ver = ver + 1 WHERE id = ?",
+func (repo *UserRepository) CheckPasswordByLogin(ctx context.Context, login, pass string) (*User, error) {
+	row := repo.db.QueryRowContext(ctx, "SELECT id, login, ver, password FROM users WHERE login = ?", login)
+	return repo.passwordIsValid(ctx, pass, row)
+}
+
+// UpdatePassword меняет пароль и увеличивает ver - все сессии пользователя становятся недействительными
+func (repo *UserRepository) UpdatePassword(ctx context.Context, userID uint32, pass string) error {
+	passHash, err := repo.Passwords.Hash(pass)
+	if err != nil {
+		return err
+	}
+	_, err = repo.db.ExecContext(ctx, "UPDATE users SET password = ?, ver = ver + 1 WHERE id = ?",
 		passHash, userID)
 	return err
 }
 
-func (repo *UserRepository) hashPass(plainPassword string, salt []byte) []byte {
-	hashedPass := argon2.IDKey([]byte(plainPassword), salt, 1, 64*1024, 4, 32)
-	res := []byte(salt)
-	return append(res, hashedPass...)
+// SetEmailVerified отмечает email подтверждённым, если он с тех пор не поменялся
+func (repo *UserRepository) SetEmailVerified(ctx context.Context, userID uint32, email string) error {
+	_, err := repo.db.ExecContext(ctx, "UPDATE users SET email_verified = 1 WHERE id = ? AND email = ?", userID, email)
+	return err
 }
 
 func makeSalt(n int) []byte {
-	salt := make([]byte, 8)
+	salt := make([]byte, n)
 	rand.Read(salt)
 	return salt
 }
 
 func parseRowToUser(row *sql.Row) (*User, error) {
 	user := &User{}
-	err := row.Scan(&user.ID, &user.Login, &user.Email, &user.Ver)
+	err := row.Scan(&user.ID, &user.Login, &user.Email, &user.Ver, &user.DisplayName, &user.Bio, &user.AvatarName)
 	if err == sql.ErrNoRows {
 		return nil, errUserNotFound
 	} else if err != nil {
@@ -175,87 +221,184 @@ func parseRowToUser(row *sql.Row) (*User, error) {
 	return user, nil
 }
 
-func (repo *UserRepository) Follow(userID uint32, currentUserID uint32, rate int) error {
-	var res sql.Result
-	var err error
-	if rate == 1 {
-		res, err = repo.db.Exec(`INSERT IGNORE INTO user_follows(follow_id, user_id) VALUES(?, ?)`, userID, currentUserID)
-	} else {
-		res, err = repo.db.Exec(`DELETE FROM user_follows WHERE follow_id = ? AND user_id = ?`, userID, currentUserID)
+// UpdateProfile меняет отображаемое имя и описание
+func (repo *UserRepository) UpdateProfile(ctx context.Context, userID uint32, displayName, bio string) error {
+	if utf8.RuneCountInString(displayName) > MaxDisplayNameLen {
+		return errDisplayNameTooLong
+	}
+	if utf8.RuneCountInString(bio) > MaxBioLen {
+		return errBioTooLong
 	}
+	res, err := repo.db.ExecContext(ctx, "UPDATE users SET display_name = ?, bio = ? WHERE id = ?",
+		displayName, bio, userID)
 	if err != nil {
 		return err
 	}
-	aff, _ := res.RowsAffected()
-	// dont update rating twice
-	if aff <= 0 {
-		return nil
+	// mysql не считает строку изменённой, если значения те же, поэтому проверяем отдельно
+	if aff, _ := res.RowsAffected(); aff == 0 {
+		_, err = repo.GetByID(ctx, userID)
+		return err
 	}
-	_, err = repo.db.Exec("UPDATE users SET followers_cnt = followers_cnt + ? WHERE id = ?", rate, userID)
+	return nil
+}
+
+// SetAvatar сохраняет новую аватарку и возвращает имя старой, чтобы её можно было удалить из хранилища
+func (repo *UserRepository) SetAvatar(ctx context.Context, userID uint32, name, format string) (string, error) {
+	tx, err := repo.db.BeginTx(ctx, nil)
 	if err != nil {
-		return err
+		return "", err
 	}
-	_, err = repo.db.Exec("UPDATE users SET following_cnt = following_cnt + ? WHERE id = ?", rate, currentUserID)
+	defer tx.Rollback()
+
+	var old string
+	err = tx.QueryRowContext(ctx, "SELECT avatar FROM users WHERE id = ? FOR UPDATE", userID).Scan(&old)
+	if err == sql.ErrNoRows {
+		return "", errUserNotFound
+	} else if err != nil {
+		return "", err
+	}
+	_, err = tx.ExecContext(ctx, "UPDATE users SET avatar = ?, avatar_format = ? WHERE id = ?", name, format, userID)
 	if err != nil {
-		return err
+		return "", err
 	}
+	return old, tx.Commit()
+}
 
-	return nil
+// GetAvatar ищет владельца аватарки по имени в хранилище - нужно для отдачи через /img/
+func (repo *UserRepository) GetAvatar(ctx context.Context, name string) (uint32, string, error) {
+	var (
+		userID uint32
+		format string
+	)
+	err := repo.db.QueryRowContext(ctx, "SELECT id, avatar_format FROM users WHERE avatar = ?", name).
+		Scan(&userID, &format)
+	if err == sql.ErrNoRows {
+		return 0, "", errUserNotFound
+	}
+	return userID, format, err
 }
 
-func (repo *UserRepository) IsFollowed(userID, currUserID uint32) (bool, error) {
-	log.Println("call UserRepository.IsFollowed - maybe user dataloader?", userID, currUserID)
+// Follow подписывает (rate > 0) или отписывает currentUserID от userID
+// счётчики обоих пользователей меняются в той же транзакции, что и user_follows
+func (repo *UserRepository) Follow(ctx context.Context, userID uint32, currentUserID uint32, rate int) error {
+	return dbutils.InTx(ctx, repo.db, func(tx *sql.Tx) error {
+		var res sql.Result
+		var err error
+		delta := 1
+		if rate == 1 {
+			res, err = tx.ExecContext(ctx, `INSERT INTO user_follows(follow_id, user_id) VALUES(?, ?)`, userID, currentUserID)
+			if dbutils.IsDuplicate(err) {
+				return nil
+			}
+		} else {
+			delta = -1
+			res, err = tx.ExecContext(ctx, `DELETE FROM user_follows WHERE follow_id = ? AND user_id = ?`, userID, currentUserID)
+		}
+		if err != nil {
+			return err
+		}
+		aff, _ := res.RowsAffected()
+		// dont update rating twice
+		if aff <= 0 {
+			return nil
+		}
+
+		// строки users всегда блокируем в порядке id, иначе встречные подписки могут словить дедлок
+		updates := []struct {
+			id uint32
+			q  string
+		}{
+			{userID, "UPDATE users SET followers_cnt = followers_cnt + ? WHERE id = ?"},
+			{currentUserID, "UPDATE users SET following_cnt = following_cnt + ? WHERE id = ?"},
+		}
+		if currentUserID < userID {
+			updates[0], updates[1] = updates[1], updates[0]
+		}
+		for _, upd := range updates {
+			_, err = tx.ExecContext(ctx, upd.q, delta, upd.id)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// ReconcileFollowCounters сверяет followers_cnt и following_cnt с user_follows, fix - исправить расхождения
+func (repo *UserRepository) ReconcileFollowCounters(ctx context.Context, fix bool) ([]*dbutils.Drift, error) {
+	followers, err := dbutils.ReconcileCounter(ctx, repo.db, dbutils.CounterUserFollowers, fix)
+	if err != nil {
+		return followers, err
+	}
+	following, err := dbutils.ReconcileCounter(ctx, repo.db, dbutils.CounterUserFollowings, fix)
+	return append(followers, following...), err
+}
+
+func (repo *UserRepository) IsFollowed(ctx context.Context, userID, currUserID uint32) (bool, error) {
+	logging.FromContext(ctx).Debug("UserRepository.IsFollowed call - maybe user dataloader?", zap.Uint32("follow_id", userID))
 	q := `SELECT count(*) as cnt FROM user_follows 
 		WHERE user_id = ? AND follow_id = ?`
 	var cnt uint32
-	err := repo.db.QueryRow(q, currUserID, userID).Scan(&cnt)
+	err := repo.db.QueryRowContext(ctx, q, currUserID, userID).Scan(&cnt)
 	return cnt != 0, err
 }
 
-func (repo *UserRepository) GetFollowedUsers(userID uint32) ([]*User, error) {
-	// TODO add limit, offset
-	rows, err := repo.db.Query(`SELECT users.id, users.login 
+// GetFollowedUsers возвращает одну страницу пользователей, на которых подписан userID
+// второй параметр - есть ли ещё страницы
+func (repo *UserRepository) GetFollowedUsers(ctx context.Context, userID uint32, page pagination.Page) ([]*User, bool, error) {
+	q := `SELECT users.id, users.login, users.display_name, users.bio, users.avatar 
 	FROM user_follows 
 	LEFT JOIN users ON users.id = user_follows.follow_id
-	WHERE user_follows.user_id = ?`, userID)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-	result := make([]*User, 0, 10)
-	for rows.Next() {
-		u := &User{}
-		err := rows.Scan(&u.ID, &u.Login)
-		if err != nil {
-			return nil, err
-		}
-		result = append(result, u)
-	}
-	return result, nil
+	WHERE user_follows.user_id = ?`
+	return repo.queryUsersPage(ctx, q, "users.id", []interface{}{userID}, page)
 }
 
-func (repo *UserRepository) GetRecomendedUsers(userID uint32) ([]*User, error) {
-	// TODO add limit, offset
-	rows, err := repo.db.Query(`select users.id, users.login 
+func (repo *UserRepository) GetRecomendedUsers(ctx context.Context, userID uint32, page pagination.Page) ([]*User, bool, error) {
+	q := `select users.id, users.login, users.display_name, users.bio, users.avatar 
 	from users 
 	left join user_follows on users.id = user_follows.follow_id and user_follows.user_id = ?
-	where users.id != ? and user_follows.user_id is null`, userID, userID)
+	where users.id != ? and user_follows.user_id is null`
+	return repo.queryUsersPage(ctx, q, "users.id", []interface{}{userID, userID}, page)
+}
+
+// queryUsersPage дописывает к запросу q условие курсора, сортировку по idField и лимит
+func (repo *UserRepository) queryUsersPage(ctx context.Context, q, idField string, args []interface{}, page pagination.Page) ([]*User, bool, error) {
+	if page.After != nil {
+		q += " AND " + idField + " > ?"
+		args = append(args, page.After.ID)
+	}
+	q += " ORDER BY " + idField + " LIMIT ?"
+	args = append(args, page.FetchLimit())
+
+	rows, err := repo.db.QueryContext(ctx, q, args...)
 	if err != nil {
-		return nil, err
+		return nil, false, err
 	}
 	defer rows.Close()
-	result := make([]*User, 0, 10)
+	result := make([]*User, 0, page.FetchLimit())
 	for rows.Next() {
 		u := &User{}
-		err := rows.Scan(&u.ID, &u.Login)
+		err := rows.Scan(&u.ID, &u.Login, &u.DisplayName, &u.Bio, &u.AvatarName)
 		if err != nil {
-			return nil, err
+			return nil, false, err
 		}
 		result = append(result, u)
 	}
-	return result, nil
+	if err = rows.Err(); err != nil {
+		return nil, false, err
+	}
+
+	hasNext := page.HasNext(len(result))
+	if hasNext {
+		result = result[:page.Limit]
+	}
+	return result, hasNext, nil
 }
 
 func IsErrUserNotFound(err error) bool {
 	return err == errUserNotFound
 }
+
+func IsErrBadProfile(err error) bool {
+	return err == errDisplayNameTooLong || err == errBioTooLong
+}
@@ -0,0 +1,118 @@
+package dbutils
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+// https://dev.mysql.com/doc/mysql-errors/8.0/en/server-error-reference.html#error_er_dup_entry
+const mysqlErrDupEntry = 1062
+
+// IsDuplicate - вставка упёрлась в уникальный ключ
+func IsDuplicate(err error) bool {
+	myErr, ok := err.(*mysql.MySQLError)
+	return ok && myErr.Number == mysqlErrDupEntry
+}
+
+// InTx выполняет fn в транзакции: коммит если fn вернула nil, иначе откат
+func InTx(ctx context.Context, db *sql.DB, fn func(tx *sql.Tx) error) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	err = fn(tx)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// Drift - расхождение счётчика с тем, что лежит в исходной таблице
+type Drift struct {
+	Table  string
+	Column string
+	ID     uint32
+	Stored int64
+	Actual int64
+}
+
+func (d *Drift) String() string {
+	return fmt.Sprintf("%s.%s id=%d: stored %d, actual %d", d.Table, d.Column, d.ID, d.Stored, d.Actual)
+}
+
+// счётчики, которые умеет сверять ReconcileCounter
+// запросы собраны заранее: имена таблиц нельзя передать плейсхолдером, а склеивать их из аргументов небезопасно
+const (
+	CounterPhotoRating    = "photos.rating"
+	CounterUserFollowers  = "users.followers_cnt"
+	CounterUserFollowings = "users.following_cnt"
+)
+
+type counterQueries struct {
+	table, column string
+	find, fix     string
+}
+
+var counters = map[string]counterQueries{
+	CounterPhotoRating: {"photos", "rating",
+		`SELECT t.id, t.rating, COUNT(s.photo_id) FROM photos t 
+		LEFT JOIN user_photos_likes s ON s.photo_id = t.id 
+		GROUP BY t.id, t.rating 
+		HAVING t.rating != COUNT(s.photo_id)`,
+		`UPDATE photos SET rating = (SELECT COUNT(*) FROM user_photos_likes WHERE photo_id = ?) WHERE id = ?`},
+	CounterUserFollowers: {"users", "followers_cnt",
+		`SELECT t.id, t.followers_cnt, COUNT(s.follow_id) FROM users t 
+		LEFT JOIN user_follows s ON s.follow_id = t.id 
+		GROUP BY t.id, t.followers_cnt 
+		HAVING t.followers_cnt != COUNT(s.follow_id)`,
+		`UPDATE users SET followers_cnt = (SELECT COUNT(*) FROM user_follows WHERE follow_id = ?) WHERE id = ?`},
+	CounterUserFollowings: {"users", "following_cnt",
+		`SELECT t.id, t.following_cnt, COUNT(s.user_id) FROM users t 
+		LEFT JOIN user_follows s ON s.user_id = t.id 
+		GROUP BY t.id, t.following_cnt 
+		HAVING t.following_cnt != COUNT(s.user_id)`,
+		`UPDATE users SET following_cnt = (SELECT COUNT(*) FROM user_follows WHERE user_id = ?) WHERE id = ?`},
+}
+
+// ReconcileCounter находит строки, где счётчик counter не совпадает с количеством строк в исходной таблице,
+// и если fix - пересчитывает их. Пересчёт идёт одним UPDATE с подзапросом на каждую строку,
+// поэтому параллельные лайки/подписки между поиском и исправлением не теряются
+func ReconcileCounter(ctx context.Context, db *sql.DB, counter string, fix bool) ([]*Drift, error) {
+	q, ok := counters[counter]
+	if !ok {
+		return nil, fmt.Errorf("unknown counter %q", counter)
+	}
+	rows, err := db.QueryContext(ctx, q.find)
+	if err != nil {
+		return nil, err
+	}
+	drifts := []*Drift{}
+	for rows.Next() {
+		d := &Drift{Table: q.table, Column: q.column}
+		err = rows.Scan(&d.ID, &d.Stored, &d.Actual)
+		if err != nil {
+			rows.Close()
+			return nil, err
+		}
+		drifts = append(drifts, d)
+	}
+	rows.Close()
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	if !fix {
+		return drifts, nil
+	}
+
+	for _, d := range drifts {
+		_, err = db.ExecContext(ctx, q.fix, d.ID, d.ID)
+		if err != nil {
+			return drifts, fmt.Errorf("cant fix %s: %w", d, err)
+		}
+	}
+	return drifts, nil
+}
@@ -0,0 +1,73 @@
+package dbutils
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	sqlmock "gopkg.in/DATA-DOG/go-sqlmock.v1"
+)
+
+func TestReconcileCounter(t *testing.T) {
+	driftRows := func() *sqlmock.Rows {
+		return sqlmock.NewRows([]string{"id", "rating", "cnt"}).
+			AddRow(1, 5, 3).
+			AddRow(7, 0, 1)
+	}
+	cases := []struct {
+		name    string
+		counter string
+		fix     bool
+		prepare func(mock sqlmock.Sqlmock)
+		drifts  int
+		ok      bool
+	}{
+		{"dry run", CounterPhotoRating, false, func(mock sqlmock.Sqlmock) {
+			mock.ExpectQuery(`SELECT t.id, t.rating, COUNT\(s.photo_id\) FROM photos t`).WillReturnRows(driftRows())
+		}, 2, true},
+		{"fix", CounterPhotoRating, true, func(mock sqlmock.Sqlmock) {
+			mock.ExpectQuery(`FROM photos t`).WillReturnRows(driftRows())
+			mock.ExpectExec(`UPDATE photos SET rating = \(SELECT COUNT\(\*\) FROM user_photos_likes WHERE photo_id = \?\) WHERE id = \?`).
+				WithArgs(1, 1).WillReturnResult(sqlmock.NewResult(0, 1))
+			mock.ExpectExec(`UPDATE photos SET rating`).WithArgs(7, 7).WillReturnResult(sqlmock.NewResult(0, 1))
+		}, 2, true},
+		{"followers", CounterUserFollowers, true, func(mock sqlmock.Sqlmock) {
+			mock.ExpectQuery(`LEFT JOIN user_follows s ON s.follow_id = t.id`).
+				WillReturnRows(sqlmock.NewRows([]string{"id", "followers_cnt", "cnt"}).AddRow(5, 2, 1))
+			mock.ExpectExec(`UPDATE users SET followers_cnt = \(SELECT COUNT\(\*\) FROM user_follows WHERE follow_id = \?\)`).
+				WithArgs(5, 5).WillReturnResult(sqlmock.NewResult(0, 1))
+		}, 1, true},
+		{"following", CounterUserFollowings, false, func(mock sqlmock.Sqlmock) {
+			mock.ExpectQuery(`LEFT JOIN user_follows s ON s.user_id = t.id`).
+				WillReturnRows(sqlmock.NewRows([]string{"id", "following_cnt", "cnt"}))
+		}, 0, true},
+		// имя таблицы из аргумента в запрос не попадает
+		{"unknown counter", "users; DROP TABLE users", true, func(mock sqlmock.Sqlmock) {}, 0, false},
+		{"query error", CounterPhotoRating, false, func(mock sqlmock.Sqlmock) {
+			mock.ExpectQuery(`FROM photos t`).WillReturnError(fmt.Errorf("bad connection"))
+		}, 0, false},
+		{"fix error", CounterPhotoRating, true, func(mock sqlmock.Sqlmock) {
+			mock.ExpectQuery(`FROM photos t`).WillReturnRows(driftRows())
+			mock.ExpectExec(`UPDATE photos SET rating`).WithArgs(1, 1).WillReturnError(fmt.Errorf("lock wait timeout"))
+		}, 2, false},
+	}
+	for _, c := range cases {
+		db, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("cant create mock: %s", err)
+		}
+		c.prepare(mock)
+
+		drifts, err := ReconcileCounter(context.Background(), db, c.counter, c.fix)
+		if c.ok != (err == nil) {
+			t.Errorf("[%s] unexpected err: %v", c.name, err)
+		}
+		if len(drifts) != c.drifts {
+			t.Errorf("[%s] expected %d drifts, got %d", c.name, c.drifts, len(drifts))
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("[%s] there were unfulfilled expectations: %s", c.name, err)
+		}
+		db.Close()
+	}
+}
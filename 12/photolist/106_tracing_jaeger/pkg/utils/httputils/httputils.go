@@ -2,31 +2,141 @@ package httputils
 
 import (
 	"encoding/json"
-	"log"
+	"fmt"
+	"net"
 	"net/http"
+	"strconv"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"photolist/pkg/apierr"
+	"photolist/pkg/logging"
 )
 
+// ErrorBody - ошибка в ответе, одна и та же у всех REST-ручек
+type ErrorBody struct {
+	Code      apierr.Code `json:"code"`
+	Message   string      `json:"message"`
+	RequestID string      `json:"request_id,omitempty"`
+}
+
 type MyResponse struct {
 	Body  interface{} `json:"body,omitempty"`
-	Error string      `json:"error,omitempty"`
+	Error *ErrorBody  `json:"error,omitempty"`
 }
 
 func RespJSON(w http.ResponseWriter, body interface{}) {
-	w.Header().Add("Content-Type", "application/json")
-	respJSON, _ := json.Marshal(&MyResponse{
+	writeJSON(w, http.StatusOK, &MyResponse{
 		Body: body,
 	})
-	w.Write(respJSON)
 }
 
-func RespJSONError(w http.ResponseWriter, status int, err error, resp string) {
-	if err != nil {
-		log.Println(err)
+// RespError - ответ с ошибкой: в api json-конверт, для страниц просто текст
+// клиент видит только безопасное сообщение, причина уходит в лог запроса
+func RespError(w http.ResponseWriter, r *http.Request, err error) {
+	e := apierr.From(err)
+	status := e.Code.HTTPStatus()
+	if e.Cause != nil {
+		logger := logging.FromContext(r.Context())
+		if status >= http.StatusInternalServerError {
+			logger.Error(e.Message, zap.String("code", string(e.Code)), zap.Error(e.Cause))
+		} else {
+			logger.Warn(e.Message, zap.String("code", string(e.Code)), zap.Error(e.Cause))
+		}
 	}
-	w.WriteHeader(status)
-	w.Header().Add("Content-Type", "application/json")
-	respJSON, _ := json.Marshal(&MyResponse{
-		Error: resp,
+
+	if !IsAPI(r) {
+		http.Error(w, e.Message, status)
+		return
+	}
+	writeJSON(w, status, &MyResponse{
+		Error: &ErrorBody{
+			Code:    e.Code,
+			Message: e.Message,
+			// RequestIDMiddleware кладёт его в заголовок запроса, если клиент не прислал свой
+			RequestID: r.Header.Get("X-Request-ID"),
+		},
 	})
+}
+
+// IsAPI - запрос от js или другого клиента, а не страница в браузере
+func IsAPI(r *http.Request) bool {
+	return strings.HasPrefix(r.URL.Path, "/api/") || strings.HasPrefix(r.URL.Path, "/graphql")
+}
+
+// заголовки надо ставить до WriteHeader, после него они уже ушли
+func writeJSON(w http.ResponseWriter, status int, resp *MyResponse) {
+	respJSON, _ := json.Marshal(resp)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
 	w.Write(respJSON)
 }
+
+// прокси, которым верим X-Real-IP и X-Forwarded-For, задаются один раз при старте
+var trustedProxies []*net.IPNet
+
+// SetTrustedProxies - ip или подсети (cidr) прокси перед photolist
+// пустой список - заголовкам не верим вовсе, иначе клиент сам пишет себе любой адрес
+func SetTrustedProxies(proxies []string) error {
+	nets := make([]*net.IPNet, 0, len(proxies))
+	for _, p := range proxies {
+		if !strings.Contains(p, "/") {
+			ip := net.ParseIP(p)
+			if ip == nil {
+				return fmt.Errorf("bad trusted proxy %q", p)
+			}
+			bits := 8 * len(ip.To4())
+			if bits == 0 {
+				bits = 8 * net.IPv6len
+			}
+			p += "/" + strconv.Itoa(bits)
+		}
+		_, ipNet, err := net.ParseCIDR(p)
+		if err != nil {
+			return fmt.Errorf("bad trusted proxy %q: %v", p, err)
+		}
+		nets = append(nets, ipNet)
+	}
+	trustedProxies = nets
+	return nil
+}
+
+func isTrustedProxy(addr string) bool {
+	ip := net.ParseIP(strings.TrimSpace(addr))
+	if ip == nil {
+		return false
+	}
+	for _, n := range trustedProxies {
+		if n.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// ClientIP - адрес клиента
+// заголовки прокси смотрим, только если запрос пришёл от доверенного прокси (nginx ставит X-Real-IP)
+// в X-Forwarded-For берём последний адрес, который дописал не наш прокси - левее клиент пишет что хочет
+func ClientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	if !isTrustedProxy(host) {
+		return host
+	}
+	if ip := strings.TrimSpace(r.Header.Get("X-Real-IP")); ip != "" {
+		return ip
+	}
+	if xff := r.Header.Get("X-Forwarded-For"); xff != "" {
+		hops := strings.Split(xff, ",")
+		for i := len(hops) - 1; i >= 0; i-- {
+			ip := strings.TrimSpace(hops[i])
+			if ip != "" && (i == 0 || !isTrustedProxy(ip)) {
+				return ip
+			}
+		}
+	}
+	return host
+}
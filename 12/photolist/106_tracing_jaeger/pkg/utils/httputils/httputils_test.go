@@ -0,0 +1,107 @@
+package httputils
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"photolist/pkg/apierr"
+)
+
+func TestRespError(t *testing.T) {
+	cases := []struct {
+		name        string
+		path        string
+		err         error
+		status      int
+		contentType string
+		code        apierr.Code
+		message     string
+	}{
+		{"api not found", "/api/v1/photos/rate", apierr.New(apierr.CodeNotFound, "no photo"),
+			http.StatusNotFound, "application/json", apierr.CodeNotFound, "no photo"},
+		{"api internal", "/api/v1/photos/list", apierr.Internal(fmt.Errorf("db is down")),
+			http.StatusInternalServerError, "application/json", apierr.CodeInternal, "internal error"},
+		{"api untyped", "/api/v1/user/follow", fmt.Errorf("db is down"),
+			http.StatusInternalServerError, "application/json", apierr.CodeInternal, "internal error"},
+		{"graphql slash", "/graphql/", apierr.New(apierr.CodeForbidden, "access denied"),
+			http.StatusForbidden, "application/json", apierr.CodeForbidden, "access denied"},
+		{"page", "/user/reg", apierr.New(apierr.CodeConflict, "Looks like user exists"),
+			http.StatusConflict, "text/plain; charset=utf-8", "", "Looks like user exists"},
+	}
+	for _, c := range cases {
+		req := httptest.NewRequest(http.MethodPost, c.path, nil)
+		req.Header.Set("X-Request-ID", "req1")
+		w := httptest.NewRecorder()
+		RespError(w, req, c.err)
+
+		if w.Code != c.status {
+			t.Errorf("[%s] bad status: %d, expected %d", c.name, w.Code, c.status)
+		}
+		if ct := w.Header().Get("Content-Type"); ct != c.contentType {
+			t.Errorf("[%s] bad content-type: %q, expected %q", c.name, ct, c.contentType)
+		}
+		if strings.Contains(w.Body.String(), "db is down") {
+			t.Errorf("[%s] internal cause in response: %s", c.name, w.Body.String())
+		}
+		if c.code == "" {
+			if strings.TrimSpace(w.Body.String()) != c.message {
+				t.Errorf("[%s] bad body: %s", c.name, w.Body.String())
+			}
+			continue
+		}
+
+		resp := &MyResponse{}
+		if err := json.Unmarshal(w.Body.Bytes(), resp); err != nil || resp.Error == nil {
+			t.Errorf("[%s] bad envelope: %s", c.name, w.Body.String())
+			continue
+		}
+		if resp.Error.Code != c.code || resp.Error.Message != c.message || resp.Error.RequestID != "req1" {
+			t.Errorf("[%s] bad error body: %+v", c.name, resp.Error)
+		}
+	}
+}
+
+func TestClientIP(t *testing.T) {
+	err := SetTrustedProxies([]string{"10.0.0.1", "172.16.0.0/12"})
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	defer SetTrustedProxies(nil)
+
+	cases := []struct {
+		name   string
+		remote string
+		realIP string
+		xff    string
+		ip     string
+	}{
+		{"direct", "1.1.1.1:1234", "", "", "1.1.1.1"},
+		{"spoofed real ip", "1.1.1.1:1234", "2.2.2.2", "", "1.1.1.1"},
+		{"spoofed xff", "1.1.1.1:1234", "", "2.2.2.2", "1.1.1.1"},
+		{"proxy real ip", "10.0.0.1:1234", "2.2.2.2", "", "2.2.2.2"},
+		{"proxy subnet", "172.18.0.5:1234", "2.2.2.2", "", "2.2.2.2"},
+		{"proxy xff", "10.0.0.1:1234", "", "6.6.6.6, 2.2.2.2, 172.18.0.5", "2.2.2.2"},
+		{"proxy no headers", "10.0.0.1:1234", "", "", "10.0.0.1"},
+	}
+	for _, c := range cases {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.RemoteAddr = c.remote
+		if c.realIP != "" {
+			req.Header.Set("X-Real-IP", c.realIP)
+		}
+		if c.xff != "" {
+			req.Header.Set("X-Forwarded-For", c.xff)
+		}
+		if ip := ClientIP(req); ip != c.ip {
+			t.Errorf("[%s] expected %s, got %s", c.name, c.ip, ip)
+		}
+	}
+
+	if err := SetTrustedProxies([]string{"nginx"}); err == nil {
+		t.Errorf("[bad proxy] expected error")
+	}
+}
@@ -0,0 +1,107 @@
+package pagination
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strconv"
+)
+
+const (
+	DefaultLimit = 10
+	MaxLimit     = 100
+)
+
+var (
+	ErrBadCursor = errors.New("bad cursor")
+)
+
+// Cursor - позиция в выборке, клиенту отдаётся в виде непрозрачной строки
+// ID есть всегда, Rating используется только при сортировке по рейтингу
+type Cursor struct {
+	ID     uint32 `json:"id"`
+	Rating int    `json:"r,omitempty"`
+}
+
+func (c *Cursor) Encode() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// Decode пустую строку считает началом выборки и возвращает nil
+func Decode(in string) (*Cursor, error) {
+	if in == "" {
+		return nil, nil
+	}
+	data, err := base64.RawURLEncoding.DecodeString(in)
+	if err != nil {
+		return nil, ErrBadCursor
+	}
+	c := &Cursor{}
+	err = json.Unmarshal(data, c)
+	if err != nil || c.ID == 0 {
+		return nil, ErrBadCursor
+	}
+	return c, nil
+}
+
+type Page struct {
+	After *Cursor
+	Limit int
+}
+
+// NewPage собирает страницу из параметров graphql-запроса (first, after)
+func NewPage(first *int, after *string) (Page, error) {
+	p := Page{Limit: DefaultLimit}
+	if first != nil {
+		p.Limit = *first
+	}
+	p.Limit = clamp(p.Limit)
+	if after != nil {
+		c, err := Decode(*after)
+		if err != nil {
+			return p, err
+		}
+		p.After = c
+	}
+	return p, nil
+}
+
+// FromQuery собирает страницу из rest-параметров ?after=&limit=
+func FromQuery(after, limit string) (Page, error) {
+	p := Page{Limit: DefaultLimit}
+	if limit != "" {
+		l, err := strconv.Atoi(limit)
+		if err != nil {
+			return p, err
+		}
+		p.Limit = clamp(l)
+	}
+	c, err := Decode(after)
+	if err != nil {
+		return p, err
+	}
+	p.After = c
+	return p, nil
+}
+
+// FetchLimit - сколько строк просить у базы: на одну больше,
+// чтобы без отдельного count(*) понять, есть ли следующая страница
+func (p Page) FetchLimit() int {
+	return p.Limit + 1
+}
+
+// HasNext сообщает, есть ли следующая страница, если из базы пришло n строк
+func (p Page) HasNext(n int) bool {
+	return n > p.Limit
+}
+
+func clamp(limit int) int {
+	if limit <= 0 {
+		return DefaultLimit
+	}
+	if limit > MaxLimit {
+		return MaxLimit
+	}
+	return limit
+}
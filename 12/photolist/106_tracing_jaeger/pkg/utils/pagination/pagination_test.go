@@ -0,0 +1,124 @@
+package pagination
+
+import (
+	"encoding/base64"
+	"testing"
+)
+
+func TestCursor(t *testing.T) {
+	cases := []struct {
+		name   string
+		cursor Cursor
+	}{
+		{"id only", Cursor{ID: 42}},
+		{"with rating", Cursor{ID: 7, Rating: -3}},
+	}
+	for _, c := range cases {
+		decoded, err := Decode(c.cursor.Encode())
+		if err != nil {
+			t.Errorf("[%s] unexpected err: %s", c.name, err)
+			continue
+		}
+		if *decoded != c.cursor {
+			t.Errorf("[%s] bad cursor: %+v, expected %+v", c.name, *decoded, c.cursor)
+		}
+	}
+
+	// пустая строка - начало выборки
+	if c, err := Decode(""); c != nil || err != nil {
+		t.Errorf("[empty] expected nil cursor, got %+v %v", c, err)
+	}
+}
+
+func TestBadCursor(t *testing.T) {
+	cases := []struct {
+		name string
+		in   string
+	}{
+		{"not base64", "!!!"},
+		{"not json", base64.RawURLEncoding.EncodeToString([]byte("hello"))},
+		{"zero id", base64.RawURLEncoding.EncodeToString([]byte(`{"id":0}`))},
+		{"no id", base64.RawURLEncoding.EncodeToString([]byte(`{"r":5}`))},
+	}
+	for _, c := range cases {
+		_, err := Decode(c.in)
+		if err != ErrBadCursor {
+			t.Errorf("[%s] expected ErrBadCursor, got %v", c.name, err)
+		}
+	}
+}
+
+func TestNewPage(t *testing.T) {
+	intPtr := func(i int) *int { return &i }
+	strPtr := func(s string) *string { return &s }
+	bad := "!!!"
+	cursor := (&Cursor{ID: 10}).Encode()
+
+	cases := []struct {
+		name  string
+		first *int
+		after *string
+		limit int
+		ok    bool
+	}{
+		{"defaults", nil, nil, DefaultLimit, true},
+		{"first", intPtr(20), nil, 20, true},
+		{"zero first", intPtr(0), nil, DefaultLimit, true},
+		{"negative first", intPtr(-5), nil, DefaultLimit, true},
+		{"too big first", intPtr(MaxLimit + 1), nil, MaxLimit, true},
+		{"with cursor", intPtr(5), strPtr(cursor), 5, true},
+		{"bad cursor", nil, &bad, DefaultLimit, false},
+	}
+	for _, c := range cases {
+		p, err := NewPage(c.first, c.after)
+		if c.ok != (err == nil) {
+			t.Errorf("[%s] unexpected err: %v", c.name, err)
+			continue
+		}
+		if p.Limit != c.limit {
+			t.Errorf("[%s] bad limit: %d, expected %d", c.name, p.Limit, c.limit)
+		}
+		if c.ok && c.after != nil && (p.After == nil || p.After.ID != 10) {
+			t.Errorf("[%s] cursor lost: %+v", c.name, p.After)
+		}
+	}
+}
+
+func TestFromQuery(t *testing.T) {
+	cases := []struct {
+		name  string
+		after string
+		limit string
+		want  int
+		ok    bool
+	}{
+		{"empty", "", "", DefaultLimit, true},
+		{"limit", "", "30", 30, true},
+		{"limit over max", "", "1000", MaxLimit, true},
+		{"bad limit", "", "ten", DefaultLimit, false},
+		{"bad cursor", "!!!", "", DefaultLimit, false},
+	}
+	for _, c := range cases {
+		p, err := FromQuery(c.after, c.limit)
+		if c.ok != (err == nil) {
+			t.Errorf("[%s] unexpected err: %v", c.name, err)
+			continue
+		}
+		if c.ok && p.Limit != c.want {
+			t.Errorf("[%s] bad limit: %d, expected %d", c.name, p.Limit, c.want)
+		}
+	}
+}
+
+func TestHasNext(t *testing.T) {
+	p := Page{Limit: 10}
+	if p.FetchLimit() != 11 {
+		t.Errorf("bad fetch limit: %d", p.FetchLimit())
+	}
+	if p.HasNext(10) {
+		t.Errorf("10 of 10 rows is the last page")
+	}
+	if !p.HasNext(11) {
+		t.Errorf("11 rows means there is a next page")
+	}
+}
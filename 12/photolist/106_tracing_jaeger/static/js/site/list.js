@@ -32,9 +32,14 @@ function rateCommentToggle(elem) {
     request.send();
 }
 
-function renderPhotos(uid) {
+// after - курсор next из прошлого ответа, без него список рисуется заново
+function renderPhotos(uid, after) {
     var request = new XMLHttpRequest();
-    request.open('GET', '/api/v1/photos/list?uid='+uid, true);
+    var url = '/api/v1/photos/list?uid='+uid;
+    if(after) {
+        url += '&after='+encodeURIComponent(after);
+    }
+    request.open('GET', url, true);
     
     var csrf_token = document.querySelector("meta[name='csrf-token']").getAttribute("content");
     
@@ -47,15 +52,18 @@ function renderPhotos(uid) {
         }
 
         msgs = document.getElementById("photolist");
-        msgs.innerHTML = "";
+        if(!after) {
+            msgs.innerHTML = "";
+        }
+        setLoadMore(resp.body.has_next ? resp.body.next : "");
         resp.body.photolist.forEach(elem => {
             // you better to use modern JS frameworks in production
             msgNode = `<div>
                 <div style="border-bottom:1px solid silver; padding:4px; font-size:14px;">
-                    <a href="/photos/${elem.user_login}" class="userName"><img style="border-radius: 15px;" src="/images/${elem.path}_32.jpg" /> ${elem.user_login}</a>
+                    <a href="/photos/${elem.user_login}" class="userName"><img style="border-radius: 15px;" src="/img/${elem.path}/avatar" /> ${elem.user_login}</a>
                     <a onclick="followUser(this);" data-id="${elem.user_id}" href="#">${elem.followed ? "[unfollow]" : "[follow]"}</a>
                 </div>
-				<img src="/images/${elem.path}_600.jpg" />
+				<img src="/img/${elem.path}/feed" />
 				<div class="details">
 					<span onclick="rateCommentToggle(this)" data-id="${elem.id}" class="hi ${elem.liked === true ? 'hi-red' : ''}">❤</span>
 					<span class="rating" id="rating-${elem.id}">${elem.rating}</span>
@@ -83,6 +91,18 @@ function renderPhotos(uid) {
     request.send();
 }
 
+// кнопка "Load more" помнит курсор следующей страницы, пустой курсор - страниц больше нет
+function setLoadMore(cursor) {
+    var btn = document.getElementById("load-more");
+    btn.setAttribute("data-cursor", cursor);
+    btn.style.display = cursor ? "" : "none";
+}
+
+function loadMorePhotos(uid) {
+    renderPhotos(uid, document.getElementById("load-more").getAttribute("data-cursor"));
+    return false;
+}
+
 function uploadPhoto(uid) {
     var form = new FormData(document.getElementById('uploadPhoto'))
     var request = new XMLHttpRequest();
@@ -59,37 +59,50 @@ function rateCommentToggle(elem) {
 }
 
   
-const getPhotosQuery = `query renderUserPage($userID: ID!) {
+const getPhotosQuery = `query renderUserPage($userID: ID!, $after: String) {
     user (userID: $userID) {
       id
       name
       avatar
-      photos {
-        id
-        user {id, name, avatar, followed}
-        url
-        comment
-        rating
-        liked
+      photos(first: 20, after: $after) {
+        edges {
+          node {
+            id
+            user {id, name, avatar, followed}
+            url
+            thumbExt
+            status
+            comment
+            rating
+            liked
+          }
+        }
+        pageInfo {hasNextPage, endCursor}
       }
     }
     me {
         id
         name
         avatar
-        followedUsers {id, name, avatar, followed}
-        recomendedUsers {id, name, avatar, followed}
+        followedUsers {edges {node {id, name, avatar, followed}}}
+        recomendedUsers {edges {node {id, name, avatar, followed}}}
     }
   }
 `
 
-function getUserPhotos(uid) {
+function edgeNodes(conn) {
+    return conn.edges.map(edge => edge.node);
+}
+
+// after - endCursor прошлой страницы, без него список рисуется заново
+function getUserPhotos(uid, after) {
     var request = NewGQLRequest();
 
     request.setRequestHeader('Content-Type', 'application/json');
     var params = {
         variables: {
-            userID: uid
+            userID: uid,
+            after: after || null,
         },
         query: getPhotosQuery,
         operationName: "renderUserPage",
@@ -104,9 +117,15 @@ function getUserPhotos(uid) {
             return;
         }
 
-        renderPhotosHTML(resp.data.user.photos);
-        renderUserListHTML(resp.data.me.followedUsers, "following");
-        renderUserListHTML(resp.data.me.recomendedUsers, "recomends");
+        var photos = resp.data.user.photos;
+        renderPhotosHTML(edgeNodes(photos), !!after);
+        setLoadMore(photos.pageInfo.hasNextPage ? photos.pageInfo.endCursor : "");
+        if(after) {
+            return;
+        }
+        renderUserListHTML(edgeNodes(resp.data.me.followedUsers), "following");
+        renderUserListHTML(edgeNodes(resp.data.me.recomendedUsers), "recomends");
+        setUnreadCounter(resp.data.me.unreadNotifications);
     };
     request.onerror = function() {
         console.log("renderPhotos error", request.responseText)
@@ -114,22 +133,51 @@ function getUserPhotos(uid) {
     request.send(body);
 }
 
-function renderPhotosHTML(elems) {
+function photoImageHTML(elem) {
+    switch(elem.status) {
+    case "READY":
+        return `<a href="/img/${elem.url}/full" target="_blank"><img src="/img/${elem.url}/feed" /></a>`;
+    case "FAILED":
+        return `<div class="details">image processing failed</div>`;
+    default:
+        return `<div class="details">image is processing...</div>`;
+    }
+}
+
+// кнопка "Load more" помнит курсор следующей страницы, пустой курсор - страниц больше нет
+function setLoadMore(cursor) {
+    var btn = document.getElementById("load-more");
+    btn.setAttribute("data-cursor", cursor);
+    btn.style.display = cursor ? "" : "none";
+}
+
+function loadMorePhotos(uid) {
+    getUserPhotos(uid, document.getElementById("load-more").getAttribute("data-cursor"));
+    return false;
+}
+
+function renderPhotosHTML(elems, append) {
     msgs = document.getElementById("photolist");
-    msgs.innerHTML = "";
+    if(!append) {
+        msgs.innerHTML = "";
+    }
     elems.forEach(elem => {
         // you better to use modern JS frameworks in production
         msgNode = `<div>
             <div style="border-bottom:1px solid silver; padding:4px; font-size:14px;">
                 <a href="/photos/${elem.user.name}" class="userName"><img style="border-radius: 15px;" height=32 width=32 src="${elem.user.avatar}" /> ${elem.user.name}</a>
                 <a onclick="followUser(this);" data-id="${elem.user.id}" href="#">${elem.user.followed ? "[unfollow]" : "[follow]"}</a>
+                ${elem.user.id == current_uid ? `
+                <a onclick="editPhoto(this); return false;" data-id="${elem.id}" href="#">[edit]</a>
+                <a onclick="deletePhoto(this); return false;" data-id="${elem.id}" href="#">[delete]</a>` : `
+                <a onclick="reportPhoto(this); return false;" data-id="${elem.id}" href="#">[report]</a>`}
             </div>
-            <img src="/images/${elem.user.id}/${elem.url}_600.jpg" />
+            ${photoImageHTML(elem)}
             <div class="details">
                 <span onclick="rateCommentToggle(this)" data-id="${elem.id}" class="hi ${elem.liked === true ? 'hi-red' : ''}">❤</span>
                 <span class="rating" id="rating-${elem.id}">${elem.rating}</span>
                 <br/>
-                ${encodeHTML(elem.comment)}
+                <span id="comment-${elem.id}">${encodeHTML(elem.comment)}</span>
             </div>
             <div class="commentForm">
                 <form onsubmit="return false">
@@ -174,9 +222,9 @@ Content-Disposition: form-data; name="map"
 */
 
 const uploadPhotoMutation = `
-mutation uploadPhoto($comment: String!, $file: Upload!) { 
-    uploadPhoto(comment: $comment, file: $file) { 
-        id, comment
+mutation uploadPhoto($comment: String!, $file: Upload!, $visibility: PhotoVisibility) { 
+    uploadPhoto(comment: $comment, file: $file, visibility: $visibility) { 
+        id, comment, visibility
     }
 }
 `
@@ -190,6 +238,7 @@ function uploadPhoto(uid) {
         variables: {
             comment: form.get("comment"),
             file: null,
+            visibility: form.get("visibility"),
         }
     }));
     form2send.set("map", JSON.stringify({
@@ -233,8 +282,9 @@ query getUserList{
       id
       name
       avatar
-      followedUsers {id, name, avatar, followed}
-      recomendedUsers {id, name, avatar, followed}
+      unreadNotifications
+      followedUsers {edges {node {id, name, avatar, followed}}}
+      recomendedUsers {edges {node {id, name, avatar, followed}}}
     }
 }
 `
@@ -255,8 +305,9 @@ function getUserList() {
             console.log("getUserList server err:", resp.errors);
             return;
         }
-        renderUserListHTML(resp.data.me.followedUsers, "following");
-        renderUserListHTML(resp.data.me.recomendedUsers, "recomends");
+        renderUserListHTML(edgeNodes(resp.data.me.followedUsers), "following");
+        renderUserListHTML(edgeNodes(resp.data.me.recomendedUsers), "recomends");
+        setUnreadCounter(resp.data.me.unreadNotifications);
     };
     request.onerror = function() {
         console.log("renderUserList error", request.responseText)
@@ -297,4 +348,187 @@ function followUser(elem) {
     };
     request.send(body);
     return false;
-}
\ No newline at end of file
+}
+
+const deletePhotoMutation = `
+mutation deletePhoto($photoID: ID!) {
+    deletePhoto(photoID: $photoID)
+}
+`
+
+function deletePhoto(elem) {
+    if(!confirm("Delete photo?")) {
+        return;
+    }
+    var request = NewGQLRequest();
+    request.setRequestHeader('Content-Type', 'application/json');
+    var params = {
+        variables: {
+            photoID: elem.getAttribute('data-id'),
+        },
+        query: deletePhotoMutation,
+        operationName: "deletePhoto",
+    };
+    var body = JSON.stringify(params);
+
+    request.onload = function() {
+        var resp = JSON.parse(request.responseText);
+        if(resp.errors) {
+            console.log("deletePhoto server err:", resp.errors);
+            return;
+        }
+        elem.closest(".photoElem").remove();
+    };
+    request.send(body);
+}
+
+const reportPhotoMutation = `
+mutation reportPhoto($photoID: ID!, $reason: String) {
+    reportPhoto(photoID: $photoID, reason: $reason)
+}
+`
+
+function reportPhoto(elem) {
+    var reason = prompt("What is wrong with this photo?");
+    if(reason === null) {
+        return;
+    }
+    var request = NewGQLRequest();
+    request.setRequestHeader('Content-Type', 'application/json');
+    var params = {
+        variables: {
+            photoID: elem.getAttribute('data-id'),
+            reason: reason,
+        },
+        query: reportPhotoMutation,
+        operationName: "reportPhoto",
+    };
+    var body = JSON.stringify(params);
+
+    request.onload = function() {
+        var resp = JSON.parse(request.responseText);
+        if(resp.errors) {
+            console.log("reportPhoto server err:", resp.errors);
+            return;
+        }
+        elem.innerHTML = "[reported]";
+        elem.onclick = null;
+    };
+    request.send(body);
+}
+
+const updatePhotoCommentMutation = `
+mutation updatePhotoComment($photoID: ID!, $comment: String!) {
+    updatePhotoComment(photoID: $photoID, comment: $comment) {
+        id
+        comment
+    }
+}
+`
+
+function editPhoto(elem) {
+    var id = elem.getAttribute('data-id');
+    var comment = prompt("Comment", document.querySelector('#comment-'+id).textContent);
+    if(comment === null) {
+        return;
+    }
+    var request = NewGQLRequest();
+    request.setRequestHeader('Content-Type', 'application/json');
+    var params = {
+        variables: {
+            photoID: id,
+            comment: comment,
+        },
+        query: updatePhotoCommentMutation,
+        operationName: "updatePhotoComment",
+    };
+    var body = JSON.stringify(params);
+
+    request.onload = function() {
+        var resp = JSON.parse(request.responseText);
+        if(resp.errors) {
+            console.log("updatePhotoComment server err:", resp.errors);
+            return;
+        }
+        var photo = resp.data.updatePhotoComment;
+        document.querySelector('#comment-'+photo.id).innerHTML = encodeHTML(photo.comment);
+    };
+    request.send(body);
+}
+
+function setUnreadCounter(cnt) {
+    var elem = document.querySelector('#notifications-cnt');
+    if(!elem) {
+        return;
+    }
+    elem.setAttribute('data-cnt', cnt);
+    elem.innerHTML = cnt > 0 ? `Notifications (${cnt})` : "Notifications";
+}
+
+const notificationAddedSubscription = `
+subscription notificationAdded {
+    notificationAdded {
+        id
+        type
+        actor {id, name}
+    }
+}
+`
+
+// протокол graphql-ws (subscriptions-transport-ws), его понимает gqlgen
+// https://github.com/apollographql/subscriptions-transport-ws/blob/master/PROTOCOL.md
+function subscribeNotifications() {
+    var proto = location.protocol == "https:" ? "wss://" : "ws://";
+    var ws = new WebSocket(proto + location.host + "/graphql", "graphql-ws");
+    ws.onopen = function() {
+        // заголовков у websocket нет, мутациям токен нужен в payload
+        var csrf_token = document.querySelector("meta[name='csrf-token']").getAttribute("content");
+        ws.send(JSON.stringify({type: "connection_init", payload: {"csrf-token": csrf_token}}));
+        ws.send(JSON.stringify({
+            id: "1",
+            type: "start",
+            payload: {query: notificationAddedSubscription, operationName: "notificationAdded"},
+        }));
+    };
+    ws.onmessage = function(event) {
+        var msg = JSON.parse(event.data);
+        if(msg.type != "data") {
+            return;
+        }
+        if(msg.payload.errors) {
+            console.log("notificationAdded server err:", msg.payload.errors);
+            return;
+        }
+        var elem = document.querySelector('#notifications-cnt');
+        setUnreadCounter(parseInt(elem.getAttribute('data-cnt') || "0") + 1);
+    };
+    ws.onclose = function() {
+        // сервер перезапустился или пропала сеть - переподключаемся
+        setTimeout(subscribeNotifications, 5000);
+    };
+}
+
+const markNotificationsReadMutation = `
+mutation markNotificationsRead {
+    markNotificationsRead
+}
+`
+
+function markNotificationsRead() {
+    var request = NewGQLRequest();
+    request.setRequestHeader('Content-Type', 'application/json');
+    var params = {
+        query: markNotificationsReadMutation,
+        operationName: "markNotificationsRead",
+    };
+    request.onload = function() {
+        var resp = JSON.parse(request.responseText);
+        if(resp.errors) {
+            console.log("markNotificationsRead server err:", resp.errors);
+            return;
+        }
+        setUnreadCounter(resp.data.markNotificationsRead);
+    };
+    request.send(JSON.stringify(params));
+    return false;
+}